@@ -0,0 +1,330 @@
+package jsl
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Severity ranks a Finding or a report Issue.
+type Severity string
+
+const (
+	// SeverityError: Convert fails for the target (a limit is exceeded).
+	SeverityError Severity = "error"
+	// SeverityWarning: the schema converts, but the model sees a degraded
+	// shape — a subtree sent as a JSON string or a keyword stripped.
+	SeverityWarning Severity = "warning"
+	// SeverityInfo: a constraint is dropped from the converted schema and
+	// only enforced again by Rehydrate.
+	SeverityInfo Severity = "info"
+)
+
+// Finding codes reported by Analyze.
+const (
+	FindingUnsupportedKeyword = "unsupported_keyword"
+	FindingDroppedConstraint  = "dropped_constraint"
+	FindingNestingDepth       = "nesting_depth"
+	FindingOversizedEnum      = "oversized_enum"
+	FindingOpaqueSubtree      = "opaque_subtree"
+	FindingNonObjectRoot      = "non_object_root"
+)
+
+// Finding is one LLM-compatibility issue Analyze found, located by Pointer
+// (a schema pointer into the input schema).
+type Finding struct {
+	Code     string   `json:"code"`
+	Severity Severity `json:"severity"`
+	Pointer  string   `json:"pointer"`
+	Keyword  string   `json:"keyword,omitempty"`
+	Target   string   `json:"target"`
+	Message  string   `json:"message"`
+}
+
+// analyzeTarget lists what a target cannot express. structural keywords
+// hold subschemas the converter strips or stringifies; dropped keywords
+// are constraints removed from the converted schema.
+type analyzeTarget struct {
+	structural []string
+	dropped    []string
+	// opaque reports whether unconstrained subschemas are stringified.
+	opaque bool
+	// objectRoot reports whether a non-object root must be wrapped.
+	objectRoot bool
+}
+
+//go:generate go run ./internal/genrules
+
+// analyzeTargets derives each target's structural and dropped keywords from
+// the core's tables (analyze_rules.go) with coreCaps.
+var analyzeTargets = map[string]analyzeTarget{
+	"openai-strict": coreCaps("openai-strict", true, true),
+	"claude":        coreCaps("claude", true, false),
+	"gemini":        coreCaps("gemini", false, false),
+}
+
+// coreCaps builds target's analyzeTarget. Every keyword the guest's
+// constraint pass prunes for target is structural if it holds subschemas
+// and a dropped constraint otherwise; "default" is skipped, as an
+// annotation Rehydrate has nothing to enforce for. OpenAI strict mode also
+// rejects its banned subschema keywords, which conversion strips or
+// stringifies.
+func coreCaps(target string, opaque, objectRoot bool) analyzeTarget {
+	subschema := map[string]bool{
+		// Draft-07 dependencies holds schemas as well as name lists.
+		"dependencies": true,
+	}
+	for _, kw := range coreSubschemaKeywords {
+		subschema[kw] = true
+	}
+	keywords := append(append([]string{}, coreDroppedKeywords[""]...), coreDroppedKeywords[target]...)
+	if target == "openai-strict" {
+		for _, kw := range coreStrictBanned {
+			if subschema[kw] && !slices.Contains(keywords, kw) {
+				keywords = append(keywords, kw)
+			}
+		}
+	}
+	caps := analyzeTarget{opaque: opaque, objectRoot: objectRoot}
+	for _, kw := range keywords {
+		switch {
+		case kw == "default":
+		case subschema[kw]:
+			caps.structural = append(caps.structural, kw)
+		default:
+			caps.dropped = append(caps.dropped, kw)
+		}
+	}
+	return caps
+}
+
+// typingKeywords give a subschema enough shape for the model to fill it.
+var typingKeywords = []string{
+	"type", "enum", "const", "$ref", "anyOf", "oneOf", "allOf",
+	"properties", "items", "prefixItems", "additionalProperties", "patternProperties", "required",
+}
+
+// Analyze reports what schema would lose or fail on when converted for
+// target ("" means openai-strict): unsupported keywords, constraints the
+// target drops, nesting past the target's depth limit, oversized enums and
+// opaque (unconstrained) subtrees. It runs entirely on the host and does
+// not convert, so it suits a CI check on schema changes. Findings are
+// sorted by pointer, then code.
+func (e *SchemaLlmEngine) Analyze(schema any, target string) ([]Finding, error) {
+	if target == "" {
+		target = defaultTarget
+	}
+	caps, ok := analyzeTargets[target]
+	if !ok {
+		return nil, &Error{Code: "schema_error", Message: fmt.Sprintf("analyze: unknown target %q", target)}
+	}
+	decoded, err := decodeForDiff(schema)
+	if err != nil {
+		return nil, fmt.Errorf("analyze: decode schema: %w", err)
+	}
+	root, ok := decoded.(map[string]any)
+	if !ok {
+		return nil, &Error{Code: "schema_error", Message: "analyze: root schema must be an object"}
+	}
+
+	a := &analyzer{
+		target: target, caps: caps, limits: TargetLimits(target), root: root,
+		deep: map[string]bool{}, seen: map[string]bool{},
+	}
+	if caps.objectRoot && !isObjectRoot(root, root) {
+		a.add(FindingNonObjectRoot, SeverityInfo, "#", "", fmt.Sprintf("%s requires an object root; the schema is wrapped in one and unwrapped by Rehydrate", target))
+	}
+	a.visit("#", root)
+	if a.limits.MaxNestingDepth > 0 {
+		a.depth("#", root, 0, map[string]bool{})
+	}
+	sort.SliceStable(a.findings, func(i, j int) bool {
+		fi, fj := a.findings[i], a.findings[j]
+		if fi.Pointer != fj.Pointer {
+			return fi.Pointer < fj.Pointer
+		}
+		return fi.Code < fj.Code
+	})
+	return a.findings, nil
+}
+
+type analyzer struct {
+	target   string
+	caps     analyzeTarget
+	limits   ProviderLimits
+	root     map[string]any
+	enums    int
+	findings []Finding
+	// deep and seen are the depth walk's reported pointers and visited
+	// ref@level pairs.
+	deep, seen map[string]bool
+}
+
+func (a *analyzer) add(code string, sev Severity, loc, keyword, msg string) {
+	a.findings = append(a.findings, Finding{Code: code, Severity: sev, Pointer: loc, Keyword: keyword, Target: a.target, Message: msg})
+}
+
+// visit checks node and descends into the subschemas the target keeps;
+// subschemas under a structural keyword are reported once, at the keyword.
+func (a *analyzer) visit(loc string, node map[string]any) {
+	for _, kw := range a.caps.structural {
+		if _, ok := node[kw]; ok {
+			a.add(FindingUnsupportedKeyword, SeverityWarning, childPointer(loc, kw), kw,
+				fmt.Sprintf("%s cannot express %s; it is stripped or the object is sent as a JSON string", a.target, kw))
+		}
+	}
+	for _, kw := range a.caps.dropped {
+		if _, ok := node[kw]; ok {
+			a.add(FindingDroppedConstraint, SeverityInfo, childPointer(loc, kw), kw,
+				fmt.Sprintf("%s drops %s; it is enforced only by Rehydrate", a.target, kw))
+		}
+	}
+	if a.caps.opaque && loc != "#" && isOpaqueSchema(node) {
+		a.add(FindingOpaqueSubtree, SeverityWarning, loc, "",
+			"the schema does not constrain its value; it is sent as a JSON string and parsed by Rehydrate")
+	}
+	if enum, ok := node["enum"].([]any); ok {
+		a.checkEnum(loc, len(enum))
+	}
+
+	skip := map[string]bool{}
+	for _, kw := range a.caps.structural {
+		skip[kw] = true
+	}
+	for _, kw := range booleanMapKeywords {
+		children, _ := node[kw].(map[string]any)
+		if skip[kw] {
+			continue
+		}
+		for _, k := range sortedKeys(children) {
+			a.child(childPointer(childPointer(loc, kw), k), children[k])
+		}
+	}
+	for _, kw := range booleanListKeywords {
+		children, _ := node[kw].([]any)
+		for i, c := range children {
+			a.child(childPointer(childPointer(loc, kw), itoa(i)), c)
+		}
+	}
+	for _, kw := range []string{"items", "additionalProperties"} {
+		switch c := node[kw].(type) {
+		case map[string]any:
+			a.child(childPointer(loc, kw), c)
+		case []any: // draft-07 tuple "items"
+			for i, item := range c {
+				a.child(childPointer(childPointer(loc, kw), itoa(i)), item)
+			}
+		}
+	}
+}
+
+// child visits a subschema; a `true` subschema is as opaque as `{}`.
+func (a *analyzer) child(loc string, c any) {
+	switch c := c.(type) {
+	case map[string]any:
+		a.visit(loc, c)
+	case bool:
+		if c && a.caps.opaque {
+			a.add(FindingOpaqueSubtree, SeverityWarning, loc, "",
+				"the `true` schema does not constrain its value; it is sent as a JSON string and parsed by Rehydrate")
+		}
+	}
+}
+
+// checkEnum reports an enum over MaxEnumValues on its own, and the enum at
+// which the schema-wide total first crosses the limit.
+func (a *analyzer) checkEnum(loc string, n int) {
+	limit := a.limits.MaxEnumValues
+	if limit <= 0 {
+		return
+	}
+	before := a.enums
+	a.enums += n
+	switch {
+	case n > limit:
+		a.add(FindingOversizedEnum, SeverityError, childPointer(loc, "enum"), "enum",
+			fmt.Sprintf("enum has %d values; %s allows %d (set ConvertOptions.MaxEnumValues to degrade it to a string)", n, a.target, limit))
+	case before <= limit && a.enums > limit:
+		a.add(FindingOversizedEnum, SeverityError, childPointer(loc, "enum"), "enum",
+			fmt.Sprintf("enum values total %d here; %s allows %d across the schema", a.enums, a.target, limit))
+	}
+}
+
+// depth follows properties, items and composition branches — and local
+// $refs, which conversion inlines — and reports each node that first sits
+// deeper than MaxNestingDepth. base is the number of object/array levels
+// above node. stack guards against recursive refs, which the recursion
+// limit handles instead.
+func (a *analyzer) depth(loc string, node map[string]any, base int, stack map[string]bool) {
+	level := base
+	if isContainerSchema(node) {
+		level++
+	}
+	if level > a.limits.MaxNestingDepth {
+		if !a.deep[loc] {
+			a.deep[loc] = true
+			a.add(FindingNestingDepth, SeverityError, loc, "",
+				fmt.Sprintf("nesting depth %d exceeds the %s limit of %d", level, a.target, a.limits.MaxNestingDepth))
+		}
+		return
+	}
+	if ref, ok := node["$ref"].(string); ok && strings.HasPrefix(ref, "#") && !stack[ref] {
+		// A shared definition reached again at the same level finds nothing new.
+		key := ref + "@" + itoa(base)
+		if target, ok := lookupPointer(a.root, ref); ok && !a.seen[key] {
+			if t, ok := target.(map[string]any); ok {
+				a.seen[key] = true
+				stack[ref] = true
+				a.depth(ref, t, base, stack)
+				delete(stack, ref)
+			}
+		}
+	}
+	if props, ok := node["properties"].(map[string]any); ok {
+		for _, k := range sortedKeys(props) {
+			if c, ok := props[k].(map[string]any); ok {
+				a.depth(childPointer(childPointer(loc, "properties"), k), c, level, stack)
+			}
+		}
+	}
+	if c, ok := node["items"].(map[string]any); ok {
+		a.depth(childPointer(loc, "items"), c, level, stack)
+	}
+	for _, kw := range []string{"anyOf", "oneOf", "allOf"} {
+		branches, _ := node[kw].([]any)
+		for i, b := range branches {
+			if c, ok := b.(map[string]any); ok {
+				a.depth(childPointer(childPointer(loc, kw), itoa(i)), c, base, stack)
+			}
+		}
+	}
+}
+
+func isOpaqueSchema(node map[string]any) bool {
+	for _, kw := range typingKeywords {
+		if _, ok := node[kw]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// isObjectRoot reports whether node (the root, or what its $ref names)
+// is an object schema.
+func isObjectRoot(root, node map[string]any) bool {
+	if _, ok := node["properties"]; ok {
+		return true
+	}
+	if node["type"] == "object" {
+		return true
+	}
+	if ref, ok := node["$ref"].(string); ok && ref != "#" {
+		if t, ok := lookupPointer(root, ref); ok {
+			if m, ok := t.(map[string]any); ok && !jsonEqual(m, node) {
+				return isObjectRoot(root, m)
+			}
+		}
+	}
+	return false
+}
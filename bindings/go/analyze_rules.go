@@ -0,0 +1,20 @@
+// Code generated by go run ./internal/genrules; DO NOT EDIT.
+
+package jsl
+
+var (
+	// coreDroppedKeywords is unsupported_constraints in passes/p7_constraints.rs:
+	// the keywords the guest prunes for every target ("") and per target.
+	coreDroppedKeywords = map[string][]string{
+		"":              {"uniqueItems", "default", "not", "if", "then", "else", "multipleOf", "minProperties", "maxProperties", "propertyNames", "dependencies", "dependentRequired", "dependentSchemas", "contains", "minContains", "maxContains", "format"},
+		"claude":        {"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "minLength", "maxLength", "minItems", "maxItems", "pattern"},
+		"gemini":        {},
+		"openai-strict": {"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "minLength", "maxLength", "minItems", "maxItems"},
+	}
+	// coreStrictBanned is BANNED_KEYWORD_RULES in validation/strict_mode.rs:
+	// keywords OpenAI strict mode rejects in a converted schema.
+	coreStrictBanned = []string{"patternProperties", "$anchor", "$dynamicRef", "$dynamicAnchor", "dependentSchemas", "dependentRequired", "unevaluatedProperties", "unevaluatedItems", "contains", "minContains", "maxContains", "if", "then", "else", "not"}
+	// coreSubschemaKeywords are MAP_KEYWORDS, SINGLE_KEYWORDS and
+	// ARRAY_KEYWORDS in schema_walker.rs: keywords that hold subschemas.
+	coreSubschemaKeywords = []string{"properties", "patternProperties", "$defs", "definitions", "dependentSchemas", "additionalProperties", "unevaluatedProperties", "propertyNames", "unevaluatedItems", "contains", "not", "if", "then", "else", "additionalItems", "anyOf", "oneOf", "allOf", "prefixItems"}
+)
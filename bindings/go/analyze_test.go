@@ -0,0 +1,157 @@
+package jsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func findingKeys(findings []Finding) []string {
+	keys := make([]string, len(findings))
+	for i, f := range findings {
+		keys[i] = fmt.Sprintf("%s %s %s", f.Severity, f.Code, f.Pointer)
+	}
+	return keys
+}
+
+// TestAnalyze verifies each finding kind, that subschemas under an
+// unsupported keyword are not reported again, and the ordering.
+func TestAnalyze(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"age": {"type": "integer", "minimum": 0},
+			"meta": {},
+			"extra": true,
+			"kind": {"enum": ["a", "b"]},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+			"shape": {"type": "object", "not": {"properties": {"x": {}}}},
+			"ref": {"$ref": "#/$defs/Thing"}
+		},
+		"$defs": {"Thing": {"type": "object", "patternProperties": {"^x": {}}}}
+	}`
+	findings, err := (*SchemaLlmEngine)(nil).Analyze(json.RawMessage(schema), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"warning unsupported_keyword #/$defs/Thing/patternProperties",
+		"info dropped_constraint #/properties/age/minimum",
+		"warning opaque_subtree #/properties/extra",
+		"warning opaque_subtree #/properties/meta",
+		"warning unsupported_keyword #/properties/shape/not",
+		"info dropped_constraint #/properties/tags/uniqueItems",
+	}
+	if got := findingKeys(findings); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings =\n%s", strings.Join(got, "\n"))
+	}
+	for _, f := range findings {
+		if f.Target != "openai-strict" || f.Message == "" {
+			t.Errorf("finding %+v: want target openai-strict and a message", f)
+		}
+	}
+
+	// gemini keeps patternProperties, bounds and unconstrained schemas.
+	findings, err = (*SchemaLlmEngine)(nil).Analyze(json.RawMessage(schema), "gemini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{
+		"warning unsupported_keyword #/properties/shape/not",
+		"info dropped_constraint #/properties/tags/uniqueItems",
+	}
+	if got := findingKeys(findings); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("gemini findings =\n%s", strings.Join(got, "\n"))
+	}
+}
+
+// TestAnalyzeLimits verifies depth is measured through $refs and that
+// single and cumulative enum overruns are errors.
+func TestAnalyzeLimits(t *testing.T) {
+	// Each Level nests one object deeper; the root is level 1.
+	defs := map[string]any{}
+	for i := 1; i <= OpenAIStrictLimits.MaxNestingDepth; i++ {
+		defs[fmt.Sprintf("L%d", i)] = map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"next": map[string]any{"$ref": fmt.Sprintf("#/$defs/L%d", i+1)}},
+		}
+	}
+	defs[fmt.Sprintf("L%d", OpenAIStrictLimits.MaxNestingDepth+1)] = map[string]any{"type": "string"}
+	big := make([]any, OpenAIStrictLimits.MaxEnumValues+1)
+	half := make([]any, OpenAIStrictLimits.MaxEnumValues/2+1)
+	for i := range big {
+		big[i] = fmt.Sprint(i)
+	}
+	copy(half, big)
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"chain": map[string]any{"$ref": "#/$defs/L1"},
+			"z":     map[string]any{"type": "string", "enum": big},
+			"b":     map[string]any{"type": "string", "enum": half},
+			"c":     map[string]any{"type": "string", "enum": half},
+		},
+		"$defs": defs,
+	}
+	findings, err := (*SchemaLlmEngine)(nil).Analyze(schema, "openai-strict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		fmt.Sprintf("error nesting_depth #/$defs/L%d", OpenAIStrictLimits.MaxNestingDepth),
+		"error oversized_enum #/properties/c/enum",
+		"error oversized_enum #/properties/z/enum",
+	}
+	if got := findingKeys(findings); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings =\n%s", strings.Join(got, "\n"))
+	}
+
+	// claude publishes no limits.
+	findings, err = (*SchemaLlmEngine)(nil).Analyze(schema, "claude")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("claude findings = %v", findingKeys(findings))
+	}
+}
+
+func TestAnalyzeErrors(t *testing.T) {
+	if _, err := (*SchemaLlmEngine)(nil).Analyze(json.RawMessage(`{}`), "bard"); err == nil {
+		t.Error("expected an error for an unknown target")
+	}
+	if _, err := (*SchemaLlmEngine)(nil).Analyze(json.RawMessage(`[1]`), ""); err == nil {
+		t.Error("expected an error for a non-object root")
+	}
+	findings, err := (*SchemaLlmEngine)(nil).Analyze(json.RawMessage(`{"$ref": "#/$defs/A", "$defs": {"A": {"type": "array", "items": {"type": "string"}}}}`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := findingKeys(findings); len(got) != 1 || got[0] != "info non_object_root #" {
+		t.Errorf("findings = %v", got)
+	}
+}
+
+// TestCoreCaps verifies keywords are classified by whether they hold
+// subschemas, and that strict mode's banned keywords apply to OpenAI only.
+func TestCoreCaps(t *testing.T) {
+	openai, gemini := analyzeTargets["openai-strict"], analyzeTargets["gemini"]
+	for _, kw := range []string{"not", "dependencies", "patternProperties", "unevaluatedProperties"} {
+		if !slices.Contains(openai.structural, kw) {
+			t.Errorf("openai-strict structural lacks %s: %v", kw, openai.structural)
+		}
+	}
+	for _, kw := range []string{"uniqueItems", "minimum", "dependentRequired"} {
+		if !slices.Contains(openai.dropped, kw) {
+			t.Errorf("openai-strict dropped lacks %s: %v", kw, openai.dropped)
+		}
+	}
+	if slices.Contains(gemini.structural, "patternProperties") || slices.Contains(gemini.dropped, "minimum") {
+		t.Errorf("gemini = %+v, want only the universal keywords", gemini)
+	}
+	if slices.Contains(openai.dropped, "default") {
+		t.Error("default is reported as a dropped constraint")
+	}
+}
@@ -0,0 +1,75 @@
+package jsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// transformAnnotationsStripped records annotation keywords removed from a
+// node by ConvertOptions.StripAnnotations.
+const transformAnnotationsStripped = "annotations_stripped"
+
+// strippableAnnotations are the keywords StripAnnotations accepts. They
+// carry no validation meaning, so removing them never changes what
+// Rehydrate accepts.
+var strippableAnnotations = map[string]bool{
+	"examples": true, "example": true, "default": true, "deprecated": true,
+	"$comment": true, "readOnly": true, "writeOnly": true, "title": true,
+}
+
+// DefaultStripAnnotations is the usual StripAnnotations list: keywords that
+// cost tokens and can leak internal notes without steering the model.
+var DefaultStripAnnotations = []string{"examples", "example", "default", "deprecated", "$comment"}
+
+// stripAnnotations removes opts.StripAnnotations keywords from every schema
+// node, recording one entry per node that lost any.
+func stripAnnotations(schema any, opts *ConvertOptions) (any, []HostTransform, error) {
+	for _, kw := range opts.StripAnnotations {
+		if !strippableAnnotations[kw] {
+			return nil, nil, fmt.Errorf("StripAnnotations: %q is not a strippable annotation keyword", kw)
+		}
+	}
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		var removed []any
+		for _, kw := range opts.StripAnnotations {
+			if _, ok := node[kw]; ok {
+				delete(node, kw)
+				removed = append(removed, kw)
+			}
+		}
+		if len(removed) > 0 {
+			entries = append(entries, HostTransform{Type: transformAnnotationsStripped, Path: loc, Params: map[string]any{"keywords": removed}})
+		}
+		return node
+	})
+	return schema, entries, nil
+}
+
+// rewriteAnnotationsStripped re-applies a recorded removal.
+func rewriteAnnotationsStripped(n any, t *HostTransform) any {
+	node, ok := n.(map[string]any)
+	if !ok {
+		return n
+	}
+	keywords, _ := t.Params["keywords"].([]any)
+	for _, kw := range keywords {
+		if s, ok := kw.(string); ok {
+			delete(node, s)
+		}
+	}
+	return node
+}
+
+func annotationsConvertWarning(t *HostTransform) (Warning, bool) {
+	keywords, _ := t.Params["keywords"].([]any)
+	names := make([]string, 0, len(keywords))
+	for _, kw := range keywords {
+		names = append(names, fmt.Sprint(kw))
+	}
+	return Warning{
+		SchemaPath: t.Path,
+		Kind:       WarningKind{Type: WarnAnnotationStripped},
+		Message:    "removed " + strings.Join(names, ", "),
+	}, true
+}
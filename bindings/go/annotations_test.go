@@ -0,0 +1,42 @@
+package jsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestStripAnnotations verifies listed keywords are removed and reported per node.
+func TestStripAnnotations(t *testing.T) {
+	var schema any
+	_ = json.Unmarshal([]byte(`{
+		"type": "object",
+		"$comment": "internal: owned by billing",
+		"properties": {
+			"default": {"type": "string", "default": "x", "examples": ["a"], "title": "Default"},
+			"age": {"type": "integer", "deprecated": true}
+		}
+	}`), &schema)
+	out, entries, err := stripAnnotations(schema, &ConvertOptions{StripAnnotations: DefaultStripAnnotations})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(out)
+	want := `{"properties":{"age":{"type":"integer"},"default":{"title":"Default","type":"string"}},"type":"object"}`
+	if string(got) != want {
+		t.Errorf("stripped = %s", got)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	warnings := hostConvertWarnings(entries)
+	if warnings[0].SchemaPath != "#/properties/age" || warnings[0].Message != "removed deprecated" {
+		t.Errorf("warning = %+v", warnings[0])
+	}
+}
+
+// TestStripAnnotationsRejectsValidationKeywords guards against stripping meaningful keywords.
+func TestStripAnnotationsRejectsValidationKeywords(t *testing.T) {
+	if _, _, err := stripAnnotations(map[string]any{}, &ConvertOptions{StripAnnotations: []string{"type"}}); err == nil {
+		t.Error("stripping type should fail")
+	}
+}
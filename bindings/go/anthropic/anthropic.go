@@ -0,0 +1,78 @@
+// Package anthropic wraps jsl conversion results as Anthropic Go SDK tool
+// definitions and rehydrates the tool_use blocks the model returns.
+//
+// It is a module of its own, so the core binding does not pin an
+// anthropic-sdk-go version on its importers.
+package anthropic
+
+import (
+	"encoding/json"
+	"fmt"
+
+	sdk "github.com/anthropics/anthropic-sdk-go"
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// Rehydrator is the engine surface RehydrateToolUse needs.
+type Rehydrator interface {
+	Rehydrate(data any, codec any, schema any) (*jsl.RehydrateResult, error)
+}
+
+// Tool returns a tool definition whose input_schema is the converted
+// schema of r. An empty description falls back to the schema's root
+// description. The converted schema must be an object schema.
+func Tool(name, description string, r *jsl.ConvertResult) (sdk.ToolParam, error) {
+	if name == "" {
+		return sdk.ToolParam{}, fmt.Errorf("anthropic: tool name is required")
+	}
+	if r == nil || r.Schema == nil {
+		return sdk.ToolParam{}, fmt.Errorf("anthropic: convert result has no schema")
+	}
+	if typ, ok := r.Schema["type"]; ok && typ != "object" {
+		return sdk.ToolParam{}, fmt.Errorf("anthropic: tool %s: input schema type is %v, not object", name, typ)
+	}
+	input := sdk.ToolInputSchemaParam{Properties: r.Schema["properties"]}
+	if required, ok := r.Schema["required"].([]any); ok {
+		for _, v := range required {
+			if s, ok := v.(string); ok {
+				input.Required = append(input.Required, s)
+			}
+		}
+	}
+	for k, v := range r.Schema {
+		switch k {
+		case "type", "properties", "required":
+			continue
+		}
+		if input.ExtraFields == nil {
+			input.ExtraFields = map[string]any{}
+		}
+		input.ExtraFields[k] = v
+	}
+	tool := sdk.ToolParam{Name: name, InputSchema: input}
+	if description == "" {
+		description, _ = r.Schema["description"].(string)
+	}
+	if description != "" {
+		tool.Description = sdk.String(description)
+	}
+	return tool, nil
+}
+
+// ToolUnion is Tool wrapped for MessageNewParams.Tools.
+func ToolUnion(name, description string, r *jsl.ConvertResult) (sdk.ToolUnionParam, error) {
+	tool, err := Tool(name, description, r)
+	if err != nil {
+		return sdk.ToolUnionParam{}, err
+	}
+	return sdk.ToolUnionParam{OfTool: &tool}, nil
+}
+
+// RehydrateToolUse restores the input of a tool_use block to the shape of
+// schema, the original schema r was converted from.
+func RehydrateToolUse(e Rehydrator, block sdk.ToolUseBlock, r *jsl.ConvertResult, schema any) (*jsl.RehydrateResult, error) {
+	if len(block.Input) == 0 {
+		return nil, fmt.Errorf("anthropic: tool_use %s has no input", block.Name)
+	}
+	return e.Rehydrate(json.RawMessage(block.Input), r.Codec, schema)
+}
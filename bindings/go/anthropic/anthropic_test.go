@@ -0,0 +1,103 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	sdk "github.com/anthropics/anthropic-sdk-go"
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+func convertResult() *jsl.ConvertResult {
+	return &jsl.ConvertResult{
+		Schema: map[string]any{
+			"type":                 "object",
+			"description":          "Look up a user.",
+			"properties":           map[string]any{"id": map[string]any{"type": "string"}},
+			"required":             []any{"id"},
+			"additionalProperties": false,
+		},
+		Codec: map[string]any{"transforms": []any{}},
+	}
+}
+
+// TestTool verifies the marshaled tool carries name, description and the
+// whole converted schema as input_schema.
+func TestTool(t *testing.T) {
+	tool, err := ToolUnion("get_user", "", convertResult())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(tool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(got, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["name"] != "get_user" || decoded["description"] != "Look up a user." {
+		t.Errorf("tool = %s", got)
+	}
+	want := map[string]any{}
+	for k, v := range convertResult().Schema {
+		want[k] = v
+	}
+	if !reflect.DeepEqual(decoded["input_schema"], jsonRoundtrip(t, want)) {
+		t.Errorf("input_schema = %v, want %v", decoded["input_schema"], want)
+	}
+}
+
+// TestToolErrors verifies missing names and non-object schemas are
+// rejected.
+func TestToolErrors(t *testing.T) {
+	if _, err := Tool("", "", convertResult()); err == nil {
+		t.Error("expected an error for an empty name")
+	}
+	if _, err := Tool("t", "", &jsl.ConvertResult{Schema: map[string]any{"type": "string"}}); err == nil {
+		t.Error("expected an error for a string schema")
+	}
+}
+
+type fakeRehydrator struct {
+	data, codec, schema any
+}
+
+func (f *fakeRehydrator) Rehydrate(data, codec, schema any) (*jsl.RehydrateResult, error) {
+	f.data, f.codec, f.schema = data, codec, schema
+	return &jsl.RehydrateResult{Data: data}, nil
+}
+
+// TestRehydrateToolUse verifies the raw block input reaches Rehydrate with
+// the result's codec.
+func TestRehydrateToolUse(t *testing.T) {
+	r := convertResult()
+	f := &fakeRehydrator{}
+	block := sdk.ToolUseBlock{ID: "toolu_1", Name: "get_user", Input: json.RawMessage(`{"id":"42"}`)}
+	if _, err := RehydrateToolUse(f, block, r, map[string]any{"type": "object"}); err != nil {
+		t.Fatal(err)
+	}
+	if raw, ok := f.data.(json.RawMessage); !ok || string(raw) != `{"id":"42"}` {
+		t.Errorf("data = %#v", f.data)
+	}
+	if !reflect.DeepEqual(f.codec, r.Codec) {
+		t.Errorf("codec = %v", f.codec)
+	}
+	if _, err := RehydrateToolUse(f, sdk.ToolUseBlock{Name: "get_user"}, r, nil); err == nil {
+		t.Error("expected an error for a block without input")
+	}
+}
+
+func jsonRoundtrip(t *testing.T, v any) any {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
@@ -0,0 +1,23 @@
+package anthropic
+
+import (
+	sdk "github.com/anthropics/anthropic-sdk-go"
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// DetectRefusal returns a *jsl.RefusalError when m stopped with the refusal
+// stop reason, nil otherwise. The error's Text joins m's text blocks. It is
+// the typed counterpart of jsl.DetectRefusal for messages already decoded
+// by the SDK.
+func DetectRefusal(m *sdk.Message) error {
+	if m == nil || m.StopReason != sdk.StopReasonRefusal {
+		return nil
+	}
+	var text string
+	for _, block := range m.Content {
+		if block.Type == "text" {
+			text += block.Text
+		}
+	}
+	return &jsl.RefusalError{Provider: jsl.ProviderAnthropic, Reason: string(m.StopReason), Text: text}
+}
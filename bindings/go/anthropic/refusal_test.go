@@ -0,0 +1,39 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	sdk "github.com/anthropics/anthropic-sdk-go"
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// TestDetectRefusal verifies SDK messages are classified like the raw
+// bodies jsl.DetectRefusal reads.
+func TestDetectRefusal(t *testing.T) {
+	refused := `{"type":"message","role":"assistant","stop_reason":"refusal","content":[{"type":"text","text":"I won't "},{"type":"text","text":"do that."}]}`
+	var m sdk.Message
+	if err := json.Unmarshal([]byte(refused), &m); err != nil {
+		t.Fatal(err)
+	}
+	err := DetectRefusal(&m)
+	var re *jsl.RefusalError
+	if !errors.As(err, &re) || !errors.Is(err, jsl.ErrModelRefusal) {
+		t.Fatalf("DetectRefusal() = %v, want a refusal", err)
+	}
+	if want := jsl.DetectRefusal(jsl.ProviderAnthropic, []byte(refused)); err.Error() != want.Error() {
+		t.Errorf("DetectRefusal() = %v, raw body gives %v", err, want)
+	}
+
+	answered := `{"type":"message","role":"assistant","stop_reason":"tool_use","content":[{"type":"tool_use","id":"t1","name":"get_user","input":{}}]}`
+	if err := json.Unmarshal([]byte(answered), &m); err != nil {
+		t.Fatal(err)
+	}
+	if err := DetectRefusal(&m); err != nil {
+		t.Errorf("DetectRefusal() = %v, want nil", err)
+	}
+	if err := DetectRefusal(nil); err != nil {
+		t.Errorf("DetectRefusal(nil) = %v", err)
+	}
+}
@@ -0,0 +1,288 @@
+// Package bench compares structured-output quality with and without
+// jsonschema-llm conversion. Each case is run against each model under each
+// strategy — the full convert → generate → rehydrate pipeline, passing the
+// raw schema straight to the provider, and describing the schema in the
+// prompt only — and every result is validated against the original schema.
+// The report gives adopters a like-for-like comparison and maintainers a
+// regression metric for pass quality.
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// Strategy is one way of asking a model for structured output.
+type Strategy string
+
+const (
+	// StrategyJSL converts the schema, sends the converted schema as the
+	// structured-output format and rehydrates the response.
+	StrategyJSL Strategy = "jsl"
+	// StrategyPassthrough sends the original schema as the structured-output
+	// format unchanged.
+	StrategyPassthrough Strategy = "passthrough"
+	// StrategyPromptOnly puts the original schema in the prompt and asks for
+	// JSON, without structured output.
+	StrategyPromptOnly Strategy = "prompt_only"
+)
+
+// Strategies is the default comparison set.
+var Strategies = []Strategy{StrategyJSL, StrategyPassthrough, StrategyPromptOnly}
+
+// ErrSchemaRejected should be wrapped by a Generator when the provider
+// rejects the response schema itself (typically an HTTP 400).
+var ErrSchemaRejected = errors.New("schema rejected by provider")
+
+// Request is one generation call.
+type Request struct {
+	Model  string
+	Prompt string
+	// Schema is the structured-output schema, or nil for prompt-only calls.
+	Schema map[string]any
+}
+
+// Generator calls a model and returns its raw response text. Wrap
+// ErrSchemaRejected or jsl.ErrModelRefusal so outcomes are classified.
+type Generator func(ctx context.Context, req Request) (string, error)
+
+// Validator checks data against a JSON Schema.
+type Validator func(schema, data any) error
+
+// Engine is the jsl surface the harness needs; *jsl.SchemaLlmEngine
+// satisfies it.
+type Engine interface {
+	Convert(schema any, opts *jsl.ConvertOptions) (*jsl.ConvertResult, error)
+	Rehydrate(data any, codec any, schema any) (*jsl.RehydrateResult, error)
+}
+
+// Case is one schema in the corpus.
+type Case struct {
+	Name   string
+	Schema map[string]any
+	// Prompt overrides Config.Prompt for this case.
+	Prompt string
+}
+
+// Config controls a run.
+type Config struct {
+	Models []string
+	// Strategies to compare (default Strategies).
+	Strategies []Strategy
+	// ConvertOptions are passed to every Convert call.
+	ConvertOptions *jsl.ConvertOptions
+	// Prompt is the task prompt (default DefaultPrompt).
+	Prompt string
+	// Logf, when set, receives a line per result.
+	Logf func(format string, args ...any)
+}
+
+// DefaultPrompt asks for realistic sample data, like the stress-test bots.
+const DefaultPrompt = "Generate realistic sample data matching the provided JSON schema. Be creative but realistic."
+
+// Outcome classifies one result.
+type Outcome string
+
+const (
+	OutcomeValid          Outcome = "valid"
+	OutcomeInvalid        Outcome = "invalid"
+	OutcomeParseError     Outcome = "parse_error"
+	OutcomeSchemaRejected Outcome = "schema_rejected"
+	OutcomeRefusal        Outcome = "refusal"
+	OutcomeConvertError   Outcome = "convert_error"
+	OutcomeRehydrateError Outcome = "rehydrate_error"
+	OutcomeError          Outcome = "error"
+)
+
+// Result is one (case, model, strategy) run.
+type Result struct {
+	Case     string        `json:"case"`
+	Model    string        `json:"model"`
+	Strategy Strategy      `json:"strategy"`
+	Outcome  Outcome       `json:"outcome"`
+	Latency  time.Duration `json:"latency"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Summary aggregates the results of one model and strategy.
+type Summary struct {
+	Model     string          `json:"model"`
+	Strategy  Strategy        `json:"strategy"`
+	Total     int             `json:"total"`
+	Outcomes  map[Outcome]int `json:"outcomes"`
+	ValidRate float64         `json:"validRate"`
+	P50       time.Duration   `json:"p50"`
+}
+
+// Report is the output of Run.
+type Report struct {
+	Results   []Result  `json:"results"`
+	Summaries []Summary `json:"summaries"`
+}
+
+// Run executes every case against every model and strategy. It stops early
+// only when ctx is done, returning the partial report.
+func Run(ctx context.Context, engine Engine, gen Generator, validate Validator, cases []Case, cfg Config) (*Report, error) {
+	if len(cases) == 0 {
+		return nil, errors.New("bench: no cases")
+	}
+	if len(cfg.Models) == 0 {
+		return nil, errors.New("bench: no models")
+	}
+	strategies := cfg.Strategies
+	if len(strategies) == 0 {
+		strategies = Strategies
+	}
+	rep := &Report{}
+	for _, c := range cases {
+		prompt := c.Prompt
+		if prompt == "" {
+			prompt = cfg.Prompt
+		}
+		if prompt == "" {
+			prompt = DefaultPrompt
+		}
+		for _, model := range cfg.Models {
+			for _, s := range strategies {
+				if err := ctx.Err(); err != nil {
+					rep.summarize()
+					return rep, err
+				}
+				r := runOne(ctx, engine, gen, validate, c, model, s, prompt, cfg.ConvertOptions)
+				if cfg.Logf != nil {
+					cfg.Logf("bench: %s %s %s: %s (%s) %s", c.Name, model, s, r.Outcome, r.Latency.Round(time.Millisecond), r.Error)
+				}
+				rep.Results = append(rep.Results, r)
+			}
+		}
+	}
+	rep.summarize()
+	return rep, nil
+}
+
+func runOne(ctx context.Context, engine Engine, gen Generator, validate Validator, c Case, model string, s Strategy, prompt string, opts *jsl.ConvertOptions) (r Result) {
+	r = Result{Case: c.Name, Model: model, Strategy: s}
+	fail := func(o Outcome, err error) Result {
+		r.Outcome, r.Error = o, err.Error()
+		return r
+	}
+	start := time.Now()
+	defer func() { r.Latency = time.Since(start) }()
+
+	req := Request{Model: model, Prompt: prompt}
+	var converted *jsl.ConvertResult
+	switch s {
+	case StrategyJSL:
+		var err error
+		if converted, err = engine.Convert(c.Schema, opts); err != nil {
+			return fail(OutcomeConvertError, err)
+		}
+		req.Schema = converted.Schema
+	case StrategyPassthrough:
+		req.Schema = c.Schema
+	case StrategyPromptOnly:
+		schemaBytes, err := json.Marshal(c.Schema)
+		if err != nil {
+			return fail(OutcomeError, err)
+		}
+		req.Prompt = fmt.Sprintf("%s\n\nRespond with only a JSON value matching this JSON Schema:\n%s", prompt, schemaBytes)
+	default:
+		return fail(OutcomeError, fmt.Errorf("unknown strategy %q", s))
+	}
+
+	text, err := gen(ctx, req)
+	switch {
+	case errors.Is(err, jsl.ErrModelRefusal):
+		return fail(OutcomeRefusal, err)
+	case errors.Is(err, ErrSchemaRejected):
+		return fail(OutcomeSchemaRejected, err)
+	case err != nil:
+		return fail(OutcomeError, err)
+	}
+	var data any
+	if err := json.Unmarshal([]byte(extractJSON(text)), &data); err != nil {
+		return fail(OutcomeParseError, err)
+	}
+	if converted != nil {
+		rehydrated, err := engine.Rehydrate(data, converted.Codec, c.Schema)
+		if err != nil {
+			return fail(OutcomeRehydrateError, err)
+		}
+		data = rehydrated.Data
+	}
+	if err := validate(c.Schema, data); err != nil {
+		return fail(OutcomeInvalid, err)
+	}
+	r.Outcome = OutcomeValid
+	return r
+}
+
+// extractJSON strips a Markdown code fence, which prompt-only responses
+// often include.
+func extractJSON(text string) string {
+	text = strings.TrimSpace(text)
+	if rest, ok := strings.CutPrefix(text, "```"); ok {
+		if nl := strings.IndexByte(rest, '\n'); nl >= 0 {
+			rest = rest[nl+1:]
+		}
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(rest), "```"))
+	}
+	return text
+}
+
+func (rep *Report) summarize() {
+	type key struct {
+		model    string
+		strategy Strategy
+	}
+	byKey := map[key]*Summary{}
+	latencies := map[key][]time.Duration{}
+	var order []key
+	for _, r := range rep.Results {
+		k := key{r.Model, r.Strategy}
+		s, ok := byKey[k]
+		if !ok {
+			s = &Summary{Model: r.Model, Strategy: r.Strategy, Outcomes: map[Outcome]int{}}
+			byKey[k] = s
+			order = append(order, k)
+		}
+		s.Total++
+		s.Outcomes[r.Outcome]++
+		latencies[k] = append(latencies[k], r.Latency)
+	}
+	rep.Summaries = rep.Summaries[:0]
+	for _, k := range order {
+		s := byKey[k]
+		s.ValidRate = float64(s.Outcomes[OutcomeValid]) / float64(s.Total)
+		l := latencies[k]
+		sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+		s.P50 = l[(len(l)-1)/2]
+		rep.Summaries = append(rep.Summaries, *s)
+	}
+}
+
+// WriteMarkdown renders the summaries as a Markdown table, one row per model
+// and strategy.
+func (rep *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| Model | Strategy | Valid | Invalid | Parse error | Schema rejected | Refusal | Other | p50 |\n")
+	b.WriteString("|---|---|---|---|---|---|---|---|---|\n")
+	for _, s := range rep.Summaries {
+		other := s.Total - s.Outcomes[OutcomeValid] - s.Outcomes[OutcomeInvalid] - s.Outcomes[OutcomeParseError] -
+			s.Outcomes[OutcomeSchemaRejected] - s.Outcomes[OutcomeRefusal]
+		fmt.Fprintf(&b, "| %s | %s | %d/%d (%.1f%%) | %d | %d | %d | %d | %d | %s |\n",
+			s.Model, s.Strategy, s.Outcomes[OutcomeValid], s.Total, 100*s.ValidRate,
+			s.Outcomes[OutcomeInvalid], s.Outcomes[OutcomeParseError], s.Outcomes[OutcomeSchemaRejected],
+			s.Outcomes[OutcomeRefusal], other, s.P50.Round(time.Millisecond))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
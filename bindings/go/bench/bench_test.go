@@ -0,0 +1,73 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// fakeEngine "converts" by renaming the schema and rehydrates by unwrapping
+// a {"value": ...} envelope.
+type fakeEngine struct{}
+
+func (fakeEngine) Convert(schema any, _ *jsl.ConvertOptions) (*jsl.ConvertResult, error) {
+	return &jsl.ConvertResult{Schema: map[string]any{"converted": true}, Codec: "codec"}, nil
+}
+
+func (fakeEngine) Rehydrate(data any, _ any, _ any) (*jsl.RehydrateResult, error) {
+	return &jsl.RehydrateResult{Data: data.(map[string]any)["value"]}, nil
+}
+
+// TestRunClassifiesOutcomes verifies each strategy's request shape and the
+// outcome classification in the summary.
+func TestRunClassifiesOutcomes(t *testing.T) {
+	gen := func(_ context.Context, req Request) (string, error) {
+		switch {
+		case req.Schema == nil:
+			if !strings.Contains(req.Prompt, `"type":"object"`) {
+				t.Errorf("prompt-only prompt lacks schema: %q", req.Prompt)
+			}
+			return "```json\n{\"name\": 1}\n```", nil
+		case req.Schema["converted"] == true:
+			return `{"value": {"name": "ok"}}`, nil
+		case req.Model == "strict":
+			return "", &jsl.RefusalError{Provider: jsl.ProviderOpenAI, Reason: "refusal"}
+		default:
+			return "", ErrSchemaRejected
+		}
+	}
+	validate := func(_, data any) error {
+		if _, ok := data.(map[string]any)["name"].(string); !ok {
+			return errors.New("name must be a string")
+		}
+		return nil
+	}
+	cases := []Case{{Name: "person", Schema: map[string]any{"type": "object"}}}
+	rep, err := Run(context.Background(), fakeEngine{}, gen, validate, cases, Config{Models: []string{"loose", "strict"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Outcome{
+		"loose/jsl": OutcomeValid, "loose/passthrough": OutcomeSchemaRejected, "loose/prompt_only": OutcomeInvalid,
+		"strict/jsl": OutcomeValid, "strict/passthrough": OutcomeRefusal, "strict/prompt_only": OutcomeInvalid,
+	}
+	for _, r := range rep.Results {
+		if got := want[r.Model+"/"+string(r.Strategy)]; r.Outcome != got {
+			t.Errorf("%s/%s = %s, want %s", r.Model, r.Strategy, r.Outcome, got)
+		}
+	}
+	if len(rep.Summaries) != 6 || rep.Summaries[0].ValidRate != 1 || rep.Summaries[1].ValidRate != 0 {
+		t.Errorf("summaries = %+v", rep.Summaries)
+	}
+
+	var md strings.Builder
+	if err := rep.WriteMarkdown(&md); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(md.String(), "| loose | jsl | 1/1 (100.0%) |") {
+		t.Errorf("markdown = %s", md.String())
+	}
+}
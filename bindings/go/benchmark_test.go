@@ -0,0 +1,112 @@
+package jsl_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	"github.com/dotslashderek/json-schema-llm/bindings/go/soak"
+)
+
+// Benchmarks for the engine's entry points on representative schemas from
+// tests/schemas. Record a baseline and compare with benchstat:
+//
+//	go test -run '^$' -bench . -benchmem -count 10 > old.txt
+//	# change the binding or rebuild the wasm core
+//	go test -run '^$' -bench . -benchmem -count 10 > new.txt
+//	benchstat old.txt new.txt
+//
+// `make bench-go` writes the same output from the repository root; pass
+// GO_BENCH_OUT to keep two runs side by side.
+
+// benchSchemasDir holds the schemas the benchmarks convert.
+const benchSchemasDir = "../../tests/schemas"
+
+var benchSchemas = map[string]string{
+	// A flat object of scalars.
+	"small": "simple.json",
+	// A realistic API payload: nested objects, arrays, enums and $refs.
+	"medium": "real-world/ecommerce_order.json",
+	// 130 KB of combinators nested 50 deep.
+	"huge": "stress/combo_depth_50_width_5.json",
+}
+
+func loadBenchSchema(b *testing.B, size string) []byte {
+	b.Helper()
+	raw, err := os.ReadFile(filepath.Join(benchSchemasDir, benchSchemas[size]))
+	if err != nil {
+		b.Fatalf("read %s schema: %v", size, err)
+	}
+	return raw
+}
+
+func newBenchEngine(b *testing.B) *jsl.SchemaLlmEngine {
+	b.Helper()
+	eng, err := jsl.NewSchemaLlmEngine()
+	if err != nil {
+		b.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	b.Cleanup(func() { eng.Close() })
+	return eng
+}
+
+func benchmarkConvert(b *testing.B, size string) {
+	raw := loadBenchSchema(b, size)
+	eng := newBenchEngine(b)
+	schema := json.RawMessage(raw)
+	b.SetBytes(int64(len(raw)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := eng.Convert(schema, nil); err != nil {
+			b.Fatalf("Convert() failed: %v", err)
+		}
+	}
+}
+
+// BenchmarkConvertSmall converts a flat object schema.
+func BenchmarkConvertSmall(b *testing.B) { benchmarkConvert(b, "small") }
+
+// BenchmarkConvertMedium converts a realistic nested API schema.
+func BenchmarkConvertMedium(b *testing.B) { benchmarkConvert(b, "medium") }
+
+// BenchmarkConvertHuge converts a deeply nested stress schema.
+func BenchmarkConvertHuge(b *testing.B) { benchmarkConvert(b, "huge") }
+
+// BenchmarkRehydrate rehydrates a minimal instance of the converted medium
+// schema, standing in for model output.
+func BenchmarkRehydrate(b *testing.B) {
+	raw := loadBenchSchema(b, "medium")
+	eng := newBenchEngine(b)
+	converted, err := eng.Convert(json.RawMessage(raw), nil)
+	if err != nil {
+		b.Fatalf("Convert() failed: %v", err)
+	}
+	data, err := json.Marshal(soak.MinimalInstance(converted.Schema))
+	if err != nil {
+		b.Fatalf("marshal instance: %v", err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := eng.Rehydrate(json.RawMessage(data), converted.Codec, json.RawMessage(raw)); err != nil {
+			b.Fatalf("Rehydrate() failed: %v", err)
+		}
+	}
+}
+
+// BenchmarkEngineNew measures engine startup: compiling the embedded wasm
+// module and instantiating WASI, then closing the runtime.
+func BenchmarkEngineNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		eng, err := jsl.NewSchemaLlmEngine()
+		if err != nil {
+			b.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+		}
+		eng.Close()
+	}
+}
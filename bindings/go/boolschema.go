@@ -0,0 +1,173 @@
+package jsl
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// transformBooleanSchema records boolean subschemas normalized under one
+// parent node. Params["opaque"] lists children that were `true` and became
+// `{}` (which the guest stringifies like any unconstrained schema);
+// Params["dropped"] lists children that were `false` and were removed.
+// Both hold parent-relative pointers such as "properties/a" or "anyOf/2".
+const transformBooleanSchema = "boolean_schema"
+
+// booleanMapKeywords hold named subschemas; booleanListKeywords hold
+// positional ones. Keyword-level booleans elsewhere (items: false,
+// additionalProperties: false, …) are meaningful to the guest and left alone.
+var (
+	booleanMapKeywords  = []string{"properties", "patternProperties", "$defs", "definitions", "dependentSchemas"}
+	booleanListKeywords = []string{"anyOf", "oneOf", "allOf", "prefixItems"}
+)
+
+// hasBooleanLiteral reports whether schemaBytes could contain a boolean
+// subschema, so schemas without one skip decoding.
+func hasBooleanLiteral(schemaBytes []byte) bool {
+	return bytes.Contains(schemaBytes, []byte("true")) || bytes.Contains(schemaBytes, []byte("false"))
+}
+
+// normalizeBooleanSchemas rewrites `true` subschemas to `{}` and removes
+// `false` properties and anyOf/oneOf branches, which can never hold a
+// valid value.
+func normalizeBooleanSchemas(schema any, _ *ConvertOptions) (any, []HostTransform, error) {
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		var opaque, dropped []any
+		for _, kw := range booleanMapKeywords {
+			children, ok := node[kw].(map[string]any)
+			if !ok {
+				continue
+			}
+			for _, k := range sortedKeys(children) {
+				b, ok := children[k].(bool)
+				if !ok {
+					continue
+				}
+				rel := escapePointerSegment(kw) + "/" + escapePointerSegment(k)
+				if b {
+					opaque = append(opaque, rel)
+				} else if kw == "properties" {
+					dropped = append(dropped, rel)
+				}
+			}
+		}
+		for _, kw := range booleanListKeywords {
+			children, ok := node[kw].([]any)
+			if !ok {
+				continue
+			}
+			falses := 0
+			for _, c := range children {
+				if b, ok := c.(bool); ok && !b {
+					falses++
+				}
+			}
+			for i, c := range children {
+				b, ok := c.(bool)
+				if !ok {
+					continue
+				}
+				rel := kw + "/" + strconv.Itoa(i)
+				switch {
+				case b:
+					opaque = append(opaque, rel)
+				case (kw == "anyOf" || kw == "oneOf") && falses < len(children):
+					dropped = append(dropped, rel)
+				}
+			}
+		}
+		if len(opaque) == 0 && len(dropped) == 0 {
+			return node
+		}
+		t := HostTransform{Type: transformBooleanSchema, Path: loc, Params: map[string]any{}}
+		if len(opaque) > 0 {
+			t.Params["opaque"] = opaque
+		}
+		if len(dropped) > 0 {
+			t.Params["dropped"] = dropped
+		}
+		entries = append(entries, t)
+		return rewriteBooleanSchema(node, &t)
+	})
+	return schema, entries, nil
+}
+
+func rewriteBooleanSchema(n any, t *HostTransform) any {
+	node, ok := n.(map[string]any)
+	if !ok {
+		return n
+	}
+	opaque, _ := t.Params["opaque"].([]any)
+	for _, rel := range opaque {
+		if s, ok := rel.(string); ok {
+			replaceAtPointer(node, "#/"+s, map[string]any{})
+		}
+	}
+
+	dropped, _ := t.Params["dropped"].([]any)
+	// Remove list entries from the highest index down so earlier indices
+	// stay valid.
+	type listDrop struct {
+		kw  string
+		idx int
+	}
+	var drops []listDrop
+	for _, rel := range dropped {
+		s, _ := rel.(string)
+		segs := splitPointer("#/" + s)
+		if len(segs) != 2 {
+			continue
+		}
+		if segs[0] == "properties" {
+			if props, ok := node["properties"].(map[string]any); ok {
+				delete(props, segs[1])
+			}
+			if required, ok := node["required"].([]any); ok {
+				kept := required[:0:0]
+				for _, r := range required {
+					if r != segs[1] {
+						kept = append(kept, r)
+					}
+				}
+				node["required"] = kept
+			}
+			continue
+		}
+		if idx, err := strconv.Atoi(segs[1]); err == nil {
+			drops = append(drops, listDrop{segs[0], idx})
+		}
+	}
+	sort.Slice(drops, func(i, j int) bool { return drops[i].idx > drops[j].idx })
+	for _, d := range drops {
+		if list, ok := node[d.kw].([]any); ok && d.idx < len(list) {
+			node[d.kw] = append(list[:d.idx:d.idx], list[d.idx+1:]...)
+		}
+	}
+	return node
+}
+
+func booleanSchemaConvertWarning(t *HostTransform) (Warning, bool) {
+	var parts []string
+	if dropped, ok := t.Params["dropped"].([]any); ok {
+		parts = append(parts, fmt.Sprintf("false subschema(s) dropped: %s", joinAny(dropped)))
+	}
+	if opaque, ok := t.Params["opaque"].([]any); ok {
+		parts = append(parts, fmt.Sprintf("true subschema(s) accept any value: %s", joinAny(opaque)))
+	}
+	return Warning{
+		SchemaPath: t.Path,
+		Kind:       WarningKind{Type: WarnBooleanSchema},
+		Message:    strings.Join(parts, "; "),
+	}, true
+}
+
+func joinAny(values []any) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = fmt.Sprint(v)
+	}
+	return strings.Join(s, ", ")
+}
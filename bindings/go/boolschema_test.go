@@ -0,0 +1,98 @@
+package jsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestNormalizeBooleanSchemas verifies true subschemas become {} and false
+// properties and branches are dropped.
+func TestNormalizeBooleanSchemas(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"any": true,
+			"never": false,
+			"name": {"type": "string"},
+			"choice": {"anyOf": [false, {"type": "integer"}, false, true]}
+		},
+		"required": ["any", "never", "name"]
+	}`)
+	out, entries, err := normalizeBooleanSchemas(schema, &ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(out)
+	want := `{"properties":{"any":{},"choice":{"anyOf":[{"type":"integer"},{}]},"name":{"type":"string"}},"required":["any","name"],"type":"object"}`
+	if string(got) != want {
+		t.Errorf("normalized = %s", got)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	warnings := hostConvertWarnings(entries)
+	if warnings[1].SchemaPath != "#" || warnings[1].Message != "false subschema(s) dropped: properties/never; true subschema(s) accept any value: properties/any" {
+		t.Errorf("warning = %+v", warnings[1])
+	}
+
+	// Replaying the entries on the original reproduces the rewrite.
+	replayed := decodeJSON(t, `{"type":"object","properties":{"any":true,"never":false,"name":{"type":"string"},"choice":{"anyOf":[false,{"type":"integer"},false,true]}},"required":["any","never","name"]}`)
+	for _, e := range roundtripEntries(t, entries) {
+		node, _ := lookupPointer(replayed, e.Path)
+		replayed = replaceAtPointer(replayed, e.Path, rewriteBooleanSchema(node, &e))
+	}
+	if again, _ := json.Marshal(replayed); string(again) != want {
+		t.Errorf("replayed = %s", again)
+	}
+}
+
+// TestNormalizeBooleanSchemasKeepsAllFalseUnion leaves a union with no
+// satisfiable branch for the guest to report.
+func TestNormalizeBooleanSchemasKeepsAllFalseUnion(t *testing.T) {
+	schema := decodeJSON(t, `{"anyOf": [false, false], "items": false, "additionalProperties": false}`)
+	out, entries, err := normalizeBooleanSchemas(schema, &ConvertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("entries = %+v", entries)
+	}
+	if got, _ := json.Marshal(out); string(got) != `{"additionalProperties":false,"anyOf":[false,false],"items":false}` {
+		t.Errorf("out = %s", got)
+	}
+}
+
+// TestConvertBooleanSubschemas verifies Convert sends a true property as an
+// opaque value and drops a false one with a convert warning. These cases
+// are Go-only: bindings without the host pass leave boolean subschemas to
+// the core.
+func TestConvertBooleanSubschemas(t *testing.T) {
+	eng, err := NewSchemaLlmEngine()
+	if err != nil {
+		t.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	result, err := eng.Convert(decodeJSON(t, `{
+		"type": "object",
+		"properties": {"name": {"type": "string"}, "payload": true, "forbidden": false},
+		"required": ["name", "payload"]
+	}`), nil)
+	if err != nil {
+		t.Fatalf("Convert() failed: %v", err)
+	}
+	props, _ := result.Schema["properties"].(map[string]any)
+	if _, ok := props["forbidden"]; ok {
+		t.Error("false property was not dropped")
+	}
+	if _, ok := props["payload"].(map[string]any); !ok {
+		t.Errorf("payload = %v, want an opaque schema object", props["payload"])
+	}
+	found := false
+	for _, w := range result.Warnings {
+		found = found || w.Kind.Type == WarnBooleanSchema
+	}
+	if !found {
+		t.Errorf("warnings = %+v, want a %s warning", result.Warnings, WarnBooleanSchema)
+	}
+}
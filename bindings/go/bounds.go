@@ -0,0 +1,108 @@
+package jsl
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// transformNumericBounds records numeric constraints that were written into
+// the description so Rehydrate can verify the returned number.
+const transformNumericBounds = "numeric_bounds"
+
+// numericBoundKeywords are checked in this order, which is also the order
+// they appear in the generated description.
+var numericBoundKeywords = []string{"minimum", "exclusiveMinimum", "maximum", "exclusiveMaximum", "multipleOf"}
+
+// describeNumericBounds appends numeric constraints to each node's
+// description, steering the model even when the target strips the keywords.
+func describeNumericBounds(schema any, _ *ConvertOptions) (any, []HostTransform, error) {
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		bounds := map[string]any{}
+		var parts []string
+		for _, kw := range numericBoundKeywords {
+			f, ok := node[kw].(float64)
+			if !ok {
+				continue
+			}
+			bounds[kw] = f
+			parts = append(parts, describeBound(kw, f))
+		}
+		if len(parts) == 0 {
+			return node
+		}
+		hint := "Must be " + strings.Join(parts, ", ") + "."
+		if desc, ok := node["description"].(string); ok && desc != "" {
+			node["description"] = strings.TrimRight(desc, " ") + " " + hint
+		} else {
+			node["description"] = hint
+		}
+		entries = append(entries, HostTransform{Type: transformNumericBounds, Path: loc, Params: bounds})
+		return node
+	})
+	return schema, entries, nil
+}
+
+func describeBound(kw string, f float64) string {
+	n := formatNumber(f)
+	switch kw {
+	case "minimum":
+		return ">= " + n
+	case "exclusiveMinimum":
+		return "> " + n
+	case "maximum":
+		return "<= " + n
+	case "exclusiveMaximum":
+		return "< " + n
+	default:
+		return "a multiple of " + n
+	}
+}
+
+// restoreNumericBounds warns when a rehydrated number violates a recorded bound.
+func restoreNumericBounds(v any, t *HostTransform, dataPath string) (any, []Warning) {
+	f, ok := v.(float64)
+	if !ok {
+		return v, nil
+	}
+	var warnings []Warning
+	for _, kw := range numericBoundKeywords {
+		bound, ok := t.Params[kw].(float64)
+		if !ok || boundHolds(kw, f, bound) {
+			continue
+		}
+		warnings = append(warnings, Warning{
+			DataPath:   dataPath,
+			SchemaPath: t.Path,
+			Kind:       WarningKind{Type: WarnConstraintViolation, Constraint: kw},
+			Message:    fmt.Sprintf("value %s violates %s %s", formatNumber(f), kw, formatNumber(bound)),
+		})
+	}
+	return v, warnings
+}
+
+func boundHolds(kw string, f, bound float64) bool {
+	switch kw {
+	case "minimum":
+		return f >= bound
+	case "exclusiveMinimum":
+		return f > bound
+	case "maximum":
+		return f <= bound
+	case "exclusiveMaximum":
+		return f < bound
+	case "multipleOf":
+		if bound <= 0 {
+			return true
+		}
+		q := f / bound
+		return math.Abs(q-math.Round(q)) < 1e-9
+	}
+	return true
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
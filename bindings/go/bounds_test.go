@@ -0,0 +1,74 @@
+package jsl
+
+import "testing"
+
+// TestDescribeNumericBounds verifies bounds are appended to descriptions and recorded.
+func TestDescribeNumericBounds(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"age": {"type": "integer", "description": "Age in years.", "minimum": 0, "maximum": 150},
+			"step": {"type": "number", "exclusiveMinimum": 0, "multipleOf": 0.5}
+		}
+	}`)
+
+	out, entries, err := describeNumericBounds(schema, &ConvertOptions{DescribeNumericBounds: true})
+	if err != nil {
+		t.Fatalf("describeNumericBounds() failed: %v", err)
+	}
+	props := out.(map[string]any)["properties"].(map[string]any)
+	if got := props["age"].(map[string]any)["description"]; got != "Age in years. Must be >= 0, <= 150." {
+		t.Errorf("age description: got %q", got)
+	}
+	if got := props["step"].(map[string]any)["description"]; got != "Must be > 0, a multiple of 0.5." {
+		t.Errorf("step description: got %q", got)
+	}
+	if len(entries) != 2 || entries[0].Path != "#/properties/age" || entries[1].Path != "#/properties/step" {
+		t.Errorf("entries: got %+v", entries)
+	}
+}
+
+// TestRestoreNumericBounds verifies violations of recorded bounds produce warnings.
+func TestRestoreNumericBounds(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {"scores": {"type": "array", "items": {"type": "number", "minimum": 0, "maximum": 10, "multipleOf": 0.5}}}
+	}`)
+	_, entries, _ := describeNumericBounds(deepCopyJSON(schema), &ConvertOptions{DescribeNumericBounds: true})
+
+	data := decodeJSON(t, `{"scores": [5, 11, -1.25, 2.5]}`)
+	_, warnings := restoreHost(data, hostStages(schema, entries), entries)
+
+	got := map[string][]string{}
+	for _, w := range warnings {
+		got[w.DataPath] = append(got[w.DataPath], w.Kind.Constraint)
+	}
+	if len(got["/scores/1"]) != 1 || got["/scores/1"][0] != "maximum" {
+		t.Errorf("/scores/1: got %v, want [maximum]", got["/scores/1"])
+	}
+	if len(got["/scores/2"]) != 2 {
+		t.Errorf("/scores/2: got %v, want [minimum multipleOf]", got["/scores/2"])
+	}
+	if _, ok := got["/scores/0"]; ok {
+		t.Error("in-range value should not warn")
+	}
+}
+
+// TestMergeWarningsDedupes verifies host warnings already reported by the guest are dropped.
+func TestMergeWarningsDedupes(t *testing.T) {
+	kind := WarningKind{Type: "constraint_violation", Constraint: "maximum"}
+	guest := []Warning{{DataPath: "/a", Kind: kind, Message: "guest"}}
+	host := []Warning{{DataPath: "/a", Kind: kind, Message: "host"}, {DataPath: "/b", Kind: kind}}
+	merged := mergeWarnings(guest, host)
+	if len(merged) != 2 || merged[0].Message != "guest" || merged[1].DataPath != "/b" {
+		t.Errorf("merged: got %+v", merged)
+	}
+
+	// The guest's "/" root path matches the host's "".
+	guest = []Warning{{DataPath: "/", Kind: kind, Message: "guest"}}
+	normalizeRootDataPaths(guest)
+	merged = mergeWarnings(guest, []Warning{{DataPath: "", Kind: kind, Message: "host"}})
+	if len(merged) != 1 || merged[0].DataPath != "" {
+		t.Errorf("merged root: got %+v", merged)
+	}
+}
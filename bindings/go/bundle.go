@@ -0,0 +1,215 @@
+package jsl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// BundleOptions configures Bundle.
+type BundleOptions struct {
+	// RefResolver fetches external $ref documents. Without one, external
+	// refs fail with code "unresolvable_ref".
+	RefResolver RefResolver
+	// KeepUnreferenced keeps $defs entries no $ref reaches from the root.
+	KeepUnreferenced bool
+}
+
+// errNoRefResolver is the resolver error Bundle reports for external refs
+// when BundleOptions.RefResolver is unset.
+var errNoRefResolver = errors.New("no RefResolver set")
+
+// Bundle returns a self-contained copy of schema: external $refs are
+// fetched (BundleOptions.RefResolver) and every reachable $ref target is
+// pulled into $defs, so each $ref is "#" or "#/$defs/<name>". Legacy
+// "definitions" move to $defs, refs into other locations get a copy of
+// their target, and unreferenced definitions are dropped. It runs entirely
+// on the host and does not convert.
+func (e *SchemaLlmEngine) Bundle(schema any, opts *BundleOptions) (map[string]any, error) {
+	if opts == nil {
+		opts = &BundleOptions{}
+	}
+	decoded, err := decodeForDiff(schema)
+	if err != nil {
+		return nil, fmt.Errorf("bundle: decode schema: %w", err)
+	}
+	root, ok := decoded.(map[string]any)
+	if !ok {
+		return nil, &Error{Code: "schema_error", Message: "bundle: root schema must be an object"}
+	}
+
+	resolver := opts.RefResolver
+	if resolver == nil {
+		resolver = RefResolverFunc(func(string) (any, error) { return nil, errNoRefResolver })
+	}
+	if _, err := bundleExternalRefs(root, resolver); err != nil {
+		return nil, err
+	}
+	if err := bundleLocalRefs(root); err != nil {
+		return nil, err
+	}
+	if !opts.KeepUnreferenced {
+		pruneDefs(root)
+	}
+	return root, nil
+}
+
+// bundleLocalRefs rewrites every local $ref to "#" or "#/$defs/<name>",
+// moving "definitions" into $defs and copying other targets there.
+func bundleLocalRefs(root map[string]any) error {
+	defs, _ := root["$defs"].(map[string]any)
+	if defs == nil {
+		defs = map[string]any{}
+	}
+	// Legacy definitions keep their names unless $defs already has one.
+	moved := map[string]string{}
+	if legacy, ok := root["definitions"].(map[string]any); ok {
+		for _, k := range sortedKeys(legacy) {
+			name := freeDefName(defs, k)
+			defs[name] = legacy[k]
+			moved["#/definitions/"+escapePointerSegment(k)] = "#/$defs/" + escapePointerSegment(name)
+		}
+		delete(root, "definitions")
+	}
+	copies := map[string]string{}
+	// Targets are looked up in the original layout; definitions have moved.
+	lookup := func(ref string) (any, bool) {
+		for from, to := range moved {
+			if ref == from || strings.HasPrefix(ref, from+"/") {
+				ref = to + strings.TrimPrefix(ref, from)
+			}
+		}
+		if v, ok := lookupPointer(root, ref); ok {
+			return v, true
+		}
+		return lookupPointer(map[string]any{"$defs": defs}, ref)
+	}
+
+	var walk func(node any, loc string) error
+	walk = func(node any, loc string) error {
+		switch t := node.(type) {
+		case map[string]any:
+			if ref, ok := t["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+				local, err := localDefRef(ref, defs, moved, copies, lookup)
+				if err != nil {
+					return &Error{Code: "unresolvable_ref", Path: childPointer(loc, "$ref"), Message: err.Error()}
+				}
+				t["$ref"] = local
+			}
+			for _, k := range sortedKeys(t) {
+				if k != "$ref" {
+					if err := walk(t[k], childPointer(loc, k)); err != nil {
+						return err
+					}
+				}
+			}
+		case []any:
+			for i, c := range t {
+				if err := walk(c, childPointer(loc, itoa(i))); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	delete(root, "$defs")
+	if err := walk(root, "#"); err != nil {
+		return err
+	}
+	// Copies added while walking are walked too; repeat until none appear.
+	for done := map[string]bool{}; ; {
+		pending := false
+		for _, k := range sortedKeys(defs) {
+			if done[k] {
+				continue
+			}
+			done[k], pending = true, true
+			if err := walk(defs[k], "#/$defs/"+escapePointerSegment(k)); err != nil {
+				return err
+			}
+		}
+		if !pending {
+			break
+		}
+	}
+	if len(defs) > 0 {
+		root["$defs"] = defs
+	}
+	return nil
+}
+
+// localDefRef maps a local ref to its bundled form.
+func localDefRef(ref string, defs map[string]any, moved, copies map[string]string, lookup func(string) (any, bool)) (string, error) {
+	if ref == "#" {
+		return ref, nil
+	}
+	if to, ok := moved[ref]; ok {
+		return to, nil
+	}
+	if segs := splitPointer(ref); len(segs) == 2 && segs[0] == "$defs" {
+		if _, ok := defs[segs[1]]; ok {
+			return ref, nil
+		}
+	}
+	if to, ok := copies[ref]; ok {
+		return to, nil
+	}
+	target, ok := lookup(ref)
+	if !ok {
+		return "", fmt.Errorf("$ref %q not found", ref)
+	}
+	segs := splitPointer(ref)
+	name := freeDefName(defs, segs[len(segs)-1])
+	defs[name] = deepCopyJSON(target)
+	to := "#/$defs/" + escapePointerSegment(name)
+	copies[ref] = to
+	return to, nil
+}
+
+func freeDefName(defs map[string]any, name string) string {
+	candidate := name
+	for i := 2; defs[candidate] != nil; i++ {
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+	return candidate
+}
+
+// pruneDefs drops $defs entries no $ref reaches from outside $defs.
+func pruneDefs(root map[string]any) {
+	defs, ok := root["$defs"].(map[string]any)
+	if !ok {
+		return
+	}
+	reached := map[string]bool{}
+	var mark func(node any)
+	mark = func(node any) {
+		switch t := node.(type) {
+		case map[string]any:
+			if ref, ok := t["$ref"].(string); ok {
+				if segs := splitPointer(ref); len(segs) == 2 && segs[0] == "$defs" && !reached[segs[1]] {
+					reached[segs[1]] = true
+					mark(defs[segs[1]])
+				}
+			}
+			for k, c := range t {
+				if k != "$ref" {
+					mark(c)
+				}
+			}
+		case []any:
+			for _, c := range t {
+				mark(c)
+			}
+		}
+	}
+	delete(root, "$defs")
+	mark(root)
+	for k := range defs {
+		if !reached[k] {
+			delete(defs, k)
+		}
+	}
+	if len(defs) > 0 {
+		root["$defs"] = defs
+	}
+}
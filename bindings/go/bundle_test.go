@@ -0,0 +1,50 @@
+package jsl
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// TestBundle verifies definitions move to $defs, non-$defs targets are
+// copied, external refs are fetched and unreferenced definitions dropped.
+func TestBundle(t *testing.T) {
+	schema := `{
+		"$id": "https://schemas.internal/order.json",
+		"type": "object",
+		"properties": {
+			"customer": {"$ref": "#/definitions/Customer"},
+			"billing": {"$ref": "#/definitions/Customer/properties/name"},
+			"total": {"$ref": "money.json"}
+		},
+		"definitions": {
+			"Customer": {"type": "object", "properties": {"name": {"type": "string"}, "parent": {"$ref": "#/definitions/Customer"}}},
+			"Unused": {"type": "string"}
+		}
+	}`
+	calls := map[string]int{}
+	out, err := (*SchemaLlmEngine)(nil).Bundle(json.RawMessage(schema), &BundleOptions{RefResolver: testRefResolver(t, calls)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(out)
+	want := `{"$defs":{` +
+		`"Country":{"enum":["US","DE"],"type":"string"},` +
+		`"Customer":{"properties":{"name":{"type":"string"},"parent":{"$ref":"#/$defs/Customer"}},"type":"object"},` +
+		`"money":{"properties":{"amount":{"type":"number"},"origin":{"$ref":"#/$defs/Country"}},"type":"object"},` +
+		`"name":{"type":"string"}},` +
+		`"$id":"https://schemas.internal/order.json",` +
+		`"properties":{"billing":{"$ref":"#/$defs/name"},"customer":{"$ref":"#/$defs/Customer"},"total":{"$ref":"#/$defs/money"}},"type":"object"}`
+	if string(got) != want {
+		t.Errorf("bundled = %s", got)
+	}
+}
+
+// TestBundleWithoutResolver verifies external refs fail without a resolver.
+func TestBundleWithoutResolver(t *testing.T) {
+	_, err := (*SchemaLlmEngine)(nil).Bundle(map[string]any{"$ref": "https://example.com/a.json"}, nil)
+	var jslErr *Error
+	if !errors.As(err, &jslErr) || jslErr.Code != "unresolvable_ref" {
+		t.Errorf("err = %v", err)
+	}
+}
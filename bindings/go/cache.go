@@ -0,0 +1,113 @@
+package jsl
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// convertCache is a least-recently-used cache of Convert results.
+type convertCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // of *cacheEntry, most recent first
+	entries map[string]*list.Element
+	hits    int
+	misses  int
+}
+
+type cacheEntry struct {
+	key    string
+	result *ConvertResult
+}
+
+// CacheStats reports a convert cache's effectiveness (WithConvertCache).
+type CacheStats struct {
+	Hits    int
+	Misses  int
+	Entries int
+}
+
+// WithConvertCache keeps the results of the size most recently used
+// distinct conversions, keyed by the schema's canonical hash (see
+// CanonicalJSON) and OptionsFingerprint, so repeated Convert calls skip the
+// guest entirely. Conversions with a RefResolver are never cached, since the
+// documents it serves may change. A size of zero or less disables the cache.
+func WithConvertCache(size int) Option {
+	return func(c *engineConfig) {
+		if size <= 0 {
+			c.cache = nil
+			return
+		}
+		c.cache = &convertCache{size: size, order: list.New(), entries: map[string]*list.Element{}}
+	}
+}
+
+// ConvertCacheStats returns the engine's cache counters; all zero without
+// WithConvertCache.
+func (e *SchemaLlmEngine) ConvertCacheStats() CacheStats {
+	c := e.cache
+	if c == nil {
+		return CacheStats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Entries: c.order.Len()}
+}
+
+// convertCacheKey returns the cache key for a conversion, or "" when it must
+// not be cached.
+func convertCacheKey(schema any, opts *ConvertOptions) string {
+	if opts != nil && opts.RefResolver != nil {
+		return ""
+	}
+	canonical, err := CanonicalJSON(schema)
+	if err != nil {
+		return "" // Convert reports the error
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]) + ":" + OptionsFingerprint(opts)
+}
+
+// get returns a copy of the cached result for key.
+func (c *convertCache) get(key string) (*ConvertResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.order.MoveToFront(el)
+	return cloneConvertResult(el.Value.(*cacheEntry).result), true
+}
+
+// put stores a copy of result, evicting the least recently used entry when
+// the cache is full.
+func (c *convertCache) put(key string, result *ConvertResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*cacheEntry).result = cloneConvertResult(result)
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, result: cloneConvertResult(result)})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// cloneConvertResult copies the decoded documents of r, so callers editing a
+// result cannot corrupt the cache. Other fields are shared and read-only.
+func cloneConvertResult(r *ConvertResult) *ConvertResult {
+	out := *r
+	out.Schema, _ = deepCopyJSON(r.Schema).(map[string]any)
+	out.Codec = deepCopyJSON(r.Codec)
+	out.BundledSchema, _ = deepCopyJSON(r.BundledSchema).(map[string]any)
+	return &out
+}
@@ -0,0 +1,66 @@
+package jsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func newTestCache(size int) *convertCache {
+	cfg := &engineConfig{}
+	WithConvertCache(size)(cfg)
+	return cfg.cache
+}
+
+// TestConvertCacheEviction verifies the least recently used entry goes first.
+func TestConvertCacheEviction(t *testing.T) {
+	c := newTestCache(2)
+	c.put("a", &ConvertResult{APIVersion: "a"})
+	c.put("b", &ConvertResult{APIVersion: "b"})
+	if _, ok := c.get("a"); !ok {
+		t.Fatal("a missing")
+	}
+	c.put("c", &ConvertResult{APIVersion: "c"})
+	if _, ok := c.get("b"); ok {
+		t.Error("b survived eviction")
+	}
+	if r, ok := c.get("a"); !ok || r.APIVersion != "a" {
+		t.Error("a was evicted")
+	}
+	if c.hits != 2 || c.misses != 1 || c.order.Len() != 2 {
+		t.Errorf("hits %d, misses %d, entries %d", c.hits, c.misses, c.order.Len())
+	}
+}
+
+// TestConvertCacheCopies verifies callers cannot edit cached results.
+func TestConvertCacheCopies(t *testing.T) {
+	c := newTestCache(1)
+	result := &ConvertResult{Schema: map[string]any{"type": "object"}}
+	c.put("k", result)
+	result.Schema["type"] = "string"
+	got, _ := c.get("k")
+	got.Schema["extra"] = true
+	again, _ := c.get("k")
+	if again.Schema["type"] != "object" || again.Schema["extra"] != nil {
+		t.Errorf("cached schema changed: %v", again.Schema)
+	}
+}
+
+// TestConvertCacheKey verifies key order does not matter, options do, and
+// RefResolver conversions are not cached.
+func TestConvertCacheKey(t *testing.T) {
+	a := convertCacheKey(json.RawMessage(`{"type":"object","properties":{"x":{}}}`), nil)
+	b := convertCacheKey(json.RawMessage(`{"properties": {"x": {}}, "type": "object"}`), nil)
+	if a == "" || a != b {
+		t.Errorf("equal schemas keyed %q and %q", a, b)
+	}
+	if c := convertCacheKey(json.RawMessage(`{"type":"object","properties":{"x":{}}}`), &ConvertOptions{Target: "gemini"}); c == a {
+		t.Error("options did not change the key")
+	}
+	resolver := RefResolverFunc(func(string) (any, error) { return nil, nil })
+	if k := convertCacheKey(map[string]any{}, &ConvertOptions{RefResolver: resolver}); k != "" {
+		t.Errorf("RefResolver conversion keyed %q", k)
+	}
+	if newTestCache(0) != nil {
+		t.Error("size 0 enabled the cache")
+	}
+}
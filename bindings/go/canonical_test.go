@@ -0,0 +1,51 @@
+package jsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestCanonicalJSON checks the RFC 8785 sample input and key ordering.
+func TestCanonicalJSON(t *testing.T) {
+	in := json.RawMessage(`{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001, -0],
+		"string": "€$\u000F\u000aA'B\"\\\\\"\/",
+		"literals": [null, true, false]
+	}`)
+	got, err := CanonicalJSON(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27,0],"string":"€$\u000f\nA'B\"\\\\\"/"}`
+	if string(got) != want {
+		t.Errorf("CanonicalJSON() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestCanonicalKeyOrder verifies keys sort by UTF-16 code units, placing a
+// supplementary-plane character before U+FB33.
+func TestCanonicalKeyOrder(t *testing.T) {
+	got, err := CanonicalJSON(map[string]any{"דּ": 1, "\U0001f600": 2, "b": 3, "<": 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\"<\":4,\"b\":3,\"\U0001f600\":2,\"דּ\":1}"; string(got) != want {
+		t.Errorf("CanonicalJSON() = %s, want %s", got, want)
+	}
+}
+
+// TestCanonicalNumber covers the ECMAScript exponent thresholds.
+func TestCanonicalNumber(t *testing.T) {
+	for f, want := range map[float64]string{
+		1e21:    "1e+21",
+		1e20:    "100000000000000000000",
+		1e-6:    "0.000001",
+		1e-7:    "1e-7",
+		-1.5e-9: "-1.5e-9",
+		12:      "12",
+	} {
+		if got, err := canonicalNumber(f); err != nil || got != want {
+			t.Errorf("canonicalNumber(%v) = %q, %v; want %q", f, got, err, want)
+		}
+	}
+}
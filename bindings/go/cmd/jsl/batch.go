@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// batchReport summarizes a directory conversion.
+type batchReport struct {
+	Converted []batchEntry `json:"converted"`
+	Failed    []batchEntry `json:"failed,omitempty"`
+}
+
+type batchEntry struct {
+	Path     string   `json:"path"`
+	Warnings []string `json:"warnings,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// convertDir converts every .json file under dir, mirroring relative paths
+// under outDir and codecDir. A failed schema is reported and skipped; the
+// exit status is 1 if any failed.
+func convertDir(dir, outDir, codecDir, reportPath string, opts *jsl.ConvertOptions, stdout, stderr io.Writer) int {
+	if err := checkBatchDirs(dir, outDir, codecDir); err != nil {
+		fmt.Fprintf(stderr, "jsl convert: %v\n", err)
+		return 2
+	}
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".json") {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	sort.Strings(files)
+
+	engine, err := jsl.NewSchemaLlmEngine()
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	defer engine.Close()
+
+	report := batchReport{Converted: []batchEntry{}}
+	for _, p := range files {
+		rel, _ := filepath.Rel(dir, p)
+		entry := batchEntry{Path: filepath.ToSlash(rel)}
+		warnings, err := convertFile(engine, p, filepath.Join(outDir, rel), filepath.Join(codecDir, rel), opts)
+		if err != nil {
+			entry.Error = err.Error()
+			report.Failed = append(report.Failed, entry)
+			fmt.Fprintf(stderr, "error: %s: %v\n", entry.Path, err)
+			continue
+		}
+		entry.Warnings = warnings
+		report.Converted = append(report.Converted, entry)
+	}
+
+	warned := 0
+	for _, e := range report.Converted {
+		if len(e.Warnings) > 0 {
+			warned++
+		}
+	}
+	fmt.Fprintf(stdout, "converted %d of %d schemas (%d with warnings, %d failed) → %s, %s\n",
+		len(report.Converted), len(files), warned, len(report.Failed), outDir, codecDir)
+	if reportPath != "" {
+		if err := writeJSONFile(reportPath, report); err != nil {
+			fmt.Fprintf(stderr, "jsl: %v\n", err)
+			return 1
+		}
+	}
+	if len(report.Failed) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// checkBatchDirs rejects output directories that would clobber each other
+// or the input: the same directory for schemas and codecs would overwrite
+// each schema with its codec, and one inside dir would have a rerun pick
+// up its own output as input.
+func checkBatchDirs(dir, outDir, codecDir string) error {
+	in, err := resolveDir(dir)
+	if err != nil {
+		return err
+	}
+	out, err := resolveDir(outDir)
+	if err != nil {
+		return err
+	}
+	codec, err := resolveDir(codecDir)
+	if err != nil {
+		return err
+	}
+	if out == codec {
+		return fmt.Errorf("--out and --codec-out must be different directories")
+	}
+	for _, d := range []struct{ flag, arg, path string }{{"--out", outDir, out}, {"--codec-out", codecDir, codec}} {
+		if within(in, d.path) {
+			return fmt.Errorf("%s %s is inside the input directory %s", d.flag, d.arg, dir)
+		}
+	}
+	return nil
+}
+
+// resolveDir returns the absolute form of p with symlinks resolved, as far
+// as p exists.
+func resolveDir(p string) (string, error) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+	var rest []string
+	for {
+		if real, err := filepath.EvalSymlinks(abs); err == nil {
+			return filepath.Join(append([]string{real}, rest...)...), nil
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return filepath.Join(append([]string{abs}, rest...)...), nil
+		}
+		rest = append([]string{filepath.Base(abs)}, rest...)
+		abs = parent
+	}
+}
+
+// within reports whether p is dir or below it.
+func within(dir, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// convertFile converts one schema file and writes its schema and codec.
+func convertFile(engine *jsl.SchemaLlmEngine, src, out, codecOut string, opts *jsl.ConvertOptions) ([]string, error) {
+	raw, err := os.ReadFile(src)
+	if err != nil {
+		return nil, err
+	}
+	var schema any
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, err
+	}
+	result, err := engine.Convert(schema, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range []string{out, codecOut} {
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeJSONFile(out, result.Schema); err != nil {
+		return nil, err
+	}
+	if err := writeJSONFile(codecOut, result.Codec); err != nil {
+		return nil, err
+	}
+	var warnings []string
+	for _, w := range result.Warnings {
+		warnings = append(warnings, w.SchemaPath+": "+w.Message)
+	}
+	return warnings, nil
+}
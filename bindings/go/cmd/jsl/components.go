@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+func runComponents(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: jsl components list|extract ...")
+		return 2
+	}
+	switch args[0] {
+	case "list":
+		return runComponentsList(args[1:], stdout, stderr)
+	case "extract":
+		return runComponentsExtract(args[1:], stdout, stderr)
+	}
+	fmt.Fprintf(stderr, "jsl components: unknown subcommand %q\n", args[0])
+	return 2
+}
+
+func runComponentsList(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("jsl components list", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts := &jsl.ListComponentsOptions{}
+	fs.StringVar(&opts.Pattern, "pattern", "", `keep pointers matching this glob (e.g. "#/components/schemas/Pet*")`)
+	fs.IntVar(&opts.MinProperties, "min-properties", 0, "keep components with at least this many properties")
+	fs.BoolVar(&opts.ExcludeTransitive, "exclude-transitive", false, "drop components only referenced from other components")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(pos) != 1 {
+		fmt.Fprintln(stderr, "usage: jsl components list [--pattern GLOB] [--min-properties N] [--exclude-transitive] SCHEMA")
+		return 2
+	}
+
+	schema, err := readJSON(pos[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	engine, err := jsl.NewSchemaLlmEngine()
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	defer engine.Close()
+
+	result, err := engine.ListComponentsWithOptions(schema, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	for _, p := range result.Components {
+		fmt.Fprintln(stdout, p)
+	}
+	return 0
+}
+
+func runComponentsExtract(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("jsl components extract", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	inline := fs.Bool("inline", false, "inline dependencies at their point of use instead of $defs")
+	maxDepth := fs.Int("max-depth", 0, "fail on dependency chains deeper than this (0 = unbounded)")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(pos) != 2 {
+		fmt.Fprintln(stderr, "usage: jsl components extract [--inline] [--max-depth N] SCHEMA POINTER")
+		return 2
+	}
+
+	schema, err := readJSON(pos[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	engine, err := jsl.NewSchemaLlmEngine()
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	defer engine.Close()
+
+	opts := &jsl.ExtractOptions{MaxDepth: *maxDepth}
+	if *inline {
+		opts.Dependencies = jsl.DependenciesInline
+	}
+	result, err := engine.ExtractComponent(schema, pos[1], opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: extract %s: %v\n", pos[1], err)
+		return 1
+	}
+	for _, ref := range result.MissingRefs {
+		fmt.Fprintf(stderr, "warning: unresolved $ref %s\n", ref)
+	}
+	return writeOutput(stdout, stderr, result.Schema)
+}
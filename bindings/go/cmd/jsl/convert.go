@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+func runConvert(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("jsl convert", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	target := fs.String("target", "", "conversion target: openai-strict (alias openai), gemini or claude")
+	out := fs.String("out", "", "write the converted schema to this file")
+	codecOut := fs.String("codec-out", "", "write the codec to this file")
+	report := fs.String("report", "", "with a directory, also write a JSON report to this file")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(pos) != 1 {
+		fmt.Fprintln(stderr, "usage: jsl convert [--target TARGET] [--out FILE --codec-out FILE] SCHEMA")
+		return 2
+	}
+	if (*out == "") != (*codecOut == "") {
+		fmt.Fprintln(stderr, "jsl convert: --out and --codec-out go together (the codec is needed to rehydrate)")
+		return 2
+	}
+	opts := &jsl.ConvertOptions{Target: targetName(*target)}
+	if info, err := os.Stat(pos[0]); err == nil && info.IsDir() {
+		if *out == "" {
+			fmt.Fprintln(stderr, "jsl convert: a directory needs --out DIR and --codec-out DIR")
+			return 2
+		}
+		return convertDir(pos[0], *out, *codecOut, *report, opts, stdout, stderr)
+	}
+	if *report != "" {
+		fmt.Fprintln(stderr, "jsl convert: --report applies to directories only")
+		return 2
+	}
+
+	schema, err := readJSON(pos[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	engine, err := jsl.NewSchemaLlmEngine()
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	defer engine.Close()
+
+	result, err := engine.Convert(schema, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: convert %s: %v\n", pos[0], err)
+		return 1
+	}
+	for _, w := range result.Warnings {
+		fmt.Fprintf(stderr, "warning: %s: %s\n", w.SchemaPath, w.Message)
+	}
+	if *out == "" {
+		return writeOutput(stdout, stderr, result)
+	}
+	if err := writeJSONFile(*out, result.Schema); err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	if err := writeJSONFile(*codecOut, result.Codec); err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func runRehydrate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("jsl rehydrate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	codecPath := fs.String("codec", "", "codec written by jsl convert")
+	schemaPath := fs.String("schema", "", "the original (unconverted) schema")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(pos) != 1 || *codecPath == "" || *schemaPath == "" {
+		fmt.Fprintln(stderr, "usage: jsl rehydrate --codec FILE --schema FILE OUTPUT")
+		return 2
+	}
+
+	codec, err := readJSON(*codecPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	schema, err := readJSON(*schemaPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	data, err := readJSON(pos[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	engine, err := jsl.NewSchemaLlmEngine()
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	defer engine.Close()
+
+	result, err := engine.Rehydrate(data, codec, schema)
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: rehydrate %s: %v\n", pos[0], err)
+		return 1
+	}
+	for _, w := range result.Warnings {
+		fmt.Fprintf(stderr, "warning: %s: %s\n", w.DataPath, w.Message)
+	}
+	return writeOutput(stdout, stderr, result.Data)
+}
+
+// parseArgs parses flags that may appear before, between or after
+// positional arguments and returns the positional ones.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return pos, nil
+		}
+		pos, args = append(pos, args[0]), args[1:]
+	}
+}
+
+// targetName maps CLI target aliases to engine target names.
+func targetName(t string) string {
+	if t == "openai" {
+		return "openai-strict"
+	}
+	return t
+}
+
+func readFile(p string) ([]byte, error) {
+	if p == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(p)
+}
+
+func readJSON(p string) (any, error) {
+	b, err := readFile(p)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+	return v, nil
+}
+
+func writeOutput(stdout, stderr io.Writer, v any) int {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	if _, err := stdout.Write(append(b, '\n')); err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func writeJSONFile(p string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", p, err)
+	}
+	return os.WriteFile(p, append(b, '\n'), 0o644)
+}
@@ -0,0 +1,126 @@
+// Command jsl is the command-line front end to the Go engine.
+//
+// Usage:
+//
+//	jsl convert [--target TARGET] [--out FILE --codec-out FILE] SCHEMA
+//	jsl convert [--target TARGET] --out DIR --codec-out DIR [--report FILE] SCHEMA_DIR
+//	jsl rehydrate --codec FILE --schema FILE OUTPUT
+//	jsl components list [--pattern GLOB] [--min-properties N] [--exclude-transitive] SCHEMA
+//	jsl components extract [--inline] [--max-depth N] SCHEMA POINTER
+//	jsl import openapi [--out DIR] [--target TARGET] PATTERN...
+//
+// File arguments may be "-" for stdin. convert prints the full result
+// (schema, codec, warnings) unless --out and --codec-out split it into
+// files. Given a directory, convert walks it for .json schemas and writes
+// each converted schema and codec to the same relative path under the two
+// output directories, then prints a summary; the two must differ and lie
+// outside SCHEMA_DIR. TARGET is openai-strict
+// (alias openai), gemini or claude.
+//
+// For import, PATTERN may be a spec file, a directory, or a glob where "**"
+// matches any number of directories (quote it so the shell does not expand
+// it).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	"github.com/dotslashderek/json-schema-llm/bindings/go/openapi"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	switch args[0] {
+	case "convert":
+		return runConvert(args[1:], stdout, stderr)
+	case "rehydrate":
+		return runRehydrate(args[1:], stdout, stderr)
+	case "components":
+		return runComponents(args[1:], stdout, stderr)
+	case "import":
+		return runImport(args[1:], stdout, stderr)
+	case "-h", "--help", "help":
+		usage(stdout)
+		return 0
+	}
+	fmt.Fprintf(stderr, "jsl: unknown command %q\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, `usage:
+  jsl convert [--target TARGET] [--out FILE --codec-out FILE] SCHEMA
+  jsl convert [--target TARGET] --out DIR --codec-out DIR [--report FILE] SCHEMA_DIR
+  jsl rehydrate --codec FILE --schema FILE OUTPUT
+  jsl components list [--pattern GLOB] [--min-properties N] [--exclude-transitive] SCHEMA
+  jsl components extract [--inline] [--max-depth N] SCHEMA POINTER
+  jsl import openapi [--out DIR] [--target TARGET] PATTERN...`)
+}
+
+func runImport(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "openapi" {
+		fmt.Fprintln(stderr, "jsl import: only \"openapi\" is supported")
+		return 2
+	}
+	fs := flag.NewFlagSet("jsl import openapi", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("out", "build", "output directory")
+	target := fs.String("target", "", "conversion target (e.g. openai-strict)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "jsl import openapi: no input patterns")
+		return 2
+	}
+
+	files, err := openapi.ExpandPatterns(fs.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(stderr, "jsl: no spec files matched")
+		return 1
+	}
+
+	engine, err := jsl.NewSchemaLlmEngine()
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	defer engine.Close()
+
+	result, err := openapi.Import(engine, files, &openapi.Options{Convert: &jsl.ConvertOptions{Target: targetName(*target)}})
+	if err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+	if err := result.Write(*out); err != nil {
+		fmt.Fprintf(stderr, "jsl: %v\n", err)
+		return 1
+	}
+
+	m := result.Manifest
+	fmt.Fprintf(stdout, "imported %d files: %d components (%d unique schemas), %d operations → %s\n",
+		len(m.Files), len(m.Components), len(result.Artifacts), len(m.Operations), *out)
+	for _, e := range m.Errors {
+		fmt.Fprintf(stderr, "error: %s: %s\n", e.Source, e.Message)
+	}
+	if len(m.Errors) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+// Command jslgen converts schemas and generates Go types for their original
+// and converted shapes plus typed rehydrate wrappers (see package jslgen).
+//
+// Usage:
+//
+//	jslgen -pkg PACKAGE [-out FILE] [-target TARGET] Name=schema.json...
+//
+// Each argument names the Go type for one schema file. Output goes to
+// stdout unless -out is set; use it from a go:generate directive.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	"github.com/dotslashderek/json-schema-llm/bindings/go/jslgen"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("jslgen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	pkg := fs.String("pkg", "", "package name of the generated file")
+	out := fs.String("out", "", "output file (default stdout)")
+	target := fs.String("target", "", "conversion target (e.g. openai-strict)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *pkg == "" || fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: jslgen -pkg PACKAGE [-out FILE] [-target TARGET] Name=schema.json...")
+		return 2
+	}
+
+	engine, err := jsl.NewSchemaLlmEngine()
+	if err != nil {
+		fmt.Fprintf(stderr, "jslgen: %v\n", err)
+		return 1
+	}
+	defer engine.Close()
+
+	var schemas []jslgen.Schema
+	for _, arg := range fs.Args() {
+		name, path, ok := strings.Cut(arg, "=")
+		if !ok {
+			fmt.Fprintf(stderr, "jslgen: %q: want Name=schema.json\n", arg)
+			return 2
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "jslgen: %v\n", err)
+			return 1
+		}
+		var schema any
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			fmt.Fprintf(stderr, "jslgen: %s: %v\n", path, err)
+			return 1
+		}
+		result, err := engine.Convert(schema, &jsl.ConvertOptions{Target: *target})
+		if err != nil {
+			fmt.Fprintf(stderr, "jslgen: convert %s: %v\n", path, err)
+			return 1
+		}
+		if result.BundledSchema != nil {
+			schema = result.BundledSchema
+		}
+		schemas = append(schemas, jslgen.Schema{Name: name, Original: schema, Result: result})
+	}
+
+	src, err := jslgen.Generate(schemas, jslgen.Options{Package: *pkg})
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+	if *out == "" {
+		_, err = stdout.Write(src)
+	} else {
+		err = os.WriteFile(*out, src, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "jslgen: %v\n", err)
+		return 1
+	}
+	return 0
+}
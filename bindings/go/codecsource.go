@@ -0,0 +1,91 @@
+package jsl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// codecSourceKey is the codec field recording what the codec was generated
+// from. The guest ignores unknown codec fields.
+const codecSourceKey = "source"
+
+// CodecSource identifies the input a codec was generated from.
+type CodecSource struct {
+	// SchemaFingerprint is SchemaFingerprint of the schema passed to Convert.
+	SchemaFingerprint string `json:"schemaFingerprint"`
+	// OptionsFingerprint is OptionsFingerprint of the options passed to
+	// Convert.
+	OptionsFingerprint string `json:"optionsFingerprint"`
+}
+
+// stampCodecSource records src on a decoded codec.
+func stampCodecSource(codec any, src CodecSource) any {
+	if m, ok := codec.(map[string]any); ok {
+		m[codecSourceKey] = src
+	}
+	return codec
+}
+
+// CodecFingerprint returns the hex SHA-256 of codec's canonical JSON (see
+// CanonicalJSON). It is stable across storage round trips and bindings, so
+// a stored codec can be checked against the fingerprint recorded with it.
+func CodecFingerprint(codec any) (string, error) {
+	b, err := CanonicalJSON(codec)
+	if err != nil {
+		return "", fmt.Errorf("canonicalize codec: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// CodecSourceOf returns the source recorded in codec by Convert; ok is false
+// for codecs written before sources were recorded.
+func CodecSourceOf(codec any) (src CodecSource, ok bool, err error) {
+	b, err := json.Marshal(codec)
+	if err != nil {
+		return CodecSource{}, false, fmt.Errorf("marshal codec: %w", err)
+	}
+	var raw struct {
+		Source *CodecSource `json:"source"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return CodecSource{}, false, fmt.Errorf("decode codec: %w", err)
+	}
+	if raw.Source == nil {
+		return CodecSource{}, false, nil
+	}
+	return *raw.Source, true, nil
+}
+
+// VerifyCodec checks that codec was generated by Convert from schema and
+// opts, so a stored codec is not applied to output of another schema
+// version. A mismatch, or a codec that records no source, fails with an
+// *Error of code "fingerprint_mismatch" (ErrFingerprintMismatch).
+func VerifyCodec(codec any, schema any, opts *ConvertOptions) error {
+	src, ok, err := CodecSourceOf(codec)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &Error{Code: "fingerprint_mismatch", Message: "codec records no source to verify"}
+	}
+	schemaFP, err := SchemaFingerprint(schema)
+	if err != nil {
+		return err
+	}
+	if src.SchemaFingerprint != schemaFP {
+		return &Error{
+			Code:    "fingerprint_mismatch",
+			Message: fmt.Sprintf("codec was generated from schema %s, not %s", src.SchemaFingerprint, schemaFP),
+		}
+	}
+	if optsFP := OptionsFingerprint(opts); src.OptionsFingerprint != optsFP {
+		return &Error{
+			Code:    "fingerprint_mismatch",
+			Message: fmt.Sprintf("codec was generated with options %s, not %s", src.OptionsFingerprint, optsFP),
+		}
+	}
+	return nil
+}
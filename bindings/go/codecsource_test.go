@@ -0,0 +1,47 @@
+package jsl
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// TestVerifyCodec verifies a stamped codec matches its own schema and
+// options and rejects others with ErrFingerprintMismatch.
+func TestVerifyCodec(t *testing.T) {
+	schema := json.RawMessage(`{"type": "object", "properties": {"a": {"type": "string"}}}`)
+	opts := &ConvertOptions{Target: "gemini"}
+	schemaFP, _ := SchemaFingerprint(schema)
+	codec := stampCodecSource(map[string]any{"transforms": []any{}}, CodecSource{SchemaFingerprint: schemaFP, OptionsFingerprint: OptionsFingerprint(opts)})
+
+	// Stored codecs come back as plain JSON.
+	stored, _ := json.Marshal(codec)
+	if err := VerifyCodec(json.RawMessage(stored), json.RawMessage(`{"properties": {"a": {"type": "string"}}, "type": "object"}`), opts); err != nil {
+		t.Fatalf("VerifyCodec() = %v", err)
+	}
+	for name, tt := range map[string]struct {
+		schema any
+		opts   *ConvertOptions
+		codec  any
+	}{
+		"schema":    {json.RawMessage(`{"type": "object"}`), opts, codec},
+		"options":   {schema, nil, codec},
+		"no source": {schema, opts, map[string]any{"transforms": []any{}}},
+	} {
+		err := VerifyCodec(tt.codec, tt.schema, tt.opts)
+		if !errors.Is(err, ErrFingerprintMismatch) {
+			t.Errorf("%s: VerifyCodec() = %v", name, err)
+		}
+	}
+}
+
+// TestCodecFingerprint verifies the fingerprint ignores key order and
+// changes with content.
+func TestCodecFingerprint(t *testing.T) {
+	a, _ := CodecFingerprint(json.RawMessage(`{"transforms": [], "$schema": "v1"}`))
+	b, _ := CodecFingerprint(map[string]any{"$schema": "v1", "transforms": []any{}})
+	c, _ := CodecFingerprint(map[string]any{"$schema": "v2", "transforms": []any{}})
+	if a == "" || a != b || a == c {
+		t.Errorf("fingerprints %q, %q, %q", a, b, c)
+	}
+}
@@ -0,0 +1,47 @@
+// Package compat preserves the v0 API of the jsl binding as thin wrappers
+// over the current one, so existing consumers can upgrade incrementally.
+//
+// Every identifier here is deprecated and names its replacement. Where a v0
+// call can no longer be reproduced faithfully, the wrapper returns a
+// *MigrationError explaining what to change instead of silently behaving
+// differently.
+package compat
+
+import (
+	"fmt"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// Engine is the v0 engine type.
+//
+// Deprecated: use jsl.SchemaLlmEngine.
+type Engine struct {
+	*jsl.SchemaLlmEngine
+}
+
+// New creates an Engine.
+//
+// Deprecated: use jsl.NewSchemaLlmEngine.
+func New(opts ...jsl.Option) (*Engine, error) {
+	e, err := jsl.NewSchemaLlmEngine(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Engine{SchemaLlmEngine: e}, nil
+}
+
+// MigrationError reports a v0 call whose behavior changed in the current
+// API and therefore cannot be served by a compat wrapper.
+type MigrationError struct {
+	// API is the v0 identifier that was called.
+	API string
+	// Replacement is the current API to migrate to.
+	Replacement string
+	// Reason describes what changed.
+	Reason string
+}
+
+func (e *MigrationError) Error() string {
+	return fmt.Sprintf("jsl compat: %s: %s; use %s", e.API, e.Reason, e.Replacement)
+}
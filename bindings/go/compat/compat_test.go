@@ -0,0 +1,38 @@
+package compat
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// TestMigrationErrorUnwraps verifies MigrationError survives wrapping and names the replacement.
+func TestMigrationErrorUnwraps(t *testing.T) {
+	err := fmt.Errorf("convert: %w", &MigrationError{API: "Engine.Convert", Replacement: "jsl.SchemaLlmEngine.Convert", Reason: "signature changed"})
+	var me *MigrationError
+	if !errors.As(err, &me) {
+		t.Fatalf("errors.As failed for %v", err)
+	}
+	want := "jsl compat: Engine.Convert: signature changed; use jsl.SchemaLlmEngine.Convert"
+	if me.Error() != want {
+		t.Errorf("Error() = %q, want %q", me.Error(), want)
+	}
+}
+
+// TestConvertAllComponentsMigrationError verifies options whose output the
+// v0 result cannot carry are refused before the engine is called.
+func TestConvertAllComponentsMigrationError(t *testing.T) {
+	e := &Engine{}
+	for _, opts := range []*jsl.ConvertOptions{{EmitPatch: true}, {Trace: true}, {RecordTransforms: true}} {
+		_, err := e.ConvertAllComponents(map[string]any{}, opts, nil)
+		var me *MigrationError
+		if !errors.As(err, &me) {
+			t.Fatalf("ConvertAllComponents(%+v) error = %v, want *MigrationError", opts, err)
+		}
+		if me.API != "Engine.ConvertAllComponents" || me.Replacement != "jsl.SchemaLlmEngine.ConvertAllComponents" {
+			t.Errorf("MigrationError = %+v", me)
+		}
+	}
+}
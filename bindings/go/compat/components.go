@@ -0,0 +1,87 @@
+package compat
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// ConvertAllResult is the v0 result of ConvertAllComponents: Components
+// holds [pointer, {"schema", "codec"}] pairs and ComponentErrors holds
+// [pointer, message] pairs, both as raw JSON.
+//
+// Deprecated: use jsl.ConvertAllResult, whose Components is a typed
+// []jsl.ComponentResult carrying failures in ComponentResult.Error.
+type ConvertAllResult struct {
+	APIVersion      string          `json:"apiVersion"`
+	Full            json.RawMessage `json:"full"`
+	Components      json.RawMessage `json:"components"`
+	ComponentErrors json.RawMessage `json:"componentErrors,omitempty"`
+}
+
+// ConvertAllComponents converts schema and its components, returning the v0
+// result shape. Options whose output the v0 pairs cannot carry (EmitPatch,
+// Trace, RecordTransforms) return a *MigrationError instead of being
+// dropped.
+//
+// Deprecated: use jsl.SchemaLlmEngine.ConvertAllComponents and read
+// ConvertAllResult.Components as []jsl.ComponentResult.
+func (e *Engine) ConvertAllComponents(schema any, convertOpts *jsl.ConvertOptions, extractOpts *jsl.ExtractOptions) (*ConvertAllResult, error) {
+	if field := v0Unrepresentable(convertOpts); field != "" {
+		return nil, &MigrationError{
+			API:         "Engine.ConvertAllComponents",
+			Replacement: "jsl.SchemaLlmEngine.ConvertAllComponents",
+			Reason:      "ConvertOptions." + field + " output has no place in the v0 component pairs",
+		}
+	}
+	result, err := e.SchemaLlmEngine.ConvertAllComponents(schema, convertOpts, extractOpts)
+	if err != nil {
+		return nil, err
+	}
+	return convertAllV0(result)
+}
+
+// v0Unrepresentable names the first option in opts whose output the v0
+// result would drop, or returns "".
+func v0Unrepresentable(opts *jsl.ConvertOptions) string {
+	switch {
+	case opts == nil:
+		return ""
+	case opts.EmitPatch:
+		return "EmitPatch"
+	case opts.Trace:
+		return "Trace"
+	case opts.RecordTransforms:
+		return "RecordTransforms"
+	}
+	return ""
+}
+
+// convertAllV0 re-encodes a typed ConvertAllResult as v0 pairs.
+func convertAllV0(result *jsl.ConvertAllResult) (*ConvertAllResult, error) {
+	type converted struct {
+		Schema map[string]any `json:"schema"`
+		Codec  any            `json:"codec"`
+	}
+	components := [][2]any{}
+	var failures [][2]any
+	for _, c := range result.Components {
+		if c.Error != nil {
+			failures = append(failures, [2]any{c.Pointer, c.Error.Message})
+			continue
+		}
+		components = append(components, [2]any{c.Pointer, converted{c.Schema, c.Codec}})
+	}
+	v0 := &ConvertAllResult{APIVersion: result.APIVersion, Full: result.Full}
+	var err error
+	if v0.Components, err = json.Marshal(components); err != nil {
+		return nil, fmt.Errorf("encode components: %w", err)
+	}
+	if failures != nil {
+		if v0.ComponentErrors, err = json.Marshal(failures); err != nil {
+			return nil, fmt.Errorf("encode component errors: %w", err)
+		}
+	}
+	return v0, nil
+}
@@ -0,0 +1,38 @@
+package compat
+
+import (
+	"encoding/json"
+	"testing"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// TestConvertAllV0 verifies typed components are re-encoded as the v0
+// [pointer, result] and [pointer, message] pairs.
+func TestConvertAllV0(t *testing.T) {
+	v0, err := convertAllV0(&jsl.ConvertAllResult{
+		APIVersion: "1.0",
+		Full:       json.RawMessage(`{"schema":{}}`),
+		Components: []jsl.ComponentResult{
+			{Pointer: "#/$defs/A", Schema: map[string]any{"type": "string"}, Codec: map[string]any{"transforms": []any{}}},
+			{Pointer: "#/$defs/B", Error: &jsl.Error{Code: "component_error", Message: "boom", Path: "#/$defs/B"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[["#/$defs/A",{"schema":{"type":"string"},"codec":{"transforms":[]}}]]`; string(v0.Components) != want {
+		t.Errorf("Components = %s, want %s", v0.Components, want)
+	}
+	if want := `[["#/$defs/B","boom"]]`; string(v0.ComponentErrors) != want {
+		t.Errorf("ComponentErrors = %s, want %s", v0.ComponentErrors, want)
+	}
+
+	v0, err = convertAllV0(&jsl.ConvertAllResult{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(v0.Components) != `[]` || v0.ComponentErrors != nil {
+		t.Errorf("empty result = %s / %s, want [] and no errors", v0.Components, v0.ComponentErrors)
+	}
+}
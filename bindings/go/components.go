@@ -0,0 +1,163 @@
+package jsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ListComponentsOptions filters the pointers ListComponentsWithOptions
+// returns. The zero value keeps every component.
+type ListComponentsOptions struct {
+	// Pattern keeps pointers matching a path.Match glob, where "*" stops at
+	// "/" (e.g. "#/components/schemas/Pet*").
+	Pattern string
+	// MinProperties keeps components declaring at least this many
+	// properties.
+	MinProperties int
+	// ExcludeTransitive drops components that are only referenced from
+	// other components. Components referenced from elsewhere in the
+	// document (the root schema, OpenAPI paths) or not referenced at all
+	// are kept.
+	ExcludeTransitive bool
+}
+
+// ListComponentsWithOptions is ListComponents with host-side filtering.
+// Components keeps the engine's order.
+func (e *SchemaLlmEngine) ListComponentsWithOptions(schema any, opts *ListComponentsOptions) (*ListComponentsResult, error) {
+	if opts != nil && opts.Pattern != "" {
+		if _, err := path.Match(opts.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("list components: pattern %q: %w", opts.Pattern, err)
+		}
+	}
+	result, err := e.ListComponents(schema)
+	if err != nil || opts == nil || *opts == (ListComponentsOptions{}) {
+		return result, err
+	}
+	decoded, err := decodeForDiff(schema)
+	if err != nil {
+		return nil, fmt.Errorf("list components: decode schema: %w", err)
+	}
+	result.Components = filterComponents(decoded, result.Components, opts)
+	return result, nil
+}
+
+// filterComponents applies opts to the component pointers listed in
+// schema. The pattern is assumed valid.
+func filterComponents(schema any, pointers []string, opts *ListComponentsOptions) []string {
+	var transitive map[string]bool
+	if opts.ExcludeTransitive {
+		transitive = transitiveComponents(schema, pointers)
+	}
+	kept := make([]string, 0, len(pointers))
+	for _, p := range pointers {
+		if opts.Pattern != "" {
+			if ok, _ := path.Match(opts.Pattern, p); !ok {
+				continue
+			}
+		}
+		if opts.MinProperties > 0 {
+			node, _ := lookupPointer(schema, p)
+			m, _ := node.(map[string]any)
+			props, _ := m["properties"].(map[string]any)
+			if len(props) < opts.MinProperties {
+				continue
+			}
+		}
+		if transitive[p] {
+			continue
+		}
+		kept = append(kept, p)
+	}
+	return kept
+}
+
+// transitiveComponents returns the components whose every incoming $ref is
+// located inside another component. A component's references to itself do
+// not count either way.
+func transitiveComponents(schema any, pointers []string) map[string]bool {
+	direct := map[string]bool{}
+	nested := map[string]bool{}
+	// owner returns the innermost component containing loc, or "".
+	owner := func(loc string) string {
+		best := ""
+		for _, p := range pointers {
+			if (loc == p || strings.HasPrefix(loc, p+"/")) && len(p) > len(best) {
+				best = p
+			}
+		}
+		return best
+	}
+	// target returns the innermost component a ref points into, or "".
+	target := owner
+
+	var walk func(node any, loc string)
+	walk = func(node any, loc string) {
+		switch t := node.(type) {
+		case map[string]any:
+			if ref, ok := t["$ref"].(string); ok {
+				if to := target(ref); to != "" {
+					switch from := owner(loc); {
+					case from == "":
+						direct[to] = true
+					case from != to && !strings.HasPrefix(from, to+"/"):
+						nested[to] = true
+					}
+				}
+			}
+			for k, c := range t {
+				walk(c, childPointer(loc, k))
+			}
+		case []any:
+			for i, c := range t {
+				walk(c, childPointer(loc, itoa(i)))
+			}
+		}
+	}
+	walk(schema, "#")
+
+	out := map[string]bool{}
+	for p := range nested {
+		if !direct[p] {
+			out[p] = true
+		}
+	}
+	return out
+}
+
+// ComponentResult is one component of a ConvertAllComponents call. Patch,
+// Trace and Transforms are reported for the whole schema only, in Full.
+type ComponentResult struct {
+	Pointer string         `json:"pointer"`
+	Schema  map[string]any `json:"schema,omitempty"`
+	Codec   any            `json:"codec,omitempty"`
+	// Warnings are the component's conversion warnings, as in
+	// ConvertResult.Warnings.
+	Warnings []Warning `json:"warnings,omitempty"`
+	// Error is set, with code "component_error" and Path set to Pointer,
+	// when the component could not be extracted or converted.
+	Error *Error `json:"error,omitempty"`
+}
+
+// convertComponent extracts the component at pointer and converts it like
+// Convert. Dependencies always stay in $defs, whatever extractOpts asks.
+func (e *SchemaLlmEngine) convertComponent(schema json.RawMessage, pointer string, convertOpts *ConvertOptions, extractOpts *ExtractOptions) ComponentResult {
+	c := ComponentResult{Pointer: pointer}
+	var opts *ExtractOptions
+	if extractOpts != nil {
+		o := *extractOpts
+		o.Dependencies = DependenciesAsDefs
+		opts = &o
+	}
+	extracted, err := e.ExtractComponent(schema, pointer, opts)
+	if err == nil {
+		var converted *ConvertResult
+		if converted, err = e.Convert(extracted.Schema, convertOpts); err == nil {
+			c.Schema, c.Codec, c.Warnings = converted.Schema, converted.Codec, converted.Warnings
+			return c
+		}
+	}
+	c.Error = &Error{Code: "component_error", Message: err.Error(), Path: pointer}
+	return c
+}
@@ -0,0 +1,81 @@
+package jsl
+
+import (
+	"reflect"
+	"testing"
+)
+
+const componentsDoc = `{
+	"paths": {"/pets": {"get": {"responses": {"200": {"content": {"application/json": {
+		"schema": {"$ref": "#/components/schemas/PetList"}
+	}}}}}}},
+	"components": {"schemas": {
+		"PetList": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}},
+		"Pet": {"type": "object", "properties": {
+			"id": {"type": "integer"},
+			"name": {"type": "string"},
+			"owner": {"$ref": "#/components/schemas/Owner"},
+			"parent": {"$ref": "#/components/schemas/Pet"}
+		}},
+		"PetTag": {"type": "object", "properties": {"label": {"type": "string"}}},
+		"Owner": {"type": "object", "properties": {"name": {"type": "string"}}}
+	}}
+}`
+
+var componentPointers = []string{
+	"#/components/schemas/Owner",
+	"#/components/schemas/Pet",
+	"#/components/schemas/PetList",
+	"#/components/schemas/PetTag",
+}
+
+func TestFilterComponents(t *testing.T) {
+	doc := decodeJSON(t, componentsDoc)
+	tests := []struct {
+		name string
+		opts ListComponentsOptions
+		want []string
+	}{
+		{"pattern", ListComponentsOptions{Pattern: "#/components/schemas/Pet*"}, []string{
+			"#/components/schemas/Pet", "#/components/schemas/PetList", "#/components/schemas/PetTag",
+		}},
+		{"min properties", ListComponentsOptions{MinProperties: 2}, []string{"#/components/schemas/Pet"}},
+		// Owner and Pet are only reached through other components; PetTag
+		// is unreferenced and PetList is used by an operation.
+		{"exclude transitive", ListComponentsOptions{ExcludeTransitive: true}, []string{
+			"#/components/schemas/PetList", "#/components/schemas/PetTag",
+		}},
+		{"combined", ListComponentsOptions{Pattern: "#/components/schemas/Pet*", MinProperties: 1, ExcludeTransitive: true}, []string{
+			"#/components/schemas/PetTag",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterComponents(doc, componentPointers, &tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransitiveComponentsSelfReference(t *testing.T) {
+	doc := decodeJSON(t, `{
+		"$ref": "#/$defs/Node",
+		"$defs": {
+			"Node": {"type": "object", "properties": {"next": {"$ref": "#/$defs/Node"}}},
+			"Loop": {"type": "object", "properties": {"self": {"$ref": "#/$defs/Loop"}}}
+		}
+	}`)
+	got := transitiveComponents(doc, []string{"#/$defs/Loop", "#/$defs/Node"})
+	if len(got) != 0 {
+		t.Errorf("self references should not make a component transitive, got %v", got)
+	}
+}
+
+func TestListComponentsWithOptionsBadPattern(t *testing.T) {
+	var e *SchemaLlmEngine
+	if _, err := e.ListComponentsWithOptions(map[string]any{}, &ListComponentsOptions{Pattern: "#/[a"}); err == nil {
+		t.Fatal("expected an error for a malformed pattern")
+	}
+}
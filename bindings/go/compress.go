@@ -0,0 +1,89 @@
+package jsl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Capability bits exchanged with the guest via the optional
+// `jsl_negotiate(offered, minBytes)` export. The host offers the capabilities
+// it supports along with its compression threshold; the guest replies with
+// the subset it agrees to use for the lifetime of the module instance, and
+// compresses results of at least minBytes once gzip is agreed.
+const (
+	capGzip uint32 = 1 << 0
+)
+
+// DefaultCompressionThreshold is the minimum payload size compressed when
+// WithCompression is given no explicit threshold. Below this size the codec
+// cost outweighs the saved copy time (see BenchmarkConvertCompression).
+const DefaultCompressionThreshold = 256 * 1024
+
+// gzipMagic is the two-byte gzip header. A JSON document can never start with
+// 0x1f, so payloads carrying this prefix are unambiguously compressed.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// WithCompression enables gzip compression for payloads of at least minBytes
+// crossing the ABI in either direction. gzip is the only codec; zstd is not
+// implemented. Compression is negotiated per module instance: guests that do
+// not export `jsl_negotiate`, or that decline gzip, continue to receive and
+// return plain JSON.
+//
+// A minBytes of 0 selects DefaultCompressionThreshold.
+func WithCompression(minBytes int) Option {
+	return func(c *engineConfig) {
+		if minBytes <= 0 {
+			minBytes = DefaultCompressionThreshold
+		}
+		c.compressMin = minBytes
+	}
+}
+
+// compressThreshold clamps minBytes to the guest's u32 threshold argument.
+func compressThreshold(minBytes int) uint32 {
+	if uint64(minBytes) > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(minBytes)
+}
+
+// isGzip reports whether payload carries the gzip magic header.
+func isGzip(payload []byte) bool {
+	return bytes.HasPrefix(payload, gzipMagic)
+}
+
+// gzipBytes compresses payload using the fastest gzip level. Speed matters
+// more than ratio here: the goal is to cut guest memory pressure and copy
+// time, not to minimize storage.
+func gzipBytes(payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(len(payload) / 4)
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(payload); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gunzipBytes decompresses a gzip payload returned by the guest.
+func gunzipBytes(payload []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("gzip header: %w", err)
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("gzip body: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,159 @@
+package jsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// syntheticSchema builds a schema of roughly n bytes with realistic repetition
+// (property names, descriptions) so compression ratios resemble real specs.
+func syntheticSchema(n int) []byte {
+	field := map[string]any{
+		"type":        "string",
+		"description": "A human-readable description of the field, as found in typical OpenAPI documents.",
+		"maxLength":   255,
+	}
+	perField := len(mustMarshal(field)) + len(`"field_00000":,`)
+	props := map[string]any{}
+	for i := 0; i*perField < n; i++ {
+		props[fmt.Sprintf("field_%05d", i)] = field
+	}
+	return mustMarshal(map[string]any{"type": "object", "properties": props})
+}
+
+func mustMarshal(v any) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// TestGzipRoundtrip verifies compressed payloads carry the magic header and decompress losslessly.
+func TestGzipRoundtrip(t *testing.T) {
+	payload := syntheticSchema(64 * 1024)
+
+	compressed, err := gzipBytes(payload)
+	if err != nil {
+		t.Fatalf("gzipBytes() failed: %v", err)
+	}
+	if !isGzip(compressed) {
+		t.Fatal("compressed payload should carry the gzip magic header")
+	}
+	if len(compressed) >= len(payload) {
+		t.Errorf("expected compression, got %d >= %d bytes", len(compressed), len(payload))
+	}
+
+	restored, err := gunzipBytes(compressed)
+	if err != nil {
+		t.Fatalf("gunzipBytes() failed: %v", err)
+	}
+	if !bytes.Equal(restored, payload) {
+		t.Error("roundtrip payload mismatch")
+	}
+}
+
+// TestIsGzipPlainJSON verifies plain JSON is never mistaken for a compressed payload.
+func TestIsGzipPlainJSON(t *testing.T) {
+	for _, payload := range []string{`{}`, `[]`, `"x"`, ` {"a":1}`, ``} {
+		if isGzip([]byte(payload)) {
+			t.Errorf("isGzip(%q) = true, want false", payload)
+		}
+	}
+}
+
+// TestWithCompressionDefaultThreshold verifies a non-positive threshold selects the default.
+func TestWithCompressionDefaultThreshold(t *testing.T) {
+	cfg := &engineConfig{}
+	WithCompression(0)(cfg)
+	if cfg.compressMin != DefaultCompressionThreshold {
+		t.Errorf("compressMin: got %d, want %d", cfg.compressMin, DefaultCompressionThreshold)
+	}
+}
+
+// TestCompressThreshold verifies the threshold offered to jsl_negotiate
+// saturates instead of wrapping.
+func TestCompressThreshold(t *testing.T) {
+	if got := compressThreshold(DefaultCompressionThreshold); got != DefaultCompressionThreshold {
+		t.Errorf("compressThreshold(default) = %d", got)
+	}
+	if got := compressThreshold(math.MaxInt); got != math.MaxUint32 {
+		t.Errorf("compressThreshold(MaxInt) = %d, want MaxUint32", got)
+	}
+}
+
+// BenchmarkCompressionCrossover compares the plain copy that callJsl performs
+// against a gzip compress + decompress cycle across payload sizes, and
+// reports the compressed size as abi-bytes/op. It measures only the
+// host-side price of compression; BenchmarkConvertCompression measures the
+// whole round trip through the guest, where the crossover shows.
+//
+//	go test -run '^$' -bench CompressionCrossover -benchmem
+func BenchmarkCompressionCrossover(b *testing.B) {
+	for _, size := range []int{4 << 10, 64 << 10, 256 << 10, 1 << 20, 8 << 20} {
+		payload := syntheticSchema(size)
+
+		b.Run(fmt.Sprintf("copy/%dKB", size>>10), func(b *testing.B) {
+			b.SetBytes(int64(len(payload)))
+			for i := 0; i < b.N; i++ {
+				dst := make([]byte, len(payload))
+				copy(dst, payload)
+				_ = json.Valid(dst)
+			}
+		})
+
+		b.Run(fmt.Sprintf("gzip/%dKB", size>>10), func(b *testing.B) {
+			b.SetBytes(int64(len(payload)))
+			for i := 0; i < b.N; i++ {
+				compressed, err := gzipBytes(payload)
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.ReportMetric(float64(len(compressed)), "abi-bytes/op")
+				restored, err := gunzipBytes(compressed)
+				if err != nil {
+					b.Fatal(err)
+				}
+				_ = json.Valid(restored)
+			}
+		})
+	}
+}
+
+// BenchmarkConvertCompression converts the same schema through the guest
+// with and without negotiated gzip across payload sizes. The payload size
+// at which the gzip rows overtake the plain ones is the crossover
+// DefaultCompressionThreshold is set from.
+//
+//	go test -run '^$' -bench ConvertCompression -benchmem
+func BenchmarkConvertCompression(b *testing.B) {
+	for _, size := range []int{64 << 10, 256 << 10, 1 << 20, 4 << 20} {
+		schema := json.RawMessage(syntheticSchema(size))
+		for _, mode := range []struct {
+			name string
+			opts []Option
+		}{
+			{"plain", nil},
+			{"gzip", []Option{WithCompression(1)}},
+		} {
+			b.Run(fmt.Sprintf("%s/%dKB", mode.name, size>>10), func(b *testing.B) {
+				eng, err := NewSchemaLlmEngine(mode.opts...)
+				if err != nil {
+					b.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+				}
+				defer eng.Close()
+				b.SetBytes(int64(len(schema)))
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := eng.Convert(schema, nil); err != nil {
+						b.Fatalf("Convert() failed: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
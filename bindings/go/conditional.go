@@ -0,0 +1,146 @@
+package jsl
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// ConditionalStrategy selects how if/then/else conditionals are handled
+// before conversion. The guest passes them through, which strict targets
+// reject.
+type ConditionalStrategy string
+
+const (
+	// ConditionalStrategyNone leaves conditionals to the guest (default).
+	ConditionalStrategyNone ConditionalStrategy = ""
+	// ConditionalStrategyAnyOf rewrites if/then/else into
+	// anyOf [allOf [if, then], else] for the polymorphism pipeline. The
+	// negation of `if` cannot be expressed on strict targets, so the else
+	// branch is looser than the original; Rehydrate checks it.
+	ConditionalStrategyAnyOf ConditionalStrategy = "any_of"
+	// ConditionalStrategyDrop removes the conditional and reports a
+	// "conditional_dropped" warning in ConvertResult.Warnings.
+	ConditionalStrategyDrop ConditionalStrategy = "drop"
+)
+
+// transformConditional records a rewritten or dropped if/then/else.
+const transformConditional = "conditional"
+
+var conditionalKeywords = []string{"if", "then", "else"}
+
+// rewriteConditionals handles every node carrying `if` according to
+// opts.ConditionalStrategy. then/else without if are inert and just removed.
+func rewriteConditionals(schema any, opts *ConvertOptions) (any, []HostTransform, error) {
+	switch opts.ConditionalStrategy {
+	case ConditionalStrategyAnyOf, ConditionalStrategyDrop:
+	default:
+		return nil, nil, fmt.Errorf("unknown ConditionalStrategy %q", opts.ConditionalStrategy)
+	}
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		params := map[string]any{"as": string(opts.ConditionalStrategy)}
+		found := false
+		for _, kw := range conditionalKeywords {
+			if sub, ok := node[kw]; ok {
+				params[kw] = sub
+				found = true
+			}
+		}
+		if !found {
+			return node
+		}
+		t := HostTransform{Type: transformConditional, Path: loc, Params: params}
+		entries = append(entries, t)
+		return rewriteConditional(node, &t)
+	})
+	return schema, entries, nil
+}
+
+func rewriteConditional(n any, t *HostTransform) any {
+	node, ok := n.(map[string]any)
+	if !ok {
+		return n
+	}
+	for _, kw := range conditionalKeywords {
+		delete(node, kw)
+	}
+	cond, hasIf := t.Params["if"]
+	if as, _ := t.Params["as"].(string); as != string(ConditionalStrategyAnyOf) || !hasIf {
+		return node
+	}
+	then, ok := t.Params["then"]
+	if !ok {
+		then = map[string]any{}
+	}
+	els, ok := t.Params["else"]
+	if !ok {
+		els = map[string]any{}
+	}
+	branches := []any{map[string]any{"allOf": []any{cond, then}}, els}
+	if _, taken := node["anyOf"]; taken {
+		allOf, _ := node["allOf"].([]any)
+		node["allOf"] = append(allOf, map[string]any{"anyOf": branches})
+	} else {
+		node["anyOf"] = branches
+	}
+	return node
+}
+
+// restoreConditional re-evaluates the original conditional against the
+// rehydrated value and warns when the branch it selects is not satisfied.
+// The check is structural (see instanceWalker.admits): when `if` or the
+// selected branch uses keywords it cannot evaluate (see notEvaluable), the
+// warning is WarnConstraintUnevaluable instead.
+func restoreConditional(v any, t *HostTransform, dataPath string) (any, []Warning) {
+	cond, ok := t.Params["if"]
+	if !ok {
+		return v, nil
+	}
+	if !notEvaluable(cond) {
+		return v, []Warning{conditionalUnevaluable(t, dataPath, "if")}
+	}
+	w := &instanceWalker{regexes: map[string]*regexp.Regexp{}}
+	branch := "else"
+	if w.admits(cond, v, 0) {
+		branch = "then"
+	}
+	sub, ok := t.Params[branch]
+	if !ok {
+		return v, nil
+	}
+	if !notEvaluable(sub) {
+		return v, []Warning{conditionalUnevaluable(t, dataPath, branch)}
+	}
+	if w.admits(sub, v, 0) {
+		return v, nil
+	}
+	return v, []Warning{{
+		DataPath:   dataPath,
+		SchemaPath: t.Path,
+		Kind:       WarningKind{Type: WarnConstraintViolation, Constraint: branch},
+		Message:    fmt.Sprintf("value does not satisfy the %q branch of the conditional", branch),
+	}}
+}
+
+func conditionalUnevaluable(t *HostTransform, dataPath, keyword string) Warning {
+	return Warning{
+		DataPath:   dataPath,
+		SchemaPath: t.Path,
+		Kind:       WarningKind{Type: WarnConstraintUnevaluable, Constraint: keyword},
+		Message:    fmt.Sprintf("conditional '%s' uses keywords that cannot be checked on rehydrate", keyword),
+	}
+}
+
+// conditionalConvertWarning reports conditionals removed without replacement.
+func conditionalConvertWarning(t *HostTransform) (Warning, bool) {
+	as, _ := t.Params["as"].(string)
+	_, hasIf := t.Params["if"]
+	if as == string(ConditionalStrategyAnyOf) && hasIf {
+		return Warning{}, false
+	}
+	return Warning{
+		SchemaPath: t.Path,
+		Kind:       WarningKind{Type: WarnConditionalDropped},
+		Message:    "if/then/else removed; the target will not enforce it",
+	}, true
+}
@@ -0,0 +1,129 @@
+package jsl
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// conditionalSchema is a typical config-style conditional.
+const conditionalSchema = `{
+	"type": "object",
+	"properties": {
+		"mode": {"type": "string", "enum": ["file", "http"]},
+		"path": {"type": "string"},
+		"url": {"type": "string"}
+	},
+	"required": ["mode"],
+	"if": {"properties": {"mode": {"const": "file"}}},
+	"then": {"required": ["path"]},
+	"else": {"required": ["url"]}
+}`
+
+// TestConditionalStrategies verifies each strategy's schema rewrite, convert warnings and rehydrate checks.
+func TestConditionalStrategies(t *testing.T) {
+	tests := []struct {
+		name         string
+		strategy     ConditionalStrategy
+		wantSchema   string
+		wantWarnings int
+	}{
+		{
+			name:         "any_of",
+			strategy:     ConditionalStrategyAnyOf,
+			wantSchema:   `{"anyOf":[{"allOf":[{"properties":{"mode":{"const":"file"}}},{"required":["path"]}]},{"required":["url"]}],"properties":{"mode":{"enum":["file","http"],"type":"string"},"path":{"type":"string"},"url":{"type":"string"}},"required":["mode"],"type":"object"}`,
+			wantWarnings: 0,
+		},
+		{
+			name:         "drop",
+			strategy:     ConditionalStrategyDrop,
+			wantSchema:   `{"properties":{"mode":{"enum":["file","http"],"type":"string"},"path":{"type":"string"},"url":{"type":"string"}},"required":["mode"],"type":"object"}`,
+			wantWarnings: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := decodeJSON(t, conditionalSchema)
+			converted, entries, err := rewriteConditionals(deepCopyJSON(schema), &ConvertOptions{ConditionalStrategy: tt.strategy})
+			if err != nil {
+				t.Fatalf("rewriteConditionals() failed: %v", err)
+			}
+			if got := string(mustMarshal(converted)); got != tt.wantSchema {
+				t.Errorf("converted:\n  got:  %s\n  want: %s", got, tt.wantSchema)
+			}
+			if got := hostConvertWarnings(entries); len(got) != tt.wantWarnings {
+				t.Errorf("convert warnings = %+v, want %d", got, tt.wantWarnings)
+			}
+
+			entries = roundtripEntries(t, entries)
+			stages := hostStages(schema, entries)
+			for _, data := range []string{`{"mode": "file", "path": "/tmp/x"}`, `{"mode": "http", "url": "https://x"}`} {
+				if _, w := restoreHost(decodeJSON(t, data), stages, entries); len(w) != 0 {
+					t.Errorf("%s: unexpected warnings %+v", data, w)
+				}
+			}
+			_, w := restoreHost(decodeJSON(t, `{"mode": "file", "url": "https://x"}`), stages, entries)
+			if len(w) != 1 || w[0].Kind.Constraint != "then" {
+				t.Errorf("expected a then-branch violation, got %+v", w)
+			}
+		})
+	}
+}
+
+// TestConditionalNestsExistingAnyOf verifies an existing anyOf is preserved by wrapping the new one in allOf.
+func TestConditionalNestsExistingAnyOf(t *testing.T) {
+	schema := decodeJSON(t, `{"anyOf": [{"type": "object"}], "if": {"required": ["a"]}, "then": {"required": ["b"]}}`)
+	converted, _, err := rewriteConditionals(schema, &ConvertOptions{ConditionalStrategy: ConditionalStrategyAnyOf})
+	if err != nil {
+		t.Fatalf("rewriteConditionals() failed: %v", err)
+	}
+	want := `{"allOf":[{"anyOf":[{"allOf":[{"required":["a"]},{"required":["b"]}]},{}]}],"anyOf":[{"type":"object"}]}`
+	if got := string(mustMarshal(converted)); got != want {
+		t.Errorf("converted:\n  got:  %s\n  want: %s", got, want)
+	}
+}
+
+// TestConditionalFixtures runs testdata/conditionals.json: each fixture's
+// data is rehydrated under both strategies and must produce exactly the
+// listed warnings ("type constraint").
+func TestConditionalFixtures(t *testing.T) {
+	raw, err := os.ReadFile(filepath.Join("testdata", "conditionals.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixtures []struct {
+		ID     string `json:"id"`
+		Schema any    `json:"schema"`
+		Cases  []struct {
+			Data     any      `json:"data"`
+			Warnings []string `json:"warnings"`
+		} `json:"cases"`
+	}
+	if err := json.Unmarshal(raw, &fixtures); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range fixtures {
+		for _, strategy := range []ConditionalStrategy{ConditionalStrategyAnyOf, ConditionalStrategyDrop} {
+			t.Run(f.ID+"/"+string(strategy), func(t *testing.T) {
+				_, entries, err := rewriteConditionals(deepCopyJSON(f.Schema), &ConvertOptions{ConditionalStrategy: strategy})
+				if err != nil {
+					t.Fatal(err)
+				}
+				entries = roundtripEntries(t, entries)
+				stages := hostStages(f.Schema, entries)
+				for _, c := range f.Cases {
+					_, warnings := restoreHost(deepCopyJSON(c.Data), stages, entries)
+					got := []string{}
+					for _, w := range warnings {
+						got = append(got, w.Kind.Type+" "+w.Kind.Constraint)
+					}
+					if !reflect.DeepEqual(got, c.Warnings) {
+						t.Errorf("data %s: warnings = %v, want %v", mustMarshal(c.Data), got, c.Warnings)
+					}
+				}
+			})
+		}
+	}
+}
@@ -2,7 +2,10 @@ package jsl
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"testing"
 )
 
@@ -34,19 +37,103 @@ type fixtureInput struct {
 	ExtractOptions map[string]any `json:"extract_options,omitempty"`
 }
 
+// defaultFixtures is the shared cross-language fixture file. Set
+// JSL_FIXTURES to a file of the same shape, or to a directory whose *.json
+// files are merged, to run the conformance tests against other fixtures.
+const defaultFixtures = "../../tests/conformance/fixtures.json"
+
+// conformanceSuites are the suites the TestConformance_* runners execute.
+var conformanceSuites = map[string]bool{
+	"convert":                true,
+	"roundtrip":              true,
+	"rehydrate_error":        true,
+	"list_components":        true,
+	"extract_component":      true,
+	"convert_all_components": true,
+}
+
+func fixturesPath() string {
+	if p := os.Getenv("JSL_FIXTURES"); p != "" {
+		return p
+	}
+	return defaultFixtures
+}
+
+// readFixtures loads a fixture file, or merges the *.json fixture files of
+// a directory in name order. A fixture ID may appear only once per suite.
+func readFixtures(path string) (fixtureFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fixtureFile{}, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.json")); err != nil {
+			return fixtureFile{}, err
+		}
+		sort.Strings(files)
+	}
+	merged := fixtureFile{Suites: map[string]suite{}}
+	seen := map[string]string{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fixtureFile{}, err
+		}
+		var f fixtureFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			return fixtureFile{}, fmt.Errorf("parse %s: %w", file, err)
+		}
+		for name, s := range f.Suites {
+			for _, fx := range s.Fixtures {
+				key := name + "/" + fx.ID
+				if prev, ok := seen[key]; ok {
+					return fixtureFile{}, fmt.Errorf("%s: fixture %s already defined in %s", file, key, prev)
+				}
+				seen[key] = file
+			}
+			m := merged.Suites[name]
+			if m.Description == "" {
+				m.Description = s.Description
+			}
+			m.Fixtures = append(m.Fixtures, s.Fixtures...)
+			merged.Suites[name] = m
+		}
+	}
+	return merged, nil
+}
+
 func loadFixtures(t *testing.T) fixtureFile {
 	t.Helper()
-	data, err := os.ReadFile("../../tests/conformance/fixtures.json")
+	f, err := readFixtures(fixturesPath())
 	if err != nil {
-		t.Fatalf("failed to load fixtures.json: %v", err)
-	}
-	var f fixtureFile
-	if err := json.Unmarshal(data, &f); err != nil {
-		t.Fatalf("failed to parse fixtures.json: %v", err)
+		t.Fatalf("failed to load fixtures from %s: %v", fixturesPath(), err)
 	}
 	return f
 }
 
+// loadSuite returns the fixtures of one suite, skipping the test when the
+// fixtures in use have none. Each fixture runs as a subtest named by its
+// ID, so `go test -run 'TestConformance_Roundtrip/<id>'` selects one.
+func loadSuite(t *testing.T, name string) []fixture {
+	t.Helper()
+	s, ok := loadFixtures(t).Suites[name]
+	if !ok {
+		t.Skipf("no %q suite in %s", name, fixturesPath())
+	}
+	return s.Fixtures
+}
+
+// TestConformance_Suites fails on suites no runner executes, so a typo in
+// an external fixture file does not pass silently.
+func TestConformance_Suites(t *testing.T) {
+	for name := range loadFixtures(t).Suites {
+		if !conformanceSuites[name] {
+			t.Errorf("unknown conformance suite %q in %s", name, fixturesPath())
+		}
+	}
+}
+
 // fixtureOptionsToConvertOptions maps fixture options (kebab-case JSON) to the
 // Go ConvertOptions struct, which is how real users interact with the library.
 func fixtureOptionsToConvertOptions(t *testing.T, opts map[string]any) *ConvertOptions {
@@ -66,10 +153,7 @@ func fixtureOptionsToConvertOptions(t *testing.T, opts map[string]any) *ConvertO
 }
 
 func TestConformance_Convert(t *testing.T) {
-	fixtures := loadFixtures(t)
-	convertSuite := fixtures.Suites["convert"]
-
-	for _, fx := range convertSuite.Fixtures {
+	for _, fx := range loadSuite(t, "convert") {
 		t.Run(fx.ID, func(t *testing.T) {
 			eng, err := NewSchemaLlmEngine()
 			if err != nil {
@@ -121,10 +205,7 @@ func TestConformance_Convert(t *testing.T) {
 }
 
 func TestConformance_Roundtrip(t *testing.T) {
-	fixtures := loadFixtures(t)
-	roundtripSuite := fixtures.Suites["roundtrip"]
-
-	for _, fx := range roundtripSuite.Fixtures {
+	for _, fx := range loadSuite(t, "roundtrip") {
 		t.Run(fx.ID, func(t *testing.T) {
 			eng, err := NewSchemaLlmEngine()
 			if err != nil {
@@ -230,10 +311,7 @@ func TestConformance_Roundtrip(t *testing.T) {
 }
 
 func TestConformance_RehydrateError(t *testing.T) {
-	fixtures := loadFixtures(t)
-	errorSuite := fixtures.Suites["rehydrate_error"]
-
-	for _, fx := range errorSuite.Fixtures {
+	for _, fx := range loadSuite(t, "rehydrate_error") {
 		t.Run(fx.ID, func(t *testing.T) {
 			eng, err := NewSchemaLlmEngine()
 			if err != nil {
@@ -348,10 +426,7 @@ func assertErrorExpected(t *testing.T, jslErr *Error, expected map[string]any) {
 }
 
 func TestConformance_ListComponents(t *testing.T) {
-	fixtures := loadFixtures(t)
-	listSuite := fixtures.Suites["list_components"]
-
-	for _, fx := range listSuite.Fixtures {
+	for _, fx := range loadSuite(t, "list_components") {
 		t.Run(fx.ID, func(t *testing.T) {
 			eng, err := NewSchemaLlmEngine()
 			if err != nil {
@@ -400,10 +475,7 @@ func TestConformance_ListComponents(t *testing.T) {
 }
 
 func TestConformance_ExtractComponent(t *testing.T) {
-	fixtures := loadFixtures(t)
-	extractSuite := fixtures.Suites["extract_component"]
-
-	for _, fx := range extractSuite.Fixtures {
+	for _, fx := range loadSuite(t, "extract_component") {
 		t.Run(fx.ID, func(t *testing.T) {
 			eng, err := NewSchemaLlmEngine()
 			if err != nil {
@@ -481,10 +553,7 @@ func TestConformance_ExtractComponent(t *testing.T) {
 }
 
 func TestConformance_ConvertAllComponents(t *testing.T) {
-	fixtures := loadFixtures(t)
-	convertAllSuite := fixtures.Suites["convert_all_components"]
-
-	for _, fx := range convertAllSuite.Fixtures {
+	for _, fx := range loadSuite(t, "convert_all_components") {
 		t.Run(fx.ID, func(t *testing.T) {
 			eng, err := NewSchemaLlmEngine()
 			if err != nil {
@@ -536,12 +605,8 @@ func TestConformance_ConvertAllComponents(t *testing.T) {
 			// components_count
 			if v, ok := expected["components_count"]; ok {
 				wantCount := int(v.(float64))
-				var comps []any
-				if err := json.Unmarshal(result.Components, &comps); err != nil {
-					t.Fatalf("failed to parse components: %v", err)
-				}
-				if len(comps) != wantCount {
-					t.Errorf("components count: got %d, want %d", len(comps), wantCount)
+				if len(result.Components) != wantCount {
+					t.Errorf("components count: got %d, want %d", len(result.Components), wantCount)
 				}
 			}
 		})
@@ -0,0 +1,90 @@
+package jsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ConstStrategy selects how `const` keywords are rewritten before conversion.
+type ConstStrategy string
+
+const (
+	// ConstStrategyNone leaves `const` to the guest (default), which turns it
+	// into a single-value enum for targets other than Gemini.
+	ConstStrategyNone ConstStrategy = ""
+	// ConstStrategyEnum rewrites `const: X` to `enum: [X]` for every target.
+	ConstStrategyEnum ConstStrategy = "enum"
+	// ConstStrategyDescription rewrites a string const to a plain string
+	// whose description states the required value, for targets that reject
+	// or mishandle one-element enums. Non-string consts fall back to enum.
+	ConstStrategyDescription ConstStrategy = "description"
+)
+
+// transformConst records a rewritten `const` so Rehydrate can check the
+// returned value still equals it.
+const transformConst = "const"
+
+// rewriteConsts replaces every `const` keyword according to opts.ConstStrategy.
+func rewriteConsts(schema any, opts *ConvertOptions) (any, []HostTransform, error) {
+	switch opts.ConstStrategy {
+	case ConstStrategyEnum, ConstStrategyDescription:
+	default:
+		return nil, nil, fmt.Errorf("unknown ConstStrategy %q", opts.ConstStrategy)
+	}
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		v, ok := node["const"]
+		if !ok {
+			return node
+		}
+		as := ConstStrategyEnum
+		if _, isString := v.(string); isString && opts.ConstStrategy == ConstStrategyDescription {
+			as = ConstStrategyDescription
+		}
+		t := HostTransform{Type: transformConst, Path: loc, Params: map[string]any{"value": v, "as": string(as)}}
+		entries = append(entries, t)
+		return rewriteConst(node, &t)
+	})
+	return schema, entries, nil
+}
+
+func rewriteConst(n any, t *HostTransform) any {
+	node, ok := n.(map[string]any)
+	if !ok {
+		return n
+	}
+	v := t.Params["value"]
+	delete(node, "const")
+	if as, _ := t.Params["as"].(string); as == string(ConstStrategyDescription) {
+		s, _ := v.(string)
+		delete(node, "enum")
+		node["type"] = "string"
+		hint := "Must be exactly " + strconv.Quote(s) + "."
+		if desc, ok := node["description"].(string); ok && desc != "" {
+			node["description"] = strings.TrimRight(desc, " ") + " " + hint
+		} else {
+			node["description"] = hint
+		}
+		return node
+	}
+	node["enum"] = []any{v}
+	return node
+}
+
+// restoreConst warns when the rehydrated value differs from the const.
+func restoreConst(v any, t *HostTransform, dataPath string) (any, []Warning) {
+	want := t.Params["value"]
+	if jsonEqual(v, want) {
+		return v, nil
+	}
+	got, _ := json.Marshal(v)
+	exp, _ := json.Marshal(want)
+	return v, []Warning{{
+		DataPath:   dataPath,
+		SchemaPath: t.Path,
+		Kind:       WarningKind{Type: WarnConstraintViolation, Constraint: "const"},
+		Message:    fmt.Sprintf("value %s does not equal const %s", got, exp),
+	}}
+}
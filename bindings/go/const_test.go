@@ -0,0 +1,57 @@
+package jsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestConstRewriteAndVerify verifies both strategies rewrite const and that rehydrate flags mismatches.
+func TestConstRewriteAndVerify(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"kind": {"const": "invoice", "description": "Document kind"},
+			"version": {"const": 2}
+		}
+	}`)
+
+	tests := []struct {
+		strategy ConstStrategy
+		want     string
+	}{
+		{ConstStrategyEnum, `{"properties":{"kind":{"description":"Document kind","enum":["invoice"]},"version":{"enum":[2]}},"type":"object"}`},
+		{ConstStrategyDescription, `{"properties":{"kind":{"description":"Document kind Must be exactly \"invoice\".","type":"string"},"version":{"enum":[2]}},"type":"object"}`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			converted, entries, err := rewriteConsts(deepCopyJSON(schema), &ConvertOptions{ConstStrategy: tt.strategy})
+			if err != nil {
+				t.Fatalf("rewriteConsts() failed: %v", err)
+			}
+			if got := string(mustMarshal(converted)); got != tt.want {
+				t.Errorf("converted:\n  got:  %s\n  want: %s", got, tt.want)
+			}
+			entries = roundtripEntries(t, entries)
+			stages := hostStages(schema, entries)
+			if got := string(mustMarshal(stages[len(stages)-1])); got != tt.want {
+				t.Errorf("re-derived schema differs:\n  got:  %s\n  want: %s", got, tt.want)
+			}
+
+			data := decodeJSON(t, `{"kind": "receipt", "version": 2}`)
+			_, warnings := restoreHost(data, stages, entries)
+			if len(warnings) != 1 || warnings[0].DataPath != "/kind" || warnings[0].Kind.Constraint != "const" {
+				t.Errorf("expected one const violation at /kind, got %+v", warnings)
+			}
+		})
+	}
+}
+
+// roundtripEntries encodes and decodes entries the way a stored codec would.
+func roundtripEntries(t *testing.T, entries []HostTransform) []HostTransform {
+	t.Helper()
+	var out []HostTransform
+	if err := json.Unmarshal(mustMarshal(entries), &out); err != nil {
+		t.Fatalf("decode entries: %v", err)
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package jsl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrHostProcessing is returned by ConvertInto when the conversion needs
+// host-side processing of the guest's result, which an in-place payload
+// cannot get. Convert the schema with Convert instead.
+var ErrHostProcessing = errors.New("conversion needs host-side processing")
+
+// ConvertInto converts schema and passes fn the wasm core's JSON result
+// while it is still in guest memory, saving the copy Convert makes (and the
+// decode, if fn streams the bytes somewhere rather than decoding them).
+// payload decodes into a ConvertResult; it is only valid until fn returns,
+// so fn must copy anything it keeps. An error from fn is returned as is.
+//
+// The payload is the core's result alone, so ConvertInto supports only
+// conversions that need nothing from the host after the guest call. It
+// returns an error matching ErrHostProcessing when the engine has node
+// plugins or custom passes, when opts asks for slimming (MaxSchemaBytes,
+// MaxSchemaTokens), AutoFit, RecordTransforms, Trace or EmitPatch, or when a
+// host pass would record codec entries for this schema; callers fall back
+// to Convert. Options that only shape the input (RefResolver and the host
+// passes that record nothing) apply as in Convert. The result is never
+// checked against provider limits, carries no CodecSource stamp, and
+// bypasses the convert cache and interceptors.
+func (e *SchemaLlmEngine) ConvertInto(schema any, opts *ConvertOptions, fn func(payload []byte) error) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if reasons := e.hostProcessing(opts); len(reasons) > 0 {
+		return fmt.Errorf("%w: %s", ErrHostProcessing, strings.Join(reasons, ", "))
+	}
+
+	endMarshal := e.startPhase("convert", PhaseMarshal)
+	schemaBytes, optsBytes, err := marshalConvertInputs(schema, opts)
+	endMarshal(err)
+	if err != nil {
+		return err
+	}
+	if opts != nil && opts.RefResolver != nil {
+		if schemaBytes, _, err = bundleSchemaBytes(schemaBytes, opts.RefResolver); err != nil {
+			return err
+		}
+	}
+	schemaBytes, hostEntries, err := runHostPasses(schemaBytes, opts, nil)
+	if err != nil {
+		return err
+	}
+	if len(hostEntries) > 0 {
+		return fmt.Errorf("%w: %s host transform at %q", ErrHostProcessing, hostEntries[0].Type, hostEntries[0].Path)
+	}
+
+	err = e.callJslInto("jsl_convert", fn, schemaBytes, optsBytes)
+	if jslErr, ok := err.(*Error); ok {
+		jslErr.Unsupported = unsupportedFromError(jslErr, opts)
+	}
+	return err
+}
+
+// hostProcessing lists the engine and option features that rewrite the
+// guest's convert result on the host.
+func (e *SchemaLlmEngine) hostProcessing(opts *ConvertOptions) []string {
+	var reasons []string
+	if len(e.nodePlugins) > 0 {
+		reasons = append(reasons, "node plugins")
+	}
+	if len(e.customPasses) > 0 {
+		reasons = append(reasons, "custom passes")
+	}
+	if opts == nil {
+		return reasons
+	}
+	for _, f := range []struct {
+		on   bool
+		name string
+	}{
+		{schemaBudget(opts) > 0, "schema budget"},
+		{opts.AutoFit, "AutoFit"},
+		{opts.RecordTransforms, "RecordTransforms"},
+		{opts.Trace, "Trace"},
+		{opts.EmitPatch, "EmitPatch"},
+	} {
+		if f.on {
+			reasons = append(reasons, f.name)
+		}
+	}
+	return reasons
+}
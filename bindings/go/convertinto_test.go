@@ -0,0 +1,87 @@
+package jsl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestConvertIntoHostProcessing verifies conversions that need the host to
+// rewrite the guest's result are refused before the guest is called.
+func TestConvertIntoHostProcessing(t *testing.T) {
+	eng := &SchemaLlmEngine{ctx: context.Background()}
+	schema := map[string]any{"type": "object", "properties": map[string]any{"a": map[string]any{"type": "string"}}}
+	for name, tc := range map[string]struct {
+		schema any
+		opts   *ConvertOptions
+	}{
+		"budget":            {schema, &ConvertOptions{MaxSchemaBytes: 1024}},
+		"auto fit":          {schema, &ConvertOptions{AutoFit: true}},
+		"record transforms": {schema, &ConvertOptions{RecordTransforms: true}},
+		"trace":             {schema, &ConvertOptions{Trace: true}},
+		"patch":             {schema, &ConvertOptions{EmitPatch: true}},
+		"host entries":      {map[string]any{"type": "object", "properties": map[string]any{"any": true}}, nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			called := false
+			err := eng.ConvertInto(tc.schema, tc.opts, func([]byte) error {
+				called = true
+				return nil
+			})
+			if !errors.Is(err, ErrHostProcessing) {
+				t.Errorf("ConvertInto() error = %v, want ErrHostProcessing", err)
+			}
+			if called {
+				t.Error("fn was called")
+			}
+		})
+	}
+}
+
+// TestConvertInto verifies the in-place payload decodes to the schema and
+// codec Convert returns, and that fn's error is returned as is.
+func TestConvertInto(t *testing.T) {
+	eng, err := NewSchemaLlmEngine()
+	if err != nil {
+		t.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           map[string]any{"tags": map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}}},
+		"additionalProperties": false,
+	}
+	want, err := eng.Convert(schema, nil)
+	if err != nil {
+		t.Fatalf("Convert() failed: %v", err)
+	}
+
+	var got ConvertResult
+	if err := eng.ConvertInto(schema, nil, func(payload []byte) error {
+		return json.Unmarshal(payload, &got)
+	}); err != nil {
+		t.Fatalf("ConvertInto() failed: %v", err)
+	}
+	if !reflect.DeepEqual(got.Schema, want.Schema) {
+		t.Errorf("schema = %v, want %v", got.Schema, want.Schema)
+	}
+	wantEntries, err := CodecEntries(want.Codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotEntries, err := CodecEntries(got.Codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gotEntries) != len(wantEntries) {
+		t.Errorf("codec has %d entries, want %d", len(gotEntries), len(wantEntries))
+	}
+
+	stop := errors.New("stop")
+	if err := eng.ConvertInto(schema, nil, func([]byte) error { return stop }); err != stop {
+		t.Errorf("ConvertInto() error = %v, want fn's error", err)
+	}
+}
@@ -0,0 +1,146 @@
+package jsl
+
+// Targets accepted by ConvertOptions.Target.
+const (
+	TargetOpenAI = "openai-strict"
+	TargetGemini = "gemini"
+	TargetClaude = "claude"
+)
+
+// Strategies accepted by ConvertOptions.Polymorphism.
+const (
+	// PolymorphismAnyOf rewrites oneOf to anyOf (default).
+	PolymorphismAnyOf = "any-of"
+	// PolymorphismFlatten merges all variants into one object with nullable
+	// fields.
+	PolymorphismFlatten = "flatten"
+)
+
+// ConvertOption sets ConvertOptions fields; see NewConvertOptions and
+// ConvertWith.
+type ConvertOption func(*ConvertOptions)
+
+// NewConvertOptions applies opts to zero ConvertOptions and validates the
+// result (see ConvertOptions.Validate).
+func NewConvertOptions(opts ...ConvertOption) (*ConvertOptions, error) {
+	o := &ConvertOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// ConvertWith is Convert with options built by NewConvertOptions:
+//
+//	e.ConvertWith(schema, jsl.WithTarget(jsl.TargetOpenAI), jsl.WithRecursionLimit(3))
+func (e *SchemaLlmEngine) ConvertWith(schema any, opts ...ConvertOption) (*ConvertResult, error) {
+	o, err := NewConvertOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return e.Convert(schema, o)
+}
+
+// WithTarget sets Target (TargetOpenAI, TargetGemini or TargetClaude).
+func WithTarget(target string) ConvertOption {
+	return func(o *ConvertOptions) { o.Target = target }
+}
+
+// WithPolymorphism sets Polymorphism (PolymorphismAnyOf or
+// PolymorphismFlatten).
+func WithPolymorphism(strategy string) ConvertOption {
+	return func(o *ConvertOptions) { o.Polymorphism = strategy }
+}
+
+// WithMaxDepth sets MaxDepth.
+func WithMaxDepth(n int) ConvertOption {
+	return func(o *ConvertOptions) { o.MaxDepth = n }
+}
+
+// WithRecursionLimit sets RecursionLimit.
+func WithRecursionLimit(n int) ConvertOption {
+	return func(o *ConvertOptions) { o.RecursionLimit = n }
+}
+
+// WithRecursionLimitFor overrides RecursionLimit for the definition at ref
+// (e.g. "#/$defs/TreeNode").
+func WithRecursionLimitFor(ref string, n int) ConvertOption {
+	return func(o *ConvertOptions) {
+		if o.RecursionLimits == nil {
+			o.RecursionLimits = map[string]int{}
+		}
+		o.RecursionLimits[ref] = n
+	}
+}
+
+// WithDisablePasses adds names to DisablePasses.
+func WithDisablePasses(names ...string) ConvertOption {
+	return func(o *ConvertOptions) { o.DisablePasses = append(o.DisablePasses, names...) }
+}
+
+// WithOverride sets the NodeOverride for the input-schema location ptr
+// (e.g. "#/properties/metadata").
+func WithOverride(ptr string, ov NodeOverride) ConvertOption {
+	return func(o *ConvertOptions) {
+		if o.Overrides == nil {
+			o.Overrides = map[string]NodeOverride{}
+		}
+		o.Overrides[ptr] = ov
+	}
+}
+
+// WithDialect sets Dialect.
+func WithDialect(d Dialect) ConvertOption {
+	return func(o *ConvertOptions) { o.Dialect = d }
+}
+
+// WithOptionalStrategy sets OptionalStrategy.
+func WithOptionalStrategy(s OptionalStrategy) ConvertOption {
+	return func(o *ConvertOptions) { o.OptionalStrategy = s }
+}
+
+// WithTupleStrategy sets TupleStrategy.
+func WithTupleStrategy(s TupleStrategy) ConvertOption {
+	return func(o *ConvertOptions) { o.TupleStrategy = s }
+}
+
+// WithConstStrategy sets ConstStrategy.
+func WithConstStrategy(s ConstStrategy) ConvertOption {
+	return func(o *ConvertOptions) { o.ConstStrategy = s }
+}
+
+// WithConditionalStrategy sets ConditionalStrategy.
+func WithConditionalStrategy(s ConditionalStrategy) ConvertOption {
+	return func(o *ConvertOptions) { o.ConditionalStrategy = s }
+}
+
+// WithPropertyOrder sets PropertyOrder.
+func WithPropertyOrder(order PropertyOrder) ConvertOption {
+	return func(o *ConvertOptions) { o.PropertyOrder = order }
+}
+
+// WithCanonical sets Canonical.
+func WithCanonical() ConvertOption {
+	return func(o *ConvertOptions) { o.Canonical = true }
+}
+
+// WithTrace sets Trace.
+func WithTrace() ConvertOption {
+	return func(o *ConvertOptions) { o.Trace = true }
+}
+
+// WithRefResolver sets RefResolver.
+func WithRefResolver(r RefResolver) ConvertOption {
+	return func(o *ConvertOptions) { o.RefResolver = r }
+}
+
+// WithLimits sets Limits and AutoFit.
+func WithLimits(l ProviderLimits, autoFit bool) ConvertOption {
+	return func(o *ConvertOptions) {
+		o.Limits = &l
+		o.AutoFit = autoFit
+	}
+}
@@ -0,0 +1,40 @@
+package jsl
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestNewConvertOptions verifies options compose and are validated.
+func TestNewConvertOptions(t *testing.T) {
+	opts, err := NewConvertOptions(
+		WithTarget(TargetGemini),
+		WithRecursionLimit(3),
+		WithRecursionLimitFor("#/$defs/Tree", 5),
+		WithOptionalStrategy(OptionalDrop),
+		WithLimits(ProviderLimits{MaxProperties: 10}, true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Target != TargetGemini || opts.RecursionLimit != 3 || opts.RecursionLimits["#/$defs/Tree"] != 5 ||
+		opts.OptionalStrategy != OptionalDrop || opts.Limits.MaxProperties != 10 || !opts.AutoFit {
+		t.Errorf("opts = %+v", opts)
+	}
+
+	_, err = NewConvertOptions(WithMaxDepth(-1))
+	var oe *OptionError
+	if !errors.As(err, &oe) || oe.Field != "MaxDepth" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+// TestConvertWithInvalidOptions verifies invalid options fail before the
+// engine is used.
+func TestConvertWithInvalidOptions(t *testing.T) {
+	_, err := (*SchemaLlmEngine)(nil).ConvertWith(map[string]any{}, WithTarget("openai"))
+	var oe *OptionError
+	if !errors.As(err, &oe) || oe.Field != "Target" {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package jsl
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CustomTransformType is the reserved HostTransform type for entries recorded
+// by user-registered custom passes. The entry's Params["handler"] names the
+// CustomHandler that undoes it.
+const CustomTransformType = "x-custom"
+
+// CustomPass is a user-supplied Go pass run after the engine's pipeline. It
+// receives the converted schema and codec and returns their replacements.
+// Use AppendCustomEntry to record what Rehydrate must undo.
+type CustomPass func(schema map[string]any, codec any) (map[string]any, any, error)
+
+// CustomHandler undoes one x-custom entry. It runs before the engine's
+// rehydration, on data still in the converted (LLM-facing) shape, and must
+// locate the value(s) it cares about itself — entry.Path is whatever the
+// pass recorded.
+type CustomHandler func(data any, entry HostTransform) (any, []Warning, error)
+
+type namedPass struct {
+	name string
+	pass CustomPass
+}
+
+// WithCustomPass registers a custom pass. Passes run in registration order
+// after every engine and host-side pass.
+func WithCustomPass(name string, pass CustomPass) Option {
+	return func(c *engineConfig) {
+		c.customPasses = append(c.customPasses, namedPass{name: name, pass: pass})
+	}
+}
+
+// WithCustomHandler registers the rehydration handler for x-custom entries
+// whose Params["handler"] is name.
+func WithCustomHandler(name string, h CustomHandler) Option {
+	return func(c *engineConfig) {
+		if c.customHandlers == nil {
+			c.customHandlers = map[string]CustomHandler{}
+		}
+		c.customHandlers[name] = h
+	}
+}
+
+// AppendCustomEntry records an x-custom entry on a codec returned by Convert
+// (or passed to a CustomPass) and returns the updated codec.
+func AppendCustomEntry(codec any, handler, path string, params map[string]any) (any, error) {
+	m, ok := codec.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("codec is %T, not an object", codec)
+	}
+	p := make(map[string]any, len(params)+1)
+	for k, v := range params {
+		p[k] = v
+	}
+	p["handler"] = handler
+	entry := HostTransform{ID: EntryID(CustomTransformType, path), Type: CustomTransformType, Path: path, Params: p}
+
+	switch existing := m[hostCodecKey].(type) {
+	case nil:
+		m[hostCodecKey] = []HostTransform{entry}
+	case []HostTransform:
+		m[hostCodecKey] = append(existing, entry)
+	case []any:
+		m[hostCodecKey] = append(existing, entry)
+	default:
+		return nil, fmt.Errorf("codec %s is %T, not a list", hostCodecKey, existing)
+	}
+	return codec, nil
+}
+
+// runCustomPasses applies the engine's custom passes to a convert result.
+func (e *SchemaLlmEngine) runCustomPasses(result *ConvertResult, trace *passTrace) error {
+	for _, p := range e.customPasses {
+		schema, codec, err := p.pass(result.Schema, result.Codec)
+		if err != nil {
+			return fmt.Errorf("custom pass %s: %w", p.name, err)
+		}
+		result.Schema, result.Codec = schema, codec
+		trace.record("custom:"+p.name, schema)
+	}
+	return nil
+}
+
+// splitCustomEntries separates x-custom entries from built-in host entries.
+func splitCustomEntries(entries []HostTransform) (host, custom []HostTransform) {
+	for _, t := range entries {
+		if t.Type == CustomTransformType {
+			custom = append(custom, t)
+		} else {
+			host = append(host, t)
+		}
+	}
+	return host, custom
+}
+
+// restoreCustom routes x-custom entries, newest first, to their registered
+// handlers and returns the re-encoded data.
+func (e *SchemaLlmEngine) restoreCustom(dataBytes []byte, entries []HostTransform) ([]byte, []Warning, error) {
+	var data any
+	if err := json.Unmarshal(dataBytes, &data); err != nil {
+		return nil, nil, fmt.Errorf("decode data: %w", err)
+	}
+	var warnings []Warning
+	for i := len(entries) - 1; i >= 0; i-- {
+		t := entries[i]
+		name, _ := t.Params["handler"].(string)
+		h, ok := e.customHandlers[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("no custom handler registered for %q (entry at %s)", name, t.Path)
+		}
+		var w []Warning
+		var err error
+		data, w, err = h(data, t)
+		if err != nil {
+			return nil, nil, fmt.Errorf("custom handler %s: %w", name, err)
+		}
+		for j := range w {
+			if w[j].EntryID == "" {
+				w[j].EntryID = EntryID(t.Type, t.Path)
+			}
+		}
+		warnings = append(warnings, w...)
+	}
+	out, err := json.Marshal(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode data: %w", err)
+	}
+	return out, warnings, nil
+}
@@ -0,0 +1,69 @@
+package jsl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestCustomPassRoundtrip verifies a custom pass's x-custom entries survive the codec and reach their handler.
+func TestCustomPassRoundtrip(t *testing.T) {
+	// A proprietary pass that renames "ssn" to "tax_id" in the LLM-facing schema.
+	rename := func(schema map[string]any, codec any) (map[string]any, any, error) {
+		props := schema["properties"].(map[string]any)
+		props["tax_id"] = props["ssn"]
+		delete(props, "ssn")
+		codec, err := AppendCustomEntry(codec, "rename", "#/properties/tax_id", map[string]any{"from": "ssn"})
+		return schema, codec, err
+	}
+	unrename := func(data any, entry HostTransform) (any, []Warning, error) {
+		obj := data.(map[string]any)
+		obj[entry.Params["from"].(string)] = obj["tax_id"]
+		delete(obj, "tax_id")
+		return obj, []Warning{{DataPath: "/ssn", Kind: WarningKind{Type: "renamed"}}}, nil
+	}
+
+	cfg := &engineConfig{}
+	WithCustomPass("rename", rename)(cfg)
+	WithCustomHandler("rename", unrename)(cfg)
+	e := &SchemaLlmEngine{customPasses: cfg.customPasses, customHandlers: cfg.customHandlers}
+
+	result := &ConvertResult{
+		Schema: decodeJSON(t, `{"type": "object", "properties": {"ssn": {"type": "string"}}}`).(map[string]any),
+		Codec:  decodeJSON(t, `{"$schema": "https://json-schema-llm.dev/codec/v1", "transforms": [], "droppedConstraints": []}`),
+	}
+	if err := e.runCustomPasses(result, nil); err != nil {
+		t.Fatalf("runCustomPasses() failed: %v", err)
+	}
+	if _, ok := result.Schema["properties"].(map[string]any)["tax_id"]; !ok {
+		t.Fatalf("custom pass did not transform the schema: %v", result.Schema)
+	}
+
+	guest, entries, err := splitHostCodec(mustMarshal(result.Codec))
+	if err != nil {
+		t.Fatalf("splitHostCodec() failed: %v", err)
+	}
+	if strings.Contains(string(guest), hostCodecKey) {
+		t.Errorf("guest codec still carries host entries: %s", guest)
+	}
+	host, custom := splitCustomEntries(entries)
+	if len(host) != 0 || len(custom) != 1 {
+		t.Fatalf("expected one custom entry, got host=%+v custom=%+v", host, custom)
+	}
+
+	out, warnings, err := e.restoreCustom([]byte(`{"tax_id": "123"}`), custom)
+	if err != nil {
+		t.Fatalf("restoreCustom() failed: %v", err)
+	}
+	var data map[string]any
+	if err := json.Unmarshal(out, &data); err != nil || data["ssn"] != "123" {
+		t.Errorf("restored data = %s (%v)", out, err)
+	}
+	if len(warnings) != 1 || warnings[0].EntryID != EntryID(CustomTransformType, "#/properties/tax_id") {
+		t.Errorf("unexpected warnings: %+v", warnings)
+	}
+
+	if _, _, err := (&SchemaLlmEngine{}).restoreCustom([]byte(`{}`), custom); err == nil {
+		t.Error("expected error for unregistered handler")
+	}
+}
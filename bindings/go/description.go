@@ -0,0 +1,103 @@
+package jsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// transformDescription records a description truncated by
+// MaxDescriptionLength, with the full text when PreserveDescriptions is set.
+const transformDescription = "description"
+
+// truncateDescriptions shortens every description longer than
+// opts.MaxDescriptionLength characters on a sentence boundary.
+func truncateDescriptions(schema any, opts *ConvertOptions) (any, []HostTransform, error) {
+	limit := opts.MaxDescriptionLength
+	if limit < 0 {
+		return nil, nil, fmt.Errorf("MaxDescriptionLength must not be negative, got %d", limit)
+	}
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		desc, ok := node["description"].(string)
+		if !ok || utf8.RuneCountInString(desc) <= limit {
+			return node
+		}
+		short := truncateDescription(desc, limit)
+		node["description"] = short
+		if opts.PreserveDescriptions {
+			entries = append(entries, HostTransform{Type: transformDescription, Path: loc, Params: map[string]any{"full": desc, "limit": float64(limit)}})
+		}
+		return node
+	})
+	return schema, entries, nil
+}
+
+// truncateDescription cuts s to at most limit characters. It keeps whole
+// sentences when at least one fits; otherwise it cuts at the last word
+// boundary and appends an ellipsis.
+func truncateDescription(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	cut := -1
+	for i := 0; i < limit; i++ {
+		switch runes[i] {
+		case '.', '!', '?':
+			if i+1 == len(runes) || runes[i+1] == ' ' || runes[i+1] == '\n' {
+				cut = i + 1
+			}
+		}
+	}
+	if cut > 0 {
+		return string(runes[:cut])
+	}
+	if limit < 2 {
+		return string(runes[:limit])
+	}
+	head := string(runes[:limit-1])
+	if i := strings.LastIndexAny(head, " \n"); i > 0 {
+		head = head[:i]
+	}
+	return strings.TrimRight(head, " ,;:") + "…"
+}
+
+// FullDescription returns the untruncated description recorded for the
+// schema location path (e.g. "#/properties/notes") by a conversion with
+// MaxDescriptionLength and PreserveDescriptions set.
+func FullDescription(codec any, path string) (string, bool, error) {
+	codecBytes, err := json.Marshal(codec)
+	if err != nil {
+		return "", false, fmt.Errorf("marshal codec: %w", err)
+	}
+	var raw struct {
+		Host []HostTransform `json:"hostTransforms"`
+	}
+	if err := json.Unmarshal(codecBytes, &raw); err != nil {
+		return "", false, fmt.Errorf("decode codec: %w", err)
+	}
+	for _, t := range raw.Host {
+		if t.Type == transformDescription && t.Path == path {
+			full, ok := t.Params["full"].(string)
+			return full, ok, nil
+		}
+	}
+	return "", false, nil
+}
+
+// rewriteDescription re-applies a recorded truncation so the rehydrate-time
+// stage matches the schema the guest converted.
+func rewriteDescription(n any, t *HostTransform) any {
+	node, ok := n.(map[string]any)
+	if !ok {
+		return n
+	}
+	if full, ok := t.Params["full"].(string); ok {
+		if limit, ok := t.Params["limit"].(float64); ok {
+			node["description"] = truncateDescription(full, int(limit))
+		}
+	}
+	return node
+}
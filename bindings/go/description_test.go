@@ -0,0 +1,54 @@
+package jsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestTruncateDescription verifies sentence-boundary and word-boundary cuts.
+func TestTruncateDescription(t *testing.T) {
+	for _, tc := range []struct {
+		in    string
+		limit int
+		want  string
+	}{
+		{"Short.", 10, "Short."},
+		{"First sentence. Second sentence is longer.", 20, "First sentence."},
+		{"First. Second. Third sentence here.", 16, "First. Second."},
+		{"One very long sentence without any early stop", 20, "One very long…"},
+		{"Supercalifragilistic", 8, "Superca…"},
+	} {
+		if got := truncateDescription(tc.in, tc.limit); got != tc.want {
+			t.Errorf("truncateDescription(%q, %d) = %q, want %q", tc.in, tc.limit, got, tc.want)
+		}
+		if got := truncateDescription(tc.in, tc.limit); len([]rune(got)) > tc.limit {
+			t.Errorf("truncateDescription(%q, %d) = %q exceeds limit", tc.in, tc.limit, got)
+		}
+	}
+}
+
+// TestTruncateDescriptionsPreserve verifies the full text is retrievable from the codec.
+func TestTruncateDescriptionsPreserve(t *testing.T) {
+	var schema any
+	_ = json.Unmarshal([]byte(`{"type":"object","properties":{"notes":{"type":"string","description":"Free-form notes. Include anything the agent should know."}}}`), &schema)
+	out, entries, err := truncateDescriptions(deepCopyJSON(schema), &ConvertOptions{MaxDescriptionLength: 20, PreserveDescriptions: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := lookupPointer(out, "#/properties/notes/description"); got != "Free-form notes." {
+		t.Errorf("description = %v", got)
+	}
+	codec := attachHostTransforms(map[string]any{}, entries)
+	full, ok, err := FullDescription(codec, "#/properties/notes")
+	if err != nil || !ok || full != "Free-form notes. Include anything the agent should know." {
+		t.Errorf("FullDescription = %q, %v, %v", full, ok, err)
+	}
+	if _, ok, _ := FullDescription(codec, "#/properties/other"); ok {
+		t.Error("FullDescription found an unrecorded path")
+	}
+
+	stage := hostStages(schema, entries)[0]
+	if got, _ := lookupPointer(stage, "#/properties/notes/description"); got != "Free-form notes." {
+		t.Errorf("rehydrate stage description = %v", got)
+	}
+}
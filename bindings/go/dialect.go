@@ -0,0 +1,151 @@
+package jsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect names the schema language a ConvertOptions input is written in.
+type Dialect string
+
+const (
+	// DialectJSONSchema is JSON Schema (draft 2020-12, or an earlier draft
+	// the guest migrates).
+	DialectJSONSchema Dialect = ""
+	// DialectSwagger2 is the Swagger 2.0 (OpenAPI 2) schema object. It is
+	// normalized to 2020-12 before any other pass: x-nullable becomes a
+	// "null" type (or an anyOf branch), definitions move to $defs (with a
+	// numeric suffix on a name $defs already holds),
+	// boolean exclusiveMinimum/exclusiveMaximum become numeric bounds,
+	// type "file" becomes a binary string, and the string discriminator
+	// is dropped.
+	DialectSwagger2 Dialect = "swagger2"
+)
+
+// transformDialect records a whole-schema dialect normalization at "#".
+// Params["dialect"] names the source dialect. The data shape does not
+// change, so there is nothing to restore; the entry lets Rehydrate
+// re-derive the schema the later passes saw.
+const transformDialect = "dialect"
+
+// normalizeDialect rewrites the schema from opts.Dialect to 2020-12.
+func normalizeDialect(schema any, opts *ConvertOptions) (any, []HostTransform, error) {
+	if opts.Dialect != DialectSwagger2 {
+		return nil, nil, fmt.Errorf("unknown dialect %q", string(opts.Dialect))
+	}
+	entry := HostTransform{Type: transformDialect, Path: "#", Params: map[string]any{"dialect": string(opts.Dialect)}}
+	return rewriteDialect(schema, &entry), []HostTransform{entry}, nil
+}
+
+func rewriteDialect(node any, t *HostTransform) any {
+	if t.Params["dialect"] != string(DialectSwagger2) {
+		return node
+	}
+	return normalizeSwagger2(node)
+}
+
+// normalizeSwagger2 converts a Swagger 2.0 schema object in place.
+// definitions move into $defs; one whose name a $defs entry already holds
+// gets a numeric suffix, and refs to it follow.
+func normalizeSwagger2(root any) any {
+	moved := map[string]string{}
+	if m, ok := root.(map[string]any); ok {
+		if legacy, ok := m["definitions"].(map[string]any); ok {
+			defs, _ := m["$defs"].(map[string]any)
+			if defs == nil {
+				defs = map[string]any{}
+			}
+			taken := make(map[string]bool, len(defs)+len(legacy))
+			for k := range defs {
+				taken[k] = true
+			}
+			for k := range legacy {
+				taken[k] = true
+			}
+			for _, k := range sortedKeys(legacy) {
+				name := k
+				if _, clash := defs[k]; clash {
+					for i := 2; taken[name]; i++ {
+						name = fmt.Sprintf("%s_%d", k, i)
+					}
+					taken[name] = true
+					moved[escapePointerSegment(k)] = escapePointerSegment(name)
+				}
+				defs[name] = legacy[k]
+			}
+			m["$defs"] = defs
+			delete(m, "definitions")
+		}
+	}
+	return walkSchema(root, func(loc string, node map[string]any) any {
+		if ref, ok := node["$ref"].(string); ok {
+			if rest, ok := strings.CutPrefix(ref, "#/definitions/"); ok {
+				name, _, _ := strings.Cut(rest, "/")
+				if renamed, ok := moved[name]; ok {
+					rest = renamed + rest[len(name):]
+				}
+				node["$ref"] = "#/$defs/" + rest
+			}
+		}
+		if node["type"] == "file" {
+			node["type"] = "string"
+			if _, ok := node["format"]; !ok {
+				node["format"] = "binary"
+			}
+		}
+		if _, ok := node["discriminator"].(string); ok {
+			delete(node, "discriminator")
+		}
+		for _, bound := range [][2]string{{"exclusiveMinimum", "minimum"}, {"exclusiveMaximum", "maximum"}} {
+			exclusive, ok := node[bound[0]].(bool)
+			if !ok {
+				continue
+			}
+			delete(node, bound[0])
+			if limit, has := node[bound[1]]; exclusive && has {
+				node[bound[0]] = limit
+				delete(node, bound[1])
+			}
+		}
+		nullable, _ := node["x-nullable"].(bool)
+		delete(node, "x-nullable")
+		// An untyped root cannot be wrapped without breaking its $defs refs.
+		if !nullable || (loc == "#" && node["type"] == nil) {
+			return node
+		}
+		return makeNullable(node)
+	})
+}
+
+// makeNullable widens node to also accept null.
+func makeNullable(node map[string]any) any {
+	if enum, ok := node["enum"].([]any); ok && !containsNil(enum) {
+		node["enum"] = append(enum, nil)
+	}
+	switch t := node["type"].(type) {
+	case string:
+		if t != "null" {
+			node["type"] = []any{t, "null"}
+		}
+		return node
+	case []any:
+		for _, v := range t {
+			if v == "null" {
+				return node
+			}
+		}
+		node["type"] = append(t, "null")
+		return node
+	}
+	// Untyped ($ref, allOf, …): offer null as an alternative branch.
+	return map[string]any{"anyOf": []any{node, map[string]any{"type": "null"}}}
+}
+
+func containsNil(values []any) bool {
+	for _, v := range values {
+		if v == nil {
+			return true
+		}
+	}
+	return false
+}
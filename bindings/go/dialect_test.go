@@ -0,0 +1,100 @@
+package jsl
+
+import "testing"
+
+func TestNormalizeSwagger2(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"discriminator": "kind",
+		"properties": {
+			"kind": {"type": "string"},
+			"nickname": {"type": "string", "x-nullable": true},
+			"status": {"type": "string", "enum": ["open", "closed"], "x-nullable": true},
+			"owner": {"$ref": "#/definitions/Owner", "x-nullable": true},
+			"score": {"type": "number", "minimum": 0, "exclusiveMinimum": true, "maximum": 10, "exclusiveMaximum": false},
+			"photo": {"type": "file"}
+		},
+		"definitions": {
+			"Owner": {"type": "object", "properties": {"name": {"type": "string"}}}
+		}
+	}`)
+	got, entries, err := normalizeDialect(schema, &ConvertOptions{Dialect: DialectSwagger2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"kind": {"type": "string"},
+			"nickname": {"type": ["string", "null"]},
+			"status": {"type": ["string", "null"], "enum": ["open", "closed", null]},
+			"owner": {"anyOf": [{"$ref": "#/$defs/Owner"}, {"type": "null"}]},
+			"score": {"type": "number", "exclusiveMinimum": 0, "maximum": 10},
+			"photo": {"type": "string", "format": "binary"}
+		},
+		"$defs": {
+			"Owner": {"type": "object", "properties": {"name": {"type": "string"}}}
+		}
+	}`)
+	if !jsonEqual(got, want) {
+		t.Errorf("got %s", mustMarshal(got))
+	}
+	if len(entries) != 1 || entries[0].Type != transformDialect || entries[0].Path != "#" {
+		t.Fatalf("entries = %+v", entries)
+	}
+
+	// Rehydrate re-derives the normalized schema from the original.
+	original := decodeJSON(t, `{"type": "object", "properties": {"a": {"type": "integer", "x-nullable": true}}}`)
+	converted, entries, _ := normalizeDialect(deepCopyJSON(original), &ConvertOptions{Dialect: DialectSwagger2})
+	if stages := hostStages(original, entries); !jsonEqual(stages[0], converted) {
+		t.Errorf("stage = %s, want %s", mustMarshal(stages[0]), mustMarshal(converted))
+	}
+}
+
+// TestNormalizeSwagger2DefsCollision verifies a definitions entry whose
+// name $defs already holds is renamed, with refs to it rewritten.
+func TestNormalizeSwagger2DefsCollision(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"a": {"$ref": "#/definitions/Owner"},
+			"b": {"$ref": "#/$defs/Owner"},
+			"c": {"$ref": "#/definitions/Owner/properties/name"},
+			"d": {"$ref": "#/definitions/Owner_2"}
+		},
+		"definitions": {
+			"Owner": {"type": "object", "properties": {"name": {"type": "string"}}},
+			"Owner_2": {"type": "integer"}
+		},
+		"$defs": {
+			"Owner": {"type": "string"}
+		}
+	}`)
+	got, _, err := normalizeDialect(schema, &ConvertOptions{Dialect: DialectSwagger2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"a": {"$ref": "#/$defs/Owner_3"},
+			"b": {"$ref": "#/$defs/Owner"},
+			"c": {"$ref": "#/$defs/Owner_3/properties/name"},
+			"d": {"$ref": "#/$defs/Owner_2"}
+		},
+		"$defs": {
+			"Owner": {"type": "string"},
+			"Owner_2": {"type": "integer"},
+			"Owner_3": {"type": "object", "properties": {"name": {"type": "string"}}}
+		}
+	}`)
+	if !jsonEqual(got, want) {
+		t.Errorf("got %s", mustMarshal(got))
+	}
+}
+
+func TestNormalizeDialectUnknown(t *testing.T) {
+	if _, _, err := normalizeDialect(map[string]any{}, &ConvertOptions{Dialect: "raml"}); err == nil {
+		t.Fatal("expected an error for an unknown dialect")
+	}
+}
@@ -0,0 +1,81 @@
+package jsl
+
+import "errors"
+
+// Sentinel errors matched (via errors.Is) by every *Error with the
+// corresponding Code, so callers need not compare Code strings.
+var (
+	// ErrJSONParse: an input was not valid JSON ("json_parse_error").
+	ErrJSONParse = errors.New("json parse error")
+	// ErrSchemaInvalid: the schema is malformed or not an object
+	// ("schema_error").
+	ErrSchemaInvalid = errors.New("invalid schema")
+	// ErrRecursionDepthExceeded: a reference cycle ran past the recursion
+	// limit ("recursion_depth_exceeded").
+	ErrRecursionDepthExceeded = errors.New("recursion depth exceeded")
+	// ErrUnsupportedFeature: the target cannot express part of the schema
+	// ("unsupported_feature").
+	ErrUnsupportedFeature = errors.New("unsupported feature")
+	// ErrUnresolvableRef: a $ref or component pointer does not resolve
+	// ("unresolvable_ref").
+	ErrUnresolvableRef = errors.New("unresolvable ref")
+	// ErrPointerNotFound: the pointer passed to ExtractComponent names no
+	// node in the schema. These errors also match ErrUnresolvableRef, the
+	// code the engine reports for them.
+	ErrPointerNotFound = errors.New("pointer not found")
+	// ErrRehydration: the output could not be restored ("rehydration_error").
+	ErrRehydration = errors.New("rehydration error")
+	// ErrCodecVersionMismatch: the codec was written by an incompatible
+	// version ("codec_version_mismatch").
+	ErrCodecVersionMismatch = errors.New("codec version mismatch")
+	// ErrProviderCompat: the converted schema fails the target's strict
+	// checks ("provider_compat_failure").
+	ErrProviderCompat = errors.New("provider compatibility failure")
+	// ErrTargetLimitExceeded: the converted schema exceeds the target's
+	// limits ("target_limit_exceeded").
+	ErrTargetLimitExceeded = errors.New("target limit exceeded")
+	// ErrSchemaBudgetExceeded: the converted schema exceeds MaxSchemaBytes
+	// or MaxSchemaTokens ("schema_budget_exceeded").
+	ErrSchemaBudgetExceeded = errors.New("schema budget exceeded")
+	// ErrFingerprintMismatch: a codec does not match the schema it is used
+	// with ("fingerprint_mismatch").
+	ErrFingerprintMismatch = errors.New("fingerprint mismatch")
+	// ErrDuplicateKey: model output repeats an object key
+	// ("duplicate_key").
+	ErrDuplicateKey = errors.New("duplicate key")
+	// ErrComponent: one component of ConvertAllComponents failed
+	// ("component_error").
+	ErrComponent = errors.New("component error")
+	// ErrInvalidInput: the engine received an unreadable argument
+	// ("invalid_pointer", "invalid_utf8", "invalid_tool_name").
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+var codeErrors = map[string]error{
+	"json_parse_error":         ErrJSONParse,
+	"schema_error":             ErrSchemaInvalid,
+	"recursion_depth_exceeded": ErrRecursionDepthExceeded,
+	"unsupported_feature":      ErrUnsupportedFeature,
+	"unresolvable_ref":         ErrUnresolvableRef,
+	"rehydration_error":        ErrRehydration,
+	"codec_version_mismatch":   ErrCodecVersionMismatch,
+	"provider_compat_failure":  ErrProviderCompat,
+	"target_limit_exceeded":    ErrTargetLimitExceeded,
+	"schema_budget_exceeded":   ErrSchemaBudgetExceeded,
+	"fingerprint_mismatch":     ErrFingerprintMismatch,
+	"duplicate_key":            ErrDuplicateKey,
+	"component_error":          ErrComponent,
+	"invalid_pointer":          ErrInvalidInput,
+	"invalid_utf8":             ErrInvalidInput,
+	"invalid_tool_name":        ErrInvalidInput,
+}
+
+// Is makes errors.Is(err, ErrX) true when err is an *Error whose Code maps
+// to ErrX.
+func (e *Error) Is(target error) bool {
+	if e.sentinel != nil && target == e.sentinel {
+		return true
+	}
+	sentinel, ok := codeErrors[e.Code]
+	return ok && target == sentinel
+}
@@ -0,0 +1,50 @@
+package jsl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorIs(t *testing.T) {
+	err := fmt.Errorf("convert order.json: %w", &Error{Code: "schema_error", Message: "bad"})
+	if !errors.Is(err, ErrSchemaInvalid) {
+		t.Error("wrapped schema_error does not match ErrSchemaInvalid")
+	}
+	if errors.Is(err, ErrUnresolvableRef) {
+		t.Error("schema_error matches ErrUnresolvableRef")
+	}
+	if errors.Is(&Error{Code: "some_future_code"}, ErrSchemaInvalid) {
+		t.Error("unknown code matches a sentinel")
+	}
+
+	ref := &Error{Code: "unresolvable_ref", sentinel: ErrPointerNotFound}
+	if !errors.Is(ref, ErrPointerNotFound) || !errors.Is(ref, ErrUnresolvableRef) {
+		t.Error("pointer-not-found error does not match both sentinels")
+	}
+	if errors.Is(&Error{Code: "unresolvable_ref"}, ErrPointerNotFound) {
+		t.Error("a plain unresolvable_ref matches ErrPointerNotFound")
+	}
+
+	// Host-side errors carry codes too.
+	_, err = (*SchemaLlmEngine)(nil).Bundle(json.RawMessage(`[]`), nil)
+	if !errors.Is(err, ErrSchemaInvalid) {
+		t.Errorf("Bundle error %v does not match ErrSchemaInvalid", err)
+	}
+}
+
+func TestPointerResolves(t *testing.T) {
+	schema := []byte(`{"$defs": {"a/b": {"type": "string"}}, "anyOf": [{}]}`)
+	for ptr, want := range map[string]bool{
+		"#":             true,
+		"#/$defs/a~1b":  true,
+		"#/anyOf/0":     true,
+		"#/$defs/Other": false,
+		"#/anyOf/1":     false,
+	} {
+		if got := pointerResolves(schema, ptr); got != want {
+			t.Errorf("pointerResolves(%q) = %v, want %v", ptr, got, want)
+		}
+	}
+}
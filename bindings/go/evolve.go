@@ -0,0 +1,383 @@
+package jsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// EvolutionKind names a schema evolution operation.
+type EvolutionKind string
+
+const (
+	// EvolveAddField adds property Name (schema Schema, default string) to
+	// the object schema at Path.
+	EvolveAddField EvolutionKind = "add_field"
+	// EvolveWidenEnum appends Values to the enum at Path.
+	EvolveWidenEnum EvolutionKind = "widen_enum"
+	// EvolveDeepenNesting adds property Name to the object at Path holding a
+	// chain of Levels nested objects (default 1) ending in a string.
+	EvolveDeepenNesting EvolutionKind = "deepen_nesting"
+)
+
+// EvolutionOp is one step of a simulated schema evolution. Path is a schema
+// pointer into the original (unconverted) schema. Repeat applies the op that
+// many times (default 1), producing one version each; repeated field names
+// get a numeric suffix and repeated enum widenings generated values.
+type EvolutionOp struct {
+	Kind     EvolutionKind `json:"kind"`
+	Path     string        `json:"path"`
+	Name     string        `json:"name,omitempty"`
+	Schema   any           `json:"schema,omitempty"`
+	Required bool          `json:"required,omitempty"`
+	Values   []any         `json:"values,omitempty"`
+	Levels   int           `json:"levels,omitempty"`
+	Repeat   int           `json:"repeat,omitempty"`
+}
+
+// ProviderLimits are the size ceilings a converted schema must stay under.
+// Zero disables a check.
+type ProviderLimits struct {
+	MaxProperties   int `json:"maxProperties,omitempty"`
+	MaxNestingDepth int `json:"maxNestingDepth,omitempty"`
+	MaxEnumValues   int `json:"maxEnumValues,omitempty"`
+	MaxSchemaBytes  int `json:"maxSchemaBytes,omitempty"`
+	// MaxStringLength bounds the total characters of all property names,
+	// definition names, enum values and const values.
+	MaxStringLength int `json:"maxStringLength,omitempty"`
+	// MaxNameLength bounds the characters of any single property name.
+	MaxNameLength int `json:"maxNameLength,omitempty"`
+	// MaxObjectProperties bounds the properties of any single object.
+	MaxObjectProperties int `json:"maxObjectProperties,omitempty"`
+}
+
+// OpenAIStrictLimits are OpenAI's documented strict-mode structured output
+// limits. OpenAI documents no per-name limit; names count toward
+// MaxStringLength.
+var OpenAIStrictLimits = ProviderLimits{
+	MaxProperties:   5000,
+	MaxNestingDepth: 10,
+	MaxEnumValues:   1000,
+	MaxStringLength: 120000,
+}
+
+// SchemaMetrics measures a converted schema against ProviderLimits.
+// Properties, EnumValues and StringLength are totals over the whole schema;
+// NestingDepth counts object/array levels from the root (root = 1) along
+// properties and items, looking through anyOf/oneOf/allOf branches.
+type SchemaMetrics struct {
+	Properties   int `json:"properties"`
+	NestingDepth int `json:"nestingDepth"`
+	EnumValues   int `json:"enumValues"`
+	SchemaBytes  int `json:"schemaBytes"`
+	StringLength int `json:"stringLength"`
+	LongestName  int `json:"longestName"`
+	// LargestObject is the property count of the largest single object.
+	LargestObject int `json:"largestObject"`
+}
+
+// EvolutionStep is the outcome of converting one schema version. Step 0 is
+// the input schema.
+type EvolutionStep struct {
+	Version int           `json:"version"`
+	Op      *EvolutionOp  `json:"op,omitempty"`
+	Metrics SchemaMetrics `json:"metrics"`
+	// LimitsHit lists each ProviderLimits ceiling the version exceeds.
+	LimitsHit []string `json:"limitsHit,omitempty"`
+	// Breaking lists changes to the converted form of parts of the schema
+	// that already existed in the previous version.
+	Breaking []string `json:"breaking,omitempty"`
+	// ConvertError is set when the version failed to convert.
+	ConvertError string `json:"convertError,omitempty"`
+}
+
+// EvolutionReport summarizes a simulation. FirstLimit and FirstBreaking are
+// the first versions exceeding a limit or breaking conversion (-1: never).
+type EvolutionReport struct {
+	Steps         []EvolutionStep `json:"steps"`
+	FirstLimit    int             `json:"firstLimit"`
+	FirstBreaking int             `json:"firstBreaking"`
+	// Headroom is how far the input schema is from each limit.
+	Headroom ProviderLimits `json:"headroom"`
+}
+
+// SimulateEvolution applies ops to schema one version at a time, converting
+// each version with opts and checking it against limits and against the
+// previous version's conversion. It lets schema owners see how many more
+// fields, enum values or nesting levels their contract can take before it
+// stops fitting the target.
+func (e *SchemaLlmEngine) SimulateEvolution(schema any, ops []EvolutionOp, opts *ConvertOptions, limits ProviderLimits) (*EvolutionReport, error) {
+	current, err := decodeForDiff(schema)
+	if err != nil {
+		return nil, fmt.Errorf("evolution: decode schema: %w", err)
+	}
+	report := &EvolutionReport{FirstLimit: -1, FirstBreaking: -1}
+
+	var prev any
+	record := func(op *EvolutionOp) {
+		step := EvolutionStep{Version: len(report.Steps), Op: op}
+		result, err := e.Convert(deepCopyJSON(current), opts)
+		if err != nil {
+			step.ConvertError = err.Error()
+			if prev != nil {
+				step.Breaking = []string{"conversion failed: " + err.Error()}
+			}
+			prev = nil
+		} else {
+			converted := any(result.Schema)
+			step.Metrics = measureSchema(converted)
+			step.LimitsHit = exceededLimits(step.Metrics, limits)
+			if prev != nil {
+				step.Breaking = breakingChanges(prev, converted)
+			}
+			prev = converted
+		}
+		if report.FirstLimit < 0 && len(step.LimitsHit) > 0 {
+			report.FirstLimit = step.Version
+		}
+		if report.FirstBreaking < 0 && len(step.Breaking) > 0 {
+			report.FirstBreaking = step.Version
+		}
+		report.Steps = append(report.Steps, step)
+	}
+
+	record(nil)
+	report.Headroom = headroom(report.Steps[0].Metrics, limits)
+	for i := range ops {
+		repeat := max(ops[i].Repeat, 1)
+		for n := 0; n < repeat; n++ {
+			next, err := applyEvolutionOp(current, &ops[i], n, repeat)
+			if err != nil {
+				return nil, fmt.Errorf("evolution op %d (%s): %w", i, ops[i].Kind, err)
+			}
+			current = next
+			record(&ops[i])
+		}
+	}
+	return report, nil
+}
+
+// applyEvolutionOp returns a copy of schema with op applied. n is the
+// repetition index; names and generated values are suffixed when the op
+// repeats.
+func applyEvolutionOp(schema any, op *EvolutionOp, n, repeat int) (any, error) {
+	out := deepCopyJSON(schema)
+	target, ok := lookupPointer(out, op.Path)
+	if !ok {
+		return nil, fmt.Errorf("path %q not found", op.Path)
+	}
+	node, ok := target.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("path %q is not a schema object", op.Path)
+	}
+	name := op.Name
+	if repeat > 1 {
+		name = fmt.Sprintf("%s_%d", op.Name, n+1)
+	}
+
+	switch op.Kind {
+	case EvolveAddField, EvolveDeepenNesting:
+		if op.Name == "" {
+			return nil, fmt.Errorf("name is required")
+		}
+		props, _ := node["properties"].(map[string]any)
+		if props == nil {
+			props = map[string]any{}
+			node["properties"] = props
+		}
+		if _, taken := props[name]; taken {
+			return nil, fmt.Errorf("property %q already exists", name)
+		}
+		var field any = map[string]any{"type": "string"}
+		if op.Kind == EvolveAddField && op.Schema != nil {
+			decoded, err := decodeForDiff(op.Schema)
+			if err != nil {
+				return nil, fmt.Errorf("field schema: %w", err)
+			}
+			field = decoded
+		}
+		if op.Kind == EvolveDeepenNesting {
+			for i := max(op.Levels, 1); i > 0; i-- {
+				field = map[string]any{
+					"type":       "object",
+					"properties": map[string]any{fmt.Sprintf("level_%d", i): field},
+					"required":   []any{fmt.Sprintf("level_%d", i)},
+				}
+			}
+		}
+		props[name] = field
+		if op.Required || op.Kind == EvolveDeepenNesting {
+			required, _ := node["required"].([]any)
+			node["required"] = append(required, name)
+		}
+	case EvolveWidenEnum:
+		enum, ok := node["enum"].([]any)
+		if !ok {
+			return nil, fmt.Errorf("path %q has no enum", op.Path)
+		}
+		values := op.Values
+		if repeat > 1 || len(values) == 0 {
+			values = []any{fmt.Sprintf("value_%d", len(enum)+1)}
+			if len(op.Values) > 0 {
+				values = []any{fmt.Sprintf("%v_%d", op.Values[0], n+1)}
+			}
+		}
+		node["enum"] = append(enum, values...)
+	default:
+		return nil, fmt.Errorf("unknown evolution kind %q", op.Kind)
+	}
+	return out, nil
+}
+
+// measureSchema computes SchemaMetrics for a converted schema.
+func measureSchema(schema any) SchemaMetrics {
+	var m SchemaMetrics
+	if b, err := json.Marshal(schema); err == nil {
+		m.SchemaBytes = len(b)
+	}
+	walkSchema(deepCopyJSON(schema), func(_ string, node map[string]any) any {
+		if props, ok := node["properties"].(map[string]any); ok {
+			m.Properties += len(props)
+			m.LargestObject = max(m.LargestObject, len(props))
+			for k := range props {
+				m.LongestName = max(m.LongestName, utf8.RuneCountInString(k))
+			}
+		}
+		if enum, ok := node["enum"].([]any); ok {
+			m.EnumValues += len(enum)
+		}
+		m.StringLength += nodeStringLength(node)
+		return node
+	})
+	if root, ok := schema.(map[string]any); ok {
+		m.NestingDepth = nestingDepth(root)
+	}
+	return m
+}
+
+// nestingDepth returns the number of object/array levels below and
+// including node. Composition branches sit at node's own level.
+func nestingDepth(node map[string]any) int {
+	children := 0
+	visit := func(child any) {
+		if c, ok := child.(map[string]any); ok {
+			children = max(children, nestingDepth(c))
+		}
+	}
+	if props, ok := node["properties"].(map[string]any); ok {
+		for _, c := range props {
+			visit(c)
+		}
+	}
+	visit(node["items"])
+	depth := children
+	if isContainerSchema(node) {
+		depth++
+	}
+	for _, kw := range []string{"anyOf", "oneOf", "allOf"} {
+		if branches, ok := node[kw].([]any); ok {
+			for _, b := range branches {
+				if c, ok := b.(map[string]any); ok {
+					depth = max(depth, nestingDepth(c))
+				}
+			}
+		}
+	}
+	return depth
+}
+
+func exceededLimits(m SchemaMetrics, l ProviderLimits) []string {
+	var hit []string
+	check := func(name string, actual, limit int) {
+		if limit > 0 && actual > limit {
+			hit = append(hit, fmt.Sprintf("%s: %d > %d", name, actual, limit))
+		}
+	}
+	check("MaxProperties", m.Properties, l.MaxProperties)
+	check("MaxNestingDepth", m.NestingDepth, l.MaxNestingDepth)
+	check("MaxEnumValues", m.EnumValues, l.MaxEnumValues)
+	check("MaxSchemaBytes", m.SchemaBytes, l.MaxSchemaBytes)
+	check("MaxStringLength", m.StringLength, l.MaxStringLength)
+	check("MaxNameLength", m.LongestName, l.MaxNameLength)
+	check("MaxObjectProperties", m.LargestObject, l.MaxObjectProperties)
+	return hit
+}
+
+// headroom returns limit - actual per configured limit (negative when
+// already over).
+func headroom(m SchemaMetrics, l ProviderLimits) ProviderLimits {
+	diff := func(actual, limit int) int {
+		if limit == 0 {
+			return 0
+		}
+		return limit - actual
+	}
+	return ProviderLimits{
+		MaxProperties:       diff(m.Properties, l.MaxProperties),
+		MaxNestingDepth:     diff(m.NestingDepth, l.MaxNestingDepth),
+		MaxEnumValues:       diff(m.EnumValues, l.MaxEnumValues),
+		MaxSchemaBytes:      diff(m.SchemaBytes, l.MaxSchemaBytes),
+		MaxStringLength:     diff(m.StringLength, l.MaxStringLength),
+		MaxNameLength:       diff(m.LongestName, l.MaxNameLength),
+		MaxObjectProperties: diff(m.LargestObject, l.MaxObjectProperties),
+	}
+}
+
+// breakingChanges diffs two consecutive converted schemas and describes
+// every change to something the previous version already had. Additions,
+// description edits, and arrays (required, enum) that only grow are not
+// breaking.
+func breakingChanges(prev, next any) []string {
+	ops, err := DiffSchemas(prev, next)
+	if err != nil {
+		return []string{"diff failed: " + err.Error()}
+	}
+	var out []string
+	for _, op := range ops {
+		switch op.Op {
+		case "add":
+			continue
+		case "remove":
+			out = append(out, "removed "+op.Path)
+		case "replace":
+			if strings.HasSuffix(op.Path, "/description") {
+				continue
+			}
+			old, _ := lookupPointer(prev, op.Path)
+			var updated any
+			_ = json.Unmarshal(op.Value, &updated)
+			if isSupersetArray(old, updated) {
+				continue
+			}
+			out = append(out, "changed "+op.Path)
+		}
+	}
+	return out
+}
+
+// isSupersetArray reports whether old and updated are arrays and updated
+// contains every element of old.
+func isSupersetArray(old, updated any) bool {
+	a, ok := old.([]any)
+	if !ok {
+		return false
+	}
+	b, ok := updated.([]any)
+	if !ok {
+		return false
+	}
+	for _, x := range a {
+		found := false
+		for _, y := range b {
+			if jsonEqual(x, y) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
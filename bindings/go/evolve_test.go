@@ -0,0 +1,106 @@
+package jsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestApplyEvolutionOps verifies each op kind against a small object schema.
+func TestApplyEvolutionOps(t *testing.T) {
+	var base any
+	_ = json.Unmarshal([]byte(`{"type":"object","properties":{"status":{"type":"string","enum":["a","b"]}},"required":["status"]}`), &base)
+
+	added, err := applyEvolutionOp(base, &EvolutionOp{Kind: EvolveAddField, Path: "#", Name: "note", Required: true}, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := json.Marshal(added); string(got) != `{"properties":{"note":{"type":"string"},"status":{"enum":["a","b"],"type":"string"}},"required":["status","note"],"type":"object"}` {
+		t.Errorf("add_field: %s", got)
+	}
+
+	widened, err := applyEvolutionOp(base, &EvolutionOp{Kind: EvolveWidenEnum, Path: "#/properties/status", Values: []any{"c"}}, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enum, _ := lookupPointer(widened, "#/properties/status/enum"); len(enum.([]any)) != 3 {
+		t.Errorf("widen_enum: %v", enum)
+	}
+
+	deepened, err := applyEvolutionOp(base, &EvolutionOp{Kind: EvolveDeepenNesting, Path: "#", Name: "deep", Levels: 3}, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := measureSchema(deepened).NestingDepth; got != 4 {
+		t.Errorf("deepen_nesting depth = %d, want 4", got)
+	}
+
+	if _, err := applyEvolutionOp(base, &EvolutionOp{Kind: EvolveWidenEnum, Path: "#"}, 0, 1); err == nil {
+		t.Error("widen_enum without enum should fail")
+	}
+	if ops, _ := lookupPointer(base, "#/properties/note"); ops != nil {
+		t.Error("applyEvolutionOp mutated its input")
+	}
+}
+
+// TestMeasureSchema verifies totals and that composition branches do not add a level.
+func TestMeasureSchema(t *testing.T) {
+	var schema any
+	_ = json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"a": {"type": "string", "enum": ["x", "y", "z"]},
+			"b": {"anyOf": [
+				{"type": "object", "properties": {"c": {"type": "array", "items": {"type": "string"}}}},
+				{"type": "null"}
+			]}
+		}
+	}`), &schema)
+	m := measureSchema(schema)
+	if m.Properties != 3 || m.EnumValues != 3 || m.NestingDepth != 3 {
+		t.Errorf("metrics = %+v", m)
+	}
+	if hit := exceededLimits(m, ProviderLimits{MaxProperties: 2, MaxNestingDepth: 3}); len(hit) != 1 {
+		t.Errorf("limits hit = %v", hit)
+	}
+	if h := headroom(m, ProviderLimits{MaxEnumValues: 10}); h.MaxEnumValues != 7 || h.MaxProperties != 0 {
+		t.Errorf("headroom = %+v", h)
+	}
+}
+
+// TestBreakingChanges verifies growth is tolerated and rewrites are flagged.
+func TestBreakingChanges(t *testing.T) {
+	var prev, grown, rewritten any
+	_ = json.Unmarshal([]byte(`{"properties":{"a":{"type":"object","description":"x"}},"required":["a"]}`), &prev)
+	_ = json.Unmarshal([]byte(`{"properties":{"a":{"type":"object","description":"y"},"b":{"type":"string"}},"required":["a","b"]}`), &grown)
+	_ = json.Unmarshal([]byte(`{"properties":{"a":{"type":"string","description":"x"}},"required":["a"]}`), &rewritten)
+
+	if got := breakingChanges(prev, grown); len(got) != 0 {
+		t.Errorf("growth flagged as breaking: %v", got)
+	}
+	if got := breakingChanges(prev, rewritten); len(got) != 1 || got[0] != "changed /properties/a/type" {
+		t.Errorf("rewrite = %v", got)
+	}
+}
+
+// TestSimulateEvolution runs a simulation against the real engine.
+func TestSimulateEvolution(t *testing.T) {
+	eng, err := NewSchemaLlmEngine()
+	if err != nil {
+		t.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	schema := map[string]any{"type": "object", "properties": map[string]any{"id": map[string]any{"type": "string"}}}
+	report, err := eng.SimulateEvolution(schema, []EvolutionOp{
+		{Kind: EvolveAddField, Path: "#", Name: "field", Repeat: 3},
+	}, nil, ProviderLimits{MaxProperties: 3})
+	if err != nil {
+		t.Fatalf("SimulateEvolution() failed: %v", err)
+	}
+	if len(report.Steps) != 4 {
+		t.Fatalf("steps = %d, want 4", len(report.Steps))
+	}
+	if report.FirstLimit != 3 || report.Headroom.MaxProperties != 2 {
+		t.Errorf("firstLimit = %d, headroom = %+v", report.FirstLimit, report.Headroom)
+	}
+}
@@ -0,0 +1,126 @@
+package jsl
+
+import "fmt"
+
+// DependencyPlacement selects where ExtractComponent puts the transitive
+// dependencies of an extracted component.
+type DependencyPlacement string
+
+const (
+	// DependenciesAsDefs emits each dependency once under $defs and
+	// references it with "#/$defs/<name>".
+	DependenciesAsDefs DependencyPlacement = "defs"
+	// DependenciesInline copies each dependency to its point of use.
+	// Recursive dependencies cannot be inlined and stay in $defs.
+	DependenciesInline DependencyPlacement = "inline"
+)
+
+func (p DependencyPlacement) validate() error {
+	switch p {
+	case "", DependenciesAsDefs, DependenciesInline:
+		return nil
+	}
+	return fmt.Errorf("extract options: unknown dependency placement %q", string(p))
+}
+
+// inlineDependencies replaces each "#/$defs/<name>" ref in an extracted
+// schema with a copy of its target, except for definitions that reach
+// themselves. A ref with sibling keywords keeps them and gains the target
+// as an allOf branch. Definitions left unreferenced are dropped.
+func inlineDependencies(root map[string]any) {
+	defs, _ := root["$defs"].(map[string]any)
+	if len(defs) == 0 {
+		return
+	}
+	defName := func(ref string) (string, bool) {
+		segs := splitPointer(ref)
+		if len(segs) != 2 || segs[0] != "$defs" || ref[0] != '#' {
+			return "", false
+		}
+		_, ok := defs[segs[1]]
+		return segs[1], ok
+	}
+
+	edges := map[string][]string{}
+	for name, def := range defs {
+		walkRefs(def, func(ref string) {
+			if to, ok := defName(ref); ok {
+				edges[name] = append(edges[name], to)
+			}
+		})
+	}
+	recursive := map[string]bool{}
+	for name := range defs {
+		seen := map[string]bool{}
+		stack := append([]string(nil), edges[name]...)
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if n == name {
+				recursive[name] = true
+				break
+			}
+			if !seen[n] {
+				seen[n] = true
+				stack = append(stack, edges[n]...)
+			}
+		}
+	}
+
+	var inline func(node any, bare bool) any
+	inline = func(node any, bare bool) any {
+		switch t := node.(type) {
+		case map[string]any:
+			for k, c := range t {
+				if k != "$ref" {
+					t[k] = inline(c, true)
+				}
+			}
+			ref, _ := t["$ref"].(string)
+			name, ok := defName(ref)
+			if !ok || recursive[name] {
+				return t
+			}
+			target := inline(deepCopyJSON(defs[name]), true)
+			if bare && len(t) == 1 {
+				return target
+			}
+			delete(t, "$ref")
+			allOf, _ := t["allOf"].([]any)
+			t["allOf"] = append(allOf, target)
+		case []any:
+			for i, c := range t {
+				t[i] = inline(c, true)
+			}
+		}
+		return node
+	}
+
+	// The root map is kept in place, so a root $ref always becomes allOf.
+	delete(root, "$defs")
+	inline(root, false)
+	for name := range recursive {
+		defs[name] = inline(defs[name], false)
+	}
+	root["$defs"] = defs
+	pruneDefs(root)
+}
+
+// walkRefs calls fn with every $ref string under node.
+func walkRefs(node any, fn func(ref string)) {
+	switch t := node.(type) {
+	case map[string]any:
+		if ref, ok := t["$ref"].(string); ok {
+			fn(ref)
+		}
+		for k, c := range t {
+			if k != "$ref" {
+				walkRefs(c, fn)
+			}
+		}
+	case []any:
+		for _, c := range t {
+			walkRefs(c, fn)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package jsl
+
+import "testing"
+
+func TestInlineDependencies(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"tag": {"$ref": "#/$defs/Tag"},
+			"owner": {"$ref": "#/$defs/Owner", "description": "Who owns it"},
+			"tree": {"$ref": "#/$defs/Node"}
+		},
+		"$defs": {
+			"Tag": {"type": "string"},
+			"Owner": {"type": "object", "properties": {"tag": {"$ref": "#/$defs/Tag"}}},
+			"Node": {"type": "object", "properties": {
+				"tag": {"$ref": "#/$defs/Tag"},
+				"children": {"type": "array", "items": {"$ref": "#/$defs/Node"}}
+			}}
+		}
+	}`).(map[string]any)
+	inlineDependencies(schema)
+
+	want := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"tag": {"type": "string"},
+			"owner": {"description": "Who owns it", "allOf": [
+				{"type": "object", "properties": {"tag": {"type": "string"}}}
+			]},
+			"tree": {"$ref": "#/$defs/Node"}
+		},
+		"$defs": {
+			"Node": {"type": "object", "properties": {
+				"tag": {"type": "string"},
+				"children": {"type": "array", "items": {"$ref": "#/$defs/Node"}}
+			}}
+		}
+	}`)
+	if !jsonEqual(schema, want) {
+		t.Errorf("got %s", mustMarshal(schema))
+	}
+}
+
+func TestInlineDependenciesDropsDefs(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"$ref": "#/$defs/Base",
+		"properties": {"a": {"$ref": "#/$defs/A"}},
+		"$defs": {"A": {"type": "integer"}, "Base": {"required": ["a"]}}
+	}`).(map[string]any)
+	inlineDependencies(schema)
+
+	want := decodeJSON(t, `{
+		"allOf": [{"required": ["a"]}],
+		"properties": {"a": {"type": "integer"}}
+	}`)
+	if !jsonEqual(schema, want) {
+		t.Errorf("got %s", mustMarshal(schema))
+	}
+}
+
+func TestExtractOptionsUnknownPlacement(t *testing.T) {
+	var e *SchemaLlmEngine
+	_, err := e.ExtractComponent(map[string]any{}, "#/$defs/A", &ExtractOptions{Dependencies: "flat"})
+	if err == nil {
+		t.Fatal("expected an error for an unknown dependency placement")
+	}
+}
@@ -0,0 +1,220 @@
+package jsl
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// transformFlatten records a subtree hoisted out of a too-deep schema. The
+// entry sits at the root ("#") because the rewrite touches both the
+// subtree's location and the root's properties.
+const transformFlatten = "flatten"
+
+const (
+	flattenKeyPrefix = "__jsl_flat_"
+	flattenJoinField = "key"
+	flattenValue     = "value"
+
+	// minFlattenDepth is the smallest MaxNestingDepth that can make progress:
+	// a hoisted subtree lands at depth 4 (root → list → entry → value).
+	minFlattenDepth = 5
+	// maxFlattenHoists bounds the rewrite loop.
+	maxFlattenHoists = 1000
+)
+
+// flattenDeepNesting hoists subtrees nested deeper than opts.MaxNestingDepth
+// into root-level lists of {key, value} entries. The original location
+// becomes a string join key the model must repeat in the matching entry;
+// Rehydrate swaps each key back for its value.
+//
+// Depth counts object and array schemas from the root (root = 1) along
+// properties and items; $ref and composition branches are not followed.
+func flattenDeepNesting(schema any, opts *ConvertOptions) (any, []HostTransform, error) {
+	limit := opts.MaxNestingDepth
+	if limit < minFlattenDepth {
+		return nil, nil, fmt.Errorf("MaxNestingDepth must be at least %d, got %d", minFlattenDepth, limit)
+	}
+	root, ok := schema.(map[string]any)
+	if !ok || !isContainerSchema(root) {
+		return nil, nil, fmt.Errorf("deep-nesting flattening requires an object root schema")
+	}
+
+	var entries []HostTransform
+	for n := 0; ; n++ {
+		loc, found := findTooDeep(root, "#", 1, limit)
+		if !found {
+			break
+		}
+		if n == maxFlattenHoists {
+			return nil, nil, fmt.Errorf("schema still exceeds depth %d after %d hoists", limit, n)
+		}
+		key := flattenKeyPrefix + strconv.Itoa(n)
+		if props, _ := root["properties"].(map[string]any); props != nil {
+			if _, taken := props[key]; taken {
+				return nil, nil, fmt.Errorf("root property %q is reserved for flattening", key)
+			}
+		}
+		t := HostTransform{Type: transformFlatten, Path: "#", Params: map[string]any{"from": loc, "key": key}}
+		entries = append(entries, t)
+		root = rewriteFlatten(root, &t).(map[string]any)
+	}
+	return root, entries, nil
+}
+
+// findTooDeep returns the first container at depth == limit that still has
+// container children, visiting properties in sorted order.
+func findTooDeep(node map[string]any, loc string, depth, limit int) (string, bool) {
+	children := containerChildren(node, loc)
+	if depth == limit {
+		return loc, len(children) > 0
+	}
+	for _, c := range children {
+		if found, ok := findTooDeep(c.node, c.loc, depth+1, limit); ok {
+			return found, true
+		}
+	}
+	return "", false
+}
+
+type locatedSchema struct {
+	loc  string
+	node map[string]any
+}
+
+// containerChildren lists the object/array subschemas reachable through
+// properties and items.
+func containerChildren(node map[string]any, loc string) []locatedSchema {
+	var out []locatedSchema
+	if props, ok := node["properties"].(map[string]any); ok {
+		for _, k := range sortedKeys(props) {
+			if c, ok := props[k].(map[string]any); ok && isContainerSchema(c) {
+				out = append(out, locatedSchema{childPointer(childPointer(loc, "properties"), k), c})
+			}
+		}
+	}
+	if c, ok := node["items"].(map[string]any); ok && isContainerSchema(c) {
+		out = append(out, locatedSchema{childPointer(loc, "items"), c})
+	}
+	return out
+}
+
+func isContainerSchema(node map[string]any) bool {
+	switch node["type"] {
+	case "object", "array":
+		return true
+	}
+	_, hasProps := node["properties"]
+	_, hasItems := node["items"]
+	return hasProps || hasItems
+}
+
+// rewriteFlatten moves the subtree at Params["from"] into a root-level list
+// under Params["key"] and leaves a join-key string in its place.
+func rewriteFlatten(n any, t *HostTransform) any {
+	root, ok := n.(map[string]any)
+	if !ok {
+		return n
+	}
+	from, _ := t.Params["from"].(string)
+	key, _ := t.Params["key"].(string)
+	subtree, ok := lookupPointer(root, from)
+	if !ok {
+		return root
+	}
+	replaceAtPointer(root, from, map[string]any{
+		"type":        "string",
+		"description": fmt.Sprintf("Join key: a unique id, repeated as %q in the matching entry of %s.", flattenJoinField, key),
+	})
+
+	props, _ := root["properties"].(map[string]any)
+	if props == nil {
+		props = map[string]any{}
+		root["properties"] = props
+	}
+	props[key] = map[string]any{
+		"type":        "array",
+		"description": fmt.Sprintf("Values for the join keys referencing %s.", key),
+		"items": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				flattenJoinField: map[string]any{"type": "string"},
+				flattenValue:     subtree,
+			},
+			"required":             []any{flattenJoinField, flattenValue},
+			"additionalProperties": false,
+		},
+	}
+	required, _ := root["required"].([]any)
+	root["required"] = append(required, key)
+	return root
+}
+
+// restoreFlatten replaces every join key found at Params["from"] with the
+// matching entry's value and removes the hoisted list.
+func restoreFlatten(v any, t *HostTransform, _ string) (any, []Warning) {
+	root, ok := v.(map[string]any)
+	if !ok {
+		return v, nil
+	}
+	from, _ := t.Params["from"].(string)
+	key, _ := t.Params["key"].(string)
+
+	values := map[string]any{}
+	list, _ := root[key].([]any)
+	for _, e := range list {
+		if entry, ok := e.(map[string]any); ok {
+			if k, ok := entry[flattenJoinField].(string); ok {
+				values[k] = entry[flattenValue]
+			}
+		}
+	}
+	delete(root, key)
+
+	var warnings []Warning
+	rejoin(root, "", splitPointer(from), func(joinKey any, dataPath string) any {
+		k, _ := joinKey.(string)
+		if val, ok := values[k]; ok {
+			return val
+		}
+		warnings = append(warnings, Warning{
+			DataPath:   dataPath,
+			SchemaPath: from,
+			Kind:       WarningKind{Type: WarnFlattenJoinMissing},
+			Message:    fmt.Sprintf("no %s entry for join key %q", key, k),
+		})
+		return nil
+	})
+	return root, warnings
+}
+
+// rejoin follows a properties/items schema path through data, replacing
+// each value it reaches with fn's result. anyOf/oneOf segments keep the
+// current value; fn sees it whichever branch it matches.
+func rejoin(data any, dataPath string, segs []string, fn func(v any, dataPath string) any) any {
+	if len(segs) == 0 {
+		return fn(data, dataPath)
+	}
+	switch segs[0] {
+	case "properties":
+		obj, ok := data.(map[string]any)
+		if !ok || len(segs) < 2 {
+			return data
+		}
+		if child, present := obj[segs[1]]; present {
+			obj[segs[1]] = rejoin(child, childDataPath(dataPath, segs[1]), segs[2:], fn)
+		}
+	case "items":
+		arr, ok := data.([]any)
+		if !ok {
+			return data
+		}
+		for i := range arr {
+			arr[i] = rejoin(arr[i], childDataPath(dataPath, i), segs[1:], fn)
+		}
+	case "anyOf", "oneOf":
+		if len(segs) >= 2 {
+			return rejoin(data, dataPath, segs[2:], fn)
+		}
+	}
+	return data
+}
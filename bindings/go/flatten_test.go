@@ -0,0 +1,73 @@
+package jsl
+
+import "testing"
+
+// nestedSchema returns an object schema nested depth levels deep, with an
+// array of leaves at the bottom.
+func nestedSchema(depth int) map[string]any {
+	node := map[string]any{"type": "array", "items": map[string]any{"type": "object", "properties": map[string]any{"v": map[string]any{"type": "integer"}}}}
+	for i := 0; i < depth-2; i++ {
+		node = map[string]any{"type": "object", "properties": map[string]any{"n": node}, "required": []any{"n"}}
+	}
+	return node
+}
+
+// maxDepth mirrors flattenDeepNesting's depth rule.
+func maxDepth(node map[string]any, loc string) int {
+	best := 0
+	for _, c := range containerChildren(node, loc) {
+		if d := maxDepth(c.node, c.loc); d > best {
+			best = d
+		}
+	}
+	return best + 1
+}
+
+// TestFlattenDeepNesting verifies deep subtrees are hoisted under the limit and rejoined on rehydrate.
+func TestFlattenDeepNesting(t *testing.T) {
+	schema := any(nestedSchema(9))
+	converted, entries, err := flattenDeepNesting(deepCopyJSON(schema), &ConvertOptions{MaxNestingDepth: 5})
+	if err != nil {
+		t.Fatalf("flattenDeepNesting() failed: %v", err)
+	}
+	if d := maxDepth(converted.(map[string]any), "#"); d > 5 {
+		t.Errorf("converted depth = %d, want <= 5", d)
+	}
+	if len(entries) < 2 {
+		t.Fatalf("expected nested hoists, got %+v", entries)
+	}
+	entries = roundtripEntries(t, entries)
+	stages := hostStages(schema, entries)
+	if !jsonEqual(stages[len(stages)-1], converted) {
+		t.Error("re-derived schema differs from converted schema")
+	}
+
+	// Build model output in the converted shape: fill every join key with a
+	// distinct id and emit the matching entries.
+	original := decodeJSON(t, `{"n":{"n":{"n":{"n":{"n":{"n":{"n":[{"v":1},{"v":2}]}}}}}}}`)
+	data := deepCopyJSON(original)
+	for i := range entries {
+		from, _ := entries[i].Params["from"].(string)
+		key, _ := entries[i].Params["key"].(string)
+		var list []any
+		root := data.(map[string]any)
+		rejoin(root, "", splitPointer(from), func(v any, _ string) any {
+			id := key + "-" + itoa(len(list))
+			list = append(list, map[string]any{"key": id, "value": v})
+			return id
+		})
+		root[key] = list
+	}
+
+	restored, warnings := restoreHost(data, stages, entries)
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %+v", warnings)
+	}
+	if !jsonEqual(restored, original) {
+		t.Errorf("restored:\n  got:  %s\n  want: %s", mustMarshal(restored), mustMarshal(original))
+	}
+
+	if _, _, err := flattenDeepNesting(deepCopyJSON(schema), &ConvertOptions{MaxNestingDepth: 4}); err == nil {
+		t.Error("expected error for a limit below the minimum")
+	}
+}
@@ -0,0 +1,196 @@
+package jsl
+
+import (
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// transformFormat records a `format` keyword so Rehydrate can validate and
+// normalize the value even when the target dropped the keyword.
+const transformFormat = "format"
+
+// preserveFormats records every `format` keyword in the source schema.
+// The schema itself is left untouched; the guest decides what the target sees.
+func preserveFormats(schema any, _ *ConvertOptions) (any, []HostTransform, error) {
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		if f, ok := node["format"].(string); ok {
+			entries = append(entries, HostTransform{
+				Type:   transformFormat,
+				Path:   loc,
+				Params: map[string]any{"format": f},
+			})
+		}
+		return node
+	})
+	return schema, entries, nil
+}
+
+// restoreFormat validates a rehydrated string against its recorded format,
+// coercing common near-misses to the canonical form. Coercions and
+// mismatches are both reported as warnings; the value is never dropped.
+func restoreFormat(v any, t *HostTransform, dataPath string) (any, []Warning) {
+	s, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+	format, _ := t.Params["format"].(string)
+	checker, ok := formatCheckers[format]
+	if !ok {
+		return v, nil
+	}
+	if checker.valid(s) {
+		return v, nil
+	}
+	if checker.coerce != nil {
+		if coerced, ok := checker.coerce(s); ok {
+			return coerced, []Warning{{
+				DataPath:   dataPath,
+				SchemaPath: t.Path,
+				Kind:       WarningKind{Type: WarnFormatCoerced, Constraint: "format"},
+				Message:    fmt.Sprintf("coerced %q to %s %q", s, format, coerced),
+			}}
+		}
+	}
+	return v, []Warning{{
+		DataPath:   dataPath,
+		SchemaPath: t.Path,
+		Kind:       WarningKind{Type: WarnConstraintViolation, Constraint: "format"},
+		Message:    fmt.Sprintf("value %q is not a valid %s", s, format),
+	}}
+}
+
+// formatChecker validates (and optionally normalizes) one `format` value.
+type formatChecker struct {
+	valid  func(s string) bool
+	coerce func(s string) (string, bool)
+}
+
+var formatCheckers = map[string]formatChecker{
+	"date-time": {
+		valid: func(s string) bool { _, err := time.Parse(time.RFC3339, s); return err == nil },
+		coerce: func(s string) (string, bool) {
+			t, ok := parseLooseTime(s)
+			if !ok {
+				return "", false
+			}
+			return t.Format(time.RFC3339Nano), true
+		},
+	},
+	"date": {
+		valid: func(s string) bool { _, err := time.Parse(time.DateOnly, s); return err == nil },
+		coerce: func(s string) (string, bool) {
+			t, ok := parseLooseTime(s)
+			if !ok {
+				return "", false
+			}
+			return t.Format(time.DateOnly), true
+		},
+	},
+	"time": {
+		valid: func(s string) bool {
+			_, err := time.Parse("15:04:05Z07:00", s)
+			return err == nil
+		},
+		coerce: func(s string) (string, bool) {
+			for _, layout := range []string{"15:04:05", "15:04", "3:04PM", "3:04 PM"} {
+				if t, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+					return t.Format("15:04:05Z07:00"), true
+				}
+			}
+			return "", false
+		},
+	},
+	"uuid": {
+		valid: func(s string) bool { return uuidPattern.MatchString(s) },
+		coerce: func(s string) (string, bool) {
+			h := strings.ToLower(strings.TrimSpace(s))
+			h = strings.TrimPrefix(h, "urn:uuid:")
+			h = strings.Trim(h, "{}")
+			h = strings.ReplaceAll(h, "-", "")
+			if len(h) != 32 || !hexPattern.MatchString(h) {
+				return "", false
+			}
+			return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32], true
+		},
+	},
+	"uri": {
+		valid: func(s string) bool {
+			u, err := url.Parse(s)
+			return err == nil && u.Scheme != "" && !strings.ContainsAny(s, " \t\n")
+		},
+		coerce: func(s string) (string, bool) {
+			trimmed := strings.TrimSpace(s)
+			u, err := url.Parse(trimmed)
+			if err != nil || u.Scheme == "" || strings.ContainsAny(trimmed, " \t\n") {
+				return "", false
+			}
+			return trimmed, true
+		},
+	},
+	"email": {
+		valid: func(s string) bool {
+			addr, err := mail.ParseAddress(s)
+			return err == nil && addr.Address == s
+		},
+		coerce: func(s string) (string, bool) {
+			addr, err := mail.ParseAddress(strings.TrimSpace(s))
+			if err != nil {
+				return "", false
+			}
+			return addr.Address, true
+		},
+	},
+	"ipv4": {
+		valid: func(s string) bool { a, err := netip.ParseAddr(s); return err == nil && a.Is4() },
+	},
+	"ipv6": {
+		valid: func(s string) bool { a, err := netip.ParseAddr(s); return err == nil && a.Is6() },
+	},
+}
+
+var (
+	// UUID hex digits are case-insensitive on input (RFC 9562, section 4).
+	uuidPattern = regexp.MustCompile(`(?i)^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	hexPattern  = regexp.MustCompile(`^[0-9a-f]+$`)
+)
+
+// looseTimeLayouts are the non-RFC 3339 shapes models most often produce.
+// Layouts without a zone are interpreted as UTC. Numeric day/month layouts
+// such as 01/02/2006 are left out: they read differently in the US and
+// elsewhere, so such values get a constraint_violation warning instead of a
+// guessed date.
+var looseTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	"2006-01-02",
+	"2006.01.02",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.ANSIC,
+}
+
+func parseLooseTime(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range looseTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
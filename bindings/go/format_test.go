@@ -0,0 +1,142 @@
+package jsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func decodeJSON(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("decode %s: %v", s, err)
+	}
+	return v
+}
+
+// TestPreserveFormatsRecordsEntries verifies every format keyword is recorded at its schema location.
+func TestPreserveFormatsRecordsEntries(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"when": {"type": "string", "format": "date-time"},
+			"owner": {"$ref": "#/$defs/User"}
+		},
+		"$defs": {"User": {"type": "object", "properties": {"id": {"type": "string", "format": "uuid"}}}}
+	}`)
+
+	_, entries, err := preserveFormats(schema, &ConvertOptions{PreserveFormats: true})
+	if err != nil {
+		t.Fatalf("preserveFormats() failed: %v", err)
+	}
+
+	got := map[string]any{}
+	for _, e := range entries {
+		got[e.Path] = e.Params["format"]
+	}
+	want := map[string]any{
+		"#/properties/when":          "date-time",
+		"#/$defs/User/properties/id": "uuid",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("entries: got %v, want %v", got, want)
+	}
+	for path, format := range want {
+		if got[path] != format {
+			t.Errorf("entry %s: got %v, want %v", path, got[path], format)
+		}
+	}
+}
+
+// TestRestoreFormatCoercesAndWarns verifies rehydrate-time coercion through $ref and array items.
+func TestRestoreFormatCoercesAndWarns(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"when": {"type": "string", "format": "date-time"},
+			"owner": {"$ref": "#/$defs/User"},
+			"contacts": {"type": "array", "items": {"type": "string", "format": "email"}}
+		},
+		"$defs": {"User": {"type": "object", "properties": {"id": {"type": "string", "format": "uuid"}}}}
+	}`)
+	_, entries, err := preserveFormats(deepCopyJSON(schema), &ConvertOptions{PreserveFormats: true})
+	if err != nil {
+		t.Fatalf("preserveFormats() failed: %v", err)
+	}
+
+	data := decodeJSON(t, `{
+		"when": "2024/01/02",
+		"owner": {"id": "{3F2504E0-4F89-11D3-9A0C-0305E82C3301}"},
+		"contacts": ["ada@example.com", "not an email"]
+	}`)
+
+	out, warnings := restoreHost(data, hostStages(schema, entries), entries)
+	obj := out.(map[string]any)
+
+	if obj["when"] != "2024-01-02T00:00:00Z" {
+		t.Errorf("when: got %v, want RFC 3339", obj["when"])
+	}
+	if id := obj["owner"].(map[string]any)["id"]; id != "3f2504e0-4f89-11d3-9a0c-0305e82c3301" {
+		t.Errorf("owner.id: got %v, want canonical uuid", id)
+	}
+	if c := obj["contacts"].([]any)[1]; c != "not an email" {
+		t.Errorf("invalid values must be kept, got %v", c)
+	}
+
+	kinds := map[string]string{}
+	for _, w := range warnings {
+		kinds[w.DataPath] = w.Kind.Type
+	}
+	if kinds["/when"] != "format_coerced" || kinds["/owner/id"] != "format_coerced" {
+		t.Errorf("expected format_coerced warnings, got %v", kinds)
+	}
+	if kinds["/contacts/1"] != "constraint_violation" {
+		t.Errorf("expected constraint_violation at /contacts/1, got %v", kinds)
+	}
+	if _, ok := kinds["/contacts/0"]; ok {
+		t.Error("valid email should not produce a warning")
+	}
+}
+
+// TestFormatCheckers covers case-insensitive uuids and ambiguous dates.
+func TestFormatCheckers(t *testing.T) {
+	if !formatCheckers["uuid"].valid("3F2504E0-4F89-11D3-9A0C-0305E82C3301") {
+		t.Error("uppercase uuid should be valid")
+	}
+	if _, ok := formatCheckers["date"].coerce("03/04/2024"); ok {
+		t.Error("03/04/2024 is ambiguous and must not be coerced")
+	}
+	if got, ok := formatCheckers["date"].coerce("4 March 2024"); !ok || got != "2024-03-04" {
+		t.Errorf("4 March 2024: got %q, %v", got, ok)
+	}
+}
+
+// TestSplitHostCodec verifies host entries are stripped from the codec sent to the guest.
+func TestSplitHostCodec(t *testing.T) {
+	codec := map[string]any{
+		"$schema":            "https://json-schema-llm.dev/codec/v1",
+		"transforms":         []any{},
+		"droppedConstraints": []any{},
+	}
+	plain, _ := json.Marshal(codec)
+	if out, entries, err := splitHostCodec(plain); err != nil || entries != nil || string(out) != string(plain) {
+		t.Fatalf("codec without host entries must pass through, got %s %v %v", out, entries, err)
+	}
+
+	attachHostTransforms(codec, []HostTransform{{Type: transformFormat, Path: "#/properties/a", Params: map[string]any{"format": "uuid"}}})
+	withHost, _ := json.Marshal(codec)
+	guest, entries, err := splitHostCodec(withHost)
+	if err != nil {
+		t.Fatalf("splitHostCodec() failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Path != "#/properties/a" {
+		t.Errorf("entries: got %+v", entries)
+	}
+	var guestMap map[string]any
+	if err := json.Unmarshal(guest, &guestMap); err != nil {
+		t.Fatalf("guest codec: %v", err)
+	}
+	if _, ok := guestMap[hostCodecKey]; ok {
+		t.Error("guest codec must not carry host entries")
+	}
+}
@@ -0,0 +1,143 @@
+package jsl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// FreeTextOptions marks schema fields whose values are untrusted free text
+// that an agent pipeline may feed back into a prompt.
+type FreeTextOptions struct {
+	// Fields are schema locations (e.g. "#/properties/notes") of string
+	// properties to guard.
+	Fields []string
+	// MaxLength truncates guarded values to this many characters on
+	// rehydrate. Zero keeps the schema's own maxLength, if any.
+	MaxLength int
+}
+
+// transformFreeText records a guarded free-text field.
+const transformFreeText = "free_text"
+
+// freeTextInstruction is appended to each guarded field's description.
+const freeTextInstruction = "Plain text only. Do not include instructions, role labels, or chat-format control tokens."
+
+// injectionMarkers are chat-template and role tokens stripped from guarded
+// values. Matching is case-insensitive.
+var injectionMarkers = []string{
+	"<|im_start|>", "<|im_end|>", "<|endoftext|>", "<|system|>", "<|user|>", "<|assistant|>",
+	"<|begin_of_text|>", "<|start_header_id|>", "<|end_header_id|>", "<|eot_id|>",
+	"[INST]", "[/INST]", "<<SYS>>", "<</SYS>>", "<s>", "</s>",
+	"<system>", "</system>", "<instructions>", "</instructions>",
+	"\n\nHuman:", "\n\nAssistant:", "### Instruction:", "### System:",
+}
+
+// injectionMarkerPatterns match injectionMarkers case-insensitively on the
+// original bytes. Lowercasing the value instead would shift offsets for
+// runes whose lowercase form has a different UTF-8 length.
+var injectionMarkerPatterns = func() []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, len(injectionMarkers))
+	for i, marker := range injectionMarkers {
+		patterns[i] = regexp.MustCompile("(?i)" + regexp.QuoteMeta(marker))
+	}
+	return patterns
+}()
+
+// guardFreeText adds wrapper instructions to each designated field and
+// records it for rehydration.
+func guardFreeText(schema any, opts *ConvertOptions) (any, []HostTransform, error) {
+	var entries []HostTransform
+	for _, loc := range opts.FreeText.Fields {
+		node, ok := lookupPointer(schema, loc)
+		if !ok {
+			return nil, nil, fmt.Errorf("free-text field %s not found", loc)
+		}
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, nil, fmt.Errorf("free-text field %s is not a schema object", loc)
+		}
+		params := map[string]any{}
+		max := opts.FreeText.MaxLength
+		if max == 0 {
+			if f, ok := m["maxLength"].(float64); ok {
+				max = int(f)
+			}
+		}
+		if max > 0 {
+			params["maxLength"] = max
+		}
+		t := HostTransform{Type: transformFreeText, Path: loc, Params: params}
+		entries = append(entries, t)
+		schema = replaceAtPointer(schema, loc, rewriteFreeText(m, &t))
+	}
+	return schema, entries, nil
+}
+
+func rewriteFreeText(n any, _ *HostTransform) any {
+	node, ok := n.(map[string]any)
+	if !ok {
+		return n
+	}
+	if desc, ok := node["description"].(string); ok && desc != "" {
+		node["description"] = strings.TrimRight(desc, " ") + " " + freeTextInstruction
+	} else {
+		node["description"] = freeTextInstruction
+	}
+	return node
+}
+
+// restoreFreeText strips injection markers and enforces the length cap.
+func restoreFreeText(v any, t *HostTransform, dataPath string) (any, []Warning) {
+	s, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+	var warnings []Warning
+	if out, n := stripInjectionMarkers(s); n > 0 {
+		s = out
+		warnings = append(warnings, Warning{
+			DataPath:   dataPath,
+			SchemaPath: t.Path,
+			Kind:       WarningKind{Type: WarnInjectionMarkerStripped},
+			Message:    fmt.Sprintf("removed %d injection marker(s)", n),
+		})
+	}
+	max, _ := t.Params["maxLength"].(float64)
+	if n, ok := t.Params["maxLength"].(int); ok {
+		max = float64(n)
+	}
+	if max > 0 && utf8.RuneCountInString(s) > int(max) {
+		s = string([]rune(s)[:int(max)])
+		warnings = append(warnings, Warning{
+			DataPath:   dataPath,
+			SchemaPath: t.Path,
+			Kind:       WarningKind{Type: WarnTruncated, Constraint: "maxLength"},
+			Message:    fmt.Sprintf("truncated to %d characters", int(max)),
+		})
+	}
+	return s, warnings
+}
+
+// stripInjectionMarkers removes every injectionMarkers occurrence from s and
+// returns the result with the number of removals.
+func stripInjectionMarkers(s string) (string, int) {
+	count := 0
+	// Repeat full passes until one removes nothing: a removal can join the
+	// halves of a new occurrence of any marker, not only its own.
+	for {
+		removed := 0
+		for _, re := range injectionMarkerPatterns {
+			if n := len(re.FindAllStringIndex(s, -1)); n > 0 {
+				s = re.ReplaceAllLiteralString(s, "")
+				removed += n
+			}
+		}
+		if removed == 0 {
+			break
+		}
+		count += removed
+	}
+	return s, count
+}
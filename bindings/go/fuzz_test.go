@@ -0,0 +1,127 @@
+package jsl
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// maxFuzzInput keeps single fuzz calls fast; larger inputs only exercise
+// the same parser paths.
+const maxFuzzInput = 1 << 16
+
+var fuzzSchemaSeeds = []string{
+	`{}`,
+	`true`,
+	`{"type": "object", "properties": {"name": {"type": "string"}, "age": {"type": "integer", "minimum": 0}}, "required": ["name"]}`,
+	`{"type": "object", "additionalProperties": {"type": "number"}}`,
+	`{"$defs": {"Node": {"type": "object", "properties": {"next": {"$ref": "#/$defs/Node"}}}}, "$ref": "#/$defs/Node"}`,
+	`{"anyOf": [{"type": "string"}, {"type": "array", "items": {"$ref": "#"}}]}`,
+	`{"type": ["string", "null"], "enum": ["a", "b", null]}`,
+	`{"oneOf": [{"const": 1}, {"not": {}}], "if": {"type": "string"}, "then": {"maxLength": 3}}`,
+	`{"$ref": "#/$defs/missing"}`,
+	`NOT VALID JSON`,
+}
+
+// fixtureSchemaSeeds returns the raw schemas of the conformance fixtures
+// (JSL_FIXTURES when set), or nil when they are unavailable.
+func fixtureSchemaSeeds() [][]byte {
+	f, err := readFixtures(fixturesPath())
+	if err != nil {
+		return nil
+	}
+	var seeds [][]byte
+	for _, s := range f.Suites {
+		for _, fx := range s.Fixtures {
+			if fx.Input.Schema == nil {
+				continue
+			}
+			if b, err := json.Marshal(fx.Input.Schema); err == nil {
+				seeds = append(seeds, b)
+			}
+		}
+	}
+	return seeds
+}
+
+// checkGuestResult fails unless the call either returned a JSON payload or
+// failed with a well-formed *Error. Traps, unparseable error payloads and
+// host-side ABI failures surface as other error types.
+func checkGuestResult(t *testing.T, fn string, payload []byte, err error) {
+	t.Helper()
+	if err == nil {
+		if !json.Valid(payload) {
+			t.Fatalf("%s returned an invalid JSON payload: %q", fn, payload)
+		}
+		return
+	}
+	var jslErr *Error
+	if !errors.As(err, &jslErr) {
+		t.Fatalf("%s failed without a structured error (guest trap?): %v", fn, err)
+	}
+	if jslErr.Code == "" || jslErr.Message == "" {
+		t.Fatalf("%s returned a malformed error payload: %+v", fn, jslErr)
+	}
+}
+
+// FuzzConvert feeds arbitrary schema and options bytes to jsl_convert.
+func FuzzConvert(f *testing.F) {
+	eng, err := NewSchemaLlmEngine()
+	if err != nil {
+		f.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	for _, opts := range []string{`{}`, `{"target": "gemini"}`, `{"target": "claude", "polymorphism": "flatten"}`, `{"max-depth": 2, "recursion-limit": 1}`} {
+		for _, s := range fuzzSchemaSeeds {
+			f.Add([]byte(s), []byte(opts))
+		}
+	}
+	for _, s := range fixtureSchemaSeeds() {
+		f.Add(s, []byte(`{}`))
+	}
+
+	f.Fuzz(func(t *testing.T, schema, opts []byte) {
+		if len(schema)+len(opts) > maxFuzzInput {
+			t.Skip()
+		}
+		payload, err := eng.callJsl("jsl_convert", schema, opts)
+		checkGuestResult(t, "jsl_convert", payload, err)
+	})
+}
+
+// FuzzRehydrate feeds arbitrary data against codecs of the seed schemas,
+// and arbitrary codecs and schemas, to jsl_rehydrate.
+func FuzzRehydrate(f *testing.F) {
+	eng, err := NewSchemaLlmEngine()
+	if err != nil {
+		f.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	for _, s := range fuzzSchemaSeeds {
+		payload, err := eng.callJsl("jsl_convert", []byte(s), []byte(`{}`))
+		if err != nil {
+			continue
+		}
+		var converted struct {
+			Codec json.RawMessage `json:"codec"`
+		}
+		if json.Unmarshal(payload, &converted) != nil {
+			continue
+		}
+		for _, data := range []string{`{}`, `null`, `{"name": "x", "age": -1}`, `[{"key": "a", "value": 1}]`, `[1, "two", {"three": 3}]`, `"{\"a\":1}"`} {
+			f.Add([]byte(data), []byte(converted.Codec), []byte(s))
+		}
+	}
+	f.Add([]byte(`{}`), []byte(`{"$schema": "https://json-schema-llm.dev/codec/v1", "transforms": [{"type": "map_to_array", "path": "#/properties/x", "keyField": "key"}], "droppedConstraints": []}`), []byte(`{}`))
+	f.Add([]byte(`{}`), []byte(`NOT A CODEC`), []byte(`{}`))
+
+	f.Fuzz(func(t *testing.T, data, codec, schema []byte) {
+		if len(data)+len(codec)+len(schema) > maxFuzzInput {
+			t.Skip()
+		}
+		payload, err := eng.callJsl("jsl_rehydrate", data, codec, schema)
+		checkGuestResult(t, "jsl_rehydrate", payload, err)
+	})
+}
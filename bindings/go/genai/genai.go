@@ -0,0 +1,396 @@
+// Package genai converts jsl conversion results into the google.golang.org/genai
+// *Schema used by GenerateContentConfig.ResponseSchema, downcasting JSON
+// Schema to the OpenAPI subset Gemini accepts.
+//
+// It is a module of its own, so the core binding does not pin a genai
+// version (and its gRPC and Google Cloud dependencies) on its importers.
+package genai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	sdk "google.golang.org/genai"
+)
+
+// supportedFormats are the formats Gemini accepts, by type.
+var supportedFormats = map[sdk.Type][]string{
+	sdk.TypeString:  {"date-time", "enum"},
+	sdk.TypeInteger: {"int32", "int64"},
+	sdk.TypeNumber:  {"float", "double"},
+}
+
+// ignoredKeywords carry no meaning for the response schema and are dropped
+// without being reported.
+var ignoredKeywords = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "$anchor": true,
+	"additionalProperties": true, "examples": true, "readOnly": true,
+	"writeOnly": true, "deprecated": true,
+}
+
+// ResponseSchema converts the converted schema of r. Properties keep the
+// order of r.SchemaJSON (see jsl.ConvertOptions.PropertyOrder) as
+// PropertyOrdering. Keywords Gemini cannot express are dropped and listed
+// as schema pointers (e.g. "#/properties/n/multipleOf").
+func ResponseSchema(r *jsl.ConvertResult) (*sdk.Schema, []string, error) {
+	if r == nil || r.Schema == nil {
+		return nil, nil, fmt.Errorf("genai: convert result has no schema")
+	}
+	if len(r.SchemaJSON) > 0 {
+		root, err := decodeOrdered(json.NewDecoder(bytes.NewReader(r.SchemaJSON)))
+		if err != nil {
+			return nil, nil, fmt.Errorf("genai: decode schema: %w", err)
+		}
+		return convertRoot(root)
+	}
+	return FromJSONSchema(r.Schema)
+}
+
+// FromJSONSchema converts a JSON Schema object like ResponseSchema, with
+// properties in sorted order.
+func FromJSONSchema(schema map[string]any) (*sdk.Schema, []string, error) {
+	return convertRoot(orderedFrom(schema))
+}
+
+func convertRoot(root any) (*sdk.Schema, []string, error) {
+	obj, ok := root.(*object)
+	if !ok {
+		return nil, nil, fmt.Errorf("genai: root schema must be an object")
+	}
+	c := &converter{}
+	s, err := c.convert(obj, "#")
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, c.dropped, nil
+}
+
+type converter struct {
+	dropped []string
+}
+
+func (c *converter) drop(loc, kw string) {
+	c.dropped = append(c.dropped, loc+"/"+escape(kw))
+}
+
+func (c *converter) convert(node *object, loc string) (*sdk.Schema, error) {
+	s := &sdk.Schema{}
+	if ref, ok := node.m["$ref"]; ok {
+		return nil, fmt.Errorf("genai: %s: unresolved $ref %v", loc, ref)
+	}
+	if err := c.convertType(node, loc, s); err != nil {
+		return nil, err
+	}
+	for _, kw := range node.keys {
+		v := node.m[kw]
+		switch kw {
+		case "type", "anyOf", "oneOf":
+			// Handled by convertType.
+		case "title":
+			s.Title, _ = v.(string)
+		case "description":
+			s.Description, _ = v.(string)
+		case "default":
+			s.Default = plain(v)
+		case "example":
+			s.Example = plain(v)
+		case "pattern":
+			s.Pattern, _ = v.(string)
+		case "format":
+			f, _ := v.(string)
+			if containsString(supportedFormats[s.Type], f) {
+				s.Format = f
+			} else {
+				c.drop(loc, kw)
+			}
+		case "enum", "const":
+			values, _ := v.([]any)
+			if kw == "const" {
+				values = []any{v}
+			}
+			if strs, ok := allStrings(values); ok {
+				s.Enum = strs
+				if s.Type == "" {
+					s.Type = sdk.TypeString
+				}
+			} else {
+				c.drop(loc, kw)
+			}
+		case "minimum", "maximum":
+			n, ok := v.(float64)
+			if !ok {
+				c.drop(loc, kw)
+				continue
+			}
+			if kw == "minimum" {
+				s.Minimum = &n
+			} else {
+				s.Maximum = &n
+			}
+		case "minLength", "maxLength", "minItems", "maxItems", "minProperties", "maxProperties":
+			n, ok := v.(float64)
+			if !ok || n != math.Trunc(n) {
+				c.drop(loc, kw)
+				continue
+			}
+			i := int64(n)
+			switch kw {
+			case "minLength":
+				s.MinLength = &i
+			case "maxLength":
+				s.MaxLength = &i
+			case "minItems":
+				s.MinItems = &i
+			case "maxItems":
+				s.MaxItems = &i
+			case "minProperties":
+				s.MinProperties = &i
+			case "maxProperties":
+				s.MaxProperties = &i
+			}
+		case "properties":
+			props, ok := v.(*object)
+			if !ok {
+				c.drop(loc, kw)
+				continue
+			}
+			s.Properties = make(map[string]*sdk.Schema, len(props.keys))
+			for _, name := range props.keys {
+				child, ok := props.m[name].(*object)
+				if !ok {
+					c.drop(loc+"/properties", name)
+					continue
+				}
+				cs, err := c.convert(child, loc+"/properties/"+escape(name))
+				if err != nil {
+					return nil, err
+				}
+				s.Properties[name] = cs
+				s.PropertyOrdering = append(s.PropertyOrdering, name)
+			}
+		case "required":
+			s.Required, _ = allStrings(asSlice(v))
+		case "items":
+			child, ok := v.(*object)
+			if !ok {
+				c.drop(loc, kw)
+				continue
+			}
+			cs, err := c.convert(child, loc+"/items")
+			if err != nil {
+				return nil, err
+			}
+			s.Items = cs
+		default:
+			if !ignoredKeywords[kw] {
+				c.drop(loc, kw)
+			}
+		}
+	}
+	return s, nil
+}
+
+// convertType maps `type` (a string or a list) and anyOf/oneOf. A null
+// member becomes Nullable; several non-null types become AnyOf branches.
+func (c *converter) convertType(node *object, loc string, s *sdk.Schema) error {
+	var types []string
+	switch t := node.m["type"].(type) {
+	case string:
+		types = []string{t}
+	case []any:
+		types, _ = allStrings(t)
+	}
+	var nonNull []string
+	for _, t := range types {
+		if t == "null" {
+			s.Nullable = boolPtr(true)
+		} else {
+			nonNull = append(nonNull, t)
+		}
+	}
+	switch len(nonNull) {
+	case 0:
+	case 1:
+		s.Type = openAPIType(nonNull[0])
+	default:
+		for _, t := range nonNull {
+			s.AnyOf = append(s.AnyOf, &sdk.Schema{Type: openAPIType(t)})
+		}
+	}
+
+	for _, kw := range []string{"anyOf", "oneOf"} {
+		branches, ok := node.m[kw].([]any)
+		if !ok {
+			continue
+		}
+		for i, b := range branches {
+			bo, ok := b.(*object)
+			if !ok {
+				c.drop(loc+"/"+kw, fmt.Sprint(i))
+				continue
+			}
+			if t, _ := bo.m["type"].(string); t == "null" && len(bo.keys) == 1 {
+				s.Nullable = boolPtr(true)
+				continue
+			}
+			bs, err := c.convert(bo, fmt.Sprintf("%s/%s/%d", loc, kw, i))
+			if err != nil {
+				return err
+			}
+			s.AnyOf = append(s.AnyOf, bs)
+		}
+	}
+	// A single remaining branch is the schema itself.
+	if len(s.AnyOf) == 1 && s.Type == "" {
+		only := s.AnyOf[0]
+		only.Nullable = s.Nullable
+		*s = *only
+	}
+	return nil
+}
+
+func openAPIType(t string) sdk.Type {
+	switch t {
+	case "string":
+		return sdk.TypeString
+	case "number":
+		return sdk.TypeNumber
+	case "integer":
+		return sdk.TypeInteger
+	case "boolean":
+		return sdk.TypeBoolean
+	case "array":
+		return sdk.TypeArray
+	case "object":
+		return sdk.TypeObject
+	}
+	return sdk.TypeUnspecified
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func escape(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+func allStrings(values []any) ([]string, bool) {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+		out = append(out, s)
+	}
+	return out, true
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// object is a decoded JSON object that remembers its key order.
+type object struct {
+	keys []string
+	m    map[string]any
+}
+
+// decodeOrdered decodes one JSON value, keeping object key order.
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			o := &object{m: map[string]any{}}
+			for dec.More() {
+				kt, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				k, _ := kt.(string)
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				if _, dup := o.m[k]; !dup {
+					o.keys = append(o.keys, k)
+				}
+				o.m[k] = v
+			}
+			_, err := dec.Token()
+			return o, err
+		case '[':
+			out := []any{}
+			for dec.More() {
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, v)
+			}
+			_, err := dec.Token()
+			return out, err
+		}
+	}
+	return tok, nil
+}
+
+// orderedFrom wraps a decoded JSON tree, ordering object keys.
+func orderedFrom(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		o := &object{m: make(map[string]any, len(t))}
+		for k, c := range t {
+			o.keys = append(o.keys, k)
+			o.m[k] = orderedFrom(c)
+		}
+		sort.Strings(o.keys)
+		return o
+	case []any:
+		out := make([]any, len(t))
+		for i, c := range t {
+			out[i] = orderedFrom(c)
+		}
+		return out
+	}
+	return v
+}
+
+// plain unwraps an ordered tree into plain maps, for values such as
+// defaults that genai carries verbatim.
+func plain(v any) any {
+	switch t := v.(type) {
+	case *object:
+		out := make(map[string]any, len(t.keys))
+		for k, c := range t.m {
+			out[k] = plain(c)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, c := range t {
+			out[i] = plain(c)
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,89 @@
+package genai
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	sdk "google.golang.org/genai"
+)
+
+// TestResponseSchema verifies types, nullability, enums and property order
+// are downcast and unsupported keywords are reported.
+func TestResponseSchema(t *testing.T) {
+	schemaJSON := `{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"zeta": {"type": ["string", "null"], "format": "email"},
+			"alpha": {"anyOf": [{"type": "integer", "minimum": 0, "multipleOf": 2}, {"type": "null"}]},
+			"tags": {"type": "array", "items": {"enum": ["a", "b"]}, "maxItems": 3},
+			"kind": {"const": "user"}
+		},
+		"required": ["zeta", "alpha", "tags", "kind"]
+	}`
+	var m map[string]any
+	if err := json.Unmarshal([]byte(schemaJSON), &m); err != nil {
+		t.Fatal(err)
+	}
+	got, dropped, err := ResponseSchema(&jsl.ConvertResult{Schema: m, SchemaJSON: json.RawMessage(schemaJSON)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	zero := 0.0
+	three := int64(3)
+	yes := true
+	want := &sdk.Schema{
+		Type: sdk.TypeObject,
+		Properties: map[string]*sdk.Schema{
+			"zeta":  {Type: sdk.TypeString, Nullable: &yes},
+			"alpha": {Type: sdk.TypeInteger, Minimum: &zero, Nullable: &yes},
+			"tags":  {Type: sdk.TypeArray, Items: &sdk.Schema{Type: sdk.TypeString, Enum: []string{"a", "b"}}, MaxItems: &three},
+			"kind":  {Type: sdk.TypeString, Enum: []string{"user"}},
+		},
+		PropertyOrdering: []string{"zeta", "alpha", "tags", "kind"},
+		Required:         []string{"zeta", "alpha", "tags", "kind"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		gb, _ := json.Marshal(got)
+		wb, _ := json.Marshal(want)
+		t.Errorf("schema =\n%s\nwant\n%s", gb, wb)
+	}
+	if want := []string{"#/properties/zeta/format", "#/properties/alpha/anyOf/0/multipleOf"}; !reflect.DeepEqual(dropped, want) {
+		t.Errorf("dropped = %v, want %v", dropped, want)
+	}
+}
+
+// TestFromJSONSchemaMultipleTypes verifies a type list becomes anyOf and
+// properties are sorted without SchemaJSON.
+func TestFromJSONSchemaMultipleTypes(t *testing.T) {
+	got, _, err := FromJSONSchema(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"b": map[string]any{"type": []any{"string", "integer"}},
+			"a": map[string]any{"type": "boolean"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.PropertyOrdering, []string{"a", "b"}) {
+		t.Errorf("ordering = %v", got.PropertyOrdering)
+	}
+	b := got.Properties["b"]
+	if b.Type != "" || len(b.AnyOf) != 2 || b.AnyOf[0].Type != sdk.TypeString || b.AnyOf[1].Type != sdk.TypeInteger {
+		t.Errorf("b = %+v", b)
+	}
+}
+
+// TestResponseSchemaErrors verifies unresolved refs and empty results fail.
+func TestResponseSchemaErrors(t *testing.T) {
+	if _, _, err := ResponseSchema(&jsl.ConvertResult{}); err == nil {
+		t.Error("expected an error for a result without a schema")
+	}
+	_, _, err := FromJSONSchema(map[string]any{"properties": map[string]any{"n": map[string]any{"$ref": "#/$defs/N"}}})
+	if err == nil {
+		t.Error("expected an error for an unresolved $ref")
+	}
+}
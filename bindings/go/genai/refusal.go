@@ -0,0 +1,34 @@
+package genai
+
+import (
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	sdk "google.golang.org/genai"
+)
+
+// blockReasons are candidate finish reasons that mean the output was
+// withheld rather than completed.
+var blockReasons = map[sdk.FinishReason]bool{
+	sdk.FinishReasonSafety:            true,
+	sdk.FinishReasonRecitation:        true,
+	sdk.FinishReasonBlocklist:         true,
+	sdk.FinishReasonProhibitedContent: true,
+	sdk.FinishReasonSPII:              true,
+}
+
+// DetectRefusal returns a *jsl.RefusalError when r's prompt was blocked or
+// its first candidate was withheld, nil otherwise. It is the typed
+// counterpart of jsl.DetectRefusal for responses already decoded by the
+// SDK.
+func DetectRefusal(r *sdk.GenerateContentResponse) error {
+	if r == nil {
+		return nil
+	}
+	if fb := r.PromptFeedback; fb != nil && fb.BlockReason != "" {
+		return &jsl.RefusalError{Provider: jsl.ProviderGemini, Reason: string(fb.BlockReason), Text: fb.BlockReasonMessage}
+	}
+	if len(r.Candidates) > 0 && r.Candidates[0] != nil && blockReasons[r.Candidates[0].FinishReason] {
+		c := r.Candidates[0]
+		return &jsl.RefusalError{Provider: jsl.ProviderGemini, Reason: string(c.FinishReason), Text: c.FinishMessage}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package genai
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	sdk "google.golang.org/genai"
+)
+
+// TestDetectRefusal verifies SDK responses are classified like the raw
+// bodies jsl.DetectRefusal reads.
+func TestDetectRefusal(t *testing.T) {
+	for name, tc := range map[string]struct {
+		body   string
+		reason string
+	}{
+		"blocked prompt": {`{"promptFeedback":{"blockReason":"SAFETY","blockReasonMessage":"unsafe"}}`, "SAFETY"},
+		"withheld":       {`{"candidates":[{"finishReason":"RECITATION","finishMessage":"quoted"}]}`, "RECITATION"},
+		"answer":         {`{"candidates":[{"finishReason":"STOP","content":{"parts":[{"text":"{}"}]}}]}`, ""},
+		"empty":          {`{}`, ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var r sdk.GenerateContentResponse
+			if err := json.Unmarshal([]byte(tc.body), &r); err != nil {
+				t.Fatal(err)
+			}
+			err := DetectRefusal(&r)
+			raw := jsl.DetectRefusal(jsl.ProviderGemini, []byte(tc.body))
+			if tc.reason == "" {
+				if err != nil || raw != nil {
+					t.Errorf("DetectRefusal() = %v, raw body gives %v; want nil", err, raw)
+				}
+				return
+			}
+			var re *jsl.RefusalError
+			if !errors.As(err, &re) || re.Reason != tc.reason || !errors.Is(err, jsl.ErrModelRefusal) {
+				t.Fatalf("DetectRefusal() = %v, want a %s refusal", err, tc.reason)
+			}
+			if raw == nil || err.Error() != raw.Error() {
+				t.Errorf("DetectRefusal() = %v, raw body gives %v", err, raw)
+			}
+		})
+	}
+}
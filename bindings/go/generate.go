@@ -0,0 +1,138 @@
+package jsl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// LLMRequest is one structured-output call Generate makes.
+type LLMRequest struct {
+	// Name names the response format (OpenAI's json_schema name, a tool
+	// name); it matches ^[a-zA-Z0-9_-]{1,64}$.
+	Name   string
+	Prompt string
+	// Schema is the converted schema to constrain the output with, and
+	// SchemaJSON its encoding.
+	Schema     map[string]any
+	SchemaJSON json.RawMessage
+}
+
+// LLMClient calls a model with structured output and returns the JSON text
+// it produced. Wrap ErrModelRefusal (see DetectRefusal) when the model
+// declines, so callers can tell refusals from pipeline failures.
+type LLMClient interface {
+	Complete(ctx context.Context, req LLMRequest) (string, error)
+}
+
+// LLMClientFunc adapts a function to LLMClient.
+type LLMClientFunc func(ctx context.Context, req LLMRequest) (string, error)
+
+// Complete calls f.
+func (f LLMClientFunc) Complete(ctx context.Context, req LLMRequest) (string, error) {
+	return f(ctx, req)
+}
+
+// GenerateOptions controls Generate. The zero value converts with the
+// engine's defaults and validates the result.
+type GenerateOptions struct {
+	Convert *ConvertOptions
+	// Rehydrate cleans up the raw model output (fences, truncation, special
+	// numbers) before rehydration; see RehydrateOptions.
+	Rehydrate *RehydrateOptions
+	// Name overrides LLMRequest.Name, which defaults to T's type name.
+	Name string
+	// SkipValidation skips validating the rehydrated data against T's
+	// schema; decoding into T still has to succeed.
+	SkipValidation bool
+}
+
+// invalidNameChars are the characters LLMRequest.Name may not contain.
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// Generate asks a model for a T: it converts SchemaOf[T], sends prompt and
+// the converted schema through client, rehydrates the response, validates
+// it against T's schema and decodes it strictly into T. The warnings are
+// Rehydrate's. A validation failure wraps a *jsonschema.ValidationError.
+//
+// When e is a *SchemaLlmEngine its guest calls run under ctx (see
+// WithContext); with a convert cache (WithConvertCache) repeated calls for
+// the same T convert once.
+func Generate[T any](ctx context.Context, e ConvertRehydrator, client LLMClient, prompt string, opts *GenerateOptions) (T, []Warning, error) {
+	var out T
+	if opts == nil {
+		opts = &GenerateOptions{}
+	}
+	if eng, ok := e.(*SchemaLlmEngine); ok {
+		e = eng.WithContext(ctx)
+	}
+	schema, err := SchemaOf[T]()
+	if err != nil {
+		return out, nil, err
+	}
+	converted, err := e.Convert(schema, opts.Convert)
+	if err != nil {
+		return out, nil, fmt.Errorf("convert %T schema: %w", out, err)
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = generateName(reflect.TypeOf((*T)(nil)).Elem())
+	}
+	text, err := client.Complete(ctx, LLMRequest{Name: name, Prompt: prompt, Schema: converted.Schema, SchemaJSON: converted.SchemaJSON})
+	if err != nil {
+		return out, nil, fmt.Errorf("llm: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return out, nil, err
+	}
+
+	result, err := e.RehydrateWithOptions(json.RawMessage(text), converted.Codec, schema, opts.Rehydrate)
+	if err != nil {
+		return out, nil, fmt.Errorf("rehydrate: %w", err)
+	}
+	if !opts.SkipValidation {
+		v := converted.validator
+		if v == nil {
+			// Results from other Converters carry no validator.
+			source, err := json.Marshal(schema)
+			if err != nil {
+				return out, result.Warnings, fmt.Errorf("marshal schema: %w", err)
+			}
+			v = &lazyValidator{source: source}
+		}
+		validator, err := v.compile()
+		if err != nil {
+			return out, result.Warnings, err
+		}
+		if err := validator.Validate(result.Data); err != nil {
+			return out, result.Warnings, fmt.Errorf("validate: %w", err)
+		}
+	}
+	b, err := json.Marshal(result.Data)
+	if err != nil {
+		return out, result.Warnings, fmt.Errorf("marshal rehydrated data: %w", err)
+	}
+	if err := decodeStrict(b, &out); err != nil {
+		return out, result.Warnings, fmt.Errorf("decode into %T: %w", out, err)
+	}
+	return out, result.Warnings, nil
+}
+
+// generateName derives a response format name from t: its type name with
+// disallowed characters replaced, or "response" for unnamed types.
+func generateName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	name := invalidNameChars.ReplaceAllString(t.Name(), "_")
+	if name == "" || name == "_" {
+		return "response"
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
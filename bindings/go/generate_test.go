@@ -0,0 +1,132 @@
+package jsl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// identityEngine converts schemas unchanged and returns model output as
+// given, so Generate's own steps can be tested without the guest.
+type identityEngine struct{}
+
+func (identityEngine) Convert(schema any, _ *ConvertOptions) (*ConvertResult, error) {
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return &ConvertResult{Schema: m, SchemaJSON: b}, nil
+}
+
+func (e identityEngine) Rehydrate(data, codec, schema any) (*RehydrateResult, error) {
+	return e.RehydrateWithOptions(data, codec, schema, nil)
+}
+
+func (identityEngine) RehydrateWithOptions(data, _, _ any, _ *RehydrateOptions) (*RehydrateResult, error) {
+	var v any
+	if err := json.Unmarshal(data.(json.RawMessage), &v); err != nil {
+		return nil, err
+	}
+	return &RehydrateResult{Data: v}, nil
+}
+
+type generateOrder struct {
+	ID    string   `json:"id"`
+	Qty   int      `json:"qty" jsonschema:"minimum=1"`
+	Notes []string `json:"notes,omitempty"`
+}
+
+func replying(text string, seen *LLMRequest) LLMClient {
+	return LLMClientFunc(func(_ context.Context, req LLMRequest) (string, error) {
+		if seen != nil {
+			*seen = req
+		}
+		return text, nil
+	})
+}
+
+func TestGenerate(t *testing.T) {
+	var req LLMRequest
+	got, _, err := Generate[generateOrder](context.Background(), identityEngine{}, replying(`{"id":"a1","qty":2}`, &req), "order something", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "a1" || got.Qty != 2 {
+		t.Errorf("Generate() = %+v", got)
+	}
+	if req.Name != "generateOrder" || req.Prompt != "order something" || req.Schema["type"] != "object" || len(req.SchemaJSON) == 0 {
+		t.Errorf("request = %+v", req)
+	}
+}
+
+// TestGenerateValidation verifies output violating T's schema fails with
+// a *jsonschema.ValidationError unless validation is skipped.
+func TestGenerateValidation(t *testing.T) {
+	client := replying(`{"id":"a1","qty":0}`, nil)
+	_, _, err := Generate[generateOrder](context.Background(), identityEngine{}, client, "", nil)
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Generate() error = %v, want a validation error", err)
+	}
+	got, _, err := Generate[generateOrder](context.Background(), identityEngine{}, client, "", &GenerateOptions{SkipValidation: true})
+	if err != nil || got.Qty != 0 {
+		t.Errorf("Generate() with SkipValidation = %+v, %v", got, err)
+	}
+}
+
+// TestGenerateErrors verifies client errors keep their identity and output
+// T cannot hold fails decoding.
+func TestGenerateErrors(t *testing.T) {
+	refused := LLMClientFunc(func(context.Context, LLMRequest) (string, error) {
+		return "", &RefusalError{Provider: ProviderOpenAI, Reason: "refusal"}
+	})
+	if _, _, err := Generate[generateOrder](context.Background(), identityEngine{}, refused, "", nil); !errors.Is(err, ErrModelRefusal) {
+		t.Errorf("Generate() error = %v, want %v", err, ErrModelRefusal)
+	}
+	extra := replying(`{"id":"a1","qty":1,"extra":true}`, nil)
+	if _, _, err := Generate[generateOrder](context.Background(), identityEngine{}, extra, "", &GenerateOptions{SkipValidation: true}); err == nil {
+		t.Error("Generate() decoded an unknown field")
+	}
+}
+
+// TestGenerateEngine runs the pipeline through the guest.
+func TestGenerateEngine(t *testing.T) {
+	eng, err := NewSchemaLlmEngine()
+	if err != nil {
+		t.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	type tagged struct {
+		Labels map[string]int `json:"labels"`
+	}
+	var req LLMRequest
+	got, _, err := Generate[tagged](context.Background(), eng, replying(`{"labels":[{"key":"a","value":1}]}`, &req), "", &GenerateOptions{Name: "labels"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Labels["a"] != 1 || req.Name != "labels" {
+		t.Errorf("Generate() = %+v, request name %q", got, req.Name)
+	}
+}
+
+func TestGenerateName(t *testing.T) {
+	for _, tc := range []struct {
+		got, want string
+	}{
+		{generateName(reflect.TypeOf(&generateOrder{})), "generateOrder"},
+		{generateName(reflect.TypeOf(map[string]any{})), "response"},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("generateName() = %q, want %q", tc.got, tc.want)
+		}
+	}
+}
@@ -1,5 +1,24 @@
 module github.com/dotslashderek/json-schema-llm/bindings/go
 
-go 1.22
+go 1.23.0
 
 require github.com/tetratelabs/wazero v1.8.2
+
+require golang.org/x/text v0.27.0
+
+require (
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.1
+	go.opentelemetry.io/otel v1.32.0
+	go.opentelemetry.io/otel/sdk v1.32.0
+	go.opentelemetry.io/otel/trace v1.32.0
+	google.golang.org/protobuf v1.34.2
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/otel/metric v1.32.0 // indirect
+	golang.org/x/sys v0.34.0 // indirect
+)
@@ -0,0 +1,364 @@
+package jsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// hostCodecKey is the codec field carrying transforms recorded by host-side
+// (Go) passes. The guest deserializes codecs leniently and would ignore it,
+// but Rehydrate strips it before the guest call regardless.
+const hostCodecKey = "hostTransforms"
+
+// HostTransform is a codec entry recorded by a host-side pass — a
+// transformation the Go binding applies around the WASI engine rather than
+// inside it. Path is the schema location the entry applies to, in the shape
+// the schema had when the pass ran.
+type HostTransform struct {
+	ID     string         `json:"id,omitempty"`
+	Type   string         `json:"type"`
+	Path   string         `json:"path"`
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// hostPass is a host-side conversion step run on the decoded source schema
+// before it is handed to the guest. Passes run in hostPasses order; each sees
+// the output of the previous one.
+type hostPass struct {
+	name    string
+	enabled func(opts *ConvertOptions) bool
+	run     func(schema any, opts *ConvertOptions) (any, []HostTransform, error)
+	// applies, when set, lets an enabled pass skip schemas it cannot
+	// affect without decoding them.
+	applies func(schemaBytes []byte) bool
+}
+
+// hostHandler undoes (or checks) a HostTransform during rehydration.
+type hostHandler struct {
+	// rewrite re-applies the recorded schema change to the node at t.Path so
+	// Rehydrate can derive the schema the guest converted. Nil for entries
+	// that only record a check.
+	rewrite func(node any, t *HostTransform) any
+	// restore is called with each rehydrated value found at t.Path and
+	// returns its replacement plus any warnings.
+	restore func(v any, t *HostTransform, dataPath string) (any, []Warning)
+	// convertWarning reports, at convert time, a feature the entry degraded.
+	convertWarning func(t *HostTransform) (Warning, bool)
+}
+
+// hostPasses is the ordered host-side pipeline. rename runs last so every
+// pass before it sees the caller's property names, which Overrides keys and
+// FreeText.Fields are written against.
+var hostPasses = []hostPass{
+	{name: "dialect", enabled: func(o *ConvertOptions) bool { return o.Dialect != DialectJSONSchema }, run: normalizeDialect},
+	{name: "boolean_schemas", enabled: func(*ConvertOptions) bool { return true }, run: normalizeBooleanSchemas, applies: hasBooleanLiteral},
+	{name: "overrides", enabled: hasStringifyOverride, run: stringifyOverrides},
+	{name: "type_inference", enabled: func(o *ConvertOptions) bool { return o.InferOpaqueTypes }, run: inferTypes},
+	{name: "descriptions", enabled: func(o *ConvertOptions) bool { return o.MaxDescriptionLength > 0 }, run: truncateDescriptions},
+	{name: "annotations", enabled: func(o *ConvertOptions) bool { return len(o.StripAnnotations) > 0 }, run: stripAnnotations},
+	{name: "format_preservation", enabled: func(o *ConvertOptions) bool { return o.PreserveFormats }, run: preserveFormats},
+	{name: "property_names", enabled: func(o *ConvertOptions) bool { return o.ValidatePropertyNames }, run: recordPropertyNames},
+	{name: "numeric_bounds", enabled: func(o *ConvertOptions) bool { return o.DescribeNumericBounds }, run: describeNumericBounds},
+	{name: "large_enums", enabled: func(o *ConvertOptions) bool { return o.MaxEnumValues > 0 }, run: degradeLargeEnums},
+	{name: "const", enabled: func(o *ConvertOptions) bool { return o.ConstStrategy != ConstStrategyNone }, run: rewriteConsts},
+	{name: "free_text", enabled: func(o *ConvertOptions) bool { return o.FreeText != nil && len(o.FreeText.Fields) > 0 }, run: guardFreeText},
+	{name: "conditionals", enabled: func(o *ConvertOptions) bool { return o.ConditionalStrategy != ConditionalStrategyNone }, run: rewriteConditionals},
+	{name: "not", enabled: func(o *ConvertOptions) bool { return o.StripNot }, run: stripNot},
+	{name: "optional", enabled: func(o *ConvertOptions) bool { return o.OptionalStrategy != OptionalNullable }, run: applyOptionalStrategy},
+	{name: "tuples", enabled: func(o *ConvertOptions) bool { return o.TupleStrategy != TupleStrategyNone }, run: transpileTuples},
+	{name: "split_objects", enabled: func(o *ConvertOptions) bool { return splitThreshold(o) > 0 }, run: splitLargeObjects},
+	{name: "flatten", enabled: func(o *ConvertOptions) bool { return o.MaxNestingDepth > 0 }, run: flattenDeepNesting},
+	{name: "rename", enabled: func(o *ConvertOptions) bool { return o.SanitizePropertyNames }, run: renameUnsafeProperties},
+}
+
+// hostHandlers maps HostTransform.Type to its rehydration handler.
+var hostHandlers = map[string]hostHandler{
+	transformDialect:             {rewrite: rewriteDialect},
+	transformOverrideStringify:   {rewrite: rewriteOverrideStringify},
+	transformNodePlugin:          {rewrite: rewriteNodePlugin},
+	transformFormat:              {restore: restoreFormat},
+	transformNumericBounds:       {restore: restoreNumericBounds},
+	transformTypeInference:       {rewrite: rewriteInferredType, convertWarning: inferConvertWarning},
+	transformPropertyNames:       {restore: restorePropertyNames},
+	transformConst:               {rewrite: rewriteConst, restore: restoreConst},
+	transformFreeText:            {rewrite: rewriteFreeText, restore: restoreFreeText},
+	transformConditional:         {rewrite: rewriteConditional, restore: restoreConditional, convertWarning: conditionalConvertWarning},
+	transformNot:                 {rewrite: rewriteNot, restore: restoreNot},
+	transformOptionalKeepNull:    {restore: restoreOptionalKeepNull},
+	transformOptionalDrop:        {rewrite: rewriteOptionalDrop},
+	transformTupleObject:         {rewrite: rewriteTupleObject, restore: restoreTupleObject},
+	transformTupleArray:          {rewrite: rewriteTupleArray, restore: restoreTupleArray},
+	transformFlatten:             {rewrite: rewriteFlatten, restore: restoreFlatten},
+	transformDescription:         {rewrite: rewriteDescription},
+	transformAnnotationsStripped: {rewrite: rewriteAnnotationsStripped, convertWarning: annotationsConvertWarning},
+	transformLargeEnum:           {rewrite: rewriteLargeEnum, restore: restoreLargeEnum},
+	transformBooleanSchema:       {rewrite: rewriteBooleanSchema, convertWarning: booleanSchemaConvertWarning},
+	transformRename:              {rewrite: rewriteRename, restore: restoreRename, convertWarning: renameConvertWarning},
+	transformSplitObject:         {rewrite: rewriteSplitObject, restore: restoreSplitObject, convertWarning: splitObjectConvertWarning},
+}
+
+// runHostPasses applies every enabled host pass to schemaBytes. It returns the
+// original bytes untouched when no pass is enabled.
+func runHostPasses(schemaBytes []byte, opts *ConvertOptions, trace *passTrace) ([]byte, []HostTransform, error) {
+	if opts == nil {
+		opts = &ConvertOptions{}
+	}
+	var active []hostPass
+	for _, p := range hostPasses {
+		if p.enabled(opts) && !opts.passDisabled(p.name) && (p.applies == nil || p.applies(schemaBytes)) {
+			active = append(active, p)
+		}
+	}
+	if len(active) == 0 {
+		return schemaBytes, nil, nil
+	}
+
+	var tree any
+	if err := json.Unmarshal(schemaBytes, &tree); err != nil {
+		return nil, nil, fmt.Errorf("decode schema: %w", err)
+	}
+	var all []HostTransform
+	for _, p := range active {
+		var entries []HostTransform
+		var err error
+		tree, entries, err = p.run(tree, opts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("host pass %s: %w", p.name, err)
+		}
+		trace.record(p.name, tree)
+		for i := range entries {
+			entries[i].ID = EntryID(entries[i].Type, entries[i].Path)
+		}
+		all = append(all, entries...)
+	}
+	out, err := json.Marshal(tree)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode schema: %w", err)
+	}
+	return out, all, nil
+}
+
+// attachHostTransforms records host entries on a decoded guest codec.
+func attachHostTransforms(codec any, entries []HostTransform) any {
+	if len(entries) == 0 {
+		return codec
+	}
+	if m, ok := codec.(map[string]any); ok {
+		m[hostCodecKey] = entries
+	}
+	return codec
+}
+
+// hostConvertWarnings collects convert-time warnings for recorded entries.
+func hostConvertWarnings(entries []HostTransform) []Warning {
+	var warnings []Warning
+	for i := range entries {
+		if h := hostHandlers[entries[i].Type]; h.convertWarning != nil {
+			if w, ok := h.convertWarning(&entries[i]); ok {
+				w.EntryID = EntryID(entries[i].Type, entries[i].Path)
+				warnings = append(warnings, w)
+			}
+		}
+	}
+	return warnings
+}
+
+// splitHostCodec separates host entries from a marshaled codec, returning the
+// guest-only codec bytes. Codecs without host entries are returned as-is.
+func splitHostCodec(codecBytes []byte) ([]byte, []HostTransform, error) {
+	if !bytes.Contains(codecBytes, []byte(`"`+hostCodecKey+`"`)) {
+		return codecBytes, nil, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(codecBytes, &fields); err != nil {
+		// Not an object — let the guest report the malformed codec.
+		return codecBytes, nil, nil
+	}
+	raw, ok := fields[hostCodecKey]
+	if !ok {
+		return codecBytes, nil, nil
+	}
+	var entries []HostTransform
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, nil, fmt.Errorf("decode %s: %w", hostCodecKey, err)
+	}
+	for i := range entries {
+		if _, ok := hostHandlers[entries[i].Type]; !ok && entries[i].Type != CustomTransformType && !slimTransformTypes[entries[i].Type] {
+			return nil, nil, fmt.Errorf("unknown host transform type %q at %s", entries[i].Type, entries[i].Path)
+		}
+	}
+	delete(fields, hostCodecKey)
+	guest, err := json.Marshal(fields)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode codec: %w", err)
+	}
+	return guest, entries, nil
+}
+
+// hostStages re-applies recorded rewrites to the decoded original schema.
+// stages[i] is the schema as it was after entry i was recorded; the last
+// stage is the schema the guest converted.
+func hostStages(schema any, entries []HostTransform) []any {
+	stages := make([]any, len(entries))
+	cur := schema
+	for i := range entries {
+		t := &entries[i]
+		if h := hostHandlers[t.Type]; h.rewrite != nil {
+			cur = deepCopyJSON(cur)
+			if node, ok := lookupPointer(cur, t.Path); ok {
+				cur = replaceAtPointer(cur, t.Path, h.rewrite(node, t))
+			}
+		}
+		stages[i] = cur
+	}
+	return stages
+}
+
+// restoreHost undoes host entries on guest-rehydrated data in reverse order,
+// co-walking each entry's stage so paths resolve in the shape they were
+// recorded against.
+func restoreHost(data any, stages []any, entries []HostTransform) (any, []Warning) {
+	var warnings []Warning
+	for i := len(entries) - 1; i >= 0; i-- {
+		t := &entries[i]
+		h := hostHandlers[t.Type]
+		if h.restore == nil {
+			continue
+		}
+		data = walkInstance(stages[i], data, func(loc string, _ map[string]any, dataPath string, v any) any {
+			if loc != t.Path {
+				return v
+			}
+			nv, w := h.restore(v, t, dataPath)
+			for j := range w {
+				w[j].EntryID = EntryID(t.Type, t.Path)
+			}
+			warnings = append(warnings, w...)
+			return nv
+		})
+	}
+	return data, warnings
+}
+
+// normalizeRootDataPaths rewrites the guest's root data path "/" to "",
+// the form host warnings and Pointer.DataPath use, so a warning reported at
+// the root by both compares equal. The guest writes "/" only for the root.
+func normalizeRootDataPaths(warnings []Warning) {
+	for i := range warnings {
+		if warnings[i].DataPath == "/" {
+			warnings[i].DataPath = ""
+		}
+	}
+}
+
+// mergeWarnings appends host warnings to guest warnings, skipping any the
+// guest already reported for the same data path and kind.
+func mergeWarnings(guest, host []Warning) []Warning {
+	type key struct {
+		path string
+		kind WarningKind
+	}
+	seen := make(map[key]bool, len(guest))
+	for _, w := range guest {
+		seen[key{w.DataPath, w.Kind}] = true
+	}
+	for _, w := range host {
+		if !seen[key{w.DataPath, w.Kind}] {
+			guest = append(guest, w)
+		}
+	}
+	return guest
+}
+
+// forEachSubschema calls fn for every subschema of node (excluding node
+// itself) with its location, descending through all schema-bearing keywords.
+// fn may return a replacement node. This is the structural walk host passes
+// use when recording entries; it does not follow $ref.
+func forEachSubschema(node any, loc string, fn func(loc string, node map[string]any) any) any {
+	m, ok := node.(map[string]any)
+	if !ok {
+		return node
+	}
+	for _, kw := range []string{"properties", "patternProperties", "$defs", "definitions", "dependentSchemas"} {
+		if children, ok := m[kw].(map[string]any); ok {
+			for _, key := range sortedKeys(children) {
+				children[key] = visitSubschema(children[key], childPointer(childPointer(loc, kw), key), fn)
+			}
+		}
+	}
+	for _, kw := range []string{"allOf", "anyOf", "oneOf", "prefixItems"} {
+		if children, ok := m[kw].([]any); ok {
+			for i, child := range children {
+				children[i] = visitSubschema(child, childPointer(childPointer(loc, kw), itoa(i)), fn)
+			}
+		}
+	}
+	for _, kw := range []string{"items", "additionalProperties", "not", "if", "then", "else", "contains", "propertyNames", "unevaluatedProperties", "unevaluatedItems"} {
+		switch child := m[kw].(type) {
+		case map[string]any:
+			m[kw] = visitSubschema(child, childPointer(loc, kw), fn)
+		case []any: // draft-07 tuple "items"
+			for i, c := range child {
+				child[i] = visitSubschema(c, childPointer(childPointer(loc, kw), itoa(i)), fn)
+			}
+		}
+	}
+	return m
+}
+
+// visitSubschema applies fn to node (post-order) after visiting its children.
+func visitSubschema(node any, loc string, fn func(loc string, node map[string]any) any) any {
+	node = forEachSubschema(node, loc, fn)
+	if m, ok := node.(map[string]any); ok {
+		return fn(loc, m)
+	}
+	return node
+}
+
+// walkSchema visits the root and every subschema in post-order.
+func walkSchema(root any, fn func(loc string, node map[string]any) any) any {
+	return visitSubschema(root, "#", fn)
+}
+
+// deepCopyJSON copies a decoded JSON tree.
+func deepCopyJSON(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, c := range t {
+			out[k] = deepCopyJSON(c)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, c := range t {
+			out[i] = deepCopyJSON(c)
+		}
+		return out
+	}
+	return v
+}
+
+// jsonEqual compares two decoded JSON values.
+func jsonEqual(a, b any) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// sortedKeys returns the keys of m in lexical order so that host passes
+// record entries deterministically.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func itoa(i int) string {
+	return strconv.Itoa(i)
+}
@@ -0,0 +1,78 @@
+package jsl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// EntryID returns the stable identifier of a codec entry: a hash of its type
+// (the transform type, or the constraint name for a dropped constraint) and
+// schema path. IDs are derived, not stored, so any codec — including ones
+// produced before IDs existed — yields the same IDs.
+func EntryID(typ, path string) string {
+	sum := sha256.Sum256([]byte(typ + "\x00" + path))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Codec entry kinds reported by CodecEntries.
+const (
+	EntryKindTransform         = "transform"
+	EntryKindDroppedConstraint = "dropped_constraint"
+	EntryKindHost              = "host"
+)
+
+// CodecEntry is one recorded transformation in a codec.
+type CodecEntry struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	Type string `json:"type"`
+	Path string `json:"path"`
+}
+
+// CodecEntries lists every entry applied during conversion — guest
+// transforms, dropped constraints, and host transforms — in codec order,
+// each with its EntryID.
+func CodecEntries(codec any) ([]CodecEntry, error) {
+	codecBytes, err := json.Marshal(codec)
+	if err != nil {
+		return nil, fmt.Errorf("marshal codec: %w", err)
+	}
+	var raw struct {
+		Transforms []struct {
+			Type string `json:"type"`
+			Path string `json:"path"`
+		} `json:"transforms"`
+		DroppedConstraints []struct {
+			Path       string `json:"path"`
+			Constraint string `json:"constraint"`
+		} `json:"droppedConstraints"`
+		Host []HostTransform `json:"hostTransforms"`
+	}
+	if err := json.Unmarshal(codecBytes, &raw); err != nil {
+		return nil, fmt.Errorf("decode codec: %w", err)
+	}
+	entries := make([]CodecEntry, 0, len(raw.Transforms)+len(raw.DroppedConstraints)+len(raw.Host))
+	for _, t := range raw.Transforms {
+		entries = append(entries, CodecEntry{ID: EntryID(t.Type, t.Path), Kind: EntryKindTransform, Type: t.Type, Path: t.Path})
+	}
+	for _, dc := range raw.DroppedConstraints {
+		entries = append(entries, CodecEntry{ID: EntryID(dc.Constraint, dc.Path), Kind: EntryKindDroppedConstraint, Type: dc.Constraint, Path: dc.Path})
+	}
+	for _, h := range raw.Host {
+		entries = append(entries, CodecEntry{ID: EntryID(h.Type, h.Path), Kind: EntryKindHost, Type: h.Type, Path: h.Path})
+	}
+	return entries, nil
+}
+
+// assignWarningIDs fills EntryID on guest warnings that stem from a dropped
+// constraint; the guest reports the constraint's path as SchemaPath.
+func assignWarningIDs(warnings []Warning) {
+	for i := range warnings {
+		w := &warnings[i]
+		if w.EntryID == "" && w.Kind.Constraint != "" && w.SchemaPath != "" {
+			w.EntryID = EntryID(w.Kind.Constraint, w.SchemaPath)
+		}
+	}
+}
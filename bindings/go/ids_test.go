@@ -0,0 +1,38 @@
+package jsl
+
+import "testing"
+
+// TestCodecEntriesIDs verifies every codec entry gets a stable ID that guest and host warnings share.
+func TestCodecEntriesIDs(t *testing.T) {
+	codec := decodeJSON(t, `{
+		"$schema": "https://json-schema-llm.dev/codec/v1",
+		"transforms": [{"type": "map_to_array", "path": "#/properties/tags", "keyField": "key"}],
+		"droppedConstraints": [{"path": "#/properties/age", "constraint": "minimum", "value": 0}],
+		"hostTransforms": [{"type": "format", "path": "#/properties/at", "params": {"format": "date"}}]
+	}`)
+	entries, err := CodecEntries(codec)
+	if err != nil {
+		t.Fatalf("CodecEntries() failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", entries)
+	}
+	kinds := []string{EntryKindTransform, EntryKindDroppedConstraint, EntryKindHost}
+	for i, e := range entries {
+		if e.Kind != kinds[i] || e.ID != EntryID(e.Type, e.Path) || len(e.ID) != 16 {
+			t.Errorf("entry %d = %+v", i, e)
+		}
+	}
+	if EntryID("minimum", "#/properties/age") != EntryID("minimum", "#/properties/age") {
+		t.Error("EntryID is not deterministic")
+	}
+	if EntryID("minimum", "#/properties/age") == EntryID("maximum", "#/properties/age") {
+		t.Error("EntryID should differ by type")
+	}
+
+	warnings := []Warning{{SchemaPath: "#/properties/age", Kind: WarningKind{Type: "constraint_violation", Constraint: "minimum"}}}
+	assignWarningIDs(warnings)
+	if warnings[0].EntryID != entries[1].ID {
+		t.Errorf("guest warning ID = %q, want %q", warnings[0].EntryID, entries[1].ID)
+	}
+}
@@ -0,0 +1,249 @@
+package jsl
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// transformTypeInference records a type guessed for an untyped,
+// annotation-only schema.
+const transformTypeInference = "type_inference"
+
+// DefaultInferConfidence is the confidence a guess needs before it is applied
+// when ConvertOptions.InferConfidence is zero. A description cue alone
+// (descriptionConfidence) stays below it; corroborating evidence is needed.
+const DefaultInferConfidence = 0.6
+
+// descriptionConfidence is the confidence of a guess from a description
+// cue, the weakest evidence inferTypes uses.
+const descriptionConfidence = 0.5
+
+// inferAnnotationKeywords are the only keywords an inference candidate may
+// carry; anything else already constrains the schema.
+var inferAnnotationKeywords = map[string]bool{
+	"description": true, "title": true, "examples": true, "default": true,
+	"$comment": true, "deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+// Description cues for the two types the guest would otherwise have to treat
+// as opaque. Matched case-insensitively as whole words, optionally plural,
+// so "name" does not match "filename" nor "text" "context".
+var (
+	objectCues = cuePattern("object", "map of", "dictionary", "key-value", "key/value", "json")
+	stringCues = cuePattern("name", "text", "label", "title", "url", "uri", "email", "date", "message", "comment", "note", "identifier", "id")
+)
+
+func cuePattern(cues ...string) *regexp.Regexp {
+	quoted := make([]string, len(cues))
+	for i, c := range cues {
+		quoted[i] = regexp.QuoteMeta(c)
+	}
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)s?\b`)
+}
+
+// typeGuess is one piece of evidence for a node's type.
+type typeGuess struct {
+	typ        string
+	confidence float64
+	source     string
+}
+
+// inferTypes guesses a type for every untyped, annotation-only subschema from
+// its examples/default, properties of the same name elsewhere in the schema,
+// and its description. Guesses at or above the confidence threshold set
+// `type`; the rest are left to the guest's unconstrained-schema handling.
+// Both outcomes are reported as convert warnings.
+func inferTypes(schema any, opts *ConvertOptions) (any, []HostTransform, error) {
+	threshold := opts.InferConfidence
+	if threshold == 0 {
+		threshold = DefaultInferConfidence
+	}
+	siblings := propertyTypesByName(schema)
+
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		if loc == "#" || !inferCandidate(node) {
+			return node
+		}
+		g, ok := bestGuess(node, propertyName(loc), siblings)
+		if !ok {
+			return node
+		}
+		t := HostTransform{Type: transformTypeInference, Path: loc, Params: map[string]any{
+			"type":       g.typ,
+			"confidence": g.confidence,
+			"source":     g.source,
+			"applied":    g.confidence >= threshold,
+		}}
+		entries = append(entries, t)
+		return rewriteInferredType(node, &t)
+	})
+	return schema, entries, nil
+}
+
+func inferCandidate(node map[string]any) bool {
+	if _, typed := node["type"]; typed {
+		return false
+	}
+	evidence := false
+	for kw := range node {
+		if !inferAnnotationKeywords[kw] {
+			return false
+		}
+		if kw == "description" || kw == "examples" || kw == "default" {
+			evidence = true
+		}
+	}
+	return evidence
+}
+
+// propertyName returns the property a schema location names, if any.
+func propertyName(loc string) string {
+	segs := splitPointer(loc)
+	if n := len(segs); n >= 2 && segs[n-2] == "properties" {
+		return segs[n-1]
+	}
+	return ""
+}
+
+// propertyTypesByName collects the declared string types of every named
+// property in the schema.
+func propertyTypesByName(schema any) map[string]map[string]bool {
+	out := map[string]map[string]bool{}
+	walkSchema(deepCopyJSON(schema), func(loc string, node map[string]any) any {
+		name := propertyName(loc)
+		if typ, ok := node["type"].(string); ok && name != "" {
+			if out[name] == nil {
+				out[name] = map[string]bool{}
+			}
+			out[name][typ] = true
+		}
+		return node
+	})
+	return out
+}
+
+func bestGuess(node map[string]any, name string, siblings map[string]map[string]bool) (typeGuess, bool) {
+	var guesses []typeGuess
+	if g, ok := guessFromValues(node); ok {
+		guesses = append(guesses, g)
+	}
+	if types := siblings[name]; len(types) == 1 {
+		for typ := range types {
+			guesses = append(guesses, typeGuess{typ: typ, confidence: 0.7, source: "sibling"})
+		}
+	}
+	if desc, ok := node["description"].(string); ok {
+		if g, ok := guessFromDescription(desc); ok {
+			guesses = append(guesses, g)
+		}
+	}
+	if len(guesses) == 0 {
+		return typeGuess{}, false
+	}
+	sort.SliceStable(guesses, func(i, j int) bool { return guesses[i].confidence > guesses[j].confidence })
+	best := guesses[0]
+	for _, g := range guesses[1:] {
+		if g.typ == best.typ {
+			best.confidence = min(0.95, best.confidence+0.1)
+			best.source += "+" + g.source
+		}
+	}
+	return best, true
+}
+
+// guessFromValues uses examples and default: agreeing values are strong
+// evidence.
+func guessFromValues(node map[string]any) (typeGuess, bool) {
+	var values []any
+	if ex, ok := node["examples"].([]any); ok {
+		values = append(values, ex...)
+	}
+	if d, ok := node["default"]; ok {
+		values = append(values, d)
+	}
+	if len(values) == 0 {
+		return typeGuess{}, false
+	}
+	typ := jsonTypeOf(values[0])
+	for _, v := range values[1:] {
+		if t := jsonTypeOf(v); t != typ {
+			if (t == "number" && typ == "integer") || (t == "integer" && typ == "number") {
+				typ = "number"
+				continue
+			}
+			return typeGuess{}, false
+		}
+	}
+	if typ == "null" {
+		return typeGuess{}, false
+	}
+	confidence := 0.8
+	if len(values) > 1 {
+		confidence = 0.9
+	}
+	return typeGuess{typ: typ, confidence: confidence, source: "examples"}, true
+}
+
+func guessFromDescription(desc string) (typeGuess, bool) {
+	switch {
+	case objectCues.MatchString(desc):
+		return typeGuess{typ: "object", confidence: descriptionConfidence, source: "description"}, true
+	case stringCues.MatchString(desc):
+		return typeGuess{typ: "string", confidence: descriptionConfidence, source: "description"}, true
+	}
+	return typeGuess{}, false
+}
+
+func jsonTypeOf(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if t == float64(int64(t)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return ""
+}
+
+func rewriteInferredType(n any, t *HostTransform) any {
+	node, ok := n.(map[string]any)
+	if !ok {
+		return n
+	}
+	if applied, _ := t.Params["applied"].(bool); applied {
+		node["type"] = t.Params["type"]
+	}
+	return node
+}
+
+// inferConvertWarning reports each guess, applied or not.
+func inferConvertWarning(t *HostTransform) (Warning, bool) {
+	typ, _ := t.Params["type"].(string)
+	confidence, _ := t.Params["confidence"].(float64)
+	source, _ := t.Params["source"].(string)
+	if applied, _ := t.Params["applied"].(bool); applied {
+		return Warning{
+			SchemaPath: t.Path,
+			Kind:       WarningKind{Type: WarnTypeInferred},
+			Message:    fmt.Sprintf("inferred type %q (confidence %.2f) from %s", typ, confidence, source),
+		}, true
+	}
+	return Warning{
+		SchemaPath: t.Path,
+		Kind:       WarningKind{Type: WarnTypeInferenceFallback},
+		Message:    fmt.Sprintf("best guess %q (confidence %.2f, from %s) is below the threshold; left unconstrained", typ, confidence, source),
+	}, true
+}
@@ -0,0 +1,97 @@
+package jsl
+
+import "testing"
+
+// TestInferTypes verifies guesses from examples, siblings and descriptions, and the below-threshold fallback.
+func TestInferTypes(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"owner": {"type": "object", "properties": {"email": {"type": "string"}}},
+			"contact": {"type": "object", "properties": {"email": {"description": "Where to reach them"}}},
+			"retries": {"description": "How often to retry", "examples": [1, 3]},
+			"settings": {"description": "Free-form map of plugin settings"},
+			"misc": {"description": "Anything else"}
+		}
+	}`)
+
+	converted, entries, err := inferTypes(deepCopyJSON(schema), &ConvertOptions{InferOpaqueTypes: true})
+	if err != nil {
+		t.Fatalf("inferTypes() failed: %v", err)
+	}
+	wantTypes := map[string]any{
+		"#/properties/contact/properties/email": "string",
+		"#/properties/retries":                  "integer",
+		"#/properties/settings":                 nil,
+		"#/properties/misc":                     nil,
+	}
+	for loc, want := range wantTypes {
+		node, _ := lookupPointer(converted, loc)
+		if got := node.(map[string]any)["type"]; got != want {
+			t.Errorf("%s: type = %v, want %v", loc, got, want)
+		}
+	}
+	if len(entries) != 3 {
+		t.Errorf("expected 3 guesses (misc has no evidence), got %+v", entries)
+	}
+	for _, w := range hostConvertWarnings(entries) {
+		want := "type_inferred"
+		if w.SchemaPath == "#/properties/settings" {
+			// A description cue alone stays below the default threshold.
+			want = "type_inference_fallback"
+		}
+		if w.Kind.Type != want {
+			t.Errorf("unexpected warning: %+v", w)
+		}
+	}
+
+	_, entries, _ = inferTypes(deepCopyJSON(schema), &ConvertOptions{InferOpaqueTypes: true, InferConfidence: 0.5})
+	for _, e := range entries {
+		if e.Path == "#/properties/settings" && e.Params["type"] != "object" {
+			t.Errorf("settings guess = %v, want object", e.Params)
+		}
+	}
+
+	_, entries, _ = inferTypes(deepCopyJSON(schema), &ConvertOptions{InferOpaqueTypes: true, InferConfidence: 0.95})
+	entries = roundtripEntries(t, entries)
+	fallbacks := 0
+	for _, w := range hostConvertWarnings(entries) {
+		if w.Kind.Type == "type_inference_fallback" {
+			fallbacks++
+		}
+	}
+	if fallbacks != 3 {
+		t.Errorf("expected every guess to fall back at 0.95, got %d", fallbacks)
+	}
+	stages := hostStages(schema, entries)
+	if !jsonEqual(stages[len(stages)-1], schema) {
+		t.Error("fallback entries must not change the schema")
+	}
+}
+
+// TestGuessFromDescriptionWholeWords verifies cues match whole words only.
+func TestGuessFromDescriptionWholeWords(t *testing.T) {
+	for desc, want := range map[string]string{
+		"The date it shipped":          "string",
+		"Free text":                    "string",
+		"The user's name":              "string",
+		"User ID":                      "string",
+		"Display names":                "string",
+		"A JSON blob":                  "object",
+		"Map of headers":               "object",
+		"When the last update ran":     "",
+		"Surrounding context":          "",
+		"The filename on disk":         "",
+		"Seconds spent idle":           "",
+		"Subject of the notification":  "",
+		"Whether the objective is met": "",
+	} {
+		g, ok := guessFromDescription(desc)
+		if got := map[bool]string{true: g.typ}[ok]; got != want {
+			t.Errorf("guessFromDescription(%q) = %q, want %q", desc, got, want)
+		}
+		if ok && g.confidence >= DefaultInferConfidence {
+			t.Errorf("guessFromDescription(%q) confidence %.2f reaches the default threshold", desc, g.confidence)
+		}
+	}
+}
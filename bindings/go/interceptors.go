@@ -0,0 +1,91 @@
+package jsl
+
+import (
+	"context"
+	"errors"
+)
+
+// Method names Call.Method reports.
+const (
+	MethodConvert   = "Convert"
+	MethodRehydrate = "Rehydrate"
+)
+
+// Call is one Convert or Rehydrate call on its way through the
+// interceptors. An interceptor may change its inputs before calling next.
+type Call struct {
+	// Method is MethodConvert (Convert and everything built on it, such as
+	// ConvertWith, ConvertTools and each conversion ConvertAllComponents
+	// makes) or MethodRehydrate (Rehydrate and
+	// RehydrateWithOptions).
+	Method string
+	// Schema is the schema argument of either method.
+	Schema any
+	// ConvertOptions is Convert's options.
+	ConvertOptions *ConvertOptions
+	// Data, Codec and RehydrateOptions are Rehydrate's other arguments.
+	Data             any
+	Codec            any
+	RehydrateOptions *RehydrateOptions
+}
+
+// CallResult is the result of a Call: Convert for MethodConvert, Rehydrate
+// for MethodRehydrate.
+type CallResult struct {
+	Convert   *ConvertResult
+	Rehydrate *RehydrateResult
+}
+
+// Handler performs a Call.
+type Handler func(ctx context.Context, call *Call) (*CallResult, error)
+
+// Interceptor runs around every Convert and Rehydrate call. It calls next
+// to proceed, or returns without calling it to short-circuit the call (a
+// cache hit, a denied request). ctx is the engine's context (see
+// WithContext); pass a derived one to next to change what the guest call
+// and its span run under.
+type Interceptor func(ctx context.Context, call *Call, next Handler) (*CallResult, error)
+
+// errNoCallResult is returned when an interceptor returns neither a result
+// nor an error.
+var errNoCallResult = errors.New("interceptor returned no result")
+
+// WithInterceptors appends interceptors. They run in registration order,
+// the first outermost, around the engine's own tracing, logging and cache.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *engineConfig) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// intercept runs call through the interceptor chain.
+func (e *SchemaLlmEngine) intercept(call *Call) (*CallResult, error) {
+	h := Handler(e.invoke)
+	for i := len(e.interceptors) - 1; i >= 0; i-- {
+		ic, next := e.interceptors[i], h
+		h = func(ctx context.Context, c *Call) (*CallResult, error) {
+			return ic(ctx, c, next)
+		}
+	}
+	result, err := h(e.ctx, call)
+	if result == nil && err == nil {
+		return nil, errNoCallResult
+	}
+	return result, err
+}
+
+// invoke is the innermost Handler: it performs call on the engine.
+func (e *SchemaLlmEngine) invoke(ctx context.Context, call *Call) (*CallResult, error) {
+	if ctx != e.ctx {
+		e = e.WithContext(ctx)
+	}
+	switch call.Method {
+	case MethodConvert:
+		result, err := e.observeConvert(call.Schema, call.ConvertOptions)
+		return &CallResult{Convert: result}, err
+	case MethodRehydrate:
+		result, err := e.observeRehydrate(call.Data, call.Codec, call.Schema, call.RehydrateOptions)
+		return &CallResult{Rehydrate: result}, err
+	}
+	return nil, errors.New("unknown call method " + call.Method)
+}
@@ -0,0 +1,124 @@
+package jsl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestInterceptorsOrder verifies interceptors run outermost-first, see each
+// call's method and inputs, and may replace inputs on the way in.
+func TestInterceptorsOrder(t *testing.T) {
+	var order []string
+	trace := func(name string) Interceptor {
+		return func(ctx context.Context, call *Call, next Handler) (*CallResult, error) {
+			order = append(order, name+">"+call.Method)
+			result, err := next(ctx, call)
+			order = append(order, name+"<"+call.Method)
+			return result, err
+		}
+	}
+	replace := func(ctx context.Context, call *Call, next Handler) (*CallResult, error) {
+		if call.Method == MethodConvert {
+			call.Schema = json.RawMessage(`{"type":"object","properties":{"b":{"type":"integer"}}}`)
+		}
+		return next(ctx, call)
+	}
+	eng, err := NewSchemaLlmEngine(WithInterceptors(trace("outer"), trace("inner")), WithInterceptors(replace))
+	if err != nil {
+		t.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	result, err := eng.Convert(json.RawMessage(`{"type":"object","properties":{"a":{"type":"string"}}}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if props, _ := result.Schema["properties"].(map[string]any); props["b"] == nil {
+		t.Errorf("schema = %v, want the replaced schema", result.Schema)
+	}
+	if _, err := eng.Rehydrate(map[string]any{"b": 1}, result.Codec, result.Schema); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"outer>Convert", "inner>Convert", "inner<Convert", "outer<Convert",
+		"outer>Rehydrate", "inner>Rehydrate", "inner<Rehydrate", "outer<Rehydrate",
+	}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
+
+// TestInterceptorsShortCircuit verifies an interceptor can answer a call
+// without reaching the guest, and that errors propagate.
+func TestInterceptorsShortCircuit(t *testing.T) {
+	canned := &ConvertResult{APIVersion: "1.0", Schema: map[string]any{"type": "object"}}
+	denied := errors.New("denied")
+	eng := &SchemaLlmEngine{ctx: context.Background(), interceptors: []Interceptor{
+		func(ctx context.Context, call *Call, next Handler) (*CallResult, error) {
+			if call.Method == MethodRehydrate {
+				return nil, denied
+			}
+			return &CallResult{Convert: canned}, nil
+		},
+	}}
+	result, err := eng.Convert(map[string]any{"type": "object"}, nil)
+	if err != nil || result != canned {
+		t.Errorf("Convert() = %v, %v; want the canned result", result, err)
+	}
+	if _, err := eng.Rehydrate(map[string]any{}, nil, nil); !errors.Is(err, denied) {
+		t.Errorf("Rehydrate() error = %v, want %v", err, denied)
+	}
+}
+
+// TestInterceptorsNoResult verifies an interceptor returning neither a
+// result nor an error fails the call instead of returning nil, nil.
+func TestInterceptorsNoResult(t *testing.T) {
+	eng := &SchemaLlmEngine{ctx: context.Background(), interceptors: []Interceptor{
+		func(context.Context, *Call, Handler) (*CallResult, error) { return nil, nil },
+	}}
+	if _, err := eng.Convert(map[string]any{}, nil); !errors.Is(err, errNoCallResult) {
+		t.Errorf("Convert() error = %v, want %v", err, errNoCallResult)
+	}
+}
+
+// TestInterceptorsConvertAllComponents verifies the interceptors see the
+// whole-schema conversion and each component's.
+func TestInterceptorsConvertAllComponents(t *testing.T) {
+	var schemas []string
+	eng, err := NewSchemaLlmEngine(WithInterceptors(func(ctx context.Context, call *Call, next Handler) (*CallResult, error) {
+		if call.Method == MethodConvert {
+			b, _ := json.Marshal(call.Schema)
+			schemas = append(schemas, string(b))
+		}
+		return next(ctx, call)
+	}))
+	if err != nil {
+		t.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	schema := map[string]any{
+		"type": "object",
+		"$defs": map[string]any{
+			"A": map[string]any{"type": "object", "title": "A", "properties": map[string]any{"a": map[string]any{"type": "string"}}},
+			"B": map[string]any{"type": "object", "title": "B", "properties": map[string]any{"b": map[string]any{"type": "integer"}}},
+		},
+	}
+	result, err := eng.ConvertAllComponents(schema, nil, nil)
+	if err != nil {
+		t.Fatalf("ConvertAllComponents() failed: %v", err)
+	}
+	if len(result.Components) != 2 || len(schemas) != 3 {
+		t.Fatalf("%d components, %d intercepted converts; want 2 and 3", len(result.Components), len(schemas))
+	}
+	for _, title := range []string{`"title":"A"`, `"title":"B"`} {
+		if !slices.ContainsFunc(schemas[1:], func(s string) bool { return strings.Contains(s, title) }) {
+			t.Errorf("component calls %v: none has %s", schemas[1:], title)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package jsl
+
+// Converter converts JSON Schemas to their LLM-compatible form. Depend on it
+// rather than on *SchemaLlmEngine to substitute a fake (see package
+// jsltest), a decorator or another backend.
+type Converter interface {
+	Convert(schema any, opts *ConvertOptions) (*ConvertResult, error)
+}
+
+// Rehydrator restores model output to the original schema's shape.
+type Rehydrator interface {
+	Rehydrate(data any, codec any, schema any) (*RehydrateResult, error)
+	RehydrateWithOptions(data any, codec any, schema any, opts *RehydrateOptions) (*RehydrateResult, error)
+}
+
+// ConvertRehydrator is the surface of a full convert → rehydrate round
+// trip, as Generate runs it.
+type ConvertRehydrator interface {
+	Converter
+	Rehydrator
+}
+
+// ComponentConverter lists, extracts and converts the components of a
+// schema document.
+type ComponentConverter interface {
+	ListComponents(schema any) (*ListComponentsResult, error)
+	ExtractComponent(schema any, pointer string, opts *ExtractOptions) (*ExtractResult, error)
+	ConvertAllComponents(schema any, convertOpts *ConvertOptions, extractOpts *ExtractOptions) (*ConvertAllResult, error)
+}
+
+var (
+	_ Converter          = (*SchemaLlmEngine)(nil)
+	_ Rehydrator         = (*SchemaLlmEngine)(nil)
+	_ ConvertRehydrator  = (*SchemaLlmEngine)(nil)
+	_ ComponentConverter = (*SchemaLlmEngine)(nil)
+)
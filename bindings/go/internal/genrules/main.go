@@ -0,0 +1,163 @@
+// Command genrules copies the core's keyword tables into analyze_rules.go,
+// so Analyze reports what the guest's passes actually prune. Run it from
+// the bindings/go directory (go generate does):
+//
+//	go run ./internal/genrules
+//
+// It reads unsupported_constraints in passes/p7_constraints.rs,
+// BANNED_KEYWORD_RULES in validation/strict_mode.rs and the subschema
+// keyword tables in schema_walker.rs.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// coreSrc is the core crate's source directory, relative to bindings/go.
+const coreSrc = "../../crates/json-schema-llm-core/src"
+
+// outFile is the generated file, relative to bindings/go.
+const outFile = "analyze_rules.go"
+
+// rustTargets maps the core's Target variants to Go target names.
+var rustTargets = map[string]string{
+	"OpenaiStrict": "openai-strict",
+	"Claude":       "claude",
+	"Gemini":       "gemini",
+}
+
+var (
+	strTablePattern  = regexp.MustCompile(`(?s)const (\w+): &\[&str\] = &\[(.*?)\];`)
+	bannedPattern    = regexp.MustCompile(`(?s)const BANNED_KEYWORD_RULES: &\[\(&str, \w+\)\] = &\[(.*?)\];`)
+	bannedRule       = regexp.MustCompile(`\(\s*"([^"]+)",`)
+	quoted           = regexp.MustCompile(`"([^"]*)"`)
+	targetArmPattern = regexp.MustCompile(`Target::(\w+) => \(UNIVERSAL, (\w+)\)`)
+)
+
+func main() {
+	src, err := render(".")
+	if err == nil {
+		err = os.WriteFile(outFile, src, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "genrules: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// render returns the contents of analyze_rules.go for the bindings/go
+// directory dir.
+func render(dir string) ([]byte, error) {
+	read := func(name string) (string, error) {
+		b, err := os.ReadFile(filepath.Join(dir, coreSrc, name))
+		return string(b), err
+	}
+
+	constraints, err := read("passes/p7_constraints.rs")
+	if err != nil {
+		return nil, err
+	}
+	tables := strTables(constraints)
+	dropped := map[string][]string{"": tables["UNIVERSAL"]}
+	if dropped[""] == nil {
+		return nil, fmt.Errorf("p7_constraints.rs: no UNIVERSAL table")
+	}
+	for _, arm := range targetArmPattern.FindAllStringSubmatch(constraints, -1) {
+		target, ok := rustTargets[arm[1]]
+		if !ok {
+			return nil, fmt.Errorf("p7_constraints.rs: unknown target Target::%s", arm[1])
+		}
+		extra, ok := tables[arm[2]]
+		if !ok {
+			return nil, fmt.Errorf("p7_constraints.rs: no %s table", arm[2])
+		}
+		dropped[target] = extra
+	}
+	if len(dropped) != len(rustTargets)+1 {
+		return nil, fmt.Errorf("p7_constraints.rs: found tables for %d of %d targets", len(dropped)-1, len(rustTargets))
+	}
+
+	strict, err := read("validation/strict_mode.rs")
+	if err != nil {
+		return nil, err
+	}
+	m := bannedPattern.FindStringSubmatch(strict)
+	if m == nil {
+		return nil, fmt.Errorf("strict_mode.rs: no BANNED_KEYWORD_RULES table")
+	}
+	var banned []string
+	for _, r := range bannedRule.FindAllStringSubmatch(m[1], -1) {
+		banned = append(banned, r[1])
+	}
+
+	walker, err := read("schema_walker.rs")
+	if err != nil {
+		return nil, err
+	}
+	tables = strTables(walker)
+	var subschema []string
+	for _, name := range []string{"MAP_KEYWORDS", "SINGLE_KEYWORDS", "ARRAY_KEYWORDS"} {
+		t, ok := tables[name]
+		if !ok {
+			return nil, fmt.Errorf("schema_walker.rs: no %s table", name)
+		}
+		subschema = append(subschema, t...)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by go run ./internal/genrules; DO NOT EDIT.\n\npackage jsl\n\n")
+	buf.WriteString("var (\n")
+	buf.WriteString("// coreDroppedKeywords is unsupported_constraints in passes/p7_constraints.rs:\n")
+	buf.WriteString("// the keywords the guest prunes for every target (\"\") and per target.\n")
+	buf.WriteString("coreDroppedKeywords = map[string][]string{\n")
+	targets := make([]string, 0, len(dropped))
+	for t := range dropped {
+		targets = append(targets, t)
+	}
+	sort.Strings(targets)
+	for _, t := range targets {
+		fmt.Fprintf(&buf, "%q: %s,\n", t, strings.TrimPrefix(goList(dropped[t]), "[]string"))
+	}
+	buf.WriteString("}\n")
+	buf.WriteString("// coreStrictBanned is BANNED_KEYWORD_RULES in validation/strict_mode.rs:\n")
+	buf.WriteString("// keywords OpenAI strict mode rejects in a converted schema.\n")
+	fmt.Fprintf(&buf, "coreStrictBanned = %s\n", goList(banned))
+	buf.WriteString("// coreSubschemaKeywords are MAP_KEYWORDS, SINGLE_KEYWORDS and\n")
+	buf.WriteString("// ARRAY_KEYWORDS in schema_walker.rs: keywords that hold subschemas.\n")
+	fmt.Fprintf(&buf, "coreSubschemaKeywords = %s\n", goList(subschema))
+	buf.WriteString(")\n")
+	return format.Source(buf.Bytes())
+}
+
+// strTables returns the string slice constants (const NAME: &[&str]) in src.
+func strTables(src string) map[string][]string {
+	tables := map[string][]string{}
+	for _, m := range strTablePattern.FindAllStringSubmatch(src, -1) {
+		values := []string{}
+		for _, q := range quoted.FindAllStringSubmatch(m[2], -1) {
+			values = append(values, q[1])
+		}
+		tables[m[1]] = values
+	}
+	return tables
+}
+
+func goList(values []string) string {
+	var b bytes.Buffer
+	b.WriteString("[]string{")
+	for i, v := range values {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%q", v)
+	}
+	b.WriteString("}")
+	return b.String()
+}
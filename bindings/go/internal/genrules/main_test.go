@@ -0,0 +1,29 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestAnalyzeRulesCurrent verifies analyze_rules.go matches the core's
+// tables; rerun `go generate` in bindings/go when it fails.
+func TestAnalyzeRulesCurrent(t *testing.T) {
+	dir := filepath.Join("..", "..")
+	if _, err := os.Stat(filepath.Join(dir, coreSrc)); errors.Is(err, fs.ErrNotExist) {
+		t.Skip("core sources not available")
+	}
+	want, err := render(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, outFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s is stale; run go generate in bindings/go", outFile)
+	}
+}
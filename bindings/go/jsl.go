@@ -9,6 +9,14 @@
 //	read JslResult (12-byte LE struct: status/ptr/len) →
 //	parse JSON → free
 //
+// Host-side passes: some ConvertOptions are implemented in Go around the
+// guest call. They record HostTransform entries under the codec's
+// "hostTransforms" field, which Rehydrate strips before calling the guest and
+// applies to the rehydrated data afterwards. User-registered custom passes
+// (WithCustomPass) run after the whole pipeline and record "x-custom"
+// entries that Rehydrate routes to handlers registered with
+// WithCustomHandler.
+//
 // Concurrency: Each Engine owns its own wazero Runtime and compiled Module.
 // Each call creates a fresh module instance. Engines are NOT thread-safe —
 // callers must synchronize access or create per-goroutine instances.
@@ -19,11 +27,16 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
+	"sort"
+	"time"
 
 	"github.com/dotslashderek/json-schema-llm/bindings/go/wasm"
 	"github.com/tetratelabs/wazero"
 	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Status codes matching the JslResult protocol.
@@ -42,6 +55,165 @@ type ConvertOptions struct {
 	Polymorphism   string `json:"polymorphism,omitempty"`
 	MaxDepth       int    `json:"max-depth,omitempty"`
 	RecursionLimit int    `json:"recursion-limit,omitempty"`
+	// RecursionLimits overrides RecursionLimit per recursive definition,
+	// keyed by $ref pointer (e.g. "#/$defs/TreeNode": 5).
+	RecursionLimits map[string]int `json:"recursion-limits,omitempty"`
+	// DisablePasses skips individual conversion passes, for targets that
+	// accept the construct a pass rewrites: host passes by name (e.g.
+	// "large_enums") and guest passes by their Trace name (e.g.
+	// "dictionary", alias "map_to_array"). "normalize" and "recursion"
+	// always run.
+	DisablePasses []string `json:"disable-passes,omitempty"`
+	// Overrides forces behavior at specific input-schema pointers where
+	// the global options do not fit (see NodeOverride).
+	Overrides map[string]NodeOverride `json:"-"`
+	// Trace fills ConvertResult.Trace with the schema after every host and
+	// guest pass, for debugging unexpected output. Snapshots copy the whole
+	// schema per pass; leave it off in production.
+	Trace bool `json:"trace,omitempty"`
+	// RecordTransforms fills ConvertResult.Transforms with every change
+	// conversion made, attributed to the pass that made it, as an audit
+	// trail of what is sent to the provider.
+	RecordTransforms bool `json:"-"`
+	// PropertyOrder selects the `properties` key order of
+	// ConvertResult.SchemaJSON.
+	PropertyOrder PropertyOrder `json:"-"`
+	// Canonical encodes ConvertResult.SchemaJSON and CodecJSON as RFC 8785
+	// canonical JSON (see CanonicalJSON), for hashing and signing. It
+	// implies sorted properties and cannot be combined with
+	// PropertyOrderSource.
+	Canonical bool `json:"-"`
+
+	// Dialect names the input's schema language when it is not JSON
+	// Schema; such input is normalized to 2020-12 host-side before any
+	// other pass runs (see DialectSwagger2).
+	Dialect Dialect `json:"-"`
+
+	// PreserveFormats records every `format` keyword in the codec so that
+	// Rehydrate validates the returned strings and normalizes near-misses
+	// (e.g. "2024/01/02" → "2024-01-02T00:00:00Z" for date-time), emitting
+	// warnings. Applied host-side; never sent to the guest.
+	PreserveFormats bool `json:"-"`
+
+	// DescribeNumericBounds appends minimum/maximum/exclusive bounds and
+	// multipleOf to the property description (e.g. "Must be >= 0, <= 100.")
+	// so the model is steered even when the target strips the keywords, and
+	// has Rehydrate warn when a returned number violates the original bound.
+	DescribeNumericBounds bool `json:"-"`
+
+	// TupleStrategy rewrites positional tuples (prefixItems) into a shape
+	// strict targets accept; Rehydrate restores the original tuple order.
+	TupleStrategy TupleStrategy `json:"-"`
+
+	// ConstStrategy rewrites `const` keywords host-side (to a single-value
+	// enum, or a described string) and has Rehydrate warn when the returned
+	// value differs from the const.
+	ConstStrategy ConstStrategy `json:"-"`
+
+	// FreeText designates untrusted free-text fields. Their descriptions get
+	// wrapper instructions, and Rehydrate strips chat-template/role markers
+	// and enforces a length cap, so output can be re-fed into prompts.
+	FreeText *FreeTextOptions `json:"-"`
+
+	// ConditionalStrategy rewrites if/then/else into an anyOf or drops it
+	// with a convert warning; either way Rehydrate checks the returned value
+	// against the original conditional.
+	ConditionalStrategy ConditionalStrategy `json:"-"`
+
+	// StripNot removes `not` keywords, which strict targets reject, and has
+	// Rehydrate warn when the returned value matches the negated schema.
+	StripNot bool `json:"-"`
+
+	// ValidatePropertyNames records propertyNames constraints (pattern,
+	// length, enum/const) in the codec so Rehydrate can check the keys of
+	// map-like objects after the map→array round trip.
+	ValidatePropertyNames bool `json:"-"`
+
+	// EmitPatch fills ConvertResult.Patch with an RFC 6902 JSON Patch from
+	// the input schema to the converted one, so artifacts can be stored as
+	// original + patch. The patch applies to the schema as passed to Convert,
+	// before any RefResolver bundling.
+	EmitPatch bool `json:"-"`
+
+	// InferOpaqueTypes guesses a type for untyped, description-only schemas
+	// from their examples/default, same-named properties elsewhere, and the
+	// description text. Guesses with at least InferConfidence (default
+	// DefaultInferConfidence) are applied; others fall back to the guest's
+	// unconstrained-schema handling. Each guess is a convert warning.
+	InferOpaqueTypes bool    `json:"-"`
+	InferConfidence  float64 `json:"-"`
+
+	// OptionalStrategy controls how properties missing from `required` are
+	// presented to strict targets and returned by Rehydrate.
+	OptionalStrategy OptionalStrategy `json:"-"`
+
+	// MaxNestingDepth, when non-zero, hoists subtrees nested deeper than this
+	// many object/array levels into root-level lists joined by synthetic
+	// keys, for targets that reject deep schemas. Rehydrate reassembles the
+	// original nesting. Must be at least 5.
+	MaxNestingDepth int `json:"-"`
+
+	// MaxSchemaBytes and MaxSchemaTokens (estimated at 4 bytes per token)
+	// cap the size of the converted schema. When it is over budget,
+	// descriptions are trimmed, large enums collapsed and low-priority
+	// subtrees stringified until it fits; each sacrifice is a convert
+	// warning, and Rehydrate decodes and checks the affected values. Convert
+	// fails with code "schema_budget_exceeded" if the schema cannot fit.
+	MaxSchemaBytes  int `json:"-"`
+	MaxSchemaTokens int `json:"-"`
+
+	// MaxDescriptionLength truncates descriptions longer than this many
+	// characters, keeping whole sentences where possible. With
+	// PreserveDescriptions the full text is kept in the codec (see
+	// FullDescription).
+	MaxDescriptionLength int  `json:"-"`
+	PreserveDescriptions bool `json:"-"`
+
+	// StripAnnotations lists annotation keywords (examples, example,
+	// default, deprecated, $comment, readOnly, writeOnly, title) to remove
+	// before conversion; DefaultStripAnnotations is a sensible set. Each
+	// node that loses keywords is reported as an "annotation_stripped"
+	// convert warning.
+	StripAnnotations []string `json:"-"`
+
+	// MaxEnumValues degrades string enums with more values than this (e.g.
+	// country or SKU lists) to a plain string with a sample in the
+	// description. Rehydrate accepts exact matches, snaps near-misses to the
+	// closest value with an "enum_coerced" warning, and flags the rest.
+	MaxEnumValues int `json:"-"`
+
+	// SanitizePropertyNames renames properties whose names contain anything
+	// but ASCII letters, digits, '_' and '-' (spaces, unicode, punctuation)
+	// to safe identifiers, which strict grammars require. Rehydrate restores
+	// the original keys; each renamed object is a "property_renamed"
+	// convert warning. Renaming is the last host pass, so Overrides keys and
+	// FreeText.Fields keep using the original names.
+	SanitizePropertyNames bool `json:"-"`
+
+	// Limits overrides the provider limits checked after conversion
+	// (default TargetLimits(Target); a zero value disables the checks). A
+	// converted schema over a limit fails Convert with code
+	// "target_limit_exceeded" and the pointer where the limit was crossed,
+	// unless AutoFit is set: then long property names are shortened, enums
+	// collapsed and subtrees stringified until it fits, each as a "slimmed"
+	// warning that Rehydrate undoes.
+	Limits  *ProviderLimits `json:"-"`
+	AutoFit bool            `json:"-"`
+
+	// MaxObjectProperties partitions objects with more properties than this
+	// into part1, part2, … sub-objects (default: the target's
+	// ProviderLimits.MaxObjectProperties). Rehydrate merges the parts back;
+	// each split is an "object_split" convert warning. Objects with keywords
+	// that cannot follow their properties into the parts (minProperties,
+	// if/then, cross-part dependentRequired, undeclared required names and
+	// the like) are not split. Must be at least 2.
+	MaxObjectProperties int `json:"-"`
+
+	// RefResolver, when set, fetches the documents external $refs (any $ref
+	// not starting with "#") point at, and Convert bundles their targets
+	// into $defs before conversion instead of failing on them. The bundled
+	// input is returned as ConvertResult.BundledSchema.
+	RefResolver RefResolver `json:"-"`
 }
 
 // ConvertResult is the result of a convert operation.
@@ -49,9 +221,40 @@ type ConvertResult struct {
 	APIVersion string         `json:"apiVersion"`
 	Schema     map[string]any `json:"schema"`
 	Codec      any            `json:"codec"`
+	// SchemaJSON is Schema encoded deterministically, with `properties` in
+	// ConvertOptions.PropertyOrder; send these bytes to the provider.
+	SchemaJSON json.RawMessage `json:"-"`
+	// CodecJSON is Codec in canonical form (ConvertOptions.Canonical).
+	CodecJSON json.RawMessage `json:"-"`
+	// Warnings reports schema features host-side passes had to degrade.
+	Warnings []Warning `json:"warnings,omitempty"`
+	// Patch is the original→converted JSON Patch (ConvertOptions.EmitPatch),
+	// based on the caller's schema rather than BundledSchema.
+	Patch []PatchOp `json:"patch,omitempty"`
+	// PatchFingerprint is SchemaFingerprint(Schema), set with Patch so
+	// ApplyConversionPatch can verify a reconstruction.
+	PatchFingerprint string `json:"patchFingerprint,omitempty"`
+	// DroppedWarnings counts warnings removed by WithWarningSampling.
+	DroppedWarnings int `json:"droppedWarnings,omitempty"`
+	// Unsupported lists what the target could not express at all, with
+	// suggested remediations (change the schema, change the target, or
+	// accept the stringified fallback).
+	Unsupported []UnsupportedFeature `json:"unsupported,omitempty"`
+	// BundledSchema is the input schema with external $refs bundled
+	// (ConvertOptions.RefResolver). Pass it to Rehydrate in place of the
+	// original.
+	BundledSchema map[string]any `json:"bundledSchema,omitempty"`
+	// Trace holds the schema after each pass in pipeline order
+	// (ConvertOptions.Trace).
+	Trace []PassSnapshot `json:"trace,omitempty"`
+	// Transforms lists every modification in pipeline order
+	// (ConvertOptions.RecordTransforms).
+	Transforms []TransformRecord `json:"transforms,omitempty"`
+
+	validator *lazyValidator
 }
 
-// WarningKind classifies rehydration warnings.
+// WarningKind classifies conversion and rehydration warnings.
 type WarningKind struct {
 	Type       string `json:"type"`
 	Constraint string `json:"constraint,omitempty"`
@@ -59,10 +262,15 @@ type WarningKind struct {
 
 // Warning represents a constraint violation detected during rehydration.
 type Warning struct {
+	// DataPath is the RFC 6901 pointer to the value in the data ("" for
+	// the root).
 	DataPath   string      `json:"dataPath"`
 	SchemaPath string      `json:"schemaPath"`
 	Kind       WarningKind `json:"kind"`
 	Message    string      `json:"message"`
+	// EntryID identifies the codec entry the warning stems from (see
+	// EntryID and CodecEntries); empty when it has none.
+	EntryID string `json:"entryId,omitempty"`
 }
 
 // RehydrateResult is the result of a rehydrate operation.
@@ -70,11 +278,20 @@ type RehydrateResult struct {
 	APIVersion string    `json:"apiVersion"`
 	Data       any       `json:"data"`
 	Warnings   []Warning `json:"warnings,omitempty"`
+	// DroppedWarnings counts warnings removed by WithWarningSampling.
+	DroppedWarnings int `json:"droppedWarnings,omitempty"`
 }
 
 // ExtractOptions configures component extraction.
 type ExtractOptions struct {
+	// MaxDepth caps how many $ref hops deep transitive dependencies are
+	// resolved; deeper chains fail with "recursion_depth_exceeded". Zero
+	// means unbounded.
 	MaxDepth int `json:"max-depth,omitempty"`
+	// Dependencies selects where ExtractComponent places the extracted
+	// dependencies. The zero value is DependenciesAsDefs. ConvertAllComponents
+	// ignores it.
+	Dependencies DependencyPlacement `json:"-"`
 }
 
 // ExtractResult is the result of an extract_component operation.
@@ -93,11 +310,18 @@ type ListComponentsResult struct {
 }
 
 // ConvertAllResult is the result of a convert_all_components operation.
+//
+// Components replaced the v0 fields Components (raw [pointer, result]
+// pairs) and ComponentErrors (raw [pointer, message] pairs); compat.Engine
+// still returns the v0 shape for callers that have not migrated.
 type ConvertAllResult struct {
-	APIVersion      string           `json:"apiVersion"`
-	Full            json.RawMessage  `json:"full"`
-	Components      json.RawMessage  `json:"components"`
-	ComponentErrors json.RawMessage  `json:"componentErrors,omitempty"`
+	APIVersion string `json:"apiVersion"`
+	// Full is the whole schema's ConvertResult as JSON, including its
+	// Warnings, Patch, Trace and Transforms when the options ask for them.
+	Full json.RawMessage `json:"full"`
+	// Components holds one entry per component, sorted by pointer.
+	// Components that failed to convert carry Error instead of a schema.
+	Components []ComponentResult `json:"components"`
 }
 
 // Error represents a structured error from the WASI binary.
@@ -105,6 +329,11 @@ type Error struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Path    string `json:"path,omitempty"`
+	// Unsupported is set on Convert errors caused by a feature the target
+	// cannot express, with the same remediations ConvertResult reports.
+	Unsupported []UnsupportedFeature `json:"unsupported,omitempty"`
+	// sentinel is a finer-grained sentinel Is matches besides Code's.
+	sentinel error
 }
 
 func (e *Error) Error() string {
@@ -114,11 +343,28 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("jsl error [%s]: %s", e.Code, e.Message)
 }
 
+// Pointer returns Path parsed into segments. An error without a location
+// yields the root pointer, like one located at "#".
+func (e *Error) Pointer() Pointer {
+	return ParsePointer(e.Path)
+}
+
 // Option configures a SchemaLlmEngine.
 type Option func(*engineConfig)
 
 type engineConfig struct {
-	wasmPath string
+	wasmPath       string
+	compressMin    int
+	customPasses   []namedPass
+	customHandlers map[string]CustomHandler
+	nodePlugins    []namedPlugin
+	sampler        *warningSampler
+	logger         *slog.Logger
+	tracer         trace.Tracer
+	cache          *convertCache
+	interceptors   []Interceptor
+	retry          RetryPolicy
+	phaseTimings   func(PhaseTiming)
 }
 
 // WithWasmPath sets an explicit path to the WASI binary,
@@ -140,6 +386,19 @@ type SchemaLlmEngine struct {
 	mod         wazero.CompiledModule
 	ctx         context.Context
 	abiVerified bool
+	compressMin int
+	guestMemMax uint32
+
+	customPasses   []namedPass
+	customHandlers map[string]CustomHandler
+	nodePlugins    []namedPlugin
+	sampler        *warningSampler
+	logger         *slog.Logger
+	tracer         trace.Tracer
+	cache          *convertCache
+	interceptors   []Interceptor
+	retry          RetryPolicy
+	phaseTimings   func(PhaseTiming)
 }
 
 // NewSchemaLlmEngine creates a new SchemaLlmEngine by compiling the WASI binary.
@@ -175,9 +434,21 @@ func NewSchemaLlmEngine(opts ...Option) (*SchemaLlmEngine, error) {
 	}
 
 	return &SchemaLlmEngine{
-		runtime: rt,
-		mod:     compiled,
-		ctx:     ctx,
+		runtime:     rt,
+		mod:         compiled,
+		ctx:         ctx,
+		compressMin: cfg.compressMin,
+
+		customPasses:   cfg.customPasses,
+		customHandlers: cfg.customHandlers,
+		nodePlugins:    cfg.nodePlugins,
+		sampler:        cfg.sampler,
+		logger:         cfg.logger,
+		tracer:         cfg.tracer,
+		cache:          cfg.cache,
+		interceptors:   cfg.interceptors,
+		retry:          cfg.retry,
+		phaseTimings:   cfg.phaseTimings,
 	}, nil
 }
 
@@ -206,6 +477,12 @@ func resolveWasm(cfg *engineConfig) ([]byte, error) {
 	return wasm.Binary, nil
 }
 
+// GuestMemoryPeak returns the largest guest linear memory, in bytes, any
+// call on this engine has grown to.
+func (e *SchemaLlmEngine) GuestMemoryPeak() uint32 {
+	return e.guestMemMax
+}
+
 // Close releases all wazero resources.
 func (e *SchemaLlmEngine) Close() error {
 	return e.runtime.Close(e.ctx)
@@ -213,60 +490,368 @@ func (e *SchemaLlmEngine) Close() error {
 
 // Convert transforms a JSON Schema into an LLM-compatible structured output schema.
 func (e *SchemaLlmEngine) Convert(schema any, opts *ConvertOptions) (*ConvertResult, error) {
-	schemaBytes, err := json.Marshal(schema)
+	if len(e.interceptors) == 0 {
+		return e.observeConvert(schema, opts)
+	}
+	result, err := e.intercept(&Call{Method: MethodConvert, Schema: schema, ConvertOptions: opts})
+	if result == nil {
+		return nil, err
+	}
+	return result.Convert, err
+}
+
+// observeConvert is Convert with its span and debug log, inside the
+// interceptors.
+func (e *SchemaLlmEngine) observeConvert(schema any, opts *ConvertOptions) (*ConvertResult, error) {
+	span := e.startSpan("Convert", schema, attribute.String("jsl.target", targetOf(opts)))
+	start := time.Now()
+	result, err := e.convert(schema, opts)
+	if span != nil {
+		endSpan(span, err, e.convertSpanAttrs(result)...)
+	}
+	if e.debugEnabled() {
+		attrs := []slog.Attr{slog.String("target", targetOf(opts))}
+		if result != nil {
+			attrs = append(attrs,
+				slog.Int("warnings", len(result.Warnings)),
+				slog.Int("dropped_warnings", result.DroppedWarnings),
+				slog.Int("unsupported", len(result.Unsupported)))
+		}
+		e.logOp("convert", start, err, attrs...)
+	}
+	return result, err
+}
+
+func (e *SchemaLlmEngine) convert(schema any, opts *ConvertOptions) (*ConvertResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if e.cache == nil {
+		return e.convertUncached(schema, opts)
+	}
+	key := convertCacheKey(schema, opts)
+	if key == "" {
+		return e.convertUncached(schema, opts)
+	}
+	if result, ok := e.cache.get(key); ok {
+		return result, nil
+	}
+	result, err := e.convertUncached(schema, opts)
+	if err == nil {
+		e.cache.put(key, result)
+	}
+	return result, err
+}
+
+func (e *SchemaLlmEngine) convertUncached(schema any, opts *ConvertOptions) (*ConvertResult, error) {
+	endMarshal := e.startPhase("convert", PhaseMarshal)
+	schemaBytes, optsBytes, err := marshalConvertInputs(schema, opts)
+	endMarshal(err)
 	if err != nil {
-		return nil, fmt.Errorf("marshal schema: %w", err)
+		return nil, err
 	}
 
-	var optsBytes []byte
-	if opts != nil {
-		optsBytes, err = json.Marshal(opts)
-		if err != nil {
-			return nil, fmt.Errorf("marshal options: %w", err)
+	sourceBytes := schemaBytes
+	var bundled map[string]any
+	if opts != nil && opts.RefResolver != nil {
+		if schemaBytes, bundled, err = bundleSchemaBytes(schemaBytes, opts.RefResolver); err != nil {
+			return nil, err
 		}
-	} else {
-		optsBytes = []byte("{}")
 	}
 
+	originalBytes := schemaBytes
+	trace := newPassTrace(opts)
+	schemaBytes, pluginEntries, err := e.runNodePlugins(schemaBytes, trace)
+	if err != nil {
+		return nil, err
+	}
+	schemaBytes, hostEntries, err := runHostPasses(schemaBytes, opts, trace)
+	if err != nil {
+		return nil, err
+	}
+	hostEntries = append(pluginEntries, hostEntries...)
+
 	payload, err := e.callJsl("jsl_convert", schemaBytes, optsBytes)
 	if err != nil {
+		if jslErr, ok := err.(*Error); ok {
+			jslErr.Unsupported = unsupportedFromError(jslErr, opts)
+		}
 		return nil, err
 	}
 
+	endUnmarshal := e.startPhase("convert", PhaseUnmarshal)
 	var result ConvertResult
-	if err := json.Unmarshal(payload, &result); err != nil {
+	err = json.Unmarshal(payload, &result)
+	endUnmarshal(err)
+	if err != nil {
 		return nil, fmt.Errorf("unmarshal convert result: %w", err)
 	}
+	result.Warnings = hostConvertWarnings(hostEntries)
+	result.Unsupported = unsupportedFeatures(payload, opts)
+	result.BundledSchema = bundled
+	result.validator = &lazyValidator{source: originalBytes}
+	if trace != nil {
+		trace.snapshots = append(trace.snapshots, result.Trace...)
+	}
+	if budget := schemaBudget(opts); budget > 0 {
+		schema, slimEntries, slimWarnings, err := slimSchema(result.Schema, budget)
+		if err != nil {
+			if jslErr, ok := err.(*Error); ok {
+				jslErr.Unsupported = append(result.Unsupported, unsupportedFromError(jslErr, opts)...)
+			}
+			return nil, err
+		}
+		for i := range slimEntries {
+			slimEntries[i].ID = EntryID(slimEntries[i].Type, slimEntries[i].Path)
+		}
+		result.Schema = schema
+		trace.record("slim", schema)
+		hostEntries = append(hostEntries, slimEntries...)
+		result.Warnings = append(result.Warnings, slimWarnings...)
+	}
+	if limits := convertLimits(opts); limits != (ProviderLimits{}) {
+		if opts != nil && opts.AutoFit {
+			schema, fitEntries, fitWarnings, err := autoFitSchema(result.Schema, limits)
+			if err != nil {
+				if jslErr, ok := err.(*Error); ok {
+					jslErr.Unsupported = append(result.Unsupported, unsupportedFromError(jslErr, opts)...)
+				}
+				return nil, err
+			}
+			for i := range fitEntries {
+				fitEntries[i].ID = EntryID(fitEntries[i].Type, fitEntries[i].Path)
+			}
+			result.Schema = schema
+			trace.record("auto_fit", schema)
+			hostEntries = append(hostEntries, fitEntries...)
+			result.Warnings = append(result.Warnings, fitWarnings...)
+		} else if jslErr := checkTargetLimits(result.Schema, limits); jslErr != nil {
+			jslErr.Unsupported = append(result.Unsupported, unsupportedFromError(jslErr, opts)...)
+			return nil, jslErr
+		}
+	}
+	result.Codec = attachHostTransforms(result.Codec, hostEntries)
+	if err := e.runCustomPasses(&result, trace); err != nil {
+		return nil, err
+	}
+	if opts != nil && opts.RecordTransforms {
+		if result.Transforms, err = transformRecords(originalBytes, trace.snapshots); err != nil {
+			return nil, fmt.Errorf("record transforms: %w", err)
+		}
+	}
+	result.Trace = nil
+	if opts != nil && opts.Trace {
+		result.Trace = trace.snapshots
+	}
+	if opts != nil && opts.Canonical {
+		if result.SchemaJSON, err = CanonicalJSON(result.Schema); err != nil {
+			return nil, fmt.Errorf("canonicalize schema: %w", err)
+		}
+		if result.CodecJSON, err = CanonicalJSON(result.Codec); err != nil {
+			return nil, fmt.Errorf("canonicalize codec: %w", err)
+		}
+	} else {
+		var order PropertyOrder
+		if opts != nil {
+			order = opts.PropertyOrder
+		}
+		if result.SchemaJSON, err = encodeSchema(result.Schema, sourceBytes, order); err != nil {
+			return nil, err
+		}
+	}
+	schemaFP, err := SchemaFingerprint(sourceBytes)
+	if err != nil {
+		return nil, err
+	}
+	result.Codec = stampCodecSource(result.Codec, CodecSource{SchemaFingerprint: schemaFP, OptionsFingerprint: OptionsFingerprint(opts)})
+	result.Warnings, result.DroppedWarnings = e.sampler.sample(result.Warnings)
+	if opts != nil && opts.EmitPatch {
+		if result.Patch, err = DiffSchemas(sourceBytes, result.Schema); err != nil {
+			return nil, fmt.Errorf("diff schemas: %w", err)
+		}
+		if result.PatchFingerprint, err = SchemaFingerprint(result.Schema); err != nil {
+			return nil, err
+		}
+	}
 	return &result, nil
 }
 
 // Rehydrate restores LLM output back to the original schema shape.
 func (e *SchemaLlmEngine) Rehydrate(data any, codec any, schema any) (*RehydrateResult, error) {
-	dataBytes, err := json.Marshal(data)
-	if err != nil {
-		return nil, fmt.Errorf("marshal data: %w", err)
+	return e.RehydrateWithOptions(data, codec, schema, nil)
+}
+
+// RehydrateWithOptions is Rehydrate with host-side output handling. Pass the
+// model's response text as a json.RawMessage to enable the options that
+// operate on raw output (e.g. SpecialNumbers); other data is marshaled as-is.
+func (e *SchemaLlmEngine) RehydrateWithOptions(data any, codec any, schema any, opts *RehydrateOptions) (*RehydrateResult, error) {
+	if len(e.interceptors) == 0 {
+		return e.observeRehydrate(data, codec, schema, opts)
+	}
+	result, err := e.intercept(&Call{Method: MethodRehydrate, Schema: schema, Data: data, Codec: codec, RehydrateOptions: opts})
+	if result == nil {
+		return nil, err
+	}
+	return result.Rehydrate, err
+}
+
+// observeRehydrate is RehydrateWithOptions with its span and debug log,
+// inside the interceptors.
+func (e *SchemaLlmEngine) observeRehydrate(data any, codec any, schema any, opts *RehydrateOptions) (*RehydrateResult, error) {
+	span := e.startSpan("Rehydrate", schema)
+	start := time.Now()
+	result, err := e.rehydrate(data, codec, schema, opts)
+	if span != nil {
+		var attrs []attribute.KeyValue
+		if result != nil {
+			attrs = append(attrs, attribute.Int("jsl.warnings", len(result.Warnings)))
+		}
+		endSpan(span, err, attrs...)
 	}
-	codecBytes, err := json.Marshal(codec)
+	if e.debugEnabled() {
+		var attrs []slog.Attr
+		if result != nil {
+			attrs = append(attrs,
+				slog.Int("warnings", len(result.Warnings)),
+				slog.Int("dropped_warnings", result.DroppedWarnings))
+		}
+		e.logOp("rehydrate", start, err, attrs...)
+	}
+	return result, err
+}
+
+func (e *SchemaLlmEngine) rehydrate(data any, codec any, schema any, opts *RehydrateOptions) (*RehydrateResult, error) {
+	var parseWarnings []Warning
+	raw, isRaw := data.(json.RawMessage)
+	if isRaw && opts.limitsEnabled() {
+		if err := checkOutputLimits(raw, opts); err != nil {
+			return nil, err
+		}
+	}
+	if isRaw && opts != nil {
+		parsed, warnings, err := parseModelOutput(raw, opts)
+		if err != nil {
+			return nil, err
+		}
+		data, parseWarnings = parsed, warnings
+	}
+
+	endMarshal := e.startPhase("rehydrate", PhaseMarshal)
+	dataBytes, codecBytes, schemaBytes, err := marshalRehydrateInputs(data, codec, schema)
+	endMarshal(err)
 	if err != nil {
-		return nil, fmt.Errorf("marshal codec: %w", err)
+		return nil, err
 	}
-	schemaBytes, err := json.Marshal(schema)
+	if !isRaw && opts.limitsEnabled() {
+		if err := checkOutputLimits(dataBytes, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	codecBytes, hostEntries, err := splitHostCodec(codecBytes)
 	if err != nil {
-		return nil, fmt.Errorf("marshal schema: %w", err)
+		return nil, fmt.Errorf("codec: %w", err)
+	}
+	hostEntries, customEntries := splitCustomEntries(hostEntries)
+	hostEntries, slimEntries := splitSlimEntries(hostEntries)
+	var customWarnings []Warning
+	if len(customEntries) > 0 {
+		if dataBytes, customWarnings, err = e.restoreCustom(dataBytes, customEntries); err != nil {
+			return nil, err
+		}
+	}
+	if len(slimEntries) > 0 {
+		var slimWarnings []Warning
+		if dataBytes, slimWarnings, err = restoreSlim(dataBytes, slimEntries); err != nil {
+			return nil, err
+		}
+		customWarnings = append(customWarnings, slimWarnings...)
+	}
+	var stages []any
+	if len(hostEntries) > 0 {
+		var original any
+		if err := json.Unmarshal(schemaBytes, &original); err != nil {
+			return nil, fmt.Errorf("decode schema: %w", err)
+		}
+		stages = hostStages(original, hostEntries)
+		if schemaBytes, err = json.Marshal(stages[len(stages)-1]); err != nil {
+			return nil, fmt.Errorf("marshal schema: %w", err)
+		}
 	}
 
 	payload, err := e.callJsl("jsl_rehydrate", dataBytes, codecBytes, schemaBytes)
 	if err != nil {
 		return nil, err
 	}
+	// Transforms such as JSON-string parsing can inflate the output; guard
+	// the result before decoding it too.
+	if opts.limitsEnabled() {
+		if err := checkOutputLimits(payload, opts); err != nil {
+			return nil, err
+		}
+	}
 
+	endUnmarshal := e.startPhase("rehydrate", PhaseUnmarshal)
 	var result RehydrateResult
-	if err := json.Unmarshal(payload, &result); err != nil {
+	err = json.Unmarshal(payload, &result)
+	endUnmarshal(err)
+	if err != nil {
 		return nil, fmt.Errorf("unmarshal rehydrate result: %w", err)
 	}
+	normalizeRootDataPaths(result.Warnings)
+	assignWarningIDs(result.Warnings)
+	if pre := append(parseWarnings, customWarnings...); len(pre) > 0 {
+		result.Warnings = append(pre, result.Warnings...)
+	}
+	if len(hostEntries) > 0 {
+		var hostWarnings []Warning
+		result.Data, hostWarnings = restoreHost(result.Data, stages, hostEntries)
+		result.Warnings = mergeWarnings(result.Warnings, hostWarnings)
+	}
+	if opts.sanitizeEnabled() {
+		var sanitizeWarnings []Warning
+		result.Data, sanitizeWarnings = sanitizeStrings(result.Data, opts)
+		result.Warnings = append(result.Warnings, sanitizeWarnings...)
+	}
+	result.Warnings, result.DroppedWarnings = e.sampler.sample(result.Warnings)
 	return &result, nil
 }
 
+// marshalConvertInputs encodes Convert's schema and options for the guest.
+func marshalConvertInputs(schema any, opts *ConvertOptions) (schemaBytes, optsBytes []byte, err error) {
+	schemaBytes, err = json.Marshal(schema)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal schema: %w", err)
+	}
+	if opts == nil {
+		return schemaBytes, []byte("{}"), nil
+	}
+	guestOpts := *opts
+	// Transforms are diffed from the guest's per-pass snapshots.
+	guestOpts.Trace = opts.Trace || opts.RecordTransforms
+	guestOpts.RecursionLimits = noUnrollLimits(opts.RecursionLimits, opts.Overrides)
+	guestOpts.DisablePasses = guestDisablePasses(opts.DisablePasses)
+	if optsBytes, err = json.Marshal(&guestOpts); err != nil {
+		return nil, nil, fmt.Errorf("marshal options: %w", err)
+	}
+	return schemaBytes, optsBytes, nil
+}
+
+// marshalRehydrateInputs encodes Rehydrate's data, codec and schema for the
+// guest.
+func marshalRehydrateInputs(data, codec, schema any) (dataBytes, codecBytes, schemaBytes []byte, err error) {
+	if dataBytes, err = json.Marshal(data); err != nil {
+		return nil, nil, nil, fmt.Errorf("marshal data: %w", err)
+	}
+	if codecBytes, err = json.Marshal(codec); err != nil {
+		return nil, nil, nil, fmt.Errorf("marshal codec: %w", err)
+	}
+	if schemaBytes, err = json.Marshal(schema); err != nil {
+		return nil, nil, nil, fmt.Errorf("marshal schema: %w", err)
+	}
+	return dataBytes, codecBytes, schemaBytes, nil
+}
+
 // ListComponents returns all extractable component JSON Pointers in a schema.
 func (e *SchemaLlmEngine) ListComponents(schema any) (*ListComponentsResult, error) {
 	schemaBytes, err := json.Marshal(schema)
@@ -288,11 +873,29 @@ func (e *SchemaLlmEngine) ListComponents(schema any) (*ListComponentsResult, err
 
 // ExtractComponent extracts a single component from a schema by JSON Pointer.
 func (e *SchemaLlmEngine) ExtractComponent(schema any, pointer string, opts *ExtractOptions) (*ExtractResult, error) {
+	span := e.startSpan("ExtractComponent", schema, attribute.String("jsl.pointer", pointer))
+	result, err := e.extractComponent(schema, pointer, opts)
+	if span != nil {
+		var attrs []attribute.KeyValue
+		if result != nil {
+			attrs = append(attrs, attribute.Int("jsl.dependency_count", result.DependencyCount))
+		}
+		endSpan(span, err, attrs...)
+	}
+	return result, err
+}
+
+func (e *SchemaLlmEngine) extractComponent(schema any, pointer string, opts *ExtractOptions) (*ExtractResult, error) {
 	schemaBytes, err := json.Marshal(schema)
 	if err != nil {
 		return nil, fmt.Errorf("marshal schema: %w", err)
 	}
 
+	if opts != nil {
+		if err := opts.Dependencies.validate(); err != nil {
+			return nil, err
+		}
+	}
 	pointerBytes := []byte(pointer)
 
 	var optsBytes []byte
@@ -307,6 +910,9 @@ func (e *SchemaLlmEngine) ExtractComponent(schema any, pointer string, opts *Ext
 
 	payload, err := e.callJsl("jsl_extract_component", schemaBytes, pointerBytes, optsBytes)
 	if err != nil {
+		if jslErr, ok := err.(*Error); ok && jslErr.Code == "unresolvable_ref" && !pointerResolves(schemaBytes, pointer) {
+			jslErr.sentinel = ErrPointerNotFound
+		}
 		return nil, err
 	}
 
@@ -314,57 +920,117 @@ func (e *SchemaLlmEngine) ExtractComponent(schema any, pointer string, opts *Ext
 	if err := json.Unmarshal(payload, &result); err != nil {
 		return nil, fmt.Errorf("unmarshal extract_component result: %w", err)
 	}
+	if opts != nil && opts.Dependencies == DependenciesInline {
+		inlineDependencies(result.Schema)
+	}
 	return &result, nil
 }
 
-// ConvertAllComponents converts a schema and all its discoverable components in one call.
+// ConvertAllComponents converts a schema and each of its discoverable
+// components with the same pipeline as Convert, so host-side options (host
+// passes, DisablePasses, budgets, limits, plugins and custom passes) apply
+// to every component, and each conversion runs through the interceptors
+// and gets Convert's span and debug log. A component that cannot be extracted or converted is
+// reported in its ComponentResult.Error; only failing to convert or list the
+// whole schema fails the call.
 func (e *SchemaLlmEngine) ConvertAllComponents(schema any, convertOpts *ConvertOptions, extractOpts *ExtractOptions) (*ConvertAllResult, error) {
+	if err := convertOpts.Validate(); err != nil {
+		return nil, err
+	}
 	schemaBytes, err := json.Marshal(schema)
 	if err != nil {
 		return nil, fmt.Errorf("marshal schema: %w", err)
 	}
+	raw := json.RawMessage(schemaBytes)
 
-	var convOptsBytes []byte
-	if convertOpts != nil {
-		convOptsBytes, err = json.Marshal(convertOpts)
-		if err != nil {
-			return nil, fmt.Errorf("marshal convert options: %w", err)
-		}
-	} else {
-		convOptsBytes = []byte("{}")
+	full, err := e.Convert(raw, convertOpts)
+	if err != nil {
+		return nil, err
 	}
-
-	var extOptsBytes []byte
-	if extractOpts != nil {
-		extOptsBytes, err = json.Marshal(extractOpts)
-		if err != nil {
-			return nil, fmt.Errorf("marshal extract options: %w", err)
-		}
-	} else {
-		extOptsBytes = []byte("{}")
+	fullBytes, err := json.Marshal(full)
+	if err != nil {
+		return nil, fmt.Errorf("marshal full result: %w", err)
 	}
-
-	payload, err := e.callJsl("jsl_convert_all_components", schemaBytes, convOptsBytes, extOptsBytes)
+	listed, err := e.ListComponents(raw)
 	if err != nil {
 		return nil, err
 	}
 
-	var result ConvertAllResult
-	if err := json.Unmarshal(payload, &result); err != nil {
-		return nil, fmt.Errorf("unmarshal convert_all_components result: %w", err)
+	result := &ConvertAllResult{
+		APIVersion: full.APIVersion,
+		Full:       fullBytes,
+		Components: make([]ComponentResult, 0, len(listed.Components)),
 	}
-	return &result, nil
+	for _, pointer := range listed.Components {
+		result.Components = append(result.Components, e.convertComponent(raw, pointer, convertOpts, extractOpts))
+	}
+	sort.SliceStable(result.Components, func(i, j int) bool {
+		return result.Components[i].Pointer < result.Components[j].Pointer
+	})
+	return result, nil
 }
 
 // callJsl executes a WASI export function following the JslResult protocol:
 // alloc → write → call → read result → parse → free.
 func (e *SchemaLlmEngine) callJsl(funcName string, jsonArgs ...[]byte) ([]byte, error) {
+	return e.callWithRetry(func() ([]byte, error) {
+		start := time.Now()
+		payload, err := e.callGuest(funcName, jsonArgs...)
+		e.logGuestCall(funcName, jsonArgs, len(payload), start, err)
+		return payload, err
+	})
+}
+
+// callJslInto is callJsl handing the payload to fn while it is still in
+// guest memory, instead of returning a copy. An error from fn is returned
+// as is.
+func (e *SchemaLlmEngine) callJslInto(funcName string, fn func(payload []byte) error, jsonArgs ...[]byte) error {
+	_, err := e.callWithRetry(func() ([]byte, error) {
+		start := time.Now()
+		n := 0
+		err := e.callGuestInto(funcName, func(payload []byte, _ bool) error {
+			n = len(payload)
+			return fn(payload)
+		}, jsonArgs...)
+		e.logGuestCall(funcName, jsonArgs, n, start, err)
+		return nil, err
+	})
+	return err
+}
+
+// callGuest runs one guest call and returns a copy of its payload.
+func (e *SchemaLlmEngine) callGuest(funcName string, jsonArgs ...[]byte) ([]byte, error) {
+	var payloadCopy []byte
+	err := e.callGuestInto(funcName, func(payload []byte, owned bool) error {
+		if owned {
+			payloadCopy = payload
+			return nil
+		}
+		payloadCopy = make([]byte, len(payload))
+		copy(payloadCopy, payload)
+		return nil
+	}, jsonArgs...)
+	if err != nil {
+		return nil, err
+	}
+	return payloadCopy, nil
+}
+
+// callGuestInto runs one guest call and, when it succeeds, passes its
+// payload to visit before freeing it. owned reports that the payload is a
+// host buffer (a decompressed result) rather than a view of guest memory,
+// which is only valid until visit returns.
+func (e *SchemaLlmEngine) callGuestInto(funcName string, visit func(payload []byte, owned bool) error, jsonArgs ...[]byte) (err error) {
 	// Instantiate a fresh module per call (wazero modules are single-use for WASI)
+	endInstantiate := e.startPhase(funcName, PhaseInstantiate)
 	mod, err := e.runtime.InstantiateModule(e.ctx, e.mod, wazero.NewModuleConfig())
+	endInstantiate(err)
 	if err != nil {
-		return nil, fmt.Errorf("instantiate: %w", err)
+		return transient(fmt.Errorf("instantiate: %w", err))
 	}
 	defer mod.Close(e.ctx)
+	endCall := e.startPhase(funcName, PhaseGuestCall)
+	defer func() { endCall(err) }()
 
 	jslAlloc := mod.ExportedFunction("jsl_alloc")
 	jslFree := mod.ExportedFunction("jsl_free")
@@ -372,28 +1038,43 @@ func (e *SchemaLlmEngine) callJsl(funcName string, jsonArgs ...[]byte) ([]byte,
 	fn := mod.ExportedFunction(funcName)
 
 	if jslAlloc == nil || jslFree == nil || jslResultFree == nil || fn == nil {
-		return nil, fmt.Errorf("missing export: %s", funcName)
+		return fmt.Errorf("missing export: %s", funcName)
 	}
 
 	// ABI version handshake (once per Engine lifetime)
 	if !e.abiVerified {
 		abiFn := mod.ExportedFunction("jsl_abi_version")
 		if abiFn == nil {
-			return nil, fmt.Errorf("incompatible WASM module: missing required 'jsl_abi_version' export")
+			return fmt.Errorf("incompatible WASM module: missing required 'jsl_abi_version' export")
 		}
 		results, err := abiFn.Call(e.ctx)
 		if err != nil {
-			return nil, fmt.Errorf("jsl_abi_version call failed: %w", err)
+			return fmt.Errorf("jsl_abi_version call failed: %w", err)
 		}
 		if len(results) != 1 {
-			return nil, fmt.Errorf("jsl_abi_version returned %d values, expected 1", len(results))
+			return fmt.Errorf("jsl_abi_version returned %d values, expected 1", len(results))
 		}
 		if results[0] != expectedABIVersion {
-			return nil, fmt.Errorf("ABI version mismatch: binary=%d, expected=%d", results[0], expectedABIVersion)
+			return fmt.Errorf("ABI version mismatch: binary=%d, expected=%d", results[0], expectedABIVersion)
 		}
 		e.abiVerified = true
 	}
 
+	// Capability negotiation (per instance — guest state does not outlive it)
+	var caps uint32
+	if e.compressMin > 0 {
+		if negotiate := mod.ExportedFunction("jsl_negotiate"); negotiate != nil {
+			results, err := negotiate.Call(e.ctx, uint64(capGzip), uint64(compressThreshold(e.compressMin)))
+			if err != nil {
+				return fmt.Errorf("jsl_negotiate call failed: %w", err)
+			}
+			if len(results) != 1 {
+				return fmt.Errorf("jsl_negotiate returned %d values, expected 1", len(results))
+			}
+			caps = uint32(results[0]) & capGzip
+		}
+	}
+
 	// Allocate and write each argument into guest memory.
 	//
 	// Memory safety: on error paths (alloc failure, fn.Call trap, etc.) we return
@@ -407,17 +1088,22 @@ func (e *SchemaLlmEngine) callJsl(funcName string, jsonArgs ...[]byte) ([]byte,
 	}
 	args := make([]ptrLen, len(jsonArgs))
 	for i, arg := range jsonArgs {
+		if caps&capGzip != 0 && len(arg) >= e.compressMin {
+			if arg, err = gzipBytes(arg); err != nil {
+				return fmt.Errorf("compress arg %d: %w", i, err)
+			}
+		}
 		results, err := jslAlloc.Call(e.ctx, uint64(len(arg)))
 		if err != nil {
-			return nil, fmt.Errorf("alloc: %w", err)
+			return transient(fmt.Errorf("alloc: %w", err))
 		}
 		ptr := uint32(results[0])
 		if ptr == 0 && len(arg) > 0 {
-			return nil, fmt.Errorf("alloc returned null for %d bytes", len(arg))
+			return transient(fmt.Errorf("alloc returned null for %d bytes", len(arg)))
 		}
 		if len(arg) > 0 {
 			if !mod.Memory().Write(ptr, arg) {
-				return nil, fmt.Errorf("memory write failed at ptr=%d len=%d", ptr, len(arg))
+				return fmt.Errorf("memory write failed at ptr=%d len=%d", ptr, len(arg))
 			}
 		}
 		args[i] = ptrLen{ptr: ptr, len: uint32(len(arg))}
@@ -432,17 +1118,20 @@ func (e *SchemaLlmEngine) callJsl(funcName string, jsonArgs ...[]byte) ([]byte,
 	// Call the function
 	results, err := fn.Call(e.ctx, flatArgs...)
 	if err != nil {
-		return nil, fmt.Errorf("%s trap: %w", funcName, err)
+		return transient(fmt.Errorf("%s trap: %w", funcName, err))
+	}
+	if size := mod.Memory().Size(); size > e.guestMemMax {
+		e.guestMemMax = size
 	}
 	resultPtr := uint32(results[0])
 	if resultPtr == 0 {
-		return nil, fmt.Errorf("%s returned null result pointer", funcName)
+		return fmt.Errorf("%s returned null result pointer", funcName)
 	}
 
 	// Read JslResult struct (12 bytes: 3 × LE u32)
 	resultBytes, ok := mod.Memory().Read(resultPtr, jslResultSize)
 	if !ok {
-		return nil, fmt.Errorf("failed to read JslResult at ptr=%d", resultPtr)
+		return fmt.Errorf("failed to read JslResult at ptr=%d", resultPtr)
 	}
 	status := binary.LittleEndian.Uint32(resultBytes[0:4])
 	payloadPtr := binary.LittleEndian.Uint32(resultBytes[4:8])
@@ -451,32 +1140,41 @@ func (e *SchemaLlmEngine) callJsl(funcName string, jsonArgs ...[]byte) ([]byte,
 	// Read JSON payload
 	payload, ok := mod.Memory().Read(payloadPtr, payloadLen)
 	if !ok {
-		return nil, fmt.Errorf("failed to read payload at ptr=%d len=%d", payloadPtr, payloadLen)
+		return fmt.Errorf("failed to read payload at ptr=%d len=%d", payloadPtr, payloadLen)
+	}
+	owned := false
+	if caps&capGzip != 0 && isGzip(payload) {
+		if payload, err = gunzipBytes(payload); err != nil {
+			return fmt.Errorf("%s decompress: %w", funcName, err)
+		}
+		owned = true
+	}
+
+	// Decode an error, or hand the payload over, before freeing it
+	var jslErr *Error
+	if status == statusError {
+		jslErr = &Error{}
+		if err := json.Unmarshal(payload, jslErr); err != nil {
+			return fmt.Errorf("error response (unparseable): %s", string(payload))
+		}
+	} else if err := visit(payload, owned); err != nil {
+		return err
 	}
-	// Copy payload before freeing
-	payloadCopy := make([]byte, len(payload))
-	copy(payloadCopy, payload)
 
 	// Free result (frees both struct and payload)
 	if _, err := jslResultFree.Call(e.ctx, uint64(resultPtr)); err != nil {
-		return nil, fmt.Errorf("result_free: %w", err)
+		return fmt.Errorf("result_free: %w", err)
 	}
 
 	// Free input buffers
 	for _, a := range args {
 		if _, err := jslFree.Call(e.ctx, uint64(a.ptr), uint64(a.len)); err != nil {
-			return nil, fmt.Errorf("free: %w", err)
+			return fmt.Errorf("free: %w", err)
 		}
 	}
 
-	// Check status
-	if status == statusError {
-		var jslErr Error
-		if err := json.Unmarshal(payloadCopy, &jslErr); err != nil {
-			return nil, fmt.Errorf("error response (unparseable): %s", string(payloadCopy))
-		}
-		return nil, &jslErr
+	if jslErr != nil {
+		return jslErr
 	}
-
-	return payloadCopy, nil
+	return nil
 }
@@ -2,6 +2,7 @@ package jsl
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 )
 
@@ -381,6 +382,9 @@ func TestExtractComponentError(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error for missing pointer, got nil")
 	}
+	if !errors.Is(err, ErrPointerNotFound) || !errors.Is(err, ErrUnresolvableRef) {
+		t.Errorf("errors.Is(%v, ErrPointerNotFound/ErrUnresolvableRef) = false", err)
+	}
 }
 
 // TestConvertAllComponents verifies batch conversion.
@@ -413,3 +417,128 @@ func TestConvertAllComponents(t *testing.T) {
 		t.Error("components should not be nil")
 	}
 }
+
+// TestConvertAllComponentsHostPasses verifies components go through the
+// host passes Convert runs, and that DisablePasses reaches them too.
+func TestConvertAllComponentsHostPasses(t *testing.T) {
+	eng, err := NewSchemaLlmEngine()
+	if err != nil {
+		t.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	schema := map[string]any{
+		"$defs": map[string]any{
+			"A": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"bad name!": map[string]any{"type": "string"}},
+			},
+		},
+	}
+	hostTypes := func(opts *ConvertOptions) []string {
+		t.Helper()
+		result, err := eng.ConvertAllComponents(schema, opts, nil)
+		if err != nil {
+			t.Fatalf("ConvertAllComponents() failed: %v", err)
+		}
+		if len(result.Components) != 1 || result.Components[0].Error != nil {
+			t.Fatalf("components = %+v", result.Components)
+		}
+		entries, err := CodecEntries(result.Components[0].Codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var types []string
+		for _, e := range entries {
+			if e.Kind == EntryKindHost {
+				types = append(types, e.Type)
+			}
+		}
+		return types
+	}
+
+	if got := hostTypes(&ConvertOptions{SanitizePropertyNames: true}); len(got) != 1 || got[0] != transformRename {
+		t.Errorf("host entries = %v, want one %s", got, transformRename)
+	}
+	if got := hostTypes(&ConvertOptions{SanitizePropertyNames: true, DisablePasses: []string{"rename"}}); len(got) != 0 {
+		t.Errorf("host entries with rename disabled = %v, want none", got)
+	}
+}
+
+// TestApplyConversionPatch verifies original + emitted patch reconstructs the converted schema.
+func TestApplyConversionPatch(t *testing.T) {
+	eng, err := NewSchemaLlmEngine()
+	if err != nil {
+		t.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string"},
+			"tags": map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+		},
+		"required": []any{"name"},
+	}
+
+	converted, err := eng.Convert(schema, &ConvertOptions{EmitPatch: true})
+	if err != nil {
+		t.Fatalf("Convert() failed: %v", err)
+	}
+	if len(converted.Patch) == 0 || converted.PatchFingerprint == "" {
+		t.Fatal("expected a patch and fingerprint")
+	}
+
+	applied, err := eng.ApplyConversionPatch(schema, converted.Patch, converted.PatchFingerprint)
+	if err != nil {
+		t.Fatalf("ApplyConversionPatch() failed: %v", err)
+	}
+	if !jsonEqual(applied.Schema, converted.Schema) {
+		t.Error("reconstructed schema differs from converted schema")
+	}
+
+	_, err = eng.ApplyConversionPatch(schema, converted.Patch, "0000")
+	if jslErr, ok := err.(*Error); !ok || jslErr.Code != "fingerprint_mismatch" {
+		t.Errorf("expected fingerprint_mismatch error, got %v", err)
+	}
+}
+
+// TestApplyConversionPatchBundled verifies a patch emitted with a RefResolver
+// applies to the caller's schema, not the bundled one.
+func TestApplyConversionPatchBundled(t *testing.T) {
+	eng, err := NewSchemaLlmEngine()
+	if err != nil {
+		t.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	schema := decodeJSON(t, `{
+		"$id": "https://schemas.internal/order.json",
+		"type": "object",
+		"properties": {"total": {"$ref": "https://schemas.internal/money.json"}}
+	}`)
+	converted, err := eng.Convert(schema, &ConvertOptions{EmitPatch: true, RefResolver: testRefResolver(t, map[string]int{})})
+	if err != nil {
+		t.Fatalf("Convert() failed: %v", err)
+	}
+	applied, err := eng.ApplyConversionPatch(schema, converted.Patch, converted.PatchFingerprint)
+	if err != nil {
+		t.Fatalf("ApplyConversionPatch() failed: %v", err)
+	}
+	if !jsonEqual(applied.Schema, converted.Schema) {
+		t.Error("reconstructed schema differs from converted schema")
+	}
+}
+
+// TestConvertOptionsRecursionLimitsJSON verifies per-pointer limits use the guest's kebab-case key.
+func TestConvertOptionsRecursionLimitsJSON(t *testing.T) {
+	b, err := json.Marshal(&ConvertOptions{RecursionLimit: 3, RecursionLimits: map[string]int{"#/$defs/TreeNode": 5}})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := `{"recursion-limit":3,"recursion-limits":{"#/$defs/TreeNode":5}}`
+	if string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+}
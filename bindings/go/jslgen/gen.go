@@ -0,0 +1,413 @@
+// Package jslgen generates Go code for converted schemas: a struct type
+// for the original shape, one for the converted (LLM-facing) shape, the
+// converted schema to send to the provider, and a typed rehydrate wrapper
+// tying them together through the codec.
+package jslgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// Schema is one schema to generate code for.
+type Schema struct {
+	// Name is the Go type name of the original shape (e.g. "Order"). The
+	// converted shape is Name+"LLM".
+	Name string
+	// Original is the source schema; Result is its conversion.
+	Original any
+	Result   *jsl.ConvertResult
+}
+
+// Options configures Generate.
+type Options struct {
+	// Package is the generated file's package name.
+	Package string
+}
+
+// Generate returns a gofmt'ed Go source file declaring, for each schema:
+// the Name and NameLLM types, NameLLMSchema (the converted schema as
+// json.RawMessage) and RehydrateName, which restores a NameLLM response to
+// a Name via jsl.RehydrateInto.
+func Generate(schemas []Schema, opts Options) ([]byte, error) {
+	if opts.Package == "" {
+		return nil, fmt.Errorf("jslgen: package name required")
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by jslgen. DO NOT EDIT.\n\npackage %s\n\n", opts.Package)
+	buf.WriteString("import (\n\t\"encoding/json\"\n\n\tjsl \"github.com/dotslashderek/json-schema-llm/bindings/go\"\n)\n")
+
+	taken := map[string]bool{}
+	for _, s := range schemas {
+		if !isIdent(s.Name) {
+			return nil, fmt.Errorf("jslgen: %q is not a Go identifier", s.Name)
+		}
+		if s.Result == nil {
+			return nil, fmt.Errorf("jslgen: %s: no convert result", s.Name)
+		}
+		original, err := decode(s.Original)
+		if err != nil {
+			return nil, fmt.Errorf("jslgen: %s: original schema: %w", s.Name, err)
+		}
+		schemaJSON, err := json.Marshal(original)
+		if err != nil {
+			return nil, fmt.Errorf("jslgen: %s: %w", s.Name, err)
+		}
+		convertedJSON, err := json.Marshal(s.Result.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("jslgen: %s: %w", s.Name, err)
+		}
+		codecJSON, err := json.Marshal(s.Result.Codec)
+		if err != nil {
+			return nil, fmt.Errorf("jslgen: %s: %w", s.Name, err)
+		}
+		converted, err := decode(convertedJSON)
+		if err != nil {
+			return nil, fmt.Errorf("jslgen: %s: converted schema: %w", s.Name, err)
+		}
+
+		for _, shape := range []struct {
+			name   string
+			schema any
+		}{{s.Name, original}, {s.Name + "LLM", converted}} {
+			if taken[shape.name] {
+				return nil, fmt.Errorf("jslgen: type %s declared twice", shape.name)
+			}
+			taken[shape.name] = true
+			g := &typeGen{root: shape.schema, taken: taken, defs: map[string]string{}, building: map[string]bool{}}
+			if err := g.named(shape.name, shape.schema, "#"); err != nil {
+				return nil, fmt.Errorf("jslgen: %s: %w", shape.name, err)
+			}
+			for _, d := range g.decls {
+				buf.WriteString("\n" + d)
+			}
+		}
+
+		lower := strings.ToLower(s.Name[:1]) + s.Name[1:]
+		fmt.Fprintf(&buf, "\n// %sLLMSchema is the converted schema to send to the provider.\n", s.Name)
+		fmt.Fprintf(&buf, "var %sLLMSchema = json.RawMessage(%s)\n", s.Name, strconv.Quote(string(convertedJSON)))
+		fmt.Fprintf(&buf, "\nvar (\n\t%sSchema = json.RawMessage(%s)\n\t%sCodec = json.RawMessage(%s)\n)\n",
+			lower, strconv.Quote(string(schemaJSON)), lower, strconv.Quote(string(codecJSON)))
+		fmt.Fprintf(&buf, "\n// Rehydrate%[1]s restores a %[1]sLLM model response to the %[1]s shape.\n", s.Name)
+		fmt.Fprintf(&buf, "func Rehydrate%[1]s(e *jsl.SchemaLlmEngine, data %[1]sLLM) (%[1]s, []jsl.Warning, error) {\n", s.Name)
+		fmt.Fprintf(&buf, "\treturn jsl.RehydrateInto[%s](e, data, %sCodec, %sSchema)\n}\n", s.Name, lower, lower)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("jslgen: format: %w\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+func decode(v any) (any, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		var err error
+		if b, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	var out any
+	return out, json.Unmarshal(b, &out)
+}
+
+// typeGen emits the named types of one shape. Nested objects become types
+// named after their parent and property; $defs targets are named after
+// the root and the definition.
+type typeGen struct {
+	decls    []string
+	root     any
+	rootName string
+	taken    map[string]bool
+	defs     map[string]string // $ref → Go type name
+	building map[string]bool
+}
+
+// named declares a type called name, already reserved in g.taken, for
+// schema. ref is the schema's $ref pointer, or "" for inline objects.
+func (g *typeGen) named(name string, schema any, ref string) error {
+	if g.rootName == "" {
+		g.rootName = name
+		g.defs["#"] = name
+	}
+	if ref != "" {
+		g.building[ref] = true
+		defer delete(g.building, ref)
+	}
+	// Reserve this type's slot so it precedes the nested types its fields
+	// declare.
+	slot := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	m, _ := schema.(map[string]any)
+	if props, ok := m["properties"].(map[string]any); ok {
+		required := map[string]bool{}
+		if req, ok := m["required"].([]any); ok {
+			for _, r := range req {
+				if s, ok := r.(string); ok {
+					required[s] = true
+				}
+			}
+		}
+		var fields bytes.Buffer
+		used := map[string]bool{}
+		for _, key := range sortedKeys(props) {
+			field := uniqueIdent(goIdent(key), used)
+			typ, err := g.typeOf(props[key], name+field, true)
+			if err != nil {
+				return fmt.Errorf("property %q: %w", key, err)
+			}
+			tag := key
+			if !required[key] {
+				tag += ",omitempty"
+				if isScalar(typ) {
+					typ = "*" + typ
+				}
+			}
+			fmt.Fprintf(&fields, "\t%s %s `json:%s`\n", field, typ, strconv.Quote(tag))
+		}
+		var decl bytes.Buffer
+		if d, ok := m["description"].(string); ok && d != "" {
+			decl.WriteString(comment(d))
+		}
+		fmt.Fprintf(&decl, "type %s struct {\n%s}\n", name, fields.String())
+		g.decls[slot] = decl.String()
+		return nil
+	}
+	typ, err := g.typeOf(schema, name+"Value", false)
+	if err != nil {
+		return err
+	}
+	g.decls[slot] = fmt.Sprintf("type %s %s\n", name, typ)
+	return nil
+}
+
+// typeOf returns the Go type expression for schema, declaring named types
+// for nested objects (as hint) and $ref targets. direct is true for
+// struct fields, where a reference back to a type still being declared
+// needs a pointer.
+func (g *typeGen) typeOf(schema any, hint string, direct bool) (string, error) {
+	m, ok := schema.(map[string]any)
+	if !ok {
+		return "json.RawMessage", nil
+	}
+	if ref, ok := m["$ref"].(string); ok {
+		return g.refType(ref, direct)
+	}
+	for _, kw := range []string{"anyOf", "oneOf"} {
+		branches, ok := m[kw].([]any)
+		if !ok {
+			continue
+		}
+		// X | null is a nullable X; other unions stay raw.
+		var nonNull []any
+		for _, b := range branches {
+			if bm, ok := b.(map[string]any); ok && bm["type"] == "null" {
+				continue
+			}
+			nonNull = append(nonNull, b)
+		}
+		if len(nonNull) == 1 && len(branches) == 2 {
+			typ, err := g.typeOf(nonNull[0], hint, false)
+			if err != nil {
+				return "", err
+			}
+			return nullable(typ), nil
+		}
+		return "json.RawMessage", nil
+	}
+
+	typ, isNull := schemaType(m)
+	var out string
+	switch typ {
+	case "string":
+		out = "string"
+	case "integer":
+		out = "int64"
+	case "number":
+		out = "float64"
+	case "boolean":
+		out = "bool"
+	case "array":
+		items, ok := m["items"]
+		if !ok {
+			out = "[]json.RawMessage"
+			break
+		}
+		elem, err := g.typeOf(items, hint+"Item", false)
+		if err != nil {
+			return "", err
+		}
+		out = "[]" + elem
+	case "object":
+		if _, ok := m["properties"]; ok {
+			name := uniqueIdent(hint, g.taken)
+			if err := g.named(name, m, ""); err != nil {
+				return "", err
+			}
+			out = name
+			break
+		}
+		if ap, ok := m["additionalProperties"].(map[string]any); ok {
+			elem, err := g.typeOf(ap, hint+"Value", false)
+			if err != nil {
+				return "", err
+			}
+			out = "map[string]" + elem
+			break
+		}
+		out = "map[string]json.RawMessage"
+	default:
+		return "json.RawMessage", nil
+	}
+	if isNull {
+		out = nullable(out)
+	}
+	return out, nil
+}
+
+// refType names the type of a local $ref target, declaring it on first
+// use.
+func (g *typeGen) refType(ref string, direct bool) (string, error) {
+	name, ok := g.defs[ref]
+	if !ok {
+		target, found := lookup(g.root, ref)
+		if !found {
+			return "", fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		name = uniqueIdent(g.rootName+goIdent(ref[strings.LastIndex(ref, "/")+1:]), g.taken)
+		g.defs[ref] = name
+		if err := g.named(name, target, ref); err != nil {
+			return "", err
+		}
+	}
+	if direct && g.building[ref] {
+		return "*" + name, nil
+	}
+	return name, nil
+}
+
+func lookup(root any, ref string) (any, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, false
+	}
+	node := root
+	for _, seg := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+		seg = strings.NewReplacer("~1", "/", "~0", "~").Replace(seg)
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if node, ok = m[seg]; !ok {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// schemaType returns the single non-null type of m and whether null is
+// also allowed.
+func schemaType(m map[string]any) (string, bool) {
+	switch t := m["type"].(type) {
+	case string:
+		return t, false
+	case []any:
+		var types []string
+		isNull := false
+		for _, v := range t {
+			if v == "null" {
+				isNull = true
+			} else if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		if len(types) == 1 {
+			return types[0], isNull
+		}
+	}
+	if _, ok := m["properties"]; ok {
+		return "object", false
+	}
+	return "", false
+}
+
+func nullable(typ string) string {
+	if strings.HasPrefix(typ, "*") || strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || typ == "json.RawMessage" {
+		return typ
+	}
+	return "*" + typ
+}
+
+func isScalar(typ string) bool {
+	return !strings.HasPrefix(typ, "*") && !strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "map[") && typ != "json.RawMessage"
+}
+
+// initialisms are upper-cased whole, per Go naming conventions.
+var initialisms = map[string]bool{"ID": true, "URL": true, "URI": true, "API": true, "HTTP": true, "JSON": true, "UUID": true, "IP": true, "SKU": true}
+
+// goIdent converts a property or definition name to an exported
+// identifier: "order_id" → "OrderID", "first-name" → "FirstName".
+func goIdent(s string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		if up := strings.ToUpper(word); initialisms[up] {
+			b.WriteString(up)
+			continue
+		}
+		rs := []rune(word)
+		rs[0] = unicode.ToUpper(rs[0])
+		b.WriteString(string(rs))
+	}
+	id := b.String()
+	if id == "" {
+		return "Field"
+	}
+	if !unicode.IsLetter([]rune(id)[0]) {
+		id = "F" + id
+	}
+	return id
+}
+
+func uniqueIdent(id string, used map[string]bool) string {
+	name := id
+	for i := 2; used[name]; i++ {
+		name = id + strconv.Itoa(i)
+	}
+	used[name] = true
+	return name
+}
+
+func isIdent(s string) bool {
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return s != "" && unicode.IsUpper([]rune(s)[0])
+}
+
+func comment(text string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		b.WriteString("// " + strings.TrimSpace(line) + "\n")
+	}
+	return b.String()
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,98 @@
+package jslgen
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+const orderOriginal = `{
+	"type": "object",
+	"description": "A customer order.",
+	"properties": {
+		"order_id": {"type": "string"},
+		"total": {"type": "number"},
+		"note": {"type": "string"},
+		"tags": {"type": "object", "additionalProperties": {"type": "string"}},
+		"lines": {"type": "array", "items": {"$ref": "#/$defs/Line"}},
+		"parent": {"$ref": "#"}
+	},
+	"required": ["order_id", "total", "lines"],
+	"$defs": {
+		"Line": {"type": "object", "properties": {"sku": {"type": "string"}, "qty": {"type": "integer"}}, "required": ["sku", "qty"]}
+	}
+}`
+
+const orderConverted = `{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"order_id": {"type": "string"},
+		"total": {"type": "number"},
+		"note": {"type": ["string", "null"]},
+		"tags": {"type": "array", "items": {"type": "object", "properties": {"key": {"type": "string"}, "value": {"type": "string"}}, "required": ["key", "value"]}},
+		"lines": {"type": "array", "items": {"type": "object", "properties": {"sku": {"type": "string"}, "qty": {"type": "integer"}}, "required": ["sku", "qty"]}},
+		"parent": {"anyOf": [{"type": "string"}, {"type": "null"}]}
+	},
+	"required": ["order_id", "total", "note", "tags", "lines", "parent"]
+}`
+
+func TestGenerate(t *testing.T) {
+	var converted map[string]any
+	if err := json.Unmarshal([]byte(orderConverted), &converted); err != nil {
+		t.Fatal(err)
+	}
+	src, err := Generate([]Schema{{
+		Name:     "Order",
+		Original: []byte(orderOriginal),
+		Result:   &jsl.ConvertResult{Schema: converted, Codec: map[string]any{"transforms": []any{}}},
+	}}, Options{Package: "schemas"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "gen.go", src, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+	// Compare with runs of spaces collapsed so gofmt alignment does not
+	// matter.
+	squash := func(s string) string { return strings.Join(strings.Fields(s), " ") }
+	code := squash(string(src))
+	for _, want := range []string{
+		"// A customer order.\ntype Order struct {",
+		"Lines   []OrderLine        `json:\"lines\"`",
+		"Note    *string            `json:\"note,omitempty\"`",
+		"OrderID string             `json:\"order_id\"`",
+		"Parent  *Order             `json:\"parent,omitempty\"`",
+		"Tags    map[string]string  `json:\"tags,omitempty\"`",
+		"type OrderLine struct {",
+		"type OrderLLM struct {",
+		"Note    *string                 `json:\"note\"`",
+		"Tags    []OrderLLMTagsItem      `json:\"tags\"`",
+		"type OrderLLMTagsItem struct {",
+		"var OrderLLMSchema = json.RawMessage(",
+		"func RehydrateOrder(e *jsl.SchemaLlmEngine, data OrderLLM) (Order, []jsl.Warning, error) {",
+		"return jsl.RehydrateInto[Order](e, data, orderCodec, orderSchema)",
+	} {
+		if !strings.Contains(code, squash(want)) {
+			t.Errorf("generated code missing %q\n%s", want, code)
+		}
+	}
+}
+
+func TestGoIdent(t *testing.T) {
+	for in, want := range map[string]string{
+		"order_id":   "OrderID",
+		"first-name": "FirstName",
+		"2fa":        "F2fa",
+		"$":          "Field",
+		"apiURL":     "ApiURL",
+	} {
+		if got := goIdent(in); got != want {
+			t.Errorf("goIdent(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+// Package jsltest provides an in-memory fake of the jsl engine for
+// application unit tests. It needs no wazero runtime or wasm binary: calls
+// return canned results registered per input, fall back to optional
+// functions, and otherwise behave as a no-op conversion (the schema is
+// returned unchanged with an empty codec, and Rehydrate returns the data
+// as given).
+package jsltest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// APIVersion is the apiVersion the fake reports, matching the engine's.
+const APIVersion = "1.0"
+
+// codecSchemaURI is the $schema of the empty codec the fake returns.
+const codecSchemaURI = "https://json-schema-llm.dev/codec/v1"
+
+// ErrClosed is returned by every call after Close.
+var ErrClosed = errors.New("jsltest: engine closed")
+
+var (
+	_ jsl.Converter          = (*Engine)(nil)
+	_ jsl.Rehydrator         = (*Engine)(nil)
+	_ jsl.ComponentConverter = (*Engine)(nil)
+)
+
+// Call records one method call on the fake.
+type Call struct {
+	Method string
+	Args   []any
+}
+
+type stub[T any] struct {
+	result *T
+	err    error
+}
+
+// Engine is a fake *jsl.SchemaLlmEngine. The zero value is ready to use
+// and safe for concurrent use.
+//
+// Each method resolves in order: a result registered with On* for the same
+// input (compared as canonical JSON), then the matching *Func field, then
+// the default no-op behavior. ConvertResult.Validator is unavailable on
+// results the fake builds.
+type Engine struct {
+	ConvertFunc              func(schema any, opts *jsl.ConvertOptions) (*jsl.ConvertResult, error)
+	RehydrateFunc            func(data, codec, schema any, opts *jsl.RehydrateOptions) (*jsl.RehydrateResult, error)
+	ListComponentsFunc       func(schema any) (*jsl.ListComponentsResult, error)
+	ExtractComponentFunc     func(schema any, pointer string, opts *jsl.ExtractOptions) (*jsl.ExtractResult, error)
+	ConvertAllComponentsFunc func(schema any, convertOpts *jsl.ConvertOptions, extractOpts *jsl.ExtractOptions) (*jsl.ConvertAllResult, error)
+
+	mu         sync.Mutex
+	converts   map[string]stub[jsl.ConvertResult]
+	rehydrates map[string]stub[jsl.RehydrateResult]
+	calls      []Call
+	closed     bool
+}
+
+// New returns an empty fake.
+func New() *Engine {
+	return &Engine{}
+}
+
+// OnConvert makes Convert return result and err for schema, whatever the
+// options. A nil schema matches every schema without a stub of its own.
+func (f *Engine) OnConvert(schema any, result *jsl.ConvertResult, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.converts == nil {
+		f.converts = map[string]stub[jsl.ConvertResult]{}
+	}
+	f.converts[key(schema)] = stub[jsl.ConvertResult]{result, err}
+}
+
+// OnRehydrate makes Rehydrate return result and err for data, whatever the
+// codec, schema and options. A nil data matches every input without a stub
+// of its own.
+func (f *Engine) OnRehydrate(data any, result *jsl.RehydrateResult, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.rehydrates == nil {
+		f.rehydrates = map[string]stub[jsl.RehydrateResult]{}
+	}
+	f.rehydrates[key(data)] = stub[jsl.RehydrateResult]{result, err}
+}
+
+// Calls returns the calls made so far, in order.
+func (f *Engine) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// CallCount returns how many times method was called.
+func (f *Engine) CallCount(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, c := range f.calls {
+		if c.Method == method {
+			n++
+		}
+	}
+	return n
+}
+
+// record logs a call and reports ErrClosed after Close.
+func (f *Engine) record(method string, args ...any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Method: method, Args: args})
+	if f.closed {
+		return ErrClosed
+	}
+	return nil
+}
+
+// key is the canonical JSON of v, decoded first so raw and decoded inputs
+// match. Unencodable values only match the nil stub.
+func key(v any) string {
+	if v == nil {
+		return ""
+	}
+	b, err := jsl.CanonicalJSON(decodeInput(v))
+	if err != nil {
+		return fmt.Sprintf("unencodable %T", v)
+	}
+	return string(b)
+}
+
+func decode(raw []byte) any {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return string(raw)
+	}
+	return v
+}
+
+func lookup[T any](f *Engine, stubs map[string]stub[T], k string) (stub[T], bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s, ok := stubs[k]; ok {
+		return s, true
+	}
+	s, ok := stubs[""]
+	return s, ok
+}
+
+// Convert returns the stubbed result for schema, ConvertFunc's, or the
+// schema unchanged with an empty codec.
+func (f *Engine) Convert(schema any, opts *jsl.ConvertOptions) (*jsl.ConvertResult, error) {
+	if err := f.record("Convert", schema, opts); err != nil {
+		return nil, err
+	}
+	if s, ok := lookup(f, f.converts, key(schema)); ok {
+		return s.result, s.err
+	}
+	if f.ConvertFunc != nil {
+		return f.ConvertFunc(schema, opts)
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	root, ok := decodeInput(schema).(map[string]any)
+	if !ok {
+		return nil, &jsl.Error{Code: "schema_error", Message: "root schema must be an object"}
+	}
+	schemaJSON, err := json.Marshal(root)
+	if err != nil {
+		return nil, fmt.Errorf("marshal schema: %w", err)
+	}
+	return &jsl.ConvertResult{
+		APIVersion: APIVersion,
+		Schema:     root,
+		Codec:      map[string]any{"$schema": codecSchemaURI, "transforms": []any{}, "droppedConstraints": []any{}},
+		SchemaJSON: schemaJSON,
+	}, nil
+}
+
+// ConvertWith is Convert with options built by jsl.NewConvertOptions.
+func (f *Engine) ConvertWith(schema any, opts ...jsl.ConvertOption) (*jsl.ConvertResult, error) {
+	o, err := jsl.NewConvertOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return f.Convert(schema, o)
+}
+
+// Rehydrate is RehydrateWithOptions without options.
+func (f *Engine) Rehydrate(data any, codec any, schema any) (*jsl.RehydrateResult, error) {
+	return f.RehydrateWithOptions(data, codec, schema, nil)
+}
+
+// RehydrateWithOptions returns the stubbed result for data,
+// RehydrateFunc's, or data unchanged (decoded first when it is a
+// json.RawMessage).
+func (f *Engine) RehydrateWithOptions(data any, codec any, schema any, opts *jsl.RehydrateOptions) (*jsl.RehydrateResult, error) {
+	if err := f.record("Rehydrate", data, codec, schema, opts); err != nil {
+		return nil, err
+	}
+	if s, ok := lookup(f, f.rehydrates, key(data)); ok {
+		return s.result, s.err
+	}
+	if f.RehydrateFunc != nil {
+		return f.RehydrateFunc(data, codec, schema, opts)
+	}
+	if raw, ok := data.(json.RawMessage); ok {
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("parse model output: %w", err)
+		}
+		data = v
+	}
+	return &jsl.RehydrateResult{APIVersion: APIVersion, Data: data}, nil
+}
+
+// ListComponents returns ListComponentsFunc's result, or no components.
+func (f *Engine) ListComponents(schema any) (*jsl.ListComponentsResult, error) {
+	if err := f.record("ListComponents", schema); err != nil {
+		return nil, err
+	}
+	if f.ListComponentsFunc != nil {
+		return f.ListComponentsFunc(schema)
+	}
+	return &jsl.ListComponentsResult{APIVersion: APIVersion, Components: []string{}}, nil
+}
+
+// ExtractComponent returns ExtractComponentFunc's result, or the node at
+// pointer with no dependencies.
+func (f *Engine) ExtractComponent(schema any, pointer string, opts *jsl.ExtractOptions) (*jsl.ExtractResult, error) {
+	if err := f.record("ExtractComponent", schema, pointer, opts); err != nil {
+		return nil, err
+	}
+	if f.ExtractComponentFunc != nil {
+		return f.ExtractComponentFunc(schema, pointer, opts)
+	}
+	node := decodeInput(schema)
+	for _, seg := range jsl.ParsePointer(pointer) {
+		m, ok := node.(map[string]any)
+		if !ok {
+			node = nil
+			break
+		}
+		node = m[seg]
+	}
+	m, ok := node.(map[string]any)
+	if !ok {
+		return nil, &jsl.Error{Code: "unresolvable_ref", Message: fmt.Sprintf("pointer %q not found", pointer), Path: pointer}
+	}
+	return &jsl.ExtractResult{APIVersion: APIVersion, Schema: m, Pointer: pointer, MissingRefs: []string{}}, nil
+}
+
+// ConvertAllComponents returns ConvertAllComponentsFunc's result, or the
+// no-op conversion of the full schema and no components.
+func (f *Engine) ConvertAllComponents(schema any, convertOpts *jsl.ConvertOptions, extractOpts *jsl.ExtractOptions) (*jsl.ConvertAllResult, error) {
+	if err := f.record("ConvertAllComponents", schema, convertOpts, extractOpts); err != nil {
+		return nil, err
+	}
+	if f.ConvertAllComponentsFunc != nil {
+		return f.ConvertAllComponentsFunc(schema, convertOpts, extractOpts)
+	}
+	full, err := json.Marshal(map[string]any{
+		"apiVersion": APIVersion,
+		"schema":     decodeInput(schema),
+		"codec":      map[string]any{"$schema": codecSchemaURI, "transforms": []any{}, "droppedConstraints": []any{}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal schema: %w", err)
+	}
+	return &jsl.ConvertAllResult{APIVersion: APIVersion, Full: full, Components: []jsl.ComponentResult{}}, nil
+}
+
+// Close makes every later call fail with ErrClosed.
+func (f *Engine) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+// decodeInput returns v as decoded JSON, the form the engine sees.
+func decodeInput(v any) any {
+	switch t := v.(type) {
+	case json.RawMessage:
+		return decode(t)
+	case []byte:
+		return decode(t)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return decode(b)
+}
@@ -0,0 +1,84 @@
+package jsltest
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+var orderSchema = map[string]any{"type": "object", "properties": map[string]any{"id": map[string]any{"type": "string"}}}
+
+// TestConvertStubs verifies canned results are matched by schema content,
+// the nil stub catches the rest, and calls are recorded.
+func TestConvertStubs(t *testing.T) {
+	f := New()
+	canned := &jsl.ConvertResult{APIVersion: APIVersion, Schema: map[string]any{"type": "object"}}
+	f.OnConvert(json.RawMessage(`{"properties": {"id": {"type": "string"}}, "type": "object"}`), canned, nil)
+	f.OnConvert(nil, nil, &jsl.Error{Code: "unsupported_feature", Message: "nope"})
+
+	if got, err := f.Convert(orderSchema, nil); err != nil || got != canned {
+		t.Errorf("Convert(orderSchema) = %v, %v; want the canned result", got, err)
+	}
+	_, err := f.ConvertWith(map[string]any{"type": "string"}, jsl.WithTarget(jsl.TargetGemini))
+	if !errors.Is(err, jsl.ErrUnsupportedFeature) {
+		t.Errorf("err = %v, want ErrUnsupportedFeature", err)
+	}
+	if n := f.CallCount("Convert"); n != 2 {
+		t.Errorf("Convert calls = %d", n)
+	}
+	if opts := f.Calls()[1].Args[1].(*jsl.ConvertOptions); opts.Target != jsl.TargetGemini {
+		t.Errorf("recorded options = %+v", opts)
+	}
+}
+
+// TestDefaults verifies the no-op conversion and rehydration.
+func TestDefaults(t *testing.T) {
+	var f Engine
+	result, err := f.Convert(orderSchema, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Schema, orderSchema) || len(result.SchemaJSON) == 0 {
+		t.Errorf("Convert = %+v", result)
+	}
+	if _, err := f.Convert([]any{}, nil); !errors.Is(err, jsl.ErrSchemaInvalid) {
+		t.Errorf("non-object schema: err = %v", err)
+	}
+	rehydrated, err := f.Rehydrate(json.RawMessage(`{"id": "a1"}`), result.Codec, orderSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]any{"id": "a1"}; !reflect.DeepEqual(rehydrated.Data, want) {
+		t.Errorf("Rehydrate = %v, want %v", rehydrated.Data, want)
+	}
+	extracted, err := f.ExtractComponent(orderSchema, "#/properties/id", nil)
+	if err != nil || extracted.Schema["type"] != "string" {
+		t.Errorf("ExtractComponent = %+v, %v", extracted, err)
+	}
+	if _, err := f.ExtractComponent(orderSchema, "#/properties/missing", nil); !errors.Is(err, jsl.ErrUnresolvableRef) {
+		t.Errorf("missing pointer: err = %v", err)
+	}
+}
+
+// TestRehydrateFuncAndClose verifies RehydrateFunc answers unstubbed data
+// and calls fail after Close.
+func TestRehydrateFuncAndClose(t *testing.T) {
+	f := &Engine{RehydrateFunc: func(data, codec, schema any, opts *jsl.RehydrateOptions) (*jsl.RehydrateResult, error) {
+		return &jsl.RehydrateResult{Data: "from func"}, nil
+	}}
+	f.OnRehydrate(map[string]any{"id": "stubbed"}, &jsl.RehydrateResult{Data: "from stub"}, nil)
+
+	if got, _ := f.Rehydrate(map[string]any{"id": "stubbed"}, nil, nil); got.Data != "from stub" {
+		t.Errorf("stubbed data = %v", got.Data)
+	}
+	if got, _ := f.RehydrateWithOptions(map[string]any{"id": "other"}, nil, nil, &jsl.RehydrateOptions{}); got.Data != "from func" {
+		t.Errorf("other data = %v", got.Data)
+	}
+	f.Close()
+	if _, err := f.Convert(orderSchema, nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("after Close: err = %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+module github.com/dotslashderek/json-schema-llm/bindings/go/langchain
+
+go 1.24.4
+
+require (
+	github.com/dotslashderek/json-schema-llm/bindings/go v0.0.0
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.1
+	github.com/tmc/langchaingo v0.1.14
+)
+
+require (
+	github.com/dlclark/regexp2 v1.11.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/pkoukk/tiktoken-go v0.1.6 // indirect
+	github.com/tetratelabs/wazero v1.8.2 // indirect
+	go.opentelemetry.io/otel v1.36.0 // indirect
+	go.opentelemetry.io/otel/trace v1.36.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+)
+
+replace github.com/dotslashderek/json-schema-llm/bindings/go => ../
@@ -0,0 +1,116 @@
+// Package langchain provides a langchaingo output parser that restores
+// structured chain output to its original schema: it rehydrates the model's
+// JSON with the conversion codec, validates the result against the
+// original schema, and surfaces rehydration warnings.
+//
+// It is a separate module so the core binding does not depend on
+// langchaingo.
+package langchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/outputparser"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Rehydrator is the engine surface Parser needs.
+type Rehydrator interface {
+	RehydrateWithOptions(data any, codec any, schema any, opts *jsl.RehydrateOptions) (*jsl.RehydrateResult, error)
+}
+
+// Output is what Parser returns: the rehydrated data and the warnings
+// rehydration produced.
+type Output struct {
+	Data     any
+	Warnings []jsl.Warning
+}
+
+// Parser is a schema.OutputParser for output generated against a converted
+// schema.
+type Parser struct {
+	Engine Rehydrator
+	// Result is the conversion the chain's response format came from.
+	Result *jsl.ConvertResult
+	// Schema is the original schema Result was converted from.
+	Schema any
+	// Options are passed to every rehydration.
+	Options *jsl.RehydrateOptions
+	// Validator checks the rehydrated data; nil uses Result.Validator().
+	Validator *jsonschema.Schema
+	// SkipValidation skips validating the rehydrated data.
+	SkipValidation bool
+}
+
+var _ schema.OutputParser[Output] = Parser{}
+
+// New returns a Parser for output generated against r.
+func New(e Rehydrator, r *jsl.ConvertResult, original any) Parser {
+	return Parser{Engine: e, Result: r, Schema: original}
+}
+
+// GetFormatInstructions asks for a single JSON value matching the
+// converted schema.
+func (p Parser) GetFormatInstructions() string {
+	schemaJSON := p.Result.SchemaJSON
+	if len(schemaJSON) == 0 {
+		schemaJSON, _ = json.Marshal(p.Result.Schema)
+	}
+	return "Respond with a single JSON value, and nothing else, that conforms to this JSON Schema:\n```json\n" + string(schemaJSON) + "\n```"
+}
+
+// Parse rehydrates text, optionally wrapped in a Markdown code fence, and
+// validates the result. Malformed JSON and validation failures are
+// returned as outputparser.ParseError.
+func (p Parser) Parse(text string) (Output, error) {
+	raw := stripFence(text)
+	if !json.Valid([]byte(raw)) {
+		return Output{}, outputparser.ParseError{Text: text, Reason: "output is not valid JSON"}
+	}
+	res, err := p.Engine.RehydrateWithOptions(json.RawMessage(raw), p.Result.Codec, p.Schema, p.Options)
+	if err != nil {
+		return Output{}, fmt.Errorf("jsl langchain: rehydrate: %w", err)
+	}
+	out := Output{Data: res.Data, Warnings: res.Warnings}
+	if p.SkipValidation {
+		return out, nil
+	}
+	v := p.Validator
+	if v == nil {
+		if v, err = p.Result.Validator(); err != nil {
+			return Output{}, fmt.Errorf("jsl langchain: %w", err)
+		}
+	}
+	if err := v.Validate(res.Data); err != nil {
+		return out, outputparser.ParseError{Text: text, Reason: err.Error()}
+	}
+	return out, nil
+}
+
+// ParseWithPrompt is Parse; the prompt is not needed.
+func (p Parser) ParseWithPrompt(text string, _ llms.PromptValue) (Output, error) {
+	return p.Parse(text)
+}
+
+// Type returns the parser's type name.
+func (p Parser) Type() string {
+	return "jsl_output_parser"
+}
+
+// stripFence removes a surrounding ``` or ```json fence.
+func stripFence(text string) string {
+	s := strings.TrimSpace(text)
+	if !strings.HasPrefix(s, "```") || !strings.HasSuffix(s, "```") || len(s) < 6 {
+		return s
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "```"), "```")
+	if nl := strings.IndexByte(s, '\n'); nl >= 0 && !strings.ContainsAny(s[:nl], "{[\"") {
+		s = s[nl+1:]
+	}
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,77 @@
+package langchain
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/tmc/langchaingo/outputparser"
+)
+
+const originalSchema = `{"type": "object", "properties": {"age": {"type": "integer", "minimum": 0}}, "required": ["age"]}`
+
+// fakeEngine returns the raw output as the rehydrated data, with one
+// warning.
+type fakeEngine struct{}
+
+func (fakeEngine) RehydrateWithOptions(data, _, _ any, _ *jsl.RehydrateOptions) (*jsl.RehydrateResult, error) {
+	var v any
+	if err := json.Unmarshal(data.(json.RawMessage), &v); err != nil {
+		return nil, err
+	}
+	return &jsl.RehydrateResult{Data: v, Warnings: []jsl.Warning{{DataPath: "/age", Kind: jsl.WarningKind{Type: jsl.WarnEnumCoerced}}}}, nil
+}
+
+func newParser(t *testing.T) Parser {
+	t.Helper()
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(originalSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(jsl.ValidatorSchemaURL, doc); err != nil {
+		t.Fatal(err)
+	}
+	v, err := c.Compile(jsl.ValidatorSchemaURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := New(fakeEngine{}, &jsl.ConvertResult{Schema: map[string]any{"type": "object"}, SchemaJSON: json.RawMessage(`{"type":"object"}`)}, nil)
+	p.Validator = v
+	return p
+}
+
+// TestParse verifies fenced output is rehydrated, validated and returned
+// with its warnings.
+func TestParse(t *testing.T) {
+	p := newParser(t)
+	out, err := p.Parse("```json\n{\"age\": 3}\n```")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Data.(map[string]any)["age"] != 3.0 || len(out.Warnings) != 1 {
+		t.Errorf("output = %+v", out)
+	}
+	if !strings.Contains(p.GetFormatInstructions(), "```json\n{\"type\":\"object\"}\n```") {
+		t.Errorf("instructions = %s", p.GetFormatInstructions())
+	}
+}
+
+// TestParseErrors verifies malformed and invalid output are ParseErrors.
+func TestParseErrors(t *testing.T) {
+	p := newParser(t)
+	for _, text := range []string{`{"age": `, `{"age": -1}`} {
+		_, err := p.Parse(text)
+		var pe outputparser.ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("Parse(%q) error = %v, want a ParseError", text, err)
+		}
+	}
+	p.SkipValidation = true
+	if _, err := p.Parse(`{"age": -1}`); err != nil {
+		t.Errorf("skip validation: %v", err)
+	}
+}
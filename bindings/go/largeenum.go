@@ -0,0 +1,152 @@
+package jsl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// transformLargeEnum records an oversized string enum replaced by a plain
+// string so Rehydrate can match the returned value against the original
+// values.
+const transformLargeEnum = "large_enum"
+
+const (
+	// largeEnumSampleSize is how many values the description hint lists.
+	largeEnumSampleSize = 5
+	// largeEnumMaxDistance is the largest edit distance a fuzzy match may
+	// have, after normalization.
+	largeEnumMaxDistance = 2
+)
+
+// degradeLargeEnums replaces every all-string enum with more than
+// opts.MaxEnumValues values by a string whose description lists a sample,
+// except where a KeepEnum override applies.
+func degradeLargeEnums(schema any, opts *ConvertOptions) (any, []HostTransform, error) {
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		enum, ok := node["enum"].([]any)
+		if !ok || len(enum) <= opts.MaxEnumValues || !allStrings(enum) || opts.override(loc).KeepEnum {
+			return node
+		}
+		t := HostTransform{Type: transformLargeEnum, Path: loc, Params: map[string]any{"values": enum}}
+		entries = append(entries, t)
+		return rewriteLargeEnum(node, &t)
+	})
+	return schema, entries, nil
+}
+
+func rewriteLargeEnum(n any, t *HostTransform) any {
+	node, ok := n.(map[string]any)
+	if !ok {
+		return n
+	}
+	values, _ := t.Params["values"].([]any)
+	delete(node, "enum")
+	node["type"] = "string"
+	sample := make([]string, 0, largeEnumSampleSize)
+	for _, v := range values[:min(len(values), largeEnumSampleSize)] {
+		sample = append(sample, strconv.Quote(fmt.Sprint(v)))
+	}
+	hint := fmt.Sprintf("Must be one of %d allowed values, e.g. %s.", len(values), strings.Join(sample, ", "))
+	if desc, ok := node["description"].(string); ok && desc != "" {
+		node["description"] = strings.TrimRight(desc, " ") + " " + hint
+	} else {
+		node["description"] = hint
+	}
+	return node
+}
+
+// restoreLargeEnum accepts exact matches, snaps near-misses (case,
+// punctuation, small typos) to the single closest allowed value with a
+// warning, and warns on anything else.
+func restoreLargeEnum(v any, t *HostTransform, dataPath string) (any, []Warning) {
+	s, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+	values, _ := t.Params["values"].([]any)
+	for _, allowed := range values {
+		if allowed == s {
+			return v, nil
+		}
+	}
+	if match, ok := closestEnumValue(s, values); ok {
+		return match, []Warning{{
+			DataPath:   dataPath,
+			SchemaPath: t.Path,
+			Kind:       WarningKind{Type: WarnEnumCoerced, Constraint: "enum"},
+			Message:    fmt.Sprintf("%q matched to enum value %q", s, match),
+		}}
+	}
+	return v, []Warning{{
+		DataPath:   dataPath,
+		SchemaPath: t.Path,
+		Kind:       WarningKind{Type: WarnConstraintViolation, Constraint: "enum"},
+		Message:    fmt.Sprintf("%q is not one of the %d enum values", s, len(values)),
+	}}
+}
+
+// closestEnumValue returns the unique allowed value nearest to s after
+// normalization, within largeEnumMaxDistance edits.
+func closestEnumValue(s string, values []any) (string, bool) {
+	target := normalizeEnumValue(s)
+	best, bestDist, tied := "", largeEnumMaxDistance+1, false
+	for _, v := range values {
+		allowed, _ := v.(string)
+		d := editDistance(target, normalizeEnumValue(allowed), bestDist+1)
+		switch {
+		case d < bestDist:
+			best, bestDist, tied = allowed, d, false
+		case d == bestDist && allowed != best:
+			tied = true
+		}
+	}
+	if bestDist > largeEnumMaxDistance || tied {
+		return "", false
+	}
+	return best, true
+}
+
+// normalizeEnumValue lowercases s and drops everything but letters and digits.
+func normalizeEnumValue(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// editDistance is the Levenshtein distance between a and b, or limit when
+// it is at least limit.
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d >= limit || -d >= limit {
+		return limit
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin >= limit {
+			return limit
+		}
+		prev, cur = cur, prev
+	}
+	return min(prev[len(rb)], limit)
+}
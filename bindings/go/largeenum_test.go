@@ -0,0 +1,83 @@
+package jsl
+
+import (
+	"fmt"
+	"testing"
+)
+
+func countrySchema(n int) map[string]any {
+	values := make([]any, n)
+	for i := range values {
+		values[i] = fmt.Sprintf("Country %03d", i)
+	}
+	values[0], values[1] = "United Kingdom", "United States"
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"country": map[string]any{"type": "string", "enum": values},
+			"small":   map[string]any{"type": "string", "enum": []any{"a", "b"}},
+		},
+	}
+}
+
+// TestDegradeLargeEnums verifies only oversized enums are replaced.
+func TestDegradeLargeEnums(t *testing.T) {
+	out, entries, err := degradeLargeEnums(countrySchema(600), &ConvertOptions{MaxEnumValues: 500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Path != "#/properties/country" {
+		t.Fatalf("entries = %+v", entries)
+	}
+	country, _ := lookupPointer(out, "#/properties/country")
+	if _, hasEnum := country.(map[string]any)["enum"]; hasEnum {
+		t.Error("large enum kept")
+	}
+	if desc := country.(map[string]any)["description"]; desc != `Must be one of 600 allowed values, e.g. "United Kingdom", "United States", "Country 002", "Country 003", "Country 004".` {
+		t.Errorf("description = %v", desc)
+	}
+	if small, _ := lookupPointer(out, "#/properties/small/enum"); small == nil {
+		t.Error("small enum degraded")
+	}
+}
+
+// TestRestoreLargeEnum verifies exact, fuzzy, ambiguous and unknown values.
+func TestRestoreLargeEnum(t *testing.T) {
+	schema := decodeJSON(t, string(mustMarshal(countrySchema(600))))
+	_, entries, _ := degradeLargeEnums(deepCopyJSON(schema), &ConvertOptions{MaxEnumValues: 500})
+	entries = roundtripEntries(t, entries)
+	stages := hostStages(schema, entries)
+	for _, tc := range []struct {
+		in, want string
+		kind     string
+	}{
+		{"United Kingdom", "United Kingdom", ""},
+		{"united-kingdom", "United Kingdom", "enum_coerced"},
+		{"Untied Kingdom", "United Kingdom", "enum_coerced"},
+		{"Country 01", "Country 01", "constraint_violation"}, // ties Country 010..019
+		{"Atlantis", "Atlantis", "constraint_violation"},
+	} {
+		data := decodeJSON(t, fmt.Sprintf(`{"country":%q}`, tc.in))
+		got, warnings := restoreHost(data, stages, entries)
+		if v := got.(map[string]any)["country"]; v != tc.want {
+			t.Errorf("%q → %v, want %q", tc.in, v, tc.want)
+		}
+		kind := ""
+		if len(warnings) > 0 {
+			kind = warnings[0].Kind.Type
+		}
+		if kind != tc.kind {
+			t.Errorf("%q warning kind = %q, want %q", tc.in, kind, tc.kind)
+		}
+	}
+}
+
+// TestEditDistance verifies the bounded Levenshtein distance.
+func TestEditDistance(t *testing.T) {
+	if d := editDistance("kitten", "sitting", 10); d != 3 {
+		t.Errorf("distance = %d", d)
+	}
+	if d := editDistance("kitten", "sitting", 2); d != 2 {
+		t.Errorf("bounded distance = %d", d)
+	}
+}
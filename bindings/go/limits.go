@@ -0,0 +1,52 @@
+package jsl
+
+import "fmt"
+
+// LimitError reports model output that exceeded a RehydrateOptions guard.
+// Path is the data path where the limit was crossed (empty for MaxBytes).
+type LimitError struct {
+	Limit  string // "MaxBytes", "MaxDepth" or "MaxItems"
+	Max    int
+	Actual int
+	Path   string
+}
+
+func (e *LimitError) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("jsl limit %s exceeded at %s: %d > %d", e.Limit, e.Path, e.Actual, e.Max)
+	}
+	return fmt.Sprintf("jsl limit %s exceeded: %d > %d", e.Limit, e.Actual, e.Max)
+}
+
+// limitsEnabled reports whether any size guard is configured.
+func (o *RehydrateOptions) limitsEnabled() bool {
+	return o != nil && (o.MaxBytes > 0 || o.MaxDepth > 0 || o.MaxItems > 0)
+}
+
+// checkOutputLimits enforces MaxBytes, MaxDepth and MaxItems on serialized
+// output in a single scan, before anything is decoded.
+func checkOutputLimits(b []byte, opts *RehydrateOptions) error {
+	if opts.MaxBytes > 0 && len(b) > opts.MaxBytes {
+		return &LimitError{Limit: "MaxBytes", Max: opts.MaxBytes, Actual: len(b)}
+	}
+	if opts.MaxDepth <= 0 && opts.MaxItems <= 0 {
+		return nil
+	}
+	sc := newOutputScanner(b)
+	for sc.next() {
+		if sc.kind != tokPunct {
+			continue
+		}
+		switch sc.token()[0] {
+		case '{', '[':
+			if opts.MaxDepth > 0 && len(sc.stack) > opts.MaxDepth {
+				return &LimitError{Limit: "MaxDepth", Max: opts.MaxDepth, Actual: len(sc.stack), Path: sc.containerPath()}
+			}
+		case ',':
+			if top := sc.top(); opts.MaxItems > 0 && top != nil && top.array && top.index >= opts.MaxItems {
+				return &LimitError{Limit: "MaxItems", Max: opts.MaxItems, Actual: top.index + 1, Path: sc.containerPath()}
+			}
+		}
+	}
+	return nil
+}
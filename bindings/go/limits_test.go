@@ -0,0 +1,44 @@
+package jsl
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestCheckOutputLimits verifies each guard trips with a typed error and the offending path.
+func TestCheckOutputLimits(t *testing.T) {
+	raw := []byte(`{"a": {"b": [1, 2, 3, {"c": "[[[not structure]]]"}]}}`)
+
+	tests := []struct {
+		name      string
+		opts      RehydrateOptions
+		wantLimit string
+		wantPath  string
+	}{
+		{"within limits", RehydrateOptions{MaxBytes: 1024, MaxDepth: 4, MaxItems: 4}, "", ""},
+		{"bytes", RehydrateOptions{MaxBytes: 10}, "MaxBytes", ""},
+		{"depth", RehydrateOptions{MaxDepth: 3}, "MaxDepth", "/a/b/3"},
+		{"items", RehydrateOptions{MaxItems: 2}, "MaxItems", "/a/b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkOutputLimits(raw, &tt.opts)
+			if tt.wantLimit == "" {
+				if err != nil {
+					t.Fatalf("checkOutputLimits() = %v, want nil", err)
+				}
+				return
+			}
+			var le *LimitError
+			if !errors.As(err, &le) {
+				t.Fatalf("checkOutputLimits() = %v, want *LimitError", err)
+			}
+			if le.Limit != tt.wantLimit || le.Path != tt.wantPath {
+				t.Errorf("got %s at %q, want %s at %q", le.Limit, le.Path, tt.wantLimit, tt.wantPath)
+			}
+			if le.Actual <= le.Max {
+				t.Errorf("Actual %d should exceed Max %d", le.Actual, le.Max)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package jsl
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// WithLogger has the engine emit debug-level records to l: one per guest
+// call (call name, payload sizes, duration) and one per Convert and
+// Rehydrate (target, duration, warning counts, error code). Records are
+// built only when l is enabled for slog.LevelDebug.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *engineConfig) {
+		c.logger = l
+	}
+}
+
+// debugEnabled reports whether debug records would be kept.
+func (e *SchemaLlmEngine) debugEnabled() bool {
+	return e != nil && e.logger != nil && e.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// logGuestCall records one callJsl round trip.
+func (e *SchemaLlmEngine) logGuestCall(funcName string, args [][]byte, outputBytes int, start time.Time, err error) {
+	if !e.debugEnabled() {
+		return
+	}
+	in := 0
+	for _, a := range args {
+		in += len(a)
+	}
+	attrs := []slog.Attr{
+		slog.String("call", funcName),
+		slog.Int("input_bytes", in),
+		slog.Int("output_bytes", outputBytes),
+		slog.Duration("duration", time.Since(start)),
+	}
+	e.logger.LogAttrs(context.Background(), slog.LevelDebug, "jsl guest call", append(attrs, errorAttrs(err)...)...)
+}
+
+// logOp records one public operation; attrs are its result counters.
+func (e *SchemaLlmEngine) logOp(op string, start time.Time, err error, attrs ...slog.Attr) {
+	if !e.debugEnabled() {
+		return
+	}
+	attrs = append([]slog.Attr{slog.String("op", op), slog.Duration("duration", time.Since(start))}, attrs...)
+	e.logger.LogAttrs(context.Background(), slog.LevelDebug, "jsl "+op, append(attrs, errorAttrs(err)...)...)
+}
+
+func errorAttrs(err error) []slog.Attr {
+	if err == nil {
+		return nil
+	}
+	attrs := []slog.Attr{slog.String("error", err.Error())}
+	var jslErr *Error
+	if errors.As(err, &jslErr) {
+		attrs = append(attrs, slog.String("code", jslErr.Code))
+	}
+	return attrs
+}
@@ -0,0 +1,79 @@
+package jsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestWithLogger verifies Convert emits a guest call record and an op record
+// carrying the target and warning counts.
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	eng, err := NewSchemaLlmEngine(WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	schema := json.RawMessage(`{"type":"object","properties":{"a":{"type":"string"}}}`)
+	if _, err := eng.Convert(schema, nil); err != nil {
+		t.Fatal(err)
+	}
+	var ops, calls int
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("bad record %q: %v", line, err)
+		}
+		switch rec["msg"] {
+		case "jsl guest call":
+			calls++
+			if rec["call"] == "" || rec["input_bytes"].(float64) == 0 {
+				t.Errorf("guest call record = %v", rec)
+			}
+		case "jsl convert":
+			ops++
+			if rec["target"] != "openai-strict" || rec["warnings"] == nil || rec["error"] != nil {
+				t.Errorf("convert record = %v", rec)
+			}
+		}
+	}
+	if ops != 1 || calls == 0 {
+		t.Errorf("got %d convert and %d guest call records:\n%s", ops, calls, buf.String())
+	}
+}
+
+// TestWithLoggerInfoLevel verifies nothing is logged above debug level.
+func TestWithLoggerInfoLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	eng, err := NewSchemaLlmEngine(WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	if _, err := eng.Convert(json.RawMessage(`{"type":"string"}`), nil); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("unexpected records:\n%s", buf.String())
+	}
+}
+
+// TestErrorAttrs verifies wrapped *Error values contribute their code.
+func TestErrorAttrs(t *testing.T) {
+	err := &Error{Code: "schema_error", Message: "bad"}
+	attrs := errorAttrs(fmt.Errorf("convert: %w", err))
+	if len(attrs) != 2 || attrs[1].Value.String() != "schema_error" {
+		t.Errorf("attrs = %v", attrs)
+	}
+	if errorAttrs(nil) != nil {
+		t.Error("nil error produced attrs")
+	}
+}
@@ -0,0 +1,92 @@
+package jsl
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// transformNot records a stripped `not` subschema so Rehydrate can check the
+// returned value does not match it.
+const transformNot = "not"
+
+// notEvaluableKeywords are the keywords restoreNot and restoreConditional
+// can decide exactly (see instanceWalker.admits); annotations are ignored. A
+// subschema using anything else is reported as unevaluable rather than
+// guessed at.
+var notEvaluableKeywords = map[string]bool{
+	"type": true, "const": true, "enum": true, "required": true, "properties": true,
+	"title": true, "description": true, "$comment": true, "default": true, "examples": true,
+}
+
+// stripNot removes every `not` keyword, recording its subschema.
+func stripNot(schema any, _ *ConvertOptions) (any, []HostTransform, error) {
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		sub, ok := node["not"]
+		if !ok {
+			return node
+		}
+		t := HostTransform{Type: transformNot, Path: loc, Params: map[string]any{"schema": sub}}
+		entries = append(entries, t)
+		return rewriteNot(node, &t)
+	})
+	return schema, entries, nil
+}
+
+func rewriteNot(n any, _ *HostTransform) any {
+	if node, ok := n.(map[string]any); ok {
+		delete(node, "not")
+	}
+	return n
+}
+
+// restoreNot warns when the rehydrated value matches the negated schema.
+func restoreNot(v any, t *HostTransform, dataPath string) (any, []Warning) {
+	sub := t.Params["schema"]
+	if !notEvaluable(sub) {
+		return v, []Warning{{
+			DataPath:   dataPath,
+			SchemaPath: t.Path,
+			Kind:       WarningKind{Type: WarnConstraintUnevaluable, Constraint: "not"},
+			Message:    "constraint 'not' uses keywords that cannot be checked on rehydrate",
+		}}
+	}
+	w := &instanceWalker{regexes: map[string]*regexp.Regexp{}}
+	if !w.admits(sub, v, 0) {
+		return v, nil
+	}
+	return v, []Warning{{
+		DataPath:   dataPath,
+		SchemaPath: t.Path,
+		Kind:       WarningKind{Type: WarnConstraintViolation, Constraint: "not"},
+		Message:    fmt.Sprintf("value matches the schema negated by 'not' at %s", t.Path),
+	}}
+}
+
+// notEvaluable reports whether schema only uses notEvaluableKeywords,
+// recursively through properties.
+func notEvaluable(schema any) bool {
+	switch s := schema.(type) {
+	case bool:
+		return true
+	case map[string]any:
+		for kw, v := range s {
+			if !notEvaluableKeywords[kw] {
+				return false
+			}
+			if kw == "properties" {
+				props, ok := v.(map[string]any)
+				if !ok {
+					return false
+				}
+				for _, p := range props {
+					if !notEvaluable(p) {
+						return false
+					}
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package jsl
+
+import "testing"
+
+// TestStripNot verifies not is removed, recorded, and evaluated on rehydrate.
+func TestStripNot(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"status": {"type": "string", "not": {"enum": ["deleted", "archived"]}},
+			"name": {"type": "string", "not": {"pattern": "^admin"}}
+		}
+	}`)
+	converted, entries, err := stripNot(deepCopyJSON(schema), &ConvertOptions{StripNot: true})
+	if err != nil {
+		t.Fatalf("stripNot() failed: %v", err)
+	}
+	want := `{"properties":{"name":{"type":"string"},"status":{"type":"string"}},"type":"object"}`
+	if got := string(mustMarshal(converted)); got != want {
+		t.Errorf("converted:\n  got:  %s\n  want: %s", got, want)
+	}
+	entries = roundtripEntries(t, entries)
+	stages := hostStages(schema, entries)
+
+	if _, w := restoreHost(decodeJSON(t, `{"status": "active"}`), stages, entries); len(w) != 0 {
+		t.Errorf("unexpected warnings: %+v", w)
+	}
+	_, w := restoreHost(decodeJSON(t, `{"status": "deleted", "name": "x"}`), stages, entries)
+	kinds := map[string]string{}
+	for _, x := range w {
+		kinds[x.DataPath] = x.Kind.Type
+	}
+	if kinds["/status"] != "constraint_violation" || kinds["/name"] != "constraint_unevaluable" || len(w) != 2 {
+		t.Errorf("unexpected warnings: %+v", w)
+	}
+}
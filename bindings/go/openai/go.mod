@@ -0,0 +1,22 @@
+module github.com/dotslashderek/json-schema-llm/bindings/go/openai
+
+go 1.23.0
+
+require (
+	github.com/dotslashderek/json-schema-llm/bindings/go v0.0.0
+	github.com/openai/openai-go v1.12.0
+)
+
+require (
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.1 // indirect
+	github.com/tetratelabs/wazero v1.8.2 // indirect
+	github.com/tidwall/gjson v1.18.0 // indirect
+	github.com/tidwall/match v1.1.1 // indirect
+	github.com/tidwall/pretty v1.2.1 // indirect
+	github.com/tidwall/sjson v1.2.5 // indirect
+	go.opentelemetry.io/otel v1.32.0 // indirect
+	go.opentelemetry.io/otel/trace v1.32.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+)
+
+replace github.com/dotslashderek/json-schema-llm/bindings/go => ../
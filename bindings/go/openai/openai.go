@@ -0,0 +1,62 @@
+// Package openai turns jsl conversion results into openai-go structured
+// output parameters, so callers do not have to re-marshal the converted
+// schema into the SDK's request types themselves.
+//
+// It is a module of its own, so the core binding does not pin an openai-go
+// version on its importers.
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	sdk "github.com/openai/openai-go"
+)
+
+// DefaultName is the response format name ResponseFormat uses when name is
+// empty.
+const DefaultName = "response"
+
+// validName matches the names the API accepts for a response format.
+var validName = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// ResponseFormat returns a strict JSON-schema response format carrying the
+// converted schema of r. The schema is sent as r.SchemaJSON when set, so
+// the provider sees the same bytes on every call; the root description, if
+// any, becomes the format's description.
+func ResponseFormat(name string, r *jsl.ConvertResult) (sdk.ResponseFormatJSONSchemaParam, error) {
+	if name == "" {
+		name = DefaultName
+	}
+	if !validName.MatchString(name) {
+		return sdk.ResponseFormatJSONSchemaParam{}, fmt.Errorf("openai: invalid response format name %q: want 1-64 letters, digits, '_' or '-'", name)
+	}
+	if r == nil || r.Schema == nil {
+		return sdk.ResponseFormatJSONSchemaParam{}, fmt.Errorf("openai: convert result has no schema")
+	}
+	var schema any = r.Schema
+	if len(r.SchemaJSON) > 0 {
+		schema = json.RawMessage(r.SchemaJSON)
+	}
+	js := sdk.ResponseFormatJSONSchemaJSONSchemaParam{
+		Name:   name,
+		Strict: sdk.Bool(true),
+		Schema: schema,
+	}
+	if desc, ok := r.Schema["description"].(string); ok && desc != "" {
+		js.Description = sdk.String(desc)
+	}
+	return sdk.ResponseFormatJSONSchemaParam{JSONSchema: js}, nil
+}
+
+// ChatResponseFormat is ResponseFormat wrapped for
+// ChatCompletionNewParams.ResponseFormat.
+func ChatResponseFormat(name string, r *jsl.ConvertResult) (sdk.ChatCompletionNewParamsResponseFormatUnion, error) {
+	f, err := ResponseFormat(name, r)
+	if err != nil {
+		return sdk.ChatCompletionNewParamsResponseFormatUnion{}, err
+	}
+	return sdk.ChatCompletionNewParamsResponseFormatUnion{OfJSONSchema: &f}, nil
+}
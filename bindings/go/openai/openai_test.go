@@ -0,0 +1,49 @@
+package openai
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// TestResponseFormat verifies the marshaled request parameter carries the
+// converted schema bytes, name, strict flag and description.
+func TestResponseFormat(t *testing.T) {
+	r := &jsl.ConvertResult{
+		Schema:     map[string]any{"type": "object", "description": "A user."},
+		SchemaJSON: json.RawMessage(`{"description":"A user.","type":"object"}`),
+	}
+	f, err := ChatResponseFormat("", r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(got, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	js, _ := decoded["json_schema"].(map[string]any)
+	if decoded["type"] != "json_schema" || js["name"] != DefaultName || js["strict"] != true || js["description"] != "A user." {
+		t.Errorf("response format = %s", got)
+	}
+	if !strings.Contains(string(got), `"schema":{"description":"A user.","type":"object"}`) {
+		t.Errorf("schema bytes not passed through: %s", got)
+	}
+}
+
+// TestResponseFormatErrors verifies bad names and empty results are
+// rejected.
+func TestResponseFormatErrors(t *testing.T) {
+	r := &jsl.ConvertResult{Schema: map[string]any{"type": "object"}}
+	if _, err := ResponseFormat("my schema", r); err == nil {
+		t.Error("expected an error for a name with a space")
+	}
+	if _, err := ResponseFormat("ok", &jsl.ConvertResult{}); err == nil {
+		t.Error("expected an error for a result without a schema")
+	}
+}
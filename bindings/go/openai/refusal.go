@@ -0,0 +1,24 @@
+package openai
+
+import (
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	sdk "github.com/openai/openai-go"
+)
+
+// DetectRefusal returns a *jsl.RefusalError when the first choice of c is a
+// refusal or was withheld by the content filter, nil otherwise. It is the
+// typed counterpart of jsl.DetectRefusal for responses already decoded by
+// the SDK.
+func DetectRefusal(c *sdk.ChatCompletion) error {
+	if c == nil || len(c.Choices) == 0 {
+		return nil
+	}
+	choice := c.Choices[0]
+	if choice.Message.Refusal != "" {
+		return &jsl.RefusalError{Provider: jsl.ProviderOpenAI, Reason: "refusal", Text: choice.Message.Refusal}
+	}
+	if choice.FinishReason == "content_filter" {
+		return &jsl.RefusalError{Provider: jsl.ProviderOpenAI, Reason: choice.FinishReason}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	sdk "github.com/openai/openai-go"
+)
+
+// TestDetectRefusal verifies SDK completions are classified like the raw
+// bodies jsl.DetectRefusal reads.
+func TestDetectRefusal(t *testing.T) {
+	for name, tc := range map[string]struct {
+		body   string
+		reason string
+	}{
+		"refusal":        {`{"choices":[{"finish_reason":"stop","message":{"role":"assistant","content":null,"refusal":"I can't help with that."}}]}`, "refusal"},
+		"content filter": {`{"choices":[{"finish_reason":"content_filter","message":{"role":"assistant","content":""}}]}`, "content_filter"},
+		"answer":         {`{"choices":[{"finish_reason":"stop","message":{"role":"assistant","content":"{}"}}]}`, ""},
+		"no choices":     {`{"choices":[]}`, ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var c sdk.ChatCompletion
+			if err := json.Unmarshal([]byte(tc.body), &c); err != nil {
+				t.Fatal(err)
+			}
+			err := DetectRefusal(&c)
+			if raw := jsl.DetectRefusal(jsl.ProviderOpenAI, []byte(tc.body)); (raw == nil) != (err == nil) {
+				t.Errorf("DetectRefusal() = %v, raw body gives %v", err, raw)
+			}
+			if tc.reason == "" {
+				if err != nil {
+					t.Errorf("DetectRefusal() = %v, want nil", err)
+				}
+				return
+			}
+			var re *jsl.RefusalError
+			if !errors.As(err, &re) || re.Reason != tc.reason || !errors.Is(err, jsl.ErrModelRefusal) {
+				t.Errorf("DetectRefusal() = %v, want a %s refusal", err, tc.reason)
+			}
+		})
+	}
+	if err := DetectRefusal(nil); err != nil {
+		t.Errorf("DetectRefusal(nil) = %v", err)
+	}
+}
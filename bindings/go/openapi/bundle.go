@@ -0,0 +1,143 @@
+package openapi
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// bundler inlines the $refs reachable from one root schema into $defs,
+// loading referenced documents (relative file refs) through the importer's
+// document cache.
+type bundler struct {
+	imp   *importer
+	defs  map[string]any
+	names map[string]string // absolute file + "#" + pointer → $defs name
+	taken map[string]bool
+}
+
+// bundle returns a self-contained copy of the schema at ptr in docPath.
+func (imp *importer) bundle(docPath, ptr string) (any, error) {
+	doc, err := imp.document(docPath)
+	if err != nil {
+		return nil, err
+	}
+	node, ok := lookup(doc, ptr)
+	if !ok {
+		return nil, fmt.Errorf("%s#%s: not found", docPath, ptr)
+	}
+	b := &bundler{imp: imp, defs: map[string]any{}, names: map[string]string{}, taken: map[string]bool{}}
+	root, err := b.rewrite(deepCopy(node), docPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(b.defs) > 0 {
+		m, ok := root.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s#%s: root schema is not an object", docPath, ptr)
+		}
+		m["$defs"] = b.defs
+	}
+	return root, nil
+}
+
+// rewrite replaces every $ref under node with a "#/$defs/..." ref,
+// bundling its target on first use.
+func (b *bundler) rewrite(node any, docPath string) (any, error) {
+	switch t := node.(type) {
+	case map[string]any:
+		if ref, ok := t["$ref"].(string); ok && !isRemote(ref) {
+			name, err := b.define(ref, docPath)
+			if err != nil {
+				return nil, err
+			}
+			t["$ref"] = "#/$defs/" + escapeSegment(name)
+		}
+		for k, c := range t {
+			if k == "$ref" {
+				continue
+			}
+			nc, err := b.rewrite(c, docPath)
+			if err != nil {
+				return nil, err
+			}
+			t[k] = nc
+		}
+	case []any:
+		for i, c := range t {
+			nc, err := b.rewrite(c, docPath)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = nc
+		}
+	}
+	return node, nil
+}
+
+// define returns the $defs name for ref (relative to docPath), bundling the
+// target the first time it is seen.
+func (b *bundler) define(ref, docPath string) (string, error) {
+	file, ptr, _ := strings.Cut(ref, "#")
+	target := docPath
+	if file != "" {
+		target = filepath.Join(filepath.Dir(docPath), filepath.FromSlash(file))
+	}
+	key := target + "#" + ptr
+	if name, ok := b.names[key]; ok {
+		return name, nil
+	}
+
+	name := ptr[strings.LastIndex(ptr, "/")+1:]
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(target), filepath.Ext(target))
+	}
+	if b.taken[name] {
+		stem := strings.TrimSuffix(filepath.Base(target), filepath.Ext(target))
+		base := stem + "_" + name
+		name = base
+		for i := 2; b.taken[name]; i++ {
+			name = fmt.Sprintf("%s_%d", base, i)
+		}
+	}
+	b.names[key], b.taken[name] = name, true
+
+	doc, err := b.imp.document(target)
+	if err != nil {
+		return "", err
+	}
+	node, ok := lookup(doc, ptr)
+	if !ok {
+		return "", fmt.Errorf("unresolvable $ref %q in %s", ref, docPath)
+	}
+	def, err := b.rewrite(deepCopy(node), target)
+	if err != nil {
+		return "", err
+	}
+	b.defs[name] = def
+	return name, nil
+}
+
+// isRemote reports refs this importer leaves for the engine (or a
+// RefResolver) to handle.
+func isRemote(ref string) bool {
+	return strings.Contains(ref, "://")
+}
+
+func deepCopy(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, c := range t {
+			out[k] = deepCopy(c)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, c := range t {
+			out[i] = deepCopy(c)
+		}
+		return out
+	}
+	return v
+}
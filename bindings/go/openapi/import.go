@@ -0,0 +1,276 @@
+// Package openapi bulk-imports OpenAPI 3 documents: it bundles every
+// component schema (resolving relative cross-file $refs), converts each
+// distinct schema once, and builds a manifest mapping components and
+// operationIds to the emitted schema and codec files.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// Converter is the engine surface the importer needs.
+type Converter interface {
+	Convert(schema any, opts *jsl.ConvertOptions) (*jsl.ConvertResult, error)
+}
+
+// Options configures Import.
+type Options struct {
+	// Convert is passed to every Convert call.
+	Convert *jsl.ConvertOptions
+}
+
+// ArtifactRef points at one converted schema in the output directory.
+type ArtifactRef struct {
+	Fingerprint string `json:"fingerprint"`
+	Schema      string `json:"schema"`
+	Codec       string `json:"codec"`
+}
+
+// Operation is a manifest entry for one operationId. Bodies are the
+// application/json request and response schemas, keyed by status code for
+// responses.
+type Operation struct {
+	File        string                 `json:"file"`
+	Method      string                 `json:"method"`
+	Path        string                 `json:"path"`
+	RequestBody *ArtifactRef           `json:"requestBody,omitempty"`
+	Responses   map[string]ArtifactRef `json:"responses,omitempty"`
+}
+
+// ImportError records a schema that failed to bundle or convert.
+type ImportError struct {
+	Source  string `json:"source"`
+	Message string `json:"message"`
+}
+
+// Manifest indexes the import's output.
+type Manifest struct {
+	Files []string `json:"files"`
+	// Components maps "file#/components/schemas/Name" to its artifact.
+	Components map[string]ArtifactRef `json:"components"`
+	Operations map[string]Operation   `json:"operations"`
+	Errors     []ImportError          `json:"errors,omitempty"`
+}
+
+// Result is a completed import: the manifest plus the converted artifacts
+// keyed by fingerprint.
+type Result struct {
+	Manifest  Manifest
+	Artifacts map[string]*jsl.ConvertResult
+}
+
+type importer struct {
+	conv   Converter
+	opts   *Options
+	docs   map[string]any
+	result *Result
+}
+
+// fingerprintLen is the number of hex digits of a schema fingerprint used
+// in artifact file names.
+const fingerprintLen = 16
+
+// Import bundles, converts and indexes every component schema and
+// operation body in files. Schemas that fail are recorded in
+// Manifest.Errors; Import itself fails only when a listed file cannot be
+// read.
+func Import(conv Converter, files []string, opts *Options) (*Result, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	imp := &importer{
+		conv: conv,
+		opts: opts,
+		docs: map[string]any{},
+		result: &Result{
+			Manifest:  Manifest{Components: map[string]ArtifactRef{}, Operations: map[string]Operation{}},
+			Artifacts: map[string]*jsl.ConvertResult{},
+		},
+	}
+	for _, f := range files {
+		if _, err := imp.document(f); err != nil {
+			return nil, err
+		}
+		imp.result.Manifest.Files = append(imp.result.Manifest.Files, filepath.ToSlash(f))
+	}
+	for _, f := range files {
+		imp.importComponents(f)
+		imp.importOperations(f)
+	}
+	return imp.result, nil
+}
+
+func (imp *importer) document(p string) (any, error) {
+	p = filepath.Clean(p)
+	if doc, ok := imp.docs[p]; ok {
+		return doc, nil
+	}
+	doc, err := loadDocument(p)
+	if err != nil {
+		return nil, err
+	}
+	imp.docs[p] = doc
+	return doc, nil
+}
+
+func (imp *importer) importComponents(file string) {
+	doc, _ := imp.document(file)
+	schemas, _ := lookup(doc, "/components/schemas")
+	m, _ := schemas.(map[string]any)
+	for _, name := range sortedKeys(m) {
+		ptr := "/components/schemas/" + escapeSegment(name)
+		if ref, ok := imp.convert(file, ptr); ok {
+			imp.result.Manifest.Components[filepath.ToSlash(file)+"#"+ptr] = ref
+		}
+	}
+}
+
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+func (imp *importer) importOperations(file string) {
+	doc, _ := imp.document(file)
+	paths, _ := lookup(doc, "/paths")
+	pm, _ := paths.(map[string]any)
+	for _, p := range sortedKeys(pm) {
+		item, _ := pm[p].(map[string]any)
+		for _, method := range httpMethods {
+			op, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			id, _ := op["operationId"].(string)
+			if id == "" {
+				continue
+			}
+			opPtr := "/paths/" + escapeSegment(p) + "/" + method
+			entry := Operation{File: filepath.ToSlash(file), Method: method, Path: p}
+			if _, ok := lookup(op, "/requestBody/content/application~1json/schema"); ok {
+				if ref, ok := imp.convert(file, opPtr+"/requestBody/content/application~1json/schema"); ok {
+					entry.RequestBody = &ref
+				}
+			}
+			responses, _ := op["responses"].(map[string]any)
+			for _, status := range sortedKeys(responses) {
+				rel := "/responses/" + escapeSegment(status) + "/content/application~1json/schema"
+				if _, ok := lookup(op, rel); !ok {
+					continue
+				}
+				if ref, ok := imp.convert(file, opPtr+rel); ok {
+					if entry.Responses == nil {
+						entry.Responses = map[string]ArtifactRef{}
+					}
+					entry.Responses[status] = ref
+				}
+			}
+			if prev, dup := imp.result.Manifest.Operations[id]; dup {
+				imp.fail(file+"#"+opPtr, fmt.Sprintf("duplicate operationId %q (also %s %s in %s)", id, prev.Method, prev.Path, prev.File))
+				continue
+			}
+			imp.result.Manifest.Operations[id] = entry
+		}
+	}
+}
+
+// convert bundles the schema at ptr and converts it unless an identical
+// bundle was already converted.
+func (imp *importer) convert(file, ptr string) (ArtifactRef, bool) {
+	source := filepath.ToSlash(file) + "#" + ptr
+	file, ptr = imp.follow(file, ptr)
+	schema, err := imp.bundle(file, ptr)
+	if err != nil {
+		imp.fail(source, err.Error())
+		return ArtifactRef{}, false
+	}
+	fp, err := jsl.SchemaFingerprint(schema)
+	if err != nil {
+		imp.fail(source, err.Error())
+		return ArtifactRef{}, false
+	}
+	fp = fp[:fingerprintLen]
+	if _, done := imp.result.Artifacts[fp]; !done {
+		result, err := imp.conv.Convert(schema, imp.opts.Convert)
+		if err != nil {
+			imp.fail(source, err.Error())
+			return ArtifactRef{}, false
+		}
+		imp.result.Artifacts[fp] = result
+	}
+	return ArtifactRef{
+		Fingerprint: fp,
+		Schema:      "schemas/" + fp + ".json",
+		Codec:       "codecs/" + fp + ".json",
+	}, true
+}
+
+// follow resolves schemas that are nothing but a $ref to their target, so
+// an operation body referencing a component dedupes with the component.
+func (imp *importer) follow(file, ptr string) (string, string) {
+	for range 32 {
+		doc, err := imp.document(file)
+		if err != nil {
+			return file, ptr
+		}
+		node, _ := lookup(doc, ptr)
+		m, ok := node.(map[string]any)
+		if !ok || len(m) != 1 {
+			return file, ptr
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok || isRemote(ref) {
+			return file, ptr
+		}
+		target, targetPtr, _ := strings.Cut(ref, "#")
+		if target != "" {
+			file = filepath.Join(filepath.Dir(file), filepath.FromSlash(target))
+		}
+		ptr = targetPtr
+	}
+	return file, ptr
+}
+
+func (imp *importer) fail(source, msg string) {
+	imp.result.Manifest.Errors = append(imp.result.Manifest.Errors, ImportError{Source: source, Message: msg})
+}
+
+// Write emits schemas/<fingerprint>.json, codecs/<fingerprint>.json and
+// manifest.json under dir.
+func (r *Result) Write(dir string) error {
+	for _, sub := range []string{"schemas", "codecs"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return err
+		}
+	}
+	for fp, a := range r.Artifacts {
+		if err := writeJSON(filepath.Join(dir, "schemas", fp+".json"), a.Schema); err != nil {
+			return err
+		}
+		if err := writeJSON(filepath.Join(dir, "codecs", fp+".json"), a.Codec); err != nil {
+			return err
+		}
+	}
+	return writeJSON(filepath.Join(dir, "manifest.json"), r.Manifest)
+}
+
+func writeJSON(p string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", p, err)
+	}
+	return os.WriteFile(p, append(b, '\n'), 0o644)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
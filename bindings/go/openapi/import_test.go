@@ -0,0 +1,202 @@
+package openapi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// fakeConverter records the schemas it was asked to convert.
+type fakeConverter struct {
+	calls []any
+}
+
+func (f *fakeConverter) Convert(schema any, _ *jsl.ConvertOptions) (*jsl.ConvertResult, error) {
+	f.calls = append(f.calls, schema)
+	m, _ := schema.(map[string]any)
+	return &jsl.ConvertResult{APIVersion: "1.0", Schema: m, Codec: map[string]any{}}, nil
+}
+
+func writeFile(t *testing.T, p, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const petsSpec = `openapi: 3.1.0
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      responses:
+        200:
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+        404:
+          content:
+            application/json:
+              schema:
+                $ref: '../shared/errors.yaml#/components/schemas/Error'
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string}
+        owner: {$ref: '../shared/people.yaml#/components/schemas/Person'}
+`
+
+const ordersSpec = `openapi: 3.1.0
+paths:
+  /orders:
+    post:
+      operationId: createOrder
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                qty: {type: integer}
+      responses:
+        default:
+          content:
+            application/json:
+              schema:
+                $ref: '../shared/errors.yaml#/components/schemas/Error'
+components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        code: {type: integer}
+        message: {type: string}
+`
+
+const errorsSpec = `components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        code: {type: integer}
+        message: {type: string}
+`
+
+const peopleSpec = `components:
+  schemas:
+    Person:
+      type: object
+      properties:
+        name: {type: string}
+`
+
+// TestImport verifies cross-file refs are bundled, identical schemas are
+// converted once, and operations map to their artifacts.
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "specs", "pets", "pets.yaml"), petsSpec)
+	writeFile(t, filepath.Join(dir, "specs", "orders", "orders.yaml"), ordersSpec)
+	writeFile(t, filepath.Join(dir, "specs", "shared", "errors.yaml"), errorsSpec)
+	writeFile(t, filepath.Join(dir, "specs", "shared", "people.yaml"), peopleSpec)
+
+	files, err := ExpandPatterns([]string{filepath.Join(dir, "specs", "**", "*.yaml")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 4 {
+		t.Fatalf("ExpandPatterns = %v", files)
+	}
+
+	conv := &fakeConverter{}
+	result, err := Import(conv, files, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := result.Manifest
+	if len(m.Errors) != 0 {
+		t.Fatalf("errors = %+v", m.Errors)
+	}
+
+	petsFile := filepath.ToSlash(filepath.Join(dir, "specs", "pets", "pets.yaml"))
+	pet := m.Components[petsFile+"#/components/schemas/Pet"]
+	bundled := result.Artifacts[pet.Fingerprint].Schema
+	if got, _ := lookup(bundled, "/properties/owner/$ref"); got != "#/$defs/Person" {
+		t.Errorf("owner ref = %v", got)
+	}
+	if _, ok := lookup(bundled, "/$defs/Person/properties/name"); !ok {
+		t.Errorf("Person not bundled: %v", bundled)
+	}
+
+	getPet := m.Operations["getPet"]
+	if getPet.Responses["200"].Fingerprint != pet.Fingerprint {
+		t.Errorf("getPet 200 = %+v, want component %+v", getPet.Responses["200"], pet)
+	}
+	createOrder := m.Operations["createOrder"]
+	if createOrder.RequestBody == nil || createOrder.Method != "post" {
+		t.Errorf("createOrder = %+v", createOrder)
+	}
+	// Error is identical in orders.yaml and shared/errors.yaml.
+	if getPet.Responses["404"].Fingerprint != createOrder.Responses["default"].Fingerprint {
+		t.Error("identical Error schemas were not deduped")
+	}
+	// Pet, Person, Error (×2, deduped) and the inline order body.
+	if len(conv.calls) != 4 {
+		t.Errorf("converted %d schemas, want 4", len(conv.calls))
+	}
+
+	out := filepath.Join(dir, "build")
+	if err := result.Write(out); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(out, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written Manifest
+	if err := json.Unmarshal(b, &written); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(out, getPet.Responses["200"].Codec)); err != nil {
+		t.Errorf("codec not written: %v", err)
+	}
+}
+
+// TestImportUnresolvableRef verifies a broken ref is reported, not fatal.
+func TestImportUnresolvableRef(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "api.yaml")
+	writeFile(t, p, "components:\n  schemas:\n    A: {$ref: '#/components/schemas/Missing'}\n    B: {type: string}\n")
+	result, err := Import(&fakeConverter{}, []string{p}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Manifest.Errors) != 1 || len(result.Manifest.Components) != 1 {
+		t.Errorf("manifest = %+v", result.Manifest)
+	}
+}
+
+// TestMatchGlob verifies "**" spans zero or more directories.
+func TestMatchGlob(t *testing.T) {
+	for _, tc := range []struct {
+		pat, name string
+		want      bool
+	}{
+		{"specs/**/*.yaml", "specs/a.yaml", true},
+		{"specs/**/*.yaml", "specs/x/y/a.yaml", true},
+		{"specs/**/*.yaml", "specs/x/a.json", false},
+		{"specs/*.yaml", "specs/x/a.yaml", false},
+	} {
+		if got := matchGlob(tc.pat, tc.name); got != tc.want {
+			t.Errorf("matchGlob(%q, %q) = %v", tc.pat, tc.name, got)
+		}
+	}
+}
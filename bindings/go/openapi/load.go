@@ -0,0 +1,188 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExpandPatterns turns command-line arguments into a sorted, de-duplicated
+// list of spec files. Each argument may be a file, a directory (walked for
+// .yaml, .yml and .json files), or a glob in which "**" matches any number
+// of directories.
+func ExpandPatterns(patterns []string) ([]string, error) {
+	seen := map[string]bool{}
+	var files []string
+	add := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			files = append(files, p)
+		}
+	}
+	for _, pat := range patterns {
+		if !strings.ContainsAny(pat, "*?[") {
+			info, err := os.Stat(pat)
+			if err != nil {
+				return nil, err
+			}
+			if !info.IsDir() {
+				add(filepath.Clean(pat))
+				continue
+			}
+			pat = filepath.Join(pat, "**", "*")
+		}
+		base := globBase(pat)
+		err := filepath.WalkDir(base, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !isSpecFile(p) {
+				return nil
+			}
+			if matchGlob(filepath.ToSlash(pat), filepath.ToSlash(p)) {
+				add(p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// globBase returns the directory prefix of pat before its first wildcard.
+func globBase(pat string) string {
+	segs := strings.Split(filepath.ToSlash(pat), "/")
+	var base []string
+	for _, s := range segs[:len(segs)-1] {
+		if strings.ContainsAny(s, "*?[") {
+			break
+		}
+		base = append(base, s)
+	}
+	if len(base) == 0 {
+		return "."
+	}
+	if joined := strings.Join(base, "/"); joined != "" {
+		return filepath.FromSlash(joined)
+	}
+	return "/"
+}
+
+// matchGlob matches slash-separated name against pat, where a "**" segment
+// matches zero or more path segments and other segments use path.Match.
+func matchGlob(pat, name string) bool {
+	return matchSegments(strings.Split(path.Clean(pat), "/"), strings.Split(path.Clean(name), "/"))
+}
+
+func matchSegments(pat, name []string) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pat[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pat[0], name[0]); !ok {
+			return false
+		}
+		pat, name = pat[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+func isSpecFile(p string) bool {
+	switch strings.ToLower(filepath.Ext(p)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// loadDocument reads a YAML or JSON document into the generic JSON shape.
+func loadDocument(p string) (any, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	if strings.EqualFold(filepath.Ext(p), ".json") {
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		return doc, nil
+	}
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+	return normalizeYAML(doc), nil
+}
+
+// normalizeYAML converts yaml.v3's decoded values to encoding/json's shape:
+// non-string map keys (e.g. response codes) become strings and integers
+// become float64.
+func normalizeYAML(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, c := range t {
+			t[k] = normalizeYAML(c)
+		}
+		return t
+	case map[any]any:
+		out := make(map[string]any, len(t))
+		for k, c := range t {
+			out[fmt.Sprint(k)] = normalizeYAML(c)
+		}
+		return out
+	case []any:
+		for i, c := range t {
+			t[i] = normalizeYAML(c)
+		}
+		return t
+	case int:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case uint64:
+		return float64(t)
+	}
+	return v
+}
+
+// lookup resolves an RFC 6901 pointer ("/components/schemas/Pet").
+func lookup(doc any, ptr string) (any, bool) {
+	ptr = strings.TrimPrefix(ptr, "#")
+	if ptr == "" || ptr == "/" {
+		return doc, true
+	}
+	node := doc
+	for _, seg := range strings.Split(strings.TrimPrefix(ptr, "/"), "/") {
+		seg = strings.ReplaceAll(strings.ReplaceAll(seg, "~1", "/"), "~0", "~")
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if node, ok = m[seg]; !ok {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+func escapeSegment(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
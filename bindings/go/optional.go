@@ -0,0 +1,117 @@
+package jsl
+
+import (
+	"fmt"
+	"sort"
+)
+
+// OptionalStrategy selects how properties missing from `required` are
+// presented to strict targets, which require every property.
+type OptionalStrategy string
+
+const (
+	// OptionalNullable makes optional properties required and nullable;
+	// Rehydrate removes the nulls the model returns for them (default, done
+	// by the guest).
+	OptionalNullable OptionalStrategy = ""
+	// OptionalKeepNull converts like OptionalNullable but has Rehydrate
+	// report every optional property explicitly, as null when the model
+	// gave no value, for consumers that expect a fixed key set.
+	OptionalKeepNull OptionalStrategy = "keep_null"
+	// OptionalDrop removes optional properties from the converted schema so
+	// the model never sees them; they are simply absent after rehydration.
+	OptionalDrop OptionalStrategy = "drop"
+)
+
+const (
+	transformOptionalKeepNull = "optional_keep_null"
+	transformOptionalDrop     = "optional_drop"
+)
+
+// applyOptionalStrategy records (and for OptionalDrop, removes) the optional
+// properties of every object schema.
+func applyOptionalStrategy(schema any, opts *ConvertOptions) (any, []HostTransform, error) {
+	var typ string
+	switch opts.OptionalStrategy {
+	case OptionalKeepNull:
+		typ = transformOptionalKeepNull
+	case OptionalDrop:
+		typ = transformOptionalDrop
+	default:
+		return nil, nil, fmt.Errorf("unknown OptionalStrategy %q", opts.OptionalStrategy)
+	}
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		names := optionalProperties(node)
+		if len(names) == 0 {
+			return node
+		}
+		t := HostTransform{Type: typ, Path: loc, Params: map[string]any{"properties": names}}
+		entries = append(entries, t)
+		if typ == transformOptionalDrop {
+			return rewriteOptionalDrop(node, &t)
+		}
+		return node
+	})
+	return schema, entries, nil
+}
+
+// optionalProperties returns the sorted names of properties not in required.
+func optionalProperties(node map[string]any) []any {
+	props, ok := node["properties"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	required := map[string]bool{}
+	if req, ok := node["required"].([]any); ok {
+		for _, r := range req {
+			if s, ok := r.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+	var names []string
+	for name := range props {
+		if !required[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	out := make([]any, len(names))
+	for i, n := range names {
+		out[i] = n
+	}
+	return out
+}
+
+func rewriteOptionalDrop(n any, t *HostTransform) any {
+	node, ok := n.(map[string]any)
+	if !ok {
+		return n
+	}
+	props, _ := node["properties"].(map[string]any)
+	names, _ := t.Params["properties"].([]any)
+	for _, name := range names {
+		if s, ok := name.(string); ok {
+			delete(props, s)
+		}
+	}
+	return node
+}
+
+// restoreOptionalKeepNull fills absent optional properties with null.
+func restoreOptionalKeepNull(v any, t *HostTransform, _ string) (any, []Warning) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return v, nil
+	}
+	names, _ := t.Params["properties"].([]any)
+	for _, name := range names {
+		if s, ok := name.(string); ok {
+			if _, present := obj[s]; !present {
+				obj[s] = nil
+			}
+		}
+	}
+	return obj, nil
+}
@@ -0,0 +1,55 @@
+package jsl
+
+import "testing"
+
+// TestOptionalStrategies verifies drop removes optional properties and keep_null reinstates them as null.
+func TestOptionalStrategies(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string"},
+			"nickname": {"type": "string"},
+			"address": {
+				"type": "object",
+				"properties": {"city": {"type": "string"}, "zip": {"type": "string"}},
+				"required": ["city"]
+			}
+		},
+		"required": ["id", "address"]
+	}`)
+
+	t.Run("drop", func(t *testing.T) {
+		converted, entries, err := applyOptionalStrategy(deepCopyJSON(schema), &ConvertOptions{OptionalStrategy: OptionalDrop})
+		if err != nil {
+			t.Fatalf("applyOptionalStrategy() failed: %v", err)
+		}
+		for _, loc := range []string{"#/properties/nickname", "#/properties/address/properties/zip"} {
+			if _, ok := lookupPointer(converted, loc); ok {
+				t.Errorf("%s should be dropped", loc)
+			}
+		}
+		entries = roundtripEntries(t, entries)
+		stages := hostStages(schema, entries)
+		if !jsonEqual(stages[len(stages)-1], converted) {
+			t.Error("re-derived schema differs from converted schema")
+		}
+	})
+
+	t.Run("keep_null", func(t *testing.T) {
+		converted, entries, err := applyOptionalStrategy(deepCopyJSON(schema), &ConvertOptions{OptionalStrategy: OptionalKeepNull})
+		if err != nil {
+			t.Fatalf("applyOptionalStrategy() failed: %v", err)
+		}
+		if !jsonEqual(converted, schema) {
+			t.Error("keep_null must not change the schema")
+		}
+		entries = roundtripEntries(t, entries)
+		// The guest has already removed the injected nulls.
+		data := decodeJSON(t, `{"id": "1", "address": {"city": "Oslo"}}`)
+		restored, _ := restoreHost(data, hostStages(schema, entries), entries)
+		want := `{"address":{"city":"Oslo","zip":null},"id":"1","nickname":null}`
+		if got := string(mustMarshal(restored)); got != want {
+			t.Errorf("restored:\n  got:  %s\n  want: %s", got, want)
+		}
+	})
+}
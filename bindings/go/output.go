@@ -0,0 +1,354 @@
+package jsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// RehydrateOptions configures host-side handling of model output in
+// RehydrateWithOptions. Options that inspect the raw text (rather than decoded
+// data) only apply when data is passed as a json.RawMessage.
+type RehydrateOptions struct {
+	// SpecialNumbers controls how the non-JSON literals NaN, Infinity and
+	// -Infinity, and numbers outside the float64 range, are handled in raw
+	// output. The default rejects them like any other JSON syntax error.
+	SpecialNumbers SpecialNumberMode
+
+	// DuplicateKeys controls detection of repeated keys within one object in
+	// raw output. encoding/json silently keeps the last occurrence; duplicates
+	// usually mean the model hallucinated structure.
+	DuplicateKeys DuplicateKeyMode
+
+	// NormalizeUnicode NFC-normalizes every string in the rehydrated data.
+	NormalizeUnicode bool
+
+	// ControlChars strips or escapes control characters and invalid UTF-8 in
+	// string values, protecting downstream systems that choke on raw model
+	// bytes. Invalid UTF-8 is only detectable in raw (json.RawMessage) output.
+	ControlChars ControlCharMode
+
+	// MaxBytes, MaxDepth and MaxItems bound the size of model output (and of
+	// the rehydrated result) so adversarial or runaway output cannot exhaust
+	// memory in a shared service. MaxDepth counts nested objects/arrays;
+	// MaxItems caps the length of any single array. Violations fail with a
+	// *LimitError. Zero disables a guard.
+	MaxBytes int
+	MaxDepth int
+	MaxItems int
+
+	// RepairTruncated makes raw output that was cut off mid-document (e.g.
+	// at max_tokens) parseable instead of failing: the trailing partial
+	// member or element is dropped and every open object and array is
+	// closed. Each repair is reported as an output_repaired warning. The
+	// repaired data is missing whatever the model did not finish, so it can
+	// still fail later schema checks.
+	RepairTruncated bool
+}
+
+// DuplicateKeyMode selects how duplicate object keys in raw model output are surfaced.
+type DuplicateKeyMode string
+
+const (
+	// DuplicateKeysIgnore keeps encoding/json behavior: last occurrence wins (default).
+	DuplicateKeysIgnore DuplicateKeyMode = ""
+	// DuplicateKeysWarn keeps the last occurrence and emits a warning per duplicate.
+	DuplicateKeysWarn DuplicateKeyMode = "warn"
+	// DuplicateKeysError fails with an *Error of code "duplicate_key".
+	DuplicateKeysError DuplicateKeyMode = "error"
+)
+
+// SpecialNumberMode selects how non-finite numbers in raw model output are mapped.
+type SpecialNumberMode string
+
+const (
+	// SpecialNumbersReject fails the parse (default).
+	SpecialNumbersReject SpecialNumberMode = ""
+	// SpecialNumbersNull replaces the literal with null.
+	SpecialNumbersNull SpecialNumberMode = "null"
+	// SpecialNumbersString replaces the literal with its source text as a string.
+	SpecialNumbersString SpecialNumberMode = "string"
+)
+
+// specialLiterals are the bare tokens models emit for non-finite numbers.
+var specialLiterals = map[string]bool{
+	"NaN": true, "-NaN": true, "Infinity": true, "-Infinity": true, "+Infinity": true,
+	"inf": true, "-inf": true, "+inf": true, "nan": true,
+}
+
+// parseModelOutput decodes raw model output according to opts, returning
+// warnings for every value it had to rewrite.
+func parseModelOutput(raw []byte, opts *RehydrateOptions) (any, []Warning, error) {
+	var warnings []Warning
+	if opts != nil && opts.RepairTruncated {
+		var w *Warning
+		if raw, w = repairTruncated(raw); w != nil {
+			warnings = append(warnings, *w)
+		}
+	}
+	if opts != nil && opts.ControlChars != ControlCharsKeep {
+		var utfWarnings []Warning
+		raw, utfWarnings = scrubInvalidUTF8(raw, opts.ControlChars)
+		warnings = append(warnings, utfWarnings...)
+	}
+	if opts != nil && opts.DuplicateKeys != DuplicateKeysIgnore {
+		dupWarnings, err := checkDuplicateKeys(raw, opts.DuplicateKeys)
+		if err != nil {
+			return nil, nil, err
+		}
+		warnings = append(warnings, dupWarnings...)
+	}
+	if opts != nil && opts.SpecialNumbers != SpecialNumbersReject {
+		rewritten, numWarnings, err := rewriteSpecialNumbers(raw, opts.SpecialNumbers)
+		if err != nil {
+			return nil, nil, err
+		}
+		raw = rewritten
+		warnings = append(warnings, numWarnings...)
+	}
+	var data any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, nil, fmt.Errorf("parse model output: %w", err)
+	}
+	return data, warnings, nil
+}
+
+// rewriteSpecialNumbers replaces non-finite literals and out-of-range numbers
+// outside of strings, tracking the data path of each replacement.
+func rewriteSpecialNumbers(raw []byte, mode SpecialNumberMode) ([]byte, []Warning, error) {
+	switch mode {
+	case SpecialNumbersNull, SpecialNumbersString:
+	default:
+		return nil, nil, fmt.Errorf("unknown SpecialNumberMode %q", mode)
+	}
+
+	var (
+		out      bytes.Buffer
+		warnings []Warning
+		sc       = newOutputScanner(raw)
+	)
+	out.Grow(len(raw))
+	for sc.next() {
+		tok := sc.token()
+		if sc.kind != tokBare || sc.isKey() {
+			out.Write(tok)
+			continue
+		}
+		literal := string(tok)
+		special := specialLiterals[literal]
+		if !special && isNumberStart(tok[0]) {
+			if _, err := strconv.ParseFloat(literal, 64); errors.Is(err, strconv.ErrRange) {
+				special = true
+			}
+		}
+		if !special {
+			out.Write(tok)
+			continue
+		}
+		replacement := "null"
+		if mode == SpecialNumbersString {
+			replacement = strconv.Quote(literal)
+		}
+		out.WriteString(replacement)
+		warnings = append(warnings, Warning{
+			DataPath: sc.path(),
+			Kind:     WarningKind{Type: WarnSpecialNumber},
+			Message:  fmt.Sprintf("non-finite number %s replaced with %s", literal, replacement),
+		})
+	}
+	if sc.err != nil {
+		return nil, nil, fmt.Errorf("parse model output: %w", sc.err)
+	}
+	return out.Bytes(), warnings, nil
+}
+
+// checkDuplicateKeys reports keys that occur more than once in the same object.
+func checkDuplicateKeys(raw []byte, mode DuplicateKeyMode) ([]Warning, error) {
+	switch mode {
+	case DuplicateKeysWarn, DuplicateKeysError:
+	default:
+		return nil, fmt.Errorf("unknown DuplicateKeyMode %q", mode)
+	}
+
+	var warnings []Warning
+	sc := newOutputScanner(raw)
+	for sc.next() {
+		if sc.kind != tokString || !sc.isKey() {
+			continue
+		}
+		top := sc.top()
+		if top.seen == nil {
+			top.seen = map[string]bool{}
+		}
+		if !top.seen[top.key] {
+			top.seen[top.key] = true
+			continue
+		}
+		path := sc.path()
+		if mode == DuplicateKeysError {
+			return nil, &Error{
+				Code:    "duplicate_key",
+				Message: fmt.Sprintf("duplicate key %q in model output", top.key),
+				Path:    path,
+			}
+		}
+		warnings = append(warnings, Warning{
+			DataPath: path,
+			Kind:     WarningKind{Type: WarnDuplicateKey},
+			Message:  fmt.Sprintf("duplicate key %q in model output; last occurrence kept", top.key),
+		})
+	}
+	if sc.err != nil {
+		return nil, fmt.Errorf("parse model output: %w", sc.err)
+	}
+	return warnings, nil
+}
+
+func isNumberStart(c byte) bool {
+	return c == '-' || c == '+' || (c >= '0' && c <= '9')
+}
+
+// outputScanner is a lenient JSON tokenizer that tracks the data path of the
+// current value. It accepts bare tokens JSON forbids (NaN, Infinity) so they
+// can be rewritten; structural validation is left to encoding/json.
+type outputScanner struct {
+	src   []byte
+	pos   int
+	start int
+	kind  tokenKind
+	stack []scanFrame
+	err   error
+}
+
+type tokenKind int
+
+const (
+	tokPunct  tokenKind = iota // whitespace and structural characters
+	tokString                  // a quoted string
+	tokBare                    // number, literal, or other unquoted run
+)
+
+type scanFrame struct {
+	array     bool
+	index     int
+	key       string
+	expectKey bool
+	seen      map[string]bool // keys already seen (duplicate detection only)
+}
+
+func newOutputScanner(src []byte) *outputScanner {
+	return &outputScanner{src: src}
+}
+
+func (s *outputScanner) token() []byte { return s.src[s.start:s.pos] }
+
+// next advances to the next token, updating container state for punctuation.
+func (s *outputScanner) next() bool {
+	if s.err != nil || s.pos >= len(s.src) {
+		return false
+	}
+	s.start = s.pos
+	c := s.src[s.pos]
+	switch {
+	case c == '"':
+		s.kind = tokString
+		s.pos++
+		for s.pos < len(s.src) && s.src[s.pos] != '"' {
+			if s.src[s.pos] == '\\' {
+				s.pos++
+			}
+			s.pos++
+		}
+		if s.pos >= len(s.src) {
+			s.err = errors.New("unterminated string")
+			s.pos = len(s.src)
+			return true
+		}
+		s.pos++
+		if top := s.top(); top != nil && !top.array && top.expectKey {
+			var key string
+			if err := json.Unmarshal(s.token(), &key); err == nil {
+				top.key = key
+			}
+		}
+	case isPunct(c):
+		s.kind = tokPunct
+		s.pos++
+		switch c {
+		case '{':
+			s.stack = append(s.stack, scanFrame{expectKey: true})
+		case '[':
+			s.stack = append(s.stack, scanFrame{array: true})
+		case '}', ']':
+			if len(s.stack) > 0 {
+				s.stack = s.stack[:len(s.stack)-1]
+			}
+		case ':':
+			if top := s.top(); top != nil {
+				top.expectKey = false
+			}
+		case ',':
+			if top := s.top(); top != nil {
+				if top.array {
+					top.index++
+				} else {
+					top.expectKey = true
+				}
+			}
+		}
+	default:
+		s.kind = tokBare
+		for s.pos < len(s.src) && !isPunct(s.src[s.pos]) && s.src[s.pos] != '"' {
+			s.pos++
+		}
+	}
+	return true
+}
+
+// isKey reports whether the current token sits in object-key position.
+func (s *outputScanner) isKey() bool {
+	top := s.top()
+	return top != nil && !top.array && top.expectKey
+}
+
+func (s *outputScanner) top() *scanFrame {
+	if len(s.stack) == 0 {
+		return nil
+	}
+	return &s.stack[len(s.stack)-1]
+}
+
+// path returns the RFC 6901 data path of the current value.
+func (s *outputScanner) path() string {
+	return framesPath(s.stack)
+}
+
+// containerPath returns the data path of the innermost open object or array.
+func (s *outputScanner) containerPath() string {
+	if len(s.stack) == 0 {
+		return ""
+	}
+	return framesPath(s.stack[:len(s.stack)-1])
+}
+
+func framesPath(frames []scanFrame) string {
+	p := ""
+	for _, f := range frames {
+		if f.array {
+			p = childDataPath(p, f.index)
+		} else {
+			p = childDataPath(p, f.key)
+		}
+	}
+	return p
+}
+
+func isPunct(c byte) bool {
+	switch c {
+	case '{', '}', '[', ']', ':', ',', ' ', '\t', '\n', '\r':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package jsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestParseModelOutputSpecialNumbers verifies non-finite literals are mapped per mode with path-accurate warnings.
+func TestParseModelOutputSpecialNumbers(t *testing.T) {
+	raw := []byte(`{"score": NaN, "bounds": [1, -Infinity, 1e400], "label": "NaN stays", "nested": {"x": Infinity}}`)
+
+	tests := []struct {
+		mode SpecialNumberMode
+		want string
+	}{
+		{SpecialNumbersNull, `{"bounds":[1,null,null],"label":"NaN stays","nested":{"x":null},"score":null}`},
+		{SpecialNumbersString, `{"bounds":[1,"-Infinity","1e400"],"label":"NaN stays","nested":{"x":"Infinity"},"score":"NaN"}`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			data, warnings, err := parseModelOutput(raw, &RehydrateOptions{SpecialNumbers: tt.mode})
+			if err != nil {
+				t.Fatalf("parseModelOutput() failed: %v", err)
+			}
+			got, _ := json.Marshal(data)
+			if string(got) != tt.want {
+				t.Errorf("data:\n  got:  %s\n  want: %s", got, tt.want)
+			}
+
+			paths := map[string]bool{}
+			for _, w := range warnings {
+				if w.Kind.Type != "special_number" {
+					t.Errorf("warning kind: got %q", w.Kind.Type)
+				}
+				paths[w.DataPath] = true
+			}
+			for _, p := range []string{"/score", "/bounds/1", "/bounds/2", "/nested/x"} {
+				if !paths[p] {
+					t.Errorf("missing warning for %s (got %v)", p, paths)
+				}
+			}
+			if len(warnings) != 4 {
+				t.Errorf("expected 4 warnings, got %d", len(warnings))
+			}
+		})
+	}
+}
+
+// TestParseModelOutputRejectsByDefault verifies the default mode keeps strict JSON parsing.
+func TestParseModelOutputRejectsByDefault(t *testing.T) {
+	if _, _, err := parseModelOutput([]byte(`{"x": NaN}`), &RehydrateOptions{}); err == nil {
+		t.Fatal("expected parse error for NaN with default options")
+	}
+}
+
+// TestParseModelOutputDuplicateKeys verifies duplicate keys are reported per object, not across objects.
+func TestParseModelOutputDuplicateKeys(t *testing.T) {
+	raw := []byte(`{"a": 1, "items": [{"id": 1, "id": 2}, {"id": 3}], "a": 4}`)
+
+	data, warnings, err := parseModelOutput(raw, &RehydrateOptions{DuplicateKeys: DuplicateKeysWarn})
+	if err != nil {
+		t.Fatalf("parseModelOutput() failed: %v", err)
+	}
+	if got := data.(map[string]any)["a"]; got != float64(4) {
+		t.Errorf("last occurrence should win, got %v", got)
+	}
+	paths := []string{}
+	for _, w := range warnings {
+		paths = append(paths, w.DataPath)
+	}
+	if len(paths) != 2 || paths[0] != "/items/0/id" || paths[1] != "/a" {
+		t.Errorf("duplicate paths: got %v, want [/items/0/id /a]", paths)
+	}
+
+	_, _, err = parseModelOutput(raw, &RehydrateOptions{DuplicateKeys: DuplicateKeysError})
+	jslErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected *Error, got %T: %v", err, err)
+	}
+	if jslErr.Code != "duplicate_key" || jslErr.Path != "/items/0/id" {
+		t.Errorf("error: got code=%q path=%q", jslErr.Code, jslErr.Path)
+	}
+}
@@ -0,0 +1,93 @@
+package jsl
+
+import "strings"
+
+// transformOverrideStringify records a subschema the caller forced to a
+// JSON-encoded string (NodeOverride.Stringify). The guest's opaque-type
+// handling does the stringifying and its codec parses the value back, so
+// the entry only re-applies the schema change for Rehydrate.
+const transformOverrideStringify = "override_stringify"
+
+// NodeOverride forces conversion behavior at one schema location. Keys of
+// ConvertOptions.Overrides are pointers into the input schema (e.g.
+// "#/properties/metadata", "#/$defs/Node"), in the escaped form
+// Pointer.String produces.
+type NodeOverride struct {
+	// Stringify replaces the subschema with a JSON-encoded string the
+	// model fills in; Rehydrate parses it back. Its constraints are not
+	// sent to the provider or checked on the way back.
+	Stringify bool `json:"stringify,omitempty"`
+	// NoUnroll keeps a recursive definition from being inlined at all:
+	// every $ref to it becomes a JSON-encoded string, as with a
+	// RecursionLimits entry of 0.
+	NoUnroll bool `json:"noUnroll,omitempty"`
+	// KeepEnum exempts the enum at this location from MaxEnumValues.
+	KeepEnum bool `json:"keepEnum,omitempty"`
+}
+
+// override returns the override for the schema location loc.
+func (o *ConvertOptions) override(loc string) NodeOverride {
+	if o == nil {
+		return NodeOverride{}
+	}
+	return o.Overrides[loc]
+}
+
+func hasStringifyOverride(o *ConvertOptions) bool {
+	for _, ov := range o.Overrides {
+		if ov.Stringify {
+			return true
+		}
+	}
+	return false
+}
+
+// noUnrollLimits returns limits with a zero entry for every NoUnroll
+// override, or limits itself when there are none.
+func noUnrollLimits(limits map[string]int, overrides map[string]NodeOverride) map[string]int {
+	var out map[string]int
+	for k, ov := range overrides {
+		if !ov.NoUnroll {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]int, len(limits)+1)
+			for ref, n := range limits {
+				out[ref] = n
+			}
+		}
+		out[k] = 0
+	}
+	if out == nil {
+		return limits
+	}
+	return out
+}
+
+// stringifyOverrides replaces every subschema with a Stringify override by
+// an untyped schema keeping only its description, which the guest turns
+// into a JSON-encoded string.
+func stringifyOverrides(schema any, opts *ConvertOptions) (any, []HostTransform, error) {
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		if loc == "#" || !opts.override(loc).Stringify {
+			return node
+		}
+		t := HostTransform{Type: transformOverrideStringify, Path: loc}
+		entries = append(entries, t)
+		return rewriteOverrideStringify(node, &t)
+	})
+	return schema, entries, nil
+}
+
+func rewriteOverrideStringify(n any, _ *HostTransform) any {
+	node, ok := n.(map[string]any)
+	if !ok {
+		return n
+	}
+	out := map[string]any{}
+	if desc, ok := node["description"].(string); ok && strings.TrimSpace(desc) != "" {
+		out["description"] = desc
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package jsl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestStringifyOverrides verifies only the overridden subschema is replaced
+// and that Rehydrate re-derives the same schema.
+func TestStringifyOverrides(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"metadata": {"type": "object", "description": "Free-form tags.", "properties": {"a": {"type": "string"}}},
+			"other": {"type": "object", "properties": {"b": {"type": "string"}}}
+		}
+	}`)
+	opts := &ConvertOptions{Overrides: map[string]NodeOverride{"#/properties/metadata": {Stringify: true}}}
+	out, entries, err := stringifyOverrides(deepCopyJSON(schema), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Path != "#/properties/metadata" {
+		t.Fatalf("entries = %+v", entries)
+	}
+	metadata, _ := lookupPointer(out, "#/properties/metadata")
+	if want := map[string]any{"description": "Free-form tags."}; !reflect.DeepEqual(metadata, want) {
+		t.Errorf("metadata = %v, want %v", metadata, want)
+	}
+	if b, _ := lookupPointer(out, "#/properties/other/properties/b"); b == nil {
+		t.Error("other property rewritten")
+	}
+	stages := hostStages(schema, roundtripEntries(t, entries))
+	if !reflect.DeepEqual(stages[len(stages)-1], out) {
+		t.Errorf("rehydrate stage = %v, want %v", stages[len(stages)-1], out)
+	}
+}
+
+// TestKeepEnumOverride verifies KeepEnum exempts one enum from
+// MaxEnumValues.
+func TestKeepEnumOverride(t *testing.T) {
+	opts := &ConvertOptions{MaxEnumValues: 500, Overrides: map[string]NodeOverride{"#/properties/country": {KeepEnum: true}}}
+	out, entries, err := degradeLargeEnums(countrySchema(600), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if enum, _ := lookupPointer(out, "#/properties/country/enum"); len(enum.([]any)) != 600 {
+		t.Error("enum degraded")
+	}
+}
+
+// TestNoUnrollLimits verifies NoUnroll overrides become zero recursion
+// limits without changing the caller's map.
+func TestNoUnrollLimits(t *testing.T) {
+	limits := map[string]int{"#/$defs/Tree": 5}
+	got := noUnrollLimits(limits, map[string]NodeOverride{"#/$defs/Node": {NoUnroll: true}, "#/properties/x": {KeepEnum: true}})
+	if want := map[string]int{"#/$defs/Tree": 5, "#/$defs/Node": 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("limits = %v, want %v", got, want)
+	}
+	if len(limits) != 1 {
+		t.Errorf("caller's limits modified: %v", limits)
+	}
+	if got := noUnrollLimits(limits, nil); !reflect.DeepEqual(got, limits) {
+		t.Errorf("limits without overrides = %v", got)
+	}
+}
+
+// TestValidateOverrides verifies malformed keys and a stringified root are
+// rejected.
+func TestValidateOverrides(t *testing.T) {
+	err := (&ConvertOptions{Overrides: map[string]NodeOverride{
+		"properties/x": {Stringify: true},
+		"#":            {Stringify: true},
+	}}).Validate()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{`Overrides: key "properties/x" is not a schema pointer`, "Overrides: the root schema cannot be stringified"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %v, want %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package jsl
+
+// guestPasses are the guest passes DisablePasses may name, by the names
+// ConvertResult.Trace uses.
+var guestPasses = []string{"composition", "polymorphism", "dictionary", "opaque", "strict", "adaptive-opaque", "constraints", "provider-compat"}
+
+// requiredPasses resolve $refs and cannot be disabled.
+var requiredPasses = []string{"normalize", "recursion"}
+
+// passAliases maps descriptive pass names to the pipeline's own.
+var passAliases = map[string]string{"map_to_array": "dictionary"}
+
+func canonicalPassName(name string) string {
+	if to, ok := passAliases[name]; ok {
+		return to
+	}
+	return name
+}
+
+// passDisabled reports whether DisablePasses names the pass.
+func (o *ConvertOptions) passDisabled(name string) bool {
+	if o == nil {
+		return false
+	}
+	for _, n := range o.DisablePasses {
+		if canonicalPassName(n) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// guestDisablePasses returns the guest passes among names, unaliased.
+func guestDisablePasses(names []string) []string {
+	var out []string
+	for _, n := range names {
+		n = canonicalPassName(n)
+		if containsString(guestPasses, n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// knownPass reports whether name (or its alias) is a host or guest pass.
+func knownPass(name string) bool {
+	name = canonicalPassName(name)
+	if containsString(guestPasses, name) {
+		return true
+	}
+	for _, p := range hostPasses {
+		if p.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package jsl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestDisableHostPass verifies a disabled host pass leaves the schema alone.
+func TestDisableHostPass(t *testing.T) {
+	schema := mustMarshal(countrySchema(600))
+	opts := &ConvertOptions{MaxEnumValues: 500, DisablePasses: []string{"large_enums"}}
+	out, entries, err := runHostPasses(schema, opts, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 || string(out) != string(schema) {
+		t.Errorf("entries = %+v, schema changed: %v", entries, string(out) != string(schema))
+	}
+}
+
+// TestGuestDisablePasses verifies aliases are resolved and host pass names
+// are not sent to the guest.
+func TestGuestDisablePasses(t *testing.T) {
+	got := guestDisablePasses([]string{"map_to_array", "large_enums", "constraints"})
+	if want := []string{"dictionary", "constraints"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("guest passes = %v, want %v", got, want)
+	}
+	opts := &ConvertOptions{DisablePasses: []string{"map_to_array"}}
+	if !opts.passDisabled("dictionary") || opts.passDisabled("opaque") {
+		t.Error("passDisabled does not resolve aliases")
+	}
+}
+
+// TestValidateDisablePasses verifies unknown and required passes are
+// rejected.
+func TestValidateDisablePasses(t *testing.T) {
+	if err := (&ConvertOptions{DisablePasses: []string{"map_to_array", "rename", "strict"}}).Validate(); err != nil {
+		t.Errorf("valid passes: %v", err)
+	}
+	err := (&ConvertOptions{DisablePasses: []string{"normalize", "maps"}}).Validate()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{`DisablePasses: pass "normalize" cannot be disabled`, `DisablePasses: unknown pass "maps"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %v, want %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package jsl
+
+import "encoding/json"
+
+// PassSnapshot is the schema as it stood after one conversion pass
+// (ConvertOptions.Trace).
+type PassSnapshot struct {
+	// Pass names the pass: a host pass ("dialect", "optional", ...), a
+	// guest pass ("normalize", "composition", "polymorphism", "dictionary",
+	// "opaque", "recursion", "strict", "adaptive-opaque", "constraints",
+	// "provider-compat"), "slim", "auto_fit", or "custom:" and the name
+	// given to WithCustomPass.
+	Pass   string         `json:"pass"`
+	Schema map[string]any `json:"schema"`
+}
+
+// passTrace collects snapshots for ConvertOptions.Trace and
+// RecordTransforms. A nil *passTrace records nothing.
+type passTrace struct {
+	snapshots []PassSnapshot
+}
+
+func newPassTrace(opts *ConvertOptions) *passTrace {
+	if opts == nil || !opts.Trace && !opts.RecordTransforms {
+		return nil
+	}
+	return &passTrace{}
+}
+
+// record appends a deep copy of schema, so later passes that edit the tree
+// in place do not rewrite earlier snapshots.
+func (t *passTrace) record(pass string, schema any) {
+	if t == nil {
+		return
+	}
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return
+	}
+	var copied map[string]any
+	if json.Unmarshal(b, &copied) != nil {
+		return
+	}
+	t.snapshots = append(t.snapshots, PassSnapshot{Pass: pass, Schema: copied})
+}
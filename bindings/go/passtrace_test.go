@@ -0,0 +1,38 @@
+package jsl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestTraceHostPasses verifies each enabled host pass leaves a snapshot of
+// its own output.
+func TestTraceHostPasses(t *testing.T) {
+	schema := `{"type": "object", "properties": {"a": {"type": "string", "not": {"const": "x"}, "description": "long description"}}}`
+	opts := &ConvertOptions{Trace: true, StripNot: true, MaxDescriptionLength: 4}
+	trace := newPassTrace(opts)
+	if _, _, err := runHostPasses([]byte(schema), opts, trace); err != nil {
+		t.Fatal(err)
+	}
+	if len(trace.snapshots) != 2 || trace.snapshots[0].Pass != "descriptions" || trace.snapshots[1].Pass != "not" {
+		t.Fatalf("snapshots = %v", trace.snapshots)
+	}
+	first, _ := json.Marshal(trace.snapshots[0].Schema)
+	if !strings.Contains(string(first), `"not"`) {
+		t.Errorf("descriptions snapshot lost `not` or was rewritten by a later pass: %s", first)
+	}
+	last, _ := json.Marshal(trace.snapshots[1].Schema)
+	if strings.Contains(string(last), `"not"`) {
+		t.Errorf("not snapshot still has `not`: %s", last)
+	}
+}
+
+// TestTraceOff verifies nothing is recorded without ConvertOptions.Trace.
+func TestTraceOff(t *testing.T) {
+	trace := newPassTrace(&ConvertOptions{})
+	if trace != nil {
+		t.Fatal("newPassTrace returned a trace with Trace unset")
+	}
+	trace.record("x", map[string]any{})
+}
@@ -0,0 +1,173 @@
+package jsl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// PatchOp is one RFC 6902 JSON Patch operation. Value is kept raw so that a
+// JSON null survives encoding.
+type PatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// DiffSchemas returns an RFC 6902 patch turning original into converted.
+// Objects are diffed key by key in sorted order; arrays of equal length are
+// diffed element-wise and otherwise replaced whole, so the patch is
+// deterministic for a given pair of documents.
+func DiffSchemas(original, converted any) ([]PatchOp, error) {
+	a, err := decodeForDiff(original)
+	if err != nil {
+		return nil, fmt.Errorf("original: %w", err)
+	}
+	b, err := decodeForDiff(converted)
+	if err != nil {
+		return nil, fmt.Errorf("converted: %w", err)
+	}
+	var ops []PatchOp
+	if err := diffJSON(a, b, "", &ops); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+// decodeForDiff normalizes v to the generic decoded-JSON representation.
+func decodeForDiff(v any) (any, error) {
+	var raw []byte
+	switch t := v.(type) {
+	case []byte:
+		raw = t
+	case json.RawMessage:
+		raw = t
+	default:
+		var err error
+		if raw, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	var out any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func diffJSON(a, b any, path string, ops *[]PatchOp) error {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			return appendOp(ops, "replace", path, b)
+		}
+		for _, k := range sortedKeys(av) {
+			if _, kept := bv[k]; !kept {
+				*ops = append(*ops, PatchOp{Op: "remove", Path: childDataPath(path, k)})
+			}
+		}
+		for _, k := range sortedKeys(bv) {
+			child := childDataPath(path, k)
+			old, existed := av[k]
+			if !existed {
+				if err := appendOp(ops, "add", child, bv[k]); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := diffJSON(old, bv[k], child, ops); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(bv) != len(av) {
+			return appendOp(ops, "replace", path, b)
+		}
+		for i := range av {
+			if err := diffJSON(av[i], bv[i], childDataPath(path, i), ops); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if jsonEqual(a, b) {
+		return nil
+	}
+	return appendOp(ops, "replace", path, b)
+}
+
+func appendOp(ops *[]PatchOp, op, path string, value any) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode patch value at %q: %w", path, err)
+	}
+	*ops = append(*ops, PatchOp{Op: op, Path: path, Value: raw})
+	return nil
+}
+
+// ApplyPatchResult is the result of an apply_patch operation.
+type ApplyPatchResult struct {
+	APIVersion string         `json:"apiVersion"`
+	Schema     map[string]any `json:"schema"`
+}
+
+// SchemaFingerprint returns the hex SHA-256 of schema's JSON encoding with
+// object keys sorted, so equal documents share a fingerprint regardless of
+// the key order they were written in.
+func SchemaFingerprint(schema any) (string, error) {
+	v, err := decodeForDiff(schema)
+	if err != nil {
+		return "", fmt.Errorf("decode schema: %w", err)
+	}
+	b, err := json.Marshal(v) // encoding/json sorts map keys
+	if err != nil {
+		return "", fmt.Errorf("encode schema: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ApplyConversionPatch reconstructs a converted schema from the original and
+// a patch produced with ConvertOptions.EmitPatch. When fingerprint is
+// non-empty (ConvertResult.PatchFingerprint), the reconstruction is verified
+// against it and a mismatch fails with code "fingerprint_mismatch".
+func (e *SchemaLlmEngine) ApplyConversionPatch(original any, patch []PatchOp, fingerprint string) (*ApplyPatchResult, error) {
+	schemaBytes, err := json.Marshal(original)
+	if err != nil {
+		return nil, fmt.Errorf("marshal schema: %w", err)
+	}
+	if patch == nil {
+		patch = []PatchOp{}
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("marshal patch: %w", err)
+	}
+
+	payload, err := e.callJsl("jsl_apply_patch", schemaBytes, patchBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ApplyPatchResult
+	if err := json.Unmarshal(payload, &result); err != nil {
+		return nil, fmt.Errorf("unmarshal apply_patch result: %w", err)
+	}
+	if fingerprint != "" {
+		got, err := SchemaFingerprint(result.Schema)
+		if err != nil {
+			return nil, err
+		}
+		if got != fingerprint {
+			return nil, &Error{
+				Code:    "fingerprint_mismatch",
+				Message: fmt.Sprintf("reconstructed schema fingerprint %s does not match %s", got, fingerprint),
+			}
+		}
+	}
+	return &result, nil
+}
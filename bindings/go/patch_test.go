@@ -0,0 +1,96 @@
+package jsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestDiffSchemas verifies the emitted patch is deterministic and reproduces the converted schema.
+func TestDiffSchemas(t *testing.T) {
+	original := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "format": "email"},
+			"tags": {"type": "object", "additionalProperties": {"type": "string"}},
+			"note": {"type": ["string", "null"]}
+		},
+		"required": ["name"]
+	}`)
+	converted := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"tags": {"type": "array", "items": {"type": "object"}},
+			"note": {"type": ["string", "null"], "default": null}
+		},
+		"required": ["name", "tags", "note"],
+		"additionalProperties": false
+	}`)
+
+	ops, err := DiffSchemas(original, converted)
+	if err != nil {
+		t.Fatalf("DiffSchemas() failed: %v", err)
+	}
+	want := `[{"op":"add","path":"/additionalProperties","value":false},` +
+		`{"op":"remove","path":"/properties/name/format"},` +
+		`{"op":"add","path":"/properties/note/default","value":null},` +
+		`{"op":"remove","path":"/properties/tags/additionalProperties"},` +
+		`{"op":"add","path":"/properties/tags/items","value":{"type":"object"}},` +
+		`{"op":"replace","path":"/properties/tags/type","value":"array"},` +
+		`{"op":"replace","path":"/required","value":["name","tags","note"]}]`
+	if got := string(mustMarshal(ops)); got != want {
+		t.Errorf("patch:\n  got:  %s\n  want: %s", got, want)
+	}
+
+	patched := applyPatchForTest(t, deepCopyJSON(original), ops)
+	if !jsonEqual(patched, converted) {
+		t.Errorf("patched schema differs:\n  got:  %s\n  want: %s", mustMarshal(patched), mustMarshal(converted))
+	}
+
+	if ops, _ := DiffSchemas(original, original); len(ops) != 0 {
+		t.Errorf("identical documents should yield an empty patch, got %+v", ops)
+	}
+}
+
+// applyPatchForTest applies the add/remove/replace subset DiffSchemas emits.
+func applyPatchForTest(t *testing.T, doc any, ops []PatchOp) any {
+	t.Helper()
+	for _, op := range ops {
+		segs := splitPointer(op.Path)
+		switch op.Op {
+		case "add", "replace":
+			var v any
+			if err := json.Unmarshal(op.Value, &v); err != nil {
+				t.Fatalf("decode value for %s: %v", op.Path, err)
+			}
+			if op.Op == "add" {
+				parent, _ := lookupPointer(doc, "#/"+joinSegments(segs[:len(segs)-1]))
+				parent.(map[string]any)[segs[len(segs)-1]] = v
+			} else {
+				doc = replaceAtPointer(doc, op.Path, v)
+			}
+		case "remove":
+			parent, _ := lookupPointer(doc, "#/"+joinSegments(segs[:len(segs)-1]))
+			delete(parent.(map[string]any), segs[len(segs)-1])
+		default:
+			t.Fatalf("unexpected op %q", op.Op)
+		}
+	}
+	return doc
+}
+
+// TestSchemaFingerprintIgnoresKeyOrder verifies fingerprints depend on content, not key order.
+func TestSchemaFingerprintIgnoresKeyOrder(t *testing.T) {
+	a, err := SchemaFingerprint(json.RawMessage(`{"type":"object","required":["a"]}`))
+	if err != nil {
+		t.Fatalf("SchemaFingerprint() failed: %v", err)
+	}
+	b, _ := SchemaFingerprint(map[string]any{"required": []any{"a"}, "type": "object"})
+	c, _ := SchemaFingerprint(map[string]any{"required": []any{"b"}, "type": "object"})
+	if a != b {
+		t.Errorf("equal documents fingerprint differently: %s vs %s", a, b)
+	}
+	if a == c {
+		t.Error("different documents share a fingerprint")
+	}
+}
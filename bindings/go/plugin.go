@@ -0,0 +1,126 @@
+package jsl
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// transformNodePlugin records a node a before-conversion NodePlugin
+// rewrote. Params["node"] is the replacement, so Rehydrate can re-derive
+// the schema the guest converted without the plugin being registered.
+const transformNodePlugin = "x-node-plugin"
+
+// PluginStage selects when a NodePlugin runs.
+type PluginStage string
+
+const (
+	// PluginBeforeConvert runs on the input schema, before every host and
+	// guest pass. This is the default.
+	PluginBeforeConvert PluginStage = ""
+	// PluginAfterConvert runs on the converted schema, in registration
+	// order with custom passes (see WithCustomPass).
+	PluginAfterConvert PluginStage = "after"
+)
+
+// NodePlugin is a proprietary transform applied to every schema node Match
+// selects, without forking the conversion pipeline. Nodes are visited in
+// post-order (children before their parent); loc is the node's pointer in
+// the schema being walked.
+//
+// Changes that alter the shape of the data the model returns need a
+// matching CustomPass and CustomHandler instead: plugins record no
+// rehydration step of their own.
+type NodePlugin struct {
+	Stage     PluginStage
+	Match     func(loc string, node map[string]any) bool
+	Transform func(loc string, node map[string]any) (map[string]any, error)
+}
+
+type namedPlugin struct {
+	name   string
+	plugin NodePlugin
+}
+
+// WithNodePlugin registers a node plugin. Before-conversion plugins run in
+// registration order; after-conversion plugins run as custom passes.
+func WithNodePlugin(name string, p NodePlugin) Option {
+	return func(c *engineConfig) {
+		if p.Stage == PluginAfterConvert {
+			c.customPasses = append(c.customPasses, namedPass{name: name, pass: p.customPass()})
+			return
+		}
+		c.nodePlugins = append(c.nodePlugins, namedPlugin{name: name, plugin: p})
+	}
+}
+
+// apply walks schema and transforms every matching node, calling record
+// with each replacement.
+func (p NodePlugin) apply(schema any, record func(loc string, node map[string]any)) (any, error) {
+	var err error
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		if err != nil || !p.Match(loc, node) {
+			return node
+		}
+		out, terr := p.Transform(loc, node)
+		if terr != nil {
+			err = fmt.Errorf("%s: %w", loc, terr)
+			return node
+		}
+		if out == nil {
+			out = map[string]any{}
+		}
+		if record != nil {
+			record(loc, out)
+		}
+		return out
+	})
+	return schema, err
+}
+
+func (p NodePlugin) customPass() CustomPass {
+	return func(schema map[string]any, codec any) (map[string]any, any, error) {
+		out, err := p.apply(schema, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		m, _ := out.(map[string]any)
+		return m, codec, nil
+	}
+}
+
+// runNodePlugins applies the before-conversion plugins to schemaBytes. It
+// returns the bytes untouched when none are registered.
+func (e *SchemaLlmEngine) runNodePlugins(schemaBytes []byte, trace *passTrace) ([]byte, []HostTransform, error) {
+	if len(e.nodePlugins) == 0 {
+		return schemaBytes, nil, nil
+	}
+	var tree any
+	if err := json.Unmarshal(schemaBytes, &tree); err != nil {
+		return nil, nil, fmt.Errorf("decode schema: %w", err)
+	}
+	var entries []HostTransform
+	for _, np := range e.nodePlugins {
+		var err error
+		tree, err = np.plugin.apply(tree, func(loc string, node map[string]any) {
+			entries = append(entries, HostTransform{
+				ID:     EntryID(transformNodePlugin, loc),
+				Type:   transformNodePlugin,
+				Path:   loc,
+				Params: map[string]any{"plugin": np.name, "node": deepCopyJSON(node)},
+			})
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("node plugin %s: %w", np.name, err)
+		}
+		trace.record("plugin:"+np.name, tree)
+	}
+	out, err := json.Marshal(tree)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode schema: %w", err)
+	}
+	return out, entries, nil
+}
+
+func rewriteNodePlugin(_ any, t *HostTransform) any {
+	return deepCopyJSON(t.Params["node"])
+}
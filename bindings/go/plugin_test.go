@@ -0,0 +1,81 @@
+package jsl
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// piiPlugin tags every property named "ssn" as sensitive.
+var piiPlugin = NodePlugin{
+	Match: func(loc string, _ map[string]any) bool { return strings.HasSuffix(loc, "/properties/ssn") },
+	Transform: func(_ string, node map[string]any) (map[string]any, error) {
+		node["description"] = "Sensitive: never echo."
+		return node, nil
+	},
+}
+
+// TestNodePluginBeforeConvert verifies matched nodes are rewritten and that
+// Rehydrate re-derives the rewritten schema from the entries alone.
+func TestNodePluginBeforeConvert(t *testing.T) {
+	cfg := &engineConfig{}
+	WithNodePlugin("pii", piiPlugin)(cfg)
+	e := &SchemaLlmEngine{nodePlugins: cfg.nodePlugins}
+
+	src := `{"type": "object", "properties": {"ssn": {"type": "string"}, "name": {"type": "string"}}}`
+	out, entries, err := e.runNodePlugins([]byte(src), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Path != "#/properties/ssn" || entries[0].Params["plugin"] != "pii" {
+		t.Fatalf("entries = %+v", entries)
+	}
+	converted := decodeJSON(t, string(out))
+	if desc, _ := lookupPointer(converted, "#/properties/ssn/description"); desc != "Sensitive: never echo." {
+		t.Errorf("ssn description = %v", desc)
+	}
+	if desc, _ := lookupPointer(converted, "#/properties/name/description"); desc != nil {
+		t.Errorf("unmatched node rewritten: %v", desc)
+	}
+
+	stages := hostStages(decodeJSON(t, src), roundtripEntries(t, entries))
+	if !reflect.DeepEqual(stages[len(stages)-1], converted) {
+		t.Errorf("rehydrate stage = %v, want %v", stages[len(stages)-1], converted)
+	}
+}
+
+// TestNodePluginAfterConvert verifies after-conversion plugins run with the
+// custom passes.
+func TestNodePluginAfterConvert(t *testing.T) {
+	p := piiPlugin
+	p.Stage = PluginAfterConvert
+	cfg := &engineConfig{}
+	WithNodePlugin("pii", p)(cfg)
+	if len(cfg.nodePlugins) != 0 || len(cfg.customPasses) != 1 {
+		t.Fatalf("plugins = %d, custom passes = %d", len(cfg.nodePlugins), len(cfg.customPasses))
+	}
+	e := &SchemaLlmEngine{customPasses: cfg.customPasses}
+	result := &ConvertResult{Schema: decodeJSON(t, `{"type": "object", "properties": {"ssn": {"type": "string"}}}`).(map[string]any)}
+	if err := e.runCustomPasses(result, nil); err != nil {
+		t.Fatal(err)
+	}
+	if desc, _ := lookupPointer(result.Schema, "#/properties/ssn/description"); desc != "Sensitive: never echo." {
+		t.Errorf("ssn description = %v", desc)
+	}
+}
+
+// TestNodePluginError verifies a Transform error names the plugin and node.
+func TestNodePluginError(t *testing.T) {
+	boom := errors.New("boom")
+	cfg := &engineConfig{}
+	WithNodePlugin("strict", NodePlugin{
+		Match:     func(string, map[string]any) bool { return true },
+		Transform: func(string, map[string]any) (map[string]any, error) { return nil, boom },
+	})(cfg)
+	e := &SchemaLlmEngine{nodePlugins: cfg.nodePlugins}
+	_, _, err := e.runNodePlugins([]byte(`{"type": "object", "properties": {"a": {}}}`), nil)
+	if !errors.Is(err, boom) || !strings.Contains(err.Error(), "node plugin strict: #/properties/a") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package jsl
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// escapePointerSegment escapes a single RFC 6901 reference token.
+func escapePointerSegment(s string) string {
+	if !strings.ContainsAny(s, "~/") {
+		return s
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// unescapePointerSegment reverses escapePointerSegment.
+func unescapePointerSegment(s string) string {
+	if !strings.Contains(s, "~") {
+		return s
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+}
+
+// childPointer appends an escaped segment to a schema pointer ("#/a" → "#/a/b").
+func childPointer(parent, segment string) string {
+	return parent + "/" + escapePointerSegment(segment)
+}
+
+// childDataPath appends a segment to a data path ("" → "/a", "/a" → "/a/0").
+func childDataPath(parent string, segment any) string {
+	switch s := segment.(type) {
+	case int:
+		return parent + "/" + strconv.Itoa(s)
+	case string:
+		return parent + "/" + escapePointerSegment(s)
+	}
+	return parent
+}
+
+// splitPointer splits a schema pointer ("#/properties/a~1b") into unescaped
+// segments (["properties", "a/b"]). The root pointer yields no segments.
+func splitPointer(ptr string) []string {
+	ptr = strings.TrimPrefix(ptr, "#")
+	ptr = strings.TrimPrefix(ptr, "/")
+	if ptr == "" {
+		return nil
+	}
+	parts := strings.Split(ptr, "/")
+	for i, p := range parts {
+		parts[i] = unescapePointerSegment(p)
+	}
+	return parts
+}
+
+// lookupPointer resolves a schema pointer against a decoded JSON tree.
+func lookupPointer(root any, ptr string) (any, bool) {
+	node := root
+	for _, seg := range splitPointer(ptr) {
+		switch n := node.(type) {
+		case map[string]any:
+			child, ok := n[seg]
+			if !ok {
+				return nil, false
+			}
+			node = child
+		case []any:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(n) {
+				return nil, false
+			}
+			node = n[idx]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// replaceAtPointer replaces the node at ptr and returns the (possibly new)
+// root. Missing intermediate nodes leave the tree unchanged.
+func replaceAtPointer(root any, ptr string, value any) any {
+	segs := splitPointer(ptr)
+	if len(segs) == 0 {
+		return value
+	}
+	parent, ok := lookupPointer(root, "#/"+joinSegments(segs[:len(segs)-1]))
+	if !ok {
+		return root
+	}
+	last := segs[len(segs)-1]
+	switch p := parent.(type) {
+	case map[string]any:
+		p[last] = value
+	case []any:
+		if idx, err := strconv.Atoi(last); err == nil && idx >= 0 && idx < len(p) {
+			p[idx] = value
+		}
+	}
+	return root
+}
+
+// joinSegments escapes and joins raw segments without the leading "#/".
+func joinSegments(segs []string) string {
+	escaped := make([]string, len(segs))
+	for i, s := range segs {
+		escaped[i] = escapePointerSegment(s)
+	}
+	return strings.Join(escaped, "/")
+}
+
+// pointerResolves reports whether ptr names a node in the JSON document
+// schemaBytes.
+func pointerResolves(schemaBytes []byte, ptr string) bool {
+	var root any
+	if json.Unmarshal(schemaBytes, &root) != nil {
+		return false
+	}
+	_, ok := lookupPointer(root, ptr)
+	return ok
+}
+
+// Pointer is a parsed JSON Pointer (RFC 6901): its reference tokens,
+// unescaped. The root pointer has no segments.
+type Pointer []string
+
+// ParsePointer parses a schema pointer ("#/properties/a~1b") or a data path
+// ("/items/0"); both forms yield the same segments.
+func ParsePointer(s string) Pointer {
+	return Pointer(splitPointer(s))
+}
+
+// String returns p in the URI-fragment form Error.Path and schema
+// locations use ("#/properties/a~1b"; "#" for the root).
+func (p Pointer) String() string {
+	if len(p) == 0 {
+		return "#"
+	}
+	return "#/" + joinSegments(p)
+}
+
+// DataPath returns p in the RFC 6901 form warning data paths use
+// ("/items/0"; "" for the root).
+func (p Pointer) DataPath() string {
+	if len(p) == 0 {
+		return ""
+	}
+	return "/" + joinSegments(p)
+}
+
+// Parent returns p without its last segment; the root is its own parent.
+func (p Pointer) Parent() Pointer {
+	if len(p) == 0 {
+		return p
+	}
+	return p[: len(p)-1 : len(p)-1]
+}
+
+// Last returns p's final segment, or "" for the root.
+func (p Pointer) Last() string {
+	if len(p) == 0 {
+		return ""
+	}
+	return p[len(p)-1]
+}
+
+// Child returns p extended by segment, leaving p unchanged.
+func (p Pointer) Child(segment string) Pointer {
+	return append(p[:len(p):len(p)], segment)
+}
+
+// Lookup resolves p against a decoded JSON document.
+func (p Pointer) Lookup(doc any) (any, bool) {
+	return lookupPointer(doc, p.String())
+}
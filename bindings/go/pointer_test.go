@@ -0,0 +1,55 @@
+package jsl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePointer(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want Pointer
+		str  string
+	}{
+		{"#", nil, "#"},
+		{"", nil, "#"},
+		{"#/properties/a~1b/items", Pointer{"properties", "a/b", "items"}, "#/properties/a~1b/items"},
+		{"/items/0/x~0y", Pointer{"items", "0", "x~y"}, "#/items/0/x~0y"},
+	} {
+		got := ParsePointer(tc.in)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParsePointer(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+		if s := got.String(); s != tc.str {
+			t.Errorf("ParsePointer(%q).String() = %q, want %q", tc.in, s, tc.str)
+		}
+	}
+	if got := ParsePointer("/items/0/x~0y").DataPath(); got != "/items/0/x~0y" {
+		t.Errorf("DataPath() = %q", got)
+	}
+}
+
+func TestPointerNavigation(t *testing.T) {
+	p := ParsePointer("#/properties/a~1b")
+	child := p.Child("items")
+	sibling := p.Child("enum")
+	if child.String() != "#/properties/a~1b/items" || sibling.String() != "#/properties/a~1b/enum" {
+		t.Errorf("Child = %s, %s", child, sibling)
+	}
+	if p.Last() != "a/b" || p.Parent().String() != "#/properties" || Pointer(nil).Parent() != nil {
+		t.Errorf("Last = %q, Parent = %s", p.Last(), p.Parent())
+	}
+
+	doc := map[string]any{"properties": map[string]any{"a/b": map[string]any{"type": "string"}}}
+	if node, ok := p.Lookup(doc); !ok || node.(map[string]any)["type"] != "string" {
+		t.Errorf("Lookup = %v, %v", node, ok)
+	}
+	if _, ok := p.Child("missing").Lookup(doc); ok {
+		t.Error("Lookup of a missing node succeeded")
+	}
+
+	err := &Error{Code: "unresolvable_ref", Path: "#/properties/x/$ref"}
+	if got := err.Pointer(); got.Last() != "$ref" || got.Parent().String() != "#/properties/x" {
+		t.Errorf("Error.Pointer() = %s", got)
+	}
+}
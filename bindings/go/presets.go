@@ -0,0 +1,58 @@
+package jsl
+
+// Presets are ConvertOptions combinations maintained with the passes they
+// configure. They are values: copy one, adjust fields if needed, and pass
+// its address to Convert (or start from one with WithPreset).
+var (
+	// PresetOpenAIStrict targets OpenAI strict structured outputs: rewrite
+	// the keywords strict mode rejects (const, if/then/else, not, tuples)
+	// instead of losing them, keep formats and numeric bounds checkable on
+	// rehydration, and shrink oversized schemas to the target's limits.
+	PresetOpenAIStrict = ConvertOptions{
+		Target:                TargetOpenAI,
+		ConstStrategy:         ConstStrategyEnum,
+		ConditionalStrategy:   ConditionalStrategyAnyOf,
+		StripNot:              true,
+		TupleStrategy:         TupleStrategyObject,
+		PreserveFormats:       true,
+		DescribeNumericBounds: true,
+		AutoFit:               true,
+	}
+
+	// PresetGemini targets Gemini, which accepts more of JSON Schema but not
+	// conditionals, `not` or tuples.
+	PresetGemini = ConvertOptions{
+		Target:              TargetGemini,
+		ConditionalStrategy: ConditionalStrategyAnyOf,
+		StripNot:            true,
+		TupleStrategy:       TupleStrategyObject,
+		PreserveFormats:     true,
+	}
+
+	// PresetConservative trades schema fidelity for the widest provider
+	// compatibility: everything PresetOpenAIStrict does, plus shallow
+	// recursion, safe property names, shortened descriptions (recoverable
+	// with FullDescription), capped enums and hoisted deep nesting.
+	PresetConservative = ConvertOptions{
+		Target:                TargetOpenAI,
+		RecursionLimit:        2,
+		ConstStrategy:         ConstStrategyEnum,
+		ConditionalStrategy:   ConditionalStrategyAnyOf,
+		StripNot:              true,
+		TupleStrategy:         TupleStrategyObject,
+		PreserveFormats:       true,
+		DescribeNumericBounds: true,
+		SanitizePropertyNames: true,
+		MaxDescriptionLength:  300,
+		PreserveDescriptions:  true,
+		MaxEnumValues:         100,
+		MaxNestingDepth:       5,
+		AutoFit:               true,
+	}
+)
+
+// WithPreset starts from a copy of preset; later options override its
+// fields.
+func WithPreset(preset ConvertOptions) ConvertOption {
+	return func(o *ConvertOptions) { *o = preset }
+}
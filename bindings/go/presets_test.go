@@ -0,0 +1,28 @@
+package jsl
+
+import "testing"
+
+// TestPresetsValid verifies every preset passes validation.
+func TestPresetsValid(t *testing.T) {
+	for name, p := range map[string]ConvertOptions{
+		"PresetOpenAIStrict": PresetOpenAIStrict,
+		"PresetGemini":       PresetGemini,
+		"PresetConservative": PresetConservative,
+	} {
+		if err := p.Validate(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+// TestWithPreset verifies later options override the preset without
+// modifying it.
+func TestWithPreset(t *testing.T) {
+	opts, err := NewConvertOptions(WithPreset(PresetConservative), WithRecursionLimit(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.RecursionLimit != 4 || !opts.SanitizePropertyNames || PresetConservative.RecursionLimit != 2 {
+		t.Errorf("opts = %+v", opts)
+	}
+}
@@ -0,0 +1,78 @@
+package jsl
+
+import (
+	"runtime/trace"
+	"strings"
+	"time"
+)
+
+// Phase is one step of an engine call, as reported by WithPhaseTimings and
+// runtime/trace regions.
+type Phase string
+
+const (
+	// PhaseMarshal encodes the call's inputs to JSON for the guest (Convert
+	// and Rehydrate only; host-side passes are not included).
+	PhaseMarshal Phase = "marshal"
+	// PhaseInstantiate creates the fresh wasm module instance each guest call
+	// runs in.
+	PhaseInstantiate Phase = "instantiate"
+	// PhaseGuestCall covers the guest round trip in that instance: copying
+	// the arguments in, running the export, and copying the result out (or,
+	// for ConvertInto, running its callback on the result in place).
+	PhaseGuestCall Phase = "guest_call"
+	// PhaseUnmarshal decodes the guest's JSON result (Convert and Rehydrate
+	// only).
+	PhaseUnmarshal Phase = "unmarshal"
+)
+
+// PhaseTiming is the duration of one phase of one call.
+type PhaseTiming struct {
+	// Op is the guest export without its "jsl_" prefix: "convert",
+	// "rehydrate", "list_components" or "extract_component".
+	// ConvertAllComponents reports the convert, list and extract calls it
+	// makes.
+	Op       string
+	Phase    Phase
+	Duration time.Duration
+	// Err is the phase's error, if it failed.
+	Err error
+}
+
+// WithPhaseTimings has the engine call fn with the duration of every phase
+// of every call, to see where time goes in production without a profiler.
+// fn runs synchronously on the calling goroutine, so it should be cheap:
+// hand the timing to a histogram or a channel.
+//
+// Independently of this option, each phase is a runtime/trace region
+// ("jsl.<op>.<phase>") whenever an execution trace is being collected, so
+// `go tool trace` shows the same breakdown.
+func WithPhaseTimings(fn func(PhaseTiming)) Option {
+	return func(c *engineConfig) {
+		c.phaseTimings = fn
+	}
+}
+
+// startPhase begins timing phase of op and returns the function that ends
+// it. When neither WithPhaseTimings nor an execution trace is active, it
+// does no work.
+func (e *SchemaLlmEngine) startPhase(op string, phase Phase) func(err error) {
+	tracing := trace.IsEnabled()
+	if e.phaseTimings == nil && !tracing {
+		return func(error) {}
+	}
+	op = strings.TrimPrefix(op, "jsl_")
+	var region *trace.Region
+	if tracing {
+		region = trace.StartRegion(e.ctx, "jsl."+op+"."+string(phase))
+	}
+	start := time.Now()
+	return func(err error) {
+		if region != nil {
+			region.End()
+		}
+		if e.phaseTimings != nil {
+			e.phaseTimings(PhaseTiming{Op: op, Phase: phase, Duration: time.Since(start), Err: err})
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package jsl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime/trace"
+	"testing"
+)
+
+// TestStartPhaseReports verifies phases are reported with the export's
+// short name, their error and a duration.
+func TestStartPhaseReports(t *testing.T) {
+	var got []PhaseTiming
+	cfg := &engineConfig{}
+	WithPhaseTimings(func(p PhaseTiming) { got = append(got, p) })(cfg)
+	eng := &SchemaLlmEngine{ctx: context.Background(), phaseTimings: cfg.phaseTimings}
+
+	eng.startPhase("jsl_convert", PhaseInstantiate)(nil)
+	boom := errors.New("boom")
+	eng.startPhase("rehydrate", PhaseUnmarshal)(boom)
+
+	if len(got) != 2 {
+		t.Fatalf("got %d timings, want 2: %+v", len(got), got)
+	}
+	if got[0].Op != "convert" || got[0].Phase != PhaseInstantiate || got[0].Err != nil || got[0].Duration < 0 {
+		t.Errorf("timing 0 = %+v, want convert/instantiate without error", got[0])
+	}
+	if got[1].Op != "rehydrate" || got[1].Phase != PhaseUnmarshal || got[1].Err != boom {
+		t.Errorf("timing 1 = %+v, want rehydrate/unmarshal with its error", got[1])
+	}
+}
+
+// TestStartPhaseTraceRegions verifies phases work without a callback
+// while an execution trace is collected.
+func TestStartPhaseTraceRegions(t *testing.T) {
+	var buf bytes.Buffer
+	if err := trace.Start(&buf); err != nil {
+		t.Skipf("execution trace unavailable: %v", err)
+	}
+	eng := &SchemaLlmEngine{ctx: context.Background()}
+	eng.startPhase("jsl_convert", PhaseGuestCall)(nil)
+	trace.Stop()
+	if !bytes.Contains(buf.Bytes(), []byte("jsl.convert.guest_call")) {
+		t.Error("trace does not contain the jsl.convert.guest_call region")
+	}
+}
+
+// TestPhaseTimingsConvert verifies a Convert reports every phase in order.
+func TestPhaseTimingsConvert(t *testing.T) {
+	var phases []Phase
+	eng, err := NewSchemaLlmEngine(WithPhaseTimings(func(p PhaseTiming) {
+		if p.Op == "convert" {
+			phases = append(phases, p.Phase)
+		}
+	}))
+	if err != nil {
+		t.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	if _, err := eng.Convert(map[string]any{"type": "object", "properties": map[string]any{"a": map[string]any{"type": "string"}}}, nil); err != nil {
+		t.Fatalf("Convert() failed: %v", err)
+	}
+	want := []Phase{PhaseMarshal, PhaseInstantiate, PhaseGuestCall, PhaseUnmarshal}
+	if len(phases) != len(want) {
+		t.Fatalf("phases = %v, want %v", phases, want)
+	}
+	for i := range want {
+		if phases[i] != want[i] {
+			t.Errorf("phases = %v, want %v", phases, want)
+			break
+		}
+	}
+}
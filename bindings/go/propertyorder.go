@@ -0,0 +1,172 @@
+package jsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// PropertyOrder selects the key order of `properties` objects in
+// ConvertResult.SchemaJSON. Every other object is encoded with sorted keys,
+// so equal inputs always produce identical bytes.
+type PropertyOrder string
+
+const (
+	// PropertyOrderSorted sorts property names, as encoding/json does for
+	// ConvertResult.Schema (default).
+	PropertyOrderSorted PropertyOrder = ""
+	// PropertyOrderSource keeps each object's properties in the order the
+	// input's JSON listed them; properties the conversion added or renamed
+	// follow, sorted. Go maps marshal sorted, so pass the schema as
+	// json.RawMessage (or a struct) to keep its text order.
+	PropertyOrderSource PropertyOrder = "source"
+)
+
+// propertyOrders records the key order of every `properties` object in a
+// source document.
+type propertyOrders struct {
+	lists  [][]string
+	byName map[string][]int // property name → indexes into lists
+}
+
+// sourcePropertyOrders reads the `properties` key order out of schemaBytes,
+// which decoding into a map would lose.
+func sourcePropertyOrders(schemaBytes []byte) (*propertyOrders, error) {
+	p := &propertyOrders{byName: map[string][]int{}}
+	dec := json.NewDecoder(bytes.NewReader(schemaBytes))
+	if err := p.read(dec, false); err != nil {
+		return nil, fmt.Errorf("read property order: %w", err)
+	}
+	return p, nil
+}
+
+// read consumes one value; isProps marks a `properties` object, whose
+// members are schemas.
+func (p *propertyOrders) read(dec *json.Decoder, isProps bool) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		var keys []string
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key := tok.(string)
+			if isProps {
+				keys = append(keys, key)
+			}
+			if err := p.read(dec, !isProps && key == "properties"); err != nil {
+				return err
+			}
+		}
+		if isProps {
+			for _, k := range keys {
+				p.byName[k] = append(p.byName[k], len(p.lists))
+			}
+			p.lists = append(p.lists, keys)
+		}
+	case json.Delim('['):
+		for dec.More() {
+			if err := p.read(dec, false); err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+	_, err = dec.Token() // closing delimiter
+	return err
+}
+
+// order sorts the names of one converted `properties` object by the source
+// object sharing the most of them (the first such on ties).
+func (p *propertyOrders) order(names []string) []string {
+	overlap := map[int]int{}
+	best, bestCount := -1, 0
+	for _, n := range names {
+		for _, i := range p.byName[n] {
+			overlap[i]++
+			if c := overlap[i]; c > bestCount || c == bestCount && i < best {
+				best, bestCount = i, c
+			}
+		}
+	}
+	pos := map[string]int{}
+	if best >= 0 {
+		for i, n := range p.lists[best] {
+			pos[n] = i
+		}
+	}
+	sort.Strings(names)
+	sort.SliceStable(names, func(i, j int) bool {
+		pi, iok := pos[names[i]]
+		pj, jok := pos[names[j]]
+		if iok && jok {
+			return pi < pj
+		}
+		return iok && !jok
+	})
+	return names
+}
+
+// encodeSchema encodes a converted schema in the requested property order.
+func encodeSchema(schema map[string]any, source []byte, order PropertyOrder) (json.RawMessage, error) {
+	if order != PropertyOrderSource {
+		return json.Marshal(schema)
+	}
+	p, err := sourcePropertyOrders(source)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := p.encode(&buf, schema, false); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (p *propertyOrders) encode(buf *bytes.Buffer, v any, isProps bool) error {
+	switch t := v.(type) {
+	case map[string]any:
+		keys := sortedKeys(t)
+		if isProps {
+			keys = p.order(keys)
+		}
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			kb, _ := json.Marshal(k)
+			buf.Write(kb)
+			buf.WriteByte(':')
+			if err := p.encode(buf, t[k], !isProps && k == "properties"); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []any:
+		buf.WriteByte('[')
+		for i, c := range t {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := p.encode(buf, c, false); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package jsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestEncodeSchemaSourceOrder verifies properties follow the source object
+// they came from, new properties trail sorted, and other keys are sorted.
+func TestEncodeSchemaSourceOrder(t *testing.T) {
+	source := []byte(`{
+		"type": "object",
+		"properties": {
+			"zeta": {"type": "string"},
+			"alpha": {"type": "object", "properties": {"reasoning": {"type": "string"}, "answer": {"type": "string"}}},
+			"properties": {"type": "object", "properties": {"b": {}, "a": {}}}
+		},
+		"required": ["zeta"]
+	}`)
+	var converted map[string]any
+	if err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"additionalProperties": false,
+		"required": ["alpha", "properties", "zeta"],
+		"properties": {
+			"zeta": {"type": "string"},
+			"alpha": {"type": "object", "additionalProperties": false, "properties": {"answer": {"type": "string"}, "reasoning": {"type": "string"}, "added": {"type": "string"}}},
+			"properties": {"type": "object", "properties": {"a": {}, "b": {}}}
+		}
+	}`), &converted); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := encodeSchema(converted, source, PropertyOrderSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"additionalProperties":false,"properties":{"zeta":{"type":"string"},"alpha":{"additionalProperties":false,"properties":{"reasoning":{"type":"string"},"answer":{"type":"string"},"added":{"type":"string"}},"type":"object"},"properties":{"properties":{"b":{},"a":{}},"type":"object"}},"required":["alpha","properties","zeta"],"type":"object"}`
+	if string(got) != want {
+		t.Errorf("encodeSchema() =\n%s\nwant\n%s", got, want)
+	}
+
+	sorted, err := encodeSchema(converted, source, PropertyOrderSorted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain, _ := json.Marshal(converted); string(sorted) != string(plain) {
+		t.Errorf("sorted encoding differs from json.Marshal: %s", sorted)
+	}
+}
@@ -0,0 +1,88 @@
+package jsl
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+// transformPropertyNames records a propertyNames constraint so Rehydrate can
+// check the keys of the restored object; the map→array pass otherwise loses it.
+const transformPropertyNames = "property_names"
+
+// recordPropertyNames records every object-form propertyNames keyword. The
+// schema is left untouched; the guest decides what the target sees.
+func recordPropertyNames(schema any, _ *ConvertOptions) (any, []HostTransform, error) {
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		if names, ok := node["propertyNames"].(map[string]any); ok {
+			entries = append(entries, HostTransform{
+				Type:   transformPropertyNames,
+				Path:   loc,
+				Params: map[string]any{"schema": names},
+			})
+		}
+		return node
+	})
+	return schema, entries, nil
+}
+
+// restorePropertyNames warns once per key of the rehydrated object that
+// violates the recorded pattern, length bounds, or enum/const.
+func restorePropertyNames(v any, t *HostTransform, dataPath string) (any, []Warning) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return v, nil
+	}
+	names, _ := t.Params["schema"].(map[string]any)
+	var re *regexp.Regexp
+	if p, ok := names["pattern"].(string); ok {
+		re, _ = regexp.Compile(p)
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var warnings []Warning
+	for _, key := range keys {
+		reason := propertyNameViolation(key, names, re)
+		if reason == "" {
+			continue
+		}
+		warnings = append(warnings, Warning{
+			DataPath:   childDataPath(dataPath, key),
+			SchemaPath: t.Path,
+			Kind:       WarningKind{Type: WarnConstraintViolation, Constraint: "propertyNames"},
+			Message:    fmt.Sprintf("key %q %s", key, reason),
+		})
+	}
+	return v, warnings
+}
+
+func propertyNameViolation(key string, names map[string]any, re *regexp.Regexp) string {
+	if re != nil && !re.MatchString(key) {
+		return fmt.Sprintf("does not match pattern %q", re.String())
+	}
+	n := utf8.RuneCountInString(key)
+	if min, ok := names["minLength"].(float64); ok && n < int(min) {
+		return fmt.Sprintf("is shorter than minLength %d", int(min))
+	}
+	if max, ok := names["maxLength"].(float64); ok && n > int(max) {
+		return fmt.Sprintf("is longer than maxLength %d", int(max))
+	}
+	if c, ok := names["const"].(string); ok && key != c {
+		return fmt.Sprintf("does not equal const %q", c)
+	}
+	if enum, ok := names["enum"].([]any); ok {
+		for _, e := range enum {
+			if e == key {
+				return ""
+			}
+		}
+		return "is not in the allowed enum"
+	}
+	return ""
+}
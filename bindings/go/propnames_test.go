@@ -0,0 +1,35 @@
+package jsl
+
+import "testing"
+
+// TestPropertyNamesChecked verifies map keys are checked against the recorded propertyNames schema.
+func TestPropertyNamesChecked(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"labels": {
+				"type": "object",
+				"propertyNames": {"pattern": "^[a-z][a-z0-9_]*$", "maxLength": 8},
+				"additionalProperties": {"type": "string"}
+			}
+		}
+	}`)
+	_, entries, err := recordPropertyNames(deepCopyJSON(schema), &ConvertOptions{ValidatePropertyNames: true})
+	if err != nil {
+		t.Fatalf("recordPropertyNames() failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Path != "#/properties/labels" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	entries = roundtripEntries(t, entries)
+
+	data := decodeJSON(t, `{"labels": {"env": "prod", "Team": "core", "very_long_key": "x"}}`)
+	_, warnings := restoreHost(data, hostStages(schema, entries), entries)
+	got := map[string]bool{}
+	for _, w := range warnings {
+		got[w.DataPath] = true
+	}
+	if len(warnings) != 2 || !got["/labels/Team"] || !got["/labels/very_long_key"] {
+		t.Errorf("unexpected warnings: %+v", warnings)
+	}
+}
@@ -0,0 +1,265 @@
+// Package protoschema maps protobuf message types to JSON Schemas that
+// describe their proto3 JSON encoding, ready for jsl Convert.
+//
+// Types come from protoreflect descriptors: a generated message's
+// ProtoReflect().Descriptor() (FromDescriptor), or a
+// google.protobuf.FileDescriptorSet (FromDescriptorSet) such as the output
+// of `protoc --include_imports --descriptor_set_out` decoded with
+// proto.Unmarshal, or a `buf build -o image.json` image decoded with
+// protojson.Unmarshal.
+package protoschema
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Options configures FromDescriptor and FromDescriptorSet.
+type Options struct {
+	// UseProtoNames keys properties by the .proto field name instead of
+	// its lowerCamelCase JSON name, like protojson.MarshalOptions.
+	UseProtoNames bool
+}
+
+// FromDescriptorSet resolves fds and returns the schema of message, a
+// fully qualified name such as "acme.v1.Order" (see FromDescriptor).
+// Imports missing from fds are tolerated for well-known types; any other
+// type they would supply is an error.
+func FromDescriptorSet(fds *descriptorpb.FileDescriptorSet, message string, opts *Options) (map[string]any, error) {
+	files, err := protodesc.FileOptions{AllowUnresolvable: true}.NewFiles(fds)
+	if err != nil {
+		return nil, fmt.Errorf("protoschema: %w", err)
+	}
+	name := protoreflect.FullName(strings.TrimPrefix(message, "."))
+	d, err := files.FindDescriptorByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("protoschema: message %q not found", message)
+	}
+	md, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("protoschema: %q is not a message", message)
+	}
+	return FromDescriptor(md, opts)
+}
+
+// FromDescriptor returns a JSON Schema for the proto3 JSON encoding of md.
+// Every other message it reaches is emitted under $defs (keyed by full
+// name), so recursive types are supported.
+//
+// Mapping: 32-bit integers become integers (unsigned ones with minimum 0),
+// 64-bit integers become integers too since protojson parses numbers,
+// bytes become base64 strings, enums become string enums of their value
+// names, map fields become objects with additionalProperties, and
+// repeated fields become arrays. Each oneof becomes a oneOf requiring
+// exactly one of its members. proto2 required fields are required; all
+// others are optional, as in protojson. Well-known types map to their
+// JSON forms (Timestamp is a date-time string, Struct an object, …).
+func FromDescriptor(md protoreflect.MessageDescriptor, opts *Options) (map[string]any, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	m := &mapper{opts: opts, root: md.FullName(), defs: map[string]any{}}
+	schema, err := m.message(md)
+	if err != nil {
+		return nil, err
+	}
+	for len(m.pending) > 0 {
+		next := m.pending[0]
+		m.pending = m.pending[1:]
+		def, err := m.message(next)
+		if err != nil {
+			return nil, err
+		}
+		m.defs[string(next.FullName())] = def
+	}
+	if len(m.defs) > 0 {
+		schema["$defs"] = m.defs
+	}
+	return schema, nil
+}
+
+type mapper struct {
+	opts    *Options
+	root    protoreflect.FullName
+	defs    map[string]any
+	queued  map[protoreflect.FullName]bool
+	pending []protoreflect.MessageDescriptor
+}
+
+// message maps one message type to an object schema.
+func (m *mapper) message(md protoreflect.MessageDescriptor) (map[string]any, error) {
+	props := map[string]any{}
+	var required []any
+	oneofs := make([][]any, md.Oneofs().Len())
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		key := f.JSONName()
+		if m.opts.UseProtoNames || key == "" {
+			key = string(f.Name())
+		}
+		schema, err := m.field(f)
+		if err != nil {
+			return nil, fmt.Errorf("protoschema: %s.%s: %w", md.FullName(), f.Name(), err)
+		}
+		props[key] = schema
+		if f.Cardinality() == protoreflect.Required {
+			required = append(required, key)
+		} else if o := f.ContainingOneof(); o != nil && !o.IsSynthetic() {
+			oneofs[o.Index()] = append(oneofs[o.Index()], key)
+		}
+	}
+	out := map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+	if len(required) > 0 {
+		out["required"] = required
+	}
+	var groups []any
+	for _, members := range oneofs {
+		if len(members) == 0 {
+			continue
+		}
+		branches := make([]any, len(members))
+		for i, k := range members {
+			branches[i] = map[string]any{"required": []any{k}}
+		}
+		groups = append(groups, branches)
+	}
+	switch len(groups) {
+	case 0:
+	case 1:
+		out["oneOf"] = groups[0]
+	default:
+		for i, g := range groups {
+			groups[i] = map[string]any{"oneOf": g}
+		}
+		out["allOf"] = groups
+	}
+	return out, nil
+}
+
+// field maps one field, including its repeated/map wrapper.
+func (m *mapper) field(f protoreflect.FieldDescriptor) (map[string]any, error) {
+	if f.IsMap() {
+		v, err := m.single(f.MapValue())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": v}, nil
+	}
+	item, err := m.single(f)
+	if err != nil {
+		return nil, err
+	}
+	if f.IsList() {
+		return map[string]any{"type": "array", "items": item}, nil
+	}
+	return item, nil
+}
+
+// single maps the element type of a field.
+func (m *mapper) single(f protoreflect.FieldDescriptor) (map[string]any, error) {
+	switch f.Kind() {
+	case protoreflect.DoubleKind, protoreflect.FloatKind:
+		return map[string]any{"type": "number"}, nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return map[string]any{"type": "integer"}, nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]any{"type": "integer", "minimum": 0}, nil
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}, nil
+	case protoreflect.StringKind:
+		return map[string]any{"type": "string"}, nil
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "contentEncoding": "base64"}, nil
+	case protoreflect.EnumKind:
+		return m.enum(f.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		md := f.Message()
+		if wk, ok := wellKnownTypes[md.FullName()]; ok {
+			return wk(), nil
+		}
+		if md.IsPlaceholder() {
+			return nil, fmt.Errorf("unknown message type %q", md.FullName())
+		}
+		return m.ref(md), nil
+	}
+	return nil, fmt.Errorf("unsupported field kind %v", f.Kind())
+}
+
+func (m *mapper) enum(e protoreflect.EnumDescriptor) (map[string]any, error) {
+	if e.FullName() == "google.protobuf.NullValue" {
+		return map[string]any{"type": "null"}, nil
+	}
+	if e.IsPlaceholder() {
+		return nil, fmt.Errorf("unknown enum type %q", e.FullName())
+	}
+	values := make([]any, e.Values().Len())
+	for i := range values {
+		values[i] = string(e.Values().Get(i).Name())
+	}
+	return map[string]any{"type": "string", "enum": values}, nil
+}
+
+// ref points at a message schema, queuing it for $defs on first use.
+func (m *mapper) ref(md protoreflect.MessageDescriptor) map[string]any {
+	name := md.FullName()
+	if name == m.root {
+		return map[string]any{"$ref": "#"}
+	}
+	if m.queued == nil {
+		m.queued = map[protoreflect.FullName]bool{}
+	}
+	if !m.queued[name] {
+		m.queued[name] = true
+		m.pending = append(m.pending, md)
+	}
+	return map[string]any{"$ref": "#/$defs/" + escapeSegment(string(name))}
+}
+
+func escapeSegment(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// wellKnownTypes maps google.protobuf types with special JSON encodings.
+var wellKnownTypes = map[protoreflect.FullName]func() map[string]any{
+	"google.protobuf.Timestamp": func() map[string]any { return map[string]any{"type": "string", "format": "date-time"} },
+	"google.protobuf.Duration": func() map[string]any {
+		return map[string]any{"type": "string", "pattern": `^-?[0-9]+(\.[0-9]{1,9})?s$`}
+	},
+	"google.protobuf.FieldMask": func() map[string]any { return map[string]any{"type": "string"} },
+	"google.protobuf.Struct":    func() map[string]any { return map[string]any{"type": "object"} },
+	"google.protobuf.Value":     func() map[string]any { return map[string]any{} },
+	"google.protobuf.ListValue": func() map[string]any { return map[string]any{"type": "array"} },
+	"google.protobuf.Empty": func() map[string]any {
+		return map[string]any{"type": "object", "properties": map[string]any{}, "additionalProperties": false}
+	},
+	"google.protobuf.Any": func() map[string]any {
+		return map[string]any{"type": "object", "properties": map[string]any{"@type": map[string]any{"type": "string"}}, "required": []any{"@type"}}
+	},
+	"google.protobuf.DoubleValue": nullableWrapper("number", nil),
+	"google.protobuf.FloatValue":  nullableWrapper("number", nil),
+	"google.protobuf.Int64Value":  nullableWrapper("integer", nil),
+	"google.protobuf.UInt64Value": nullableWrapper("integer", map[string]any{"minimum": 0}),
+	"google.protobuf.Int32Value":  nullableWrapper("integer", nil),
+	"google.protobuf.UInt32Value": nullableWrapper("integer", map[string]any{"minimum": 0}),
+	"google.protobuf.BoolValue":   nullableWrapper("boolean", nil),
+	"google.protobuf.StringValue": nullableWrapper("string", nil),
+	"google.protobuf.BytesValue":  nullableWrapper("string", map[string]any{"contentEncoding": "base64"}),
+}
+
+// nullableWrapper maps a wrapper type, which encodes as its bare value or
+// null.
+func nullableWrapper(typ string, extra map[string]any) func() map[string]any {
+	return func() map[string]any {
+		out := map[string]any{"type": []any{typ, "null"}}
+		for k, v := range extra {
+			out[k] = v
+		}
+		return out
+	}
+}
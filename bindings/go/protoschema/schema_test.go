@@ -0,0 +1,200 @@
+package protoschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// orderSet describes:
+//
+//	package acme.v1;
+//	import "google/protobuf/timestamp.proto";
+//	message Order {
+//	  string order_id = 1;
+//	  Status status = 2;
+//	  repeated Item items = 3;
+//	  map<string, int64> totals = 4;
+//	  oneof payment { string card = 5; string iban = 6; }
+//	  google.protobuf.Timestamp created = 7;
+//	  optional Order parent = 8;
+//	  enum Status { STATUS_UNSPECIFIED = 0; OPEN = 1; }
+//	}
+//	message Item { uint32 qty = 1; bytes blob = 2; }
+//
+// in the protojson form of `buf build -o image.json`. timestamp.proto
+// itself is left out, as when --include_imports is not given.
+const orderSetJSON = `{"file": [{
+	"name": "order.proto",
+	"package": "acme.v1",
+	"dependency": ["google/protobuf/timestamp.proto"],
+	"syntax": "proto3",
+	"messageType": [
+		{
+			"name": "Order",
+			"field": [
+				{"name": "order_id", "jsonName": "orderId", "number": 1, "label": "LABEL_OPTIONAL", "type": "TYPE_STRING"},
+				{"name": "status", "jsonName": "status", "number": 2, "label": "LABEL_OPTIONAL", "type": "TYPE_ENUM", "typeName": ".acme.v1.Order.Status"},
+				{"name": "items", "jsonName": "items", "number": 3, "label": "LABEL_REPEATED", "type": "TYPE_MESSAGE", "typeName": ".acme.v1.Item"},
+				{"name": "totals", "jsonName": "totals", "number": 4, "label": "LABEL_REPEATED", "type": "TYPE_MESSAGE", "typeName": ".acme.v1.Order.TotalsEntry"},
+				{"name": "card", "jsonName": "card", "number": 5, "label": "LABEL_OPTIONAL", "type": "TYPE_STRING", "oneofIndex": 0},
+				{"name": "iban", "jsonName": "iban", "number": 6, "label": "LABEL_OPTIONAL", "type": "TYPE_STRING", "oneofIndex": 0},
+				{"name": "created", "jsonName": "created", "number": 7, "label": "LABEL_OPTIONAL", "type": "TYPE_MESSAGE", "typeName": ".google.protobuf.Timestamp"},
+				{"name": "parent", "jsonName": "parent", "number": 8, "label": "LABEL_OPTIONAL", "type": "TYPE_MESSAGE", "typeName": ".acme.v1.Order", "oneofIndex": 1, "proto3Optional": true}
+			],
+			"nestedType": [{
+				"name": "TotalsEntry",
+				"field": [
+					{"name": "key", "jsonName": "key", "number": 1, "label": "LABEL_OPTIONAL", "type": "TYPE_STRING"},
+					{"name": "value", "jsonName": "value", "number": 2, "label": "LABEL_OPTIONAL", "type": "TYPE_INT64"}
+				],
+				"options": {"mapEntry": true}
+			}],
+			"enumType": [{"name": "Status", "value": [{"name": "STATUS_UNSPECIFIED", "number": 0}, {"name": "OPEN", "number": 1}]}],
+			"oneofDecl": [{"name": "payment"}, {"name": "_parent"}]
+		},
+		{
+			"name": "Item",
+			"field": [
+				{"name": "qty", "jsonName": "qty", "number": 1, "label": "LABEL_OPTIONAL", "type": "TYPE_UINT32"},
+				{"name": "blob", "jsonName": "blob", "number": 2, "label": "LABEL_OPTIONAL", "type": "TYPE_BYTES"}
+			]
+		}
+	]
+}]}`
+
+func orderSet(t *testing.T) *descriptorpb.FileDescriptorSet {
+	t.Helper()
+	fds := &descriptorpb.FileDescriptorSet{}
+	if err := protojson.Unmarshal([]byte(orderSetJSON), fds); err != nil {
+		t.Fatal(err)
+	}
+	return fds
+}
+
+const orderSchema = `{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"orderId": {"type": "string"},
+		"status": {"type": "string", "enum": ["STATUS_UNSPECIFIED", "OPEN"]},
+		"items": {"type": "array", "items": {"$ref": "#/$defs/acme.v1.Item"}},
+		"totals": {"type": "object", "additionalProperties": {"type": "integer"}},
+		"card": {"type": "string"},
+		"iban": {"type": "string"},
+		"created": {"type": "string", "format": "date-time"},
+		"parent": {"$ref": "#"}
+	},
+	"oneOf": [{"required": ["card"]}, {"required": ["iban"]}],
+	"$defs": {
+		"acme.v1.Item": {
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"qty": {"type": "integer", "minimum": 0},
+				"blob": {"type": "string", "contentEncoding": "base64"}
+			}
+		}
+	}
+}`
+
+func assertSchema(t *testing.T, got map[string]any, want string) {
+	t.Helper()
+	// Round-trip through JSON so numeric types compare equal.
+	b, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g, w any
+	if err := json.Unmarshal(b, &g); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("schema = %s", b)
+	}
+}
+
+func TestFromDescriptorSet(t *testing.T) {
+	got, err := FromDescriptorSet(orderSet(t), ".acme.v1.Order", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSchema(t, got, orderSchema)
+}
+
+func TestFromDescriptorSetProtoNames(t *testing.T) {
+	got, err := FromDescriptorSet(orderSet(t), "acme.v1.Order", &Options{UseProtoNames: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	props := got["properties"].(map[string]any)
+	if _, ok := props["order_id"]; !ok {
+		t.Errorf("expected proto field names, got %v", props)
+	}
+}
+
+func TestFromDescriptorSetUnknownMessage(t *testing.T) {
+	if _, err := FromDescriptorSet(orderSet(t), "acme.v1.Missing", nil); err == nil {
+		t.Fatal("expected an error for an unknown message")
+	}
+}
+
+// TestFromDescriptorSetBinary verifies a set decoded from the binary
+// `protoc --descriptor_set_out` form maps the same way.
+func TestFromDescriptorSetBinary(t *testing.T) {
+	b, err := proto.Marshal(orderSet(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fds := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(b, fds); err != nil {
+		t.Fatal(err)
+	}
+	got, err := FromDescriptorSet(fds, "acme.v1.Order", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSchema(t, got, orderSchema)
+}
+
+// TestFromDescriptorSetUnresolved verifies a missing import is only
+// tolerated for well-known types.
+func TestFromDescriptorSetUnresolved(t *testing.T) {
+	fds := orderSet(t)
+	fds.File[0].Dependency = append(fds.File[0].Dependency, "acme/v1/money.proto")
+	fds.File[0].MessageType[1].Field[0].Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	fds.File[0].MessageType[1].Field[0].TypeName = proto.String(".acme.v1.Money")
+	if _, err := FromDescriptorSet(fds, "acme.v1.Order", nil); err == nil || !strings.Contains(err.Error(), "acme.v1.Money") {
+		t.Fatalf("FromDescriptorSet() error = %v, want the unknown type named", err)
+	}
+}
+
+// TestFromDescriptor verifies a generated message's descriptor maps
+// directly, recursive references included.
+func TestFromDescriptor(t *testing.T) {
+	got, err := FromDescriptor((&descriptorpb.FileDescriptorSet{}).ProtoReflect().Descriptor(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := got["properties"].(map[string]any)["file"].(map[string]any)
+	if ref := file["items"].(map[string]any)["$ref"]; ref != "#/$defs/google.protobuf.FileDescriptorProto" {
+		t.Errorf("file items = %v", file["items"])
+	}
+	defs := got["$defs"].(map[string]any)
+	msg, ok := defs["google.protobuf.DescriptorProto"].(map[string]any)
+	if !ok {
+		t.Fatalf("$defs lacks DescriptorProto: %v", defs)
+	}
+	nested := msg["properties"].(map[string]any)["nestedType"].(map[string]any)
+	if ref := nested["items"].(map[string]any)["$ref"]; ref != "#/$defs/google.protobuf.DescriptorProto" {
+		t.Errorf("nestedType items = %v", nested["items"])
+	}
+}
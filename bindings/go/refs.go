@@ -0,0 +1,246 @@
+package jsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// RefResolver fetches the document an external $ref points at. uri has no
+// fragment and is resolved against the enclosing $id (so it is absolute
+// whenever the schema declares one); the returned value is the decoded JSON
+// document.
+type RefResolver interface {
+	Resolve(uri string) (any, error)
+}
+
+// RefResolverFunc adapts a function to RefResolver.
+type RefResolverFunc func(uri string) (any, error)
+
+// Resolve calls f.
+func (f RefResolverFunc) Resolve(uri string) (any, error) { return f(uri) }
+
+// HTTPRefResolver fetches http(s) documents with Client (default
+// http.DefaultClient). Prefixes, when set, restricts the URIs it will fetch.
+type HTTPRefResolver struct {
+	Client   *http.Client
+	Prefixes []string
+}
+
+// maxRefDocumentBytes caps a fetched document.
+const maxRefDocumentBytes = 32 << 20
+
+// Resolve GETs uri and decodes the JSON body.
+func (r *HTTPRefResolver) Resolve(uri string) (any, error) {
+	if len(r.Prefixes) > 0 {
+		allowed := false
+		for _, p := range r.Prefixes {
+			allowed = allowed || strings.HasPrefix(uri, p)
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%s is outside the allowed prefixes", uri)
+		}
+	}
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", uri, resp.Status)
+	}
+	var doc any
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRefDocumentBytes)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", uri, err)
+	}
+	return doc, nil
+}
+
+// refBundler pulls the targets of external $refs into the root's $defs,
+// rewriting each ref to its local copy. Refs inside fetched documents are
+// resolved against that document, so a whole graph of remote schemas is
+// bundled.
+type refBundler struct {
+	resolver RefResolver
+	defs     map[string]any
+	docs     map[string]any
+	names    map[string]string // absolute URI with fragment → $defs name
+}
+
+// bundleExternalRefs returns schema with every external $ref bundled into
+// $defs. Relative refs are resolved against the root's $id.
+func bundleExternalRefs(schema any, resolver RefResolver) (any, error) {
+	root, ok := schema.(map[string]any)
+	if !ok {
+		return schema, nil
+	}
+	defs, _ := root["$defs"].(map[string]any)
+	if defs == nil {
+		defs = map[string]any{}
+	}
+	b := &refBundler{resolver: resolver, defs: defs, docs: map[string]any{}, names: map[string]string{}}
+	base, _ := root["$id"].(string)
+	if err := b.rewrite(root, base, "#", true); err != nil {
+		return nil, err
+	}
+	if len(defs) > 0 {
+		root["$defs"] = defs
+	}
+	return root, nil
+}
+
+// rewrite walks node, bundling external refs. local reports whether node is
+// part of the root document, whose "#..." refs stay as they are.
+func (b *refBundler) rewrite(node any, base, loc string, local bool) error {
+	switch t := node.(type) {
+	case map[string]any:
+		// The root's own definitions are walked last, without the copies
+		// define adds along the way.
+		var ownDefs []string
+		if loc == "#" && local {
+			existing, _ := t["$defs"].(map[string]any)
+			ownDefs = sortedKeys(existing)
+		}
+		if id, ok := t["$id"].(string); ok && loc != "#" {
+			if abs, err := resolveURI(base, id); err == nil {
+				base = abs
+			}
+		}
+		if ref, ok := t["$ref"].(string); ok && (!local || !strings.HasPrefix(ref, "#")) {
+			name, err := b.define(ref, base, childPointer(loc, "$ref"))
+			if err != nil {
+				return err
+			}
+			t["$ref"] = "#/$defs/" + escapePointerSegment(name)
+		}
+		for _, k := range sortedKeys(t) {
+			if k == "$ref" || (loc == "#" && local && k == "$defs") {
+				continue
+			}
+			if err := b.rewrite(t[k], base, childPointer(loc, k), local); err != nil {
+				return err
+			}
+		}
+		for _, k := range ownDefs {
+			if err := b.rewrite(b.defs[k], base, childPointer(childPointer(loc, "$defs"), k), local); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, c := range t {
+			if err := b.rewrite(c, base, childPointer(loc, itoa(i)), local); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// define bundles the target of ref (relative to base) and returns its $defs
+// name.
+func (b *refBundler) define(ref, base, loc string) (string, error) {
+	abs, err := resolveURI(base, ref)
+	if err != nil {
+		return "", &Error{Code: "unresolvable_ref", Path: loc, Message: fmt.Sprintf("invalid $ref %q: %v", ref, err)}
+	}
+	if name, ok := b.names[abs]; ok {
+		return name, nil
+	}
+	docURI, fragment, _ := strings.Cut(abs, "#")
+	if docURI == "" {
+		return "", &Error{Code: "unresolvable_ref", Path: loc, Message: fmt.Sprintf("$ref %q has no base URI to resolve against", ref)}
+	}
+	doc, ok := b.docs[docURI]
+	if !ok {
+		if doc, err = b.resolver.Resolve(docURI); err != nil {
+			return "", &Error{Code: "unresolvable_ref", Path: loc, Message: fmt.Sprintf("resolve %s: %v", docURI, err)}
+		}
+		b.docs[docURI] = doc
+	}
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		return "", &Error{Code: "unresolvable_ref", Path: loc, Message: fmt.Sprintf("$ref %q: only JSON Pointer fragments are supported", ref)}
+	}
+	target, ok := lookupPointer(doc, "#"+fragment)
+	if !ok {
+		return "", &Error{Code: "unresolvable_ref", Path: loc, Message: fmt.Sprintf("$ref %q: %s not found in %s", ref, "#"+fragment, docURI)}
+	}
+
+	name := b.defName(docURI, fragment)
+	b.names[abs] = name
+	def := deepCopyJSON(target)
+	b.defs[name] = def
+	if err := b.rewrite(def, docURI, "#/$defs/"+escapePointerSegment(name), false); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// defName picks a free $defs name from the pointer's last segment, or the
+// document's file name for whole-document refs.
+func (b *refBundler) defName(docURI, fragment string) string {
+	name := ""
+	if segs := splitPointer("#" + fragment); len(segs) > 0 {
+		name = segs[len(segs)-1]
+	}
+	if name == "" {
+		u, _ := url.Parse(docURI)
+		name = strings.TrimSuffix(path.Base(u.Path), path.Ext(u.Path))
+	}
+	if name == "" || name == "." || name == "/" {
+		name = "external"
+	}
+	candidate := name
+	for i := 2; b.defs[candidate] != nil; i++ {
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+	return candidate
+}
+
+func resolveURI(base, ref string) (string, error) {
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	if base == "" {
+		return r.String(), nil
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	return b.ResolveReference(r).String(), nil
+}
+
+// bundleSchemaBytes bundles the external refs of a marshaled schema. It
+// returns the input unchanged (and a nil map) when there are none.
+func bundleSchemaBytes(schemaBytes []byte, resolver RefResolver) ([]byte, map[string]any, error) {
+	var schema any
+	if err := json.Unmarshal(schemaBytes, &schema); err != nil {
+		return nil, nil, fmt.Errorf("decode schema: %w", err)
+	}
+	out, err := bundleExternalRefs(schema, resolver)
+	if err != nil {
+		return nil, nil, err
+	}
+	root, ok := out.(map[string]any)
+	if !ok {
+		return schemaBytes, nil, nil
+	}
+	b, err := json.Marshal(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode schema: %w", err)
+	}
+	if bytes.Equal(b, schemaBytes) {
+		return schemaBytes, nil, nil
+	}
+	return b, root, nil
+}
@@ -0,0 +1,96 @@
+package jsl
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var testRefDocs = map[string]string{
+	"https://schemas.internal/common.json": `{
+		"$defs": {
+			"Address": {"type": "object", "properties": {"country": {"$ref": "#/$defs/Country"}}},
+			"Country": {"type": "string", "enum": ["US", "DE"]}
+		}
+	}`,
+	"https://schemas.internal/money.json": `{
+		"type": "object",
+		"properties": {"amount": {"type": "number"}, "origin": {"$ref": "common.json#/$defs/Country"}}
+	}`,
+}
+
+func testRefResolver(t *testing.T, calls map[string]int) RefResolver {
+	return RefResolverFunc(func(uri string) (any, error) {
+		calls[uri]++
+		doc, ok := testRefDocs[uri]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return decodeJSON(t, doc), nil
+	})
+}
+
+// TestBundleExternalRefs verifies remote targets (and the refs inside them)
+// are pulled into $defs and each document is fetched once.
+func TestBundleExternalRefs(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"$id": "https://schemas.internal/order.json",
+		"type": "object",
+		"properties": {
+			"ship_to": {"$ref": "common.json#/$defs/Address"},
+			"total": {"$ref": "https://schemas.internal/money.json"},
+			"note": {"$ref": "#/$defs/Note"}
+		},
+		"$defs": {"Note": {"type": "string"}, "Country": {"type": "integer"}}
+	}`)
+	calls := map[string]int{}
+	out, err := bundleExternalRefs(schema, testRefResolver(t, calls))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(out)
+	want := `{"$defs":{` +
+		`"Address":{"properties":{"country":{"$ref":"#/$defs/Country_2"}},"type":"object"},` +
+		`"Country":{"type":"integer"},` +
+		`"Country_2":{"enum":["US","DE"],"type":"string"},` +
+		`"Note":{"type":"string"},` +
+		`"money":{"properties":{"amount":{"type":"number"},"origin":{"$ref":"#/$defs/Country_2"}},"type":"object"}},` +
+		`"$id":"https://schemas.internal/order.json",` +
+		`"properties":{"note":{"$ref":"#/$defs/Note"},"ship_to":{"$ref":"#/$defs/Address"},"total":{"$ref":"#/$defs/money"}},"type":"object"}`
+	if string(got) != want {
+		t.Errorf("bundled = %s", got)
+	}
+	for uri, n := range calls {
+		if n != 1 {
+			t.Errorf("%s fetched %d times", uri, n)
+		}
+	}
+}
+
+// TestBundleExternalRefsUnresolvable verifies failures carry the ref's pointer.
+func TestBundleExternalRefsUnresolvable(t *testing.T) {
+	schema := decodeJSON(t, `{"properties": {"x": {"$ref": "https://schemas.internal/missing.json"}}}`)
+	_, err := bundleExternalRefs(schema, testRefResolver(t, map[string]int{}))
+	var jslErr *Error
+	if !errors.As(err, &jslErr) || jslErr.Code != "unresolvable_ref" || jslErr.Path != "#/properties/x/$ref" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+// TestHTTPRefResolver verifies fetching and the prefix allowlist.
+func TestHTTPRefResolver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"type": "string"}`))
+	}))
+	defer srv.Close()
+	r := &HTTPRefResolver{Client: srv.Client(), Prefixes: []string{srv.URL + "/schemas/"}}
+	doc, err := r.Resolve(srv.URL + "/schemas/a.json")
+	if err != nil || doc.(map[string]any)["type"] != "string" {
+		t.Errorf("doc = %v, err = %v", doc, err)
+	}
+	if _, err := r.Resolve(srv.URL + "/other/a.json"); err == nil {
+		t.Error("expected prefix rejection")
+	}
+}
@@ -0,0 +1,130 @@
+package jsl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrModelRefusal matches (via errors.Is) every *RefusalError, letting
+// callers tell "the model declined" apart from conversion or parse failures.
+var ErrModelRefusal = errors.New("model refusal")
+
+// Provider names a model API whose raw responses DetectRefusal understands.
+type Provider string
+
+const (
+	ProviderOpenAI    Provider = "openai"
+	ProviderAnthropic Provider = "anthropic"
+	ProviderGemini    Provider = "gemini"
+)
+
+// RefusalError reports a response in which the model declined to answer or
+// the provider blocked the output. Text is the model's refusal message when
+// the provider returns one; Reason is the provider's stop/finish/block
+// reason.
+type RefusalError struct {
+	Provider Provider
+	Reason   string
+	Text     string
+}
+
+func (e *RefusalError) Error() string {
+	msg := fmt.Sprintf("%s: model refusal", e.Provider)
+	if e.Reason != "" {
+		msg += " (" + e.Reason + ")"
+	}
+	if e.Text != "" {
+		msg += ": " + e.Text
+	}
+	return msg
+}
+
+// Is makes errors.Is(err, ErrModelRefusal) true for every RefusalError.
+func (e *RefusalError) Is(target error) bool {
+	return target == ErrModelRefusal
+}
+
+// geminiBlockReasons are candidate finishReasons that mean the output was
+// withheld rather than completed.
+var geminiBlockReasons = map[string]bool{
+	"SAFETY": true, "RECITATION": true, "BLOCKLIST": true,
+	"PROHIBITED_CONTENT": true, "SPII": true,
+}
+
+// DetectRefusal inspects a raw provider response body (an OpenAI chat
+// completion, an Anthropic message, or a Gemini generateContent response)
+// and returns a *RefusalError when the model refused or the provider
+// blocked the output, nil otherwise. Only the first choice/candidate is
+// inspected. For responses already decoded by a provider SDK, use
+// DetectRefusal in the openai, anthropic or genai package.
+func DetectRefusal(provider Provider, response []byte) error {
+	switch provider {
+	case ProviderOpenAI:
+		var r struct {
+			Choices []struct {
+				FinishReason string `json:"finish_reason"`
+				Message      struct {
+					Refusal *string `json:"refusal"`
+				} `json:"message"`
+			} `json:"choices"`
+		}
+		if err := json.Unmarshal(response, &r); err != nil {
+			return fmt.Errorf("decode %s response: %w", provider, err)
+		}
+		if len(r.Choices) == 0 {
+			return nil
+		}
+		c := r.Choices[0]
+		if c.Message.Refusal != nil && *c.Message.Refusal != "" {
+			return &RefusalError{Provider: provider, Reason: "refusal", Text: *c.Message.Refusal}
+		}
+		if c.FinishReason == "content_filter" {
+			return &RefusalError{Provider: provider, Reason: c.FinishReason}
+		}
+	case ProviderAnthropic:
+		var r struct {
+			StopReason string `json:"stop_reason"`
+			Content    []struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"content"`
+		}
+		if err := json.Unmarshal(response, &r); err != nil {
+			return fmt.Errorf("decode %s response: %w", provider, err)
+		}
+		if r.StopReason == "refusal" {
+			var text string
+			for _, c := range r.Content {
+				if c.Type == "text" {
+					text += c.Text
+				}
+			}
+			return &RefusalError{Provider: provider, Reason: r.StopReason, Text: text}
+		}
+	case ProviderGemini:
+		var r struct {
+			PromptFeedback struct {
+				BlockReason        string `json:"blockReason"`
+				BlockReasonMessage string `json:"blockReasonMessage"`
+			} `json:"promptFeedback"`
+			Candidates []struct {
+				FinishReason  string `json:"finishReason"`
+				FinishMessage string `json:"finishMessage"`
+			} `json:"candidates"`
+		}
+		if err := json.Unmarshal(response, &r); err != nil {
+			return fmt.Errorf("decode %s response: %w", provider, err)
+		}
+		if fb := r.PromptFeedback; fb.BlockReason != "" {
+			return &RefusalError{Provider: provider, Reason: fb.BlockReason, Text: fb.BlockReasonMessage}
+		}
+		if len(r.Candidates) > 0 && geminiBlockReasons[r.Candidates[0].FinishReason] {
+			c := r.Candidates[0]
+			return &RefusalError{Provider: provider, Reason: c.FinishReason, Text: c.FinishMessage}
+		}
+	default:
+		return fmt.Errorf("unknown provider %q", provider)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package jsl
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestDetectRefusal verifies refusal and block detection for each provider.
+func TestDetectRefusal(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider Provider
+		body     string
+		reason   string
+		text     string
+	}{
+		{"openai refusal", ProviderOpenAI, `{"choices":[{"finish_reason":"stop","message":{"content":null,"refusal":"I can't help with that."}}]}`, "refusal", "I can't help with that."},
+		{"openai filter", ProviderOpenAI, `{"choices":[{"finish_reason":"content_filter","message":{"content":""}}]}`, "content_filter", ""},
+		{"openai ok", ProviderOpenAI, `{"choices":[{"finish_reason":"stop","message":{"content":"{}","refusal":null}}]}`, "", ""},
+		{"anthropic refusal", ProviderAnthropic, `{"stop_reason":"refusal","content":[{"type":"text","text":"No."}]}`, "refusal", "No."},
+		{"anthropic ok", ProviderAnthropic, `{"stop_reason":"end_turn","content":[{"type":"text","text":"{}"}]}`, "", ""},
+		{"gemini prompt blocked", ProviderGemini, `{"promptFeedback":{"blockReason":"SAFETY"}}`, "SAFETY", ""},
+		{"gemini candidate blocked", ProviderGemini, `{"candidates":[{"finishReason":"RECITATION"}]}`, "RECITATION", ""},
+		{"gemini ok", ProviderGemini, `{"candidates":[{"finishReason":"STOP"}]}`, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DetectRefusal(tt.provider, []byte(tt.body))
+			if tt.reason == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrModelRefusal) {
+				t.Fatalf("expected ErrModelRefusal, got %v", err)
+			}
+			var re *RefusalError
+			if !errors.As(err, &re) || re.Reason != tt.reason || re.Text != tt.text {
+				t.Errorf("got %+v", re)
+			}
+		})
+	}
+}
+
+// TestDetectRefusalBadInput verifies parse failures are not reported as refusals.
+func TestDetectRefusalBadInput(t *testing.T) {
+	err := DetectRefusal(ProviderOpenAI, []byte("not json"))
+	if err == nil || errors.Is(err, ErrModelRefusal) {
+		t.Errorf("got %v", err)
+	}
+}
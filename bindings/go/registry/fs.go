@@ -0,0 +1,104 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const artifactExt = ".json"
+
+// FSStore keeps one JSON file per artifact in a directory. Writes go through
+// a temporary file and rename, so readers (including other processes
+// sharing the directory) never observe partial artifacts.
+type FSStore struct {
+	dir string
+}
+
+// NewFSStore returns an FSStore rooted at dir, creating it if needed.
+func NewFSStore(dir string) (*FSStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FSStore{dir: dir}, nil
+}
+
+func (s *FSStore) path(key string) (string, error) {
+	if key == "" || strings.ContainsAny(key, `/\`) || key == "." || key == ".." {
+		return "", fmt.Errorf("registry: invalid key %q", key)
+	}
+	return filepath.Join(s.dir, key+artifactExt), nil
+}
+
+func (s *FSStore) Get(_ context.Context, key string) (*Artifact, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeArtifact(b)
+}
+
+func (s *FSStore) Put(_ context.Context, a *Artifact) error {
+	p, err := s.path(a.Key)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (s *FSStore) Delete(_ context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *FSStore) List(_ context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, artifactExt) {
+			continue
+		}
+		keys = append(keys, strings.TrimSuffix(name, artifactExt))
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
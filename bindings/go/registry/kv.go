@@ -0,0 +1,64 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// KV is the byte-level backend KVStore needs. The rediskv and s3kv modules
+// implement it for Redis and S3-compatible buckets; Get must return
+// ErrNotFound (or an error wrapping it) for missing keys.
+type KV interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte) error
+	Delete(ctx context.Context, key string) error
+	// Keys returns every key starting with prefix.
+	Keys(ctx context.Context, prefix string) ([]string, error)
+}
+
+// KVStore stores artifacts in a KV backend under a key prefix, so one
+// bucket or Redis database can hold several registries.
+type KVStore struct {
+	kv     KV
+	prefix string
+}
+
+// NewKVStore returns a KVStore writing keys as prefix+key.
+func NewKVStore(kv KV, prefix string) *KVStore {
+	return &KVStore{kv: kv, prefix: prefix}
+}
+
+func (s *KVStore) Get(ctx context.Context, key string) (*Artifact, error) {
+	b, err := s.kv.Get(ctx, s.prefix+key)
+	if err != nil {
+		return nil, err
+	}
+	return decodeArtifact(b)
+}
+
+func (s *KVStore) Put(ctx context.Context, a *Artifact) error {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	return s.kv.Set(ctx, s.prefix+a.Key, b)
+}
+
+func (s *KVStore) Delete(ctx context.Context, key string) error {
+	return s.kv.Delete(ctx, s.prefix+key)
+}
+
+func (s *KVStore) List(ctx context.Context) ([]string, error) {
+	raw, err := s.kv.Keys(ctx, s.prefix)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(raw))
+	for _, k := range raw {
+		keys = append(keys, strings.TrimPrefix(k, s.prefix))
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
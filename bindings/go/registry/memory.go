@@ -0,0 +1,67 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+// MemoryStore keeps artifacts in process memory. Artifacts are stored
+// encoded, so callers cannot mutate stored state through returned values.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	items map[string][]byte
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: map[string][]byte{}}
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) (*Artifact, error) {
+	s.mu.RLock()
+	b, ok := s.items[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return decodeArtifact(b)
+}
+
+func (s *MemoryStore) Put(_ context.Context, a *Artifact) error {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.items[a.Key] = b
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	delete(s.items, key)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) List(_ context.Context) ([]string, error) {
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.items))
+	for k := range s.items {
+		keys = append(keys, k)
+	}
+	s.mu.RUnlock()
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func decodeArtifact(b []byte) (*Artifact, error) {
+	var a Artifact
+	if err := json.Unmarshal(b, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
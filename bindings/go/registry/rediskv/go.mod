@@ -0,0 +1,23 @@
+module github.com/dotslashderek/json-schema-llm/bindings/go/registry/rediskv
+
+go 1.24
+
+require (
+	github.com/alicebob/miniredis/v2 v2.39.0
+	github.com/dotslashderek/json-schema-llm/bindings/go v0.0.0
+	github.com/redis/go-redis/v9 v9.22.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.1 // indirect
+	github.com/tetratelabs/wazero v1.8.2 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	go.opentelemetry.io/otel v1.32.0 // indirect
+	go.opentelemetry.io/otel/trace v1.32.0 // indirect
+	go.uber.org/atomic v1.11.0 // indirect
+	golang.org/x/sys v0.34.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+)
+
+replace github.com/dotslashderek/json-schema-llm/bindings/go => ../../
@@ -0,0 +1,81 @@
+// Package rediskv backs a registry.KVStore with Redis, so converted
+// artifacts are shared by every replica pointed at the same database.
+//
+// It is a module of its own, so the core binding does not pull in a Redis
+// client.
+package rediskv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dotslashderek/json-schema-llm/bindings/go/registry"
+	"github.com/redis/go-redis/v9"
+)
+
+// scanCount is the COUNT hint Keys passes to each SCAN.
+const scanCount = 512
+
+// KV implements registry.KV over a Redis client. Values are stored as plain
+// strings without expiry.
+type KV struct {
+	client redis.UniversalClient
+}
+
+// New returns a KV using client, which may be a single-node, cluster or
+// failover client.
+func New(client redis.UniversalClient) *KV {
+	return &KV{client: client}
+}
+
+// NewStore is registry.NewKVStore over a KV using client.
+func NewStore(client redis.UniversalClient, prefix string) *registry.KVStore {
+	return registry.NewKVStore(New(client), prefix)
+}
+
+func (k *KV) Get(ctx context.Context, key string) ([]byte, error) {
+	b, err := k.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("%w: %s", registry.ErrNotFound, key)
+	}
+	return b, err
+}
+
+func (k *KV) Set(ctx context.Context, key string, value []byte) error {
+	return k.client.Set(ctx, key, value, 0).Err()
+}
+
+func (k *KV) Delete(ctx context.Context, key string) error {
+	return k.client.Del(ctx, key).Err()
+}
+
+// Keys SCANs for prefix, so it does not block the server the way KEYS
+// would. Keys added or removed during the scan may or may not be listed.
+func (k *KV) Keys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	seen := map[string]bool{}
+	iter := k.client.Scan(ctx, 0, escapeGlob(prefix)+"*", scanCount).Iterator()
+	for iter.Next(ctx) {
+		// SCAN may return a key more than once.
+		if key := iter.Val(); !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys, iter.Err()
+}
+
+// escapeGlob quotes the characters MATCH treats as a pattern.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\', '^', '-':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package rediskv
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dotslashderek/json-schema-llm/bindings/go/registry"
+	"github.com/redis/go-redis/v9"
+)
+
+func newClient(t *testing.T) redis.UniversalClient {
+	t.Helper()
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// TestKV verifies the byte-level round trip, ErrNotFound for missing keys
+// and prefix listing with glob characters in the prefix.
+func TestKV(t *testing.T) {
+	ctx := context.Background()
+	kv := New(newClient(t))
+
+	if _, err := kv.Get(ctx, "missing"); !errors.Is(err, registry.ErrNotFound) {
+		t.Fatalf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+	for _, key := range []string{"jsl*/a", "jsl*/b", "jslx/c", "other"} {
+		if err := kv.Set(ctx, key, []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := kv.Get(ctx, "jsl*/a")
+	if err != nil || string(got) != "jsl*/a" {
+		t.Fatalf("Get() = %q, %v", got, err)
+	}
+	keys, err := kv.Keys(ctx, "jsl*/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(keys)
+	if want := []string{"jsl*/a", "jsl*/b"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Keys() = %v, want %v", keys, want)
+	}
+	if err := kv.Delete(ctx, "jsl*/a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kv.Get(ctx, "jsl*/a"); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("Get() after Delete error = %v, want ErrNotFound", err)
+	}
+}
+
+// TestStore verifies artifacts round-trip through a KVStore backed by
+// Redis.
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(newClient(t), "jsl/")
+	a := &registry.Artifact{Key: "k1", APIVersion: "1.0", Schema: map[string]any{"type": "object"}}
+	if err := store.Put(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get(ctx, "k1")
+	if err != nil || got.Key != "k1" || got.Schema["type"] != "object" {
+		t.Fatalf("Get() = %+v, %v", got, err)
+	}
+	keys, err := store.List(ctx)
+	if err != nil || !reflect.DeepEqual(keys, []string{"k1"}) {
+		t.Errorf("List() = %v, %v", keys, err)
+	}
+}
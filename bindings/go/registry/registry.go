@@ -0,0 +1,95 @@
+// Package registry caches converted schema artifacts (schema + codec) behind
+// a pluggable Store so that replicas can share conversions and survive
+// restarts. MemoryStore and FSStore are provided; KVStore adapts any
+// byte-oriented key/value backend through the small KV interface. Redis and
+// S3-compatible backends live in the registry/rediskv and registry/s3kv
+// modules, so the binding itself takes on no client dependencies.
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// ErrNotFound is returned by Store.Get for unknown keys.
+var ErrNotFound = errors.New("registry: artifact not found")
+
+// Artifact is a stored conversion.
+type Artifact struct {
+	Key        string         `json:"key"`
+	APIVersion string         `json:"apiVersion"`
+	Schema     map[string]any `json:"schema"`
+	Codec      any            `json:"codec"`
+	CreatedAt  time.Time      `json:"createdAt"`
+}
+
+// Store persists artifacts by key. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	Get(ctx context.Context, key string) (*Artifact, error)
+	Put(ctx context.Context, a *Artifact) error
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context) ([]string, error)
+}
+
+// Registry converts schemas through an engine, caching artifacts in a Store
+// keyed by schema and options fingerprints. Like the engine it wraps, a
+// Registry is not safe for concurrent Convert calls.
+type Registry struct {
+	engine jsl.Converter
+	store  Store
+}
+
+// New returns a Registry over engine and store.
+func New(engine jsl.Converter, store Store) *Registry {
+	return &Registry{engine: engine, store: store}
+}
+
+// Key returns the artifact key for schema converted with opts.
+func Key(schema any, opts *jsl.ConvertOptions) (string, error) {
+	fp, err := jsl.SchemaFingerprint(schema)
+	if err != nil {
+		return "", err
+	}
+	return fp[:32] + "-" + jsl.OptionsFingerprint(opts)[:16], nil
+}
+
+// Convert returns the stored artifact for schema and opts, converting and
+// storing it on a miss.
+func (r *Registry) Convert(ctx context.Context, schema any, opts *jsl.ConvertOptions) (*Artifact, error) {
+	key, err := Key(schema, opts)
+	if err != nil {
+		return nil, fmt.Errorf("registry: %w", err)
+	}
+	a, err := r.store.Get(ctx, key)
+	if err == nil {
+		return a, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("registry: get %s: %w", key, err)
+	}
+	result, err := r.engine.Convert(schema, opts)
+	if err != nil {
+		return nil, err
+	}
+	a = &Artifact{
+		Key:        key,
+		APIVersion: result.APIVersion,
+		Schema:     result.Schema,
+		Codec:      result.Codec,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := r.store.Put(ctx, a); err != nil {
+		return nil, fmt.Errorf("registry: put %s: %w", key, err)
+	}
+	return a, nil
+}
+
+// Get returns a stored artifact by key.
+func (r *Registry) Get(ctx context.Context, key string) (*Artifact, error) {
+	return r.store.Get(ctx, key)
+}
@@ -0,0 +1,143 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	"github.com/dotslashderek/json-schema-llm/bindings/go/jsltest"
+)
+
+// mapKV is an in-memory KV for exercising KVStore.
+type mapKV struct {
+	mu sync.Mutex
+	m  map[string][]byte
+}
+
+func (k *mapKV) Get(_ context.Context, key string) ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	b, ok := k.m[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return b, nil
+}
+
+func (k *mapKV) Set(_ context.Context, key string, value []byte) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.m[key] = value
+	return nil
+}
+
+func (k *mapKV) Delete(_ context.Context, key string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.m, key)
+	return nil
+}
+
+func (k *mapKV) Keys(_ context.Context, prefix string) ([]string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	var out []string
+	for key := range k.m {
+		if strings.HasPrefix(key, prefix) {
+			out = append(out, key)
+		}
+	}
+	return out, nil
+}
+
+// TestStores runs the same contract against every Store implementation.
+func TestStores(t *testing.T) {
+	fsStore, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	kv := &mapKV{m: map[string][]byte{"other/x": []byte("{}")}}
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"fs":     fsStore,
+		"kv":     NewKVStore(kv, "jsl/"),
+	}
+	ctx := context.Background()
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			if _, err := s.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get(missing) err = %v", err)
+			}
+			a := &Artifact{
+				Key:       "abc",
+				Schema:    map[string]any{"type": "object"},
+				Codec:     map[string]any{"transforms": []any{}},
+				CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			}
+			if err := s.Put(ctx, a); err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.Get(ctx, "abc")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Schema["type"] != "object" || !got.CreatedAt.Equal(a.CreatedAt) {
+				t.Errorf("Get = %+v", got)
+			}
+			keys, err := s.List(ctx)
+			if err != nil || len(keys) != 1 || keys[0] != "abc" {
+				t.Errorf("List = %v, %v", keys, err)
+			}
+			if err := s.Delete(ctx, "abc"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.Get(ctx, "abc"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get after Delete err = %v", err)
+			}
+		})
+	}
+}
+
+// TestFSStoreRejectsPathKeys verifies keys cannot escape the store directory.
+func TestFSStoreRejectsPathKeys(t *testing.T) {
+	s, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(context.Background(), &Artifact{Key: "../escape"}); err == nil {
+		t.Error("Put with a path key should fail")
+	}
+}
+
+// TestKeyDependsOnOptions verifies schema key order is ignored but options are not.
+func TestKeyDependsOnOptions(t *testing.T) {
+	a, _ := Key(map[string]any{"type": "object", "title": "x"}, nil)
+	b, _ := Key(map[string]any{"title": "x", "type": "object"}, &jsl.ConvertOptions{})
+	c, _ := Key(map[string]any{"type": "object", "title": "x"}, &jsl.ConvertOptions{StripNot: true})
+	if a != b {
+		t.Errorf("equivalent inputs keyed differently: %s vs %s", a, b)
+	}
+	if a == c {
+		t.Error("options did not affect the key")
+	}
+}
+
+// TestRegistryConvertCaches verifies a stored artifact is served without
+// converting again.
+func TestRegistryConvertCaches(t *testing.T) {
+	fake := jsltest.New()
+	r := New(fake, NewMemoryStore())
+	schema := map[string]any{"type": "object"}
+	for i := 0; i < 2; i++ {
+		if _, err := r.Convert(context.Background(), schema, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := fake.CallCount("Convert"); n != 1 {
+		t.Errorf("Convert calls = %d, want 1", n)
+	}
+}
@@ -0,0 +1,90 @@
+// Package s3kv backs a registry.KVStore with an S3-compatible bucket (AWS
+// S3, MinIO, R2, GCS interoperability), so converted artifacts survive
+// restarts and are shared by every replica.
+//
+// It is a module of its own, so the core binding does not pull in the AWS
+// SDK.
+package s3kv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/dotslashderek/json-schema-llm/bindings/go/registry"
+)
+
+// API is the subset of *s3.Client KV calls.
+type API interface {
+	GetObject(ctx context.Context, in *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	PutObject(ctx context.Context, in *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	DeleteObject(ctx context.Context, in *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+	s3.ListObjectsV2APIClient
+}
+
+// KV implements registry.KV over the objects of one bucket, one object per
+// key.
+type KV struct {
+	client API
+	bucket string
+}
+
+// New returns a KV storing objects in bucket through client.
+func New(client API, bucket string) *KV {
+	return &KV{client: client, bucket: bucket}
+}
+
+// NewStore is registry.NewKVStore over a KV storing objects in bucket.
+func NewStore(client API, bucket, prefix string) *registry.KVStore {
+	return registry.NewKVStore(New(client, bucket), prefix)
+}
+
+func (k *KV) Get(ctx context.Context, key string) ([]byte, error) {
+	out, err := k.client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(k.bucket), Key: aws.String(key)})
+	if err != nil {
+		var noKey *types.NoSuchKey
+		var notFound *types.NotFound
+		if errors.As(err, &noKey) || errors.As(err, &notFound) {
+			return nil, fmt.Errorf("%w: %s", registry.ErrNotFound, key)
+		}
+		return nil, err
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+func (k *KV) Set(ctx context.Context, key string, value []byte) error {
+	_, err := k.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(k.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(value),
+		ContentType: aws.String("application/json"),
+	})
+	return err
+}
+
+// Delete removes key. Deleting a missing key is not an error, as in S3.
+func (k *KV) Delete(ctx context.Context, key string) error {
+	_, err := k.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(k.bucket), Key: aws.String(key)})
+	return err
+}
+
+func (k *KV) Keys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	pages := s3.NewListObjectsV2Paginator(k.client, &s3.ListObjectsV2Input{Bucket: aws.String(k.bucket), Prefix: aws.String(prefix)})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.ToString(obj.Key))
+		}
+	}
+	return keys, nil
+}
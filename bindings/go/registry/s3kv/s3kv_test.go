@@ -0,0 +1,116 @@
+package s3kv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/dotslashderek/json-schema-llm/bindings/go/registry"
+)
+
+// fakeS3 is an in-memory bucket that pages listings two keys at a time.
+type fakeS3 struct {
+	objects map[string][]byte
+}
+
+func (f *fakeS3) GetObject(_ context.Context, in *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	b, ok := f.objects[aws.ToString(in.Key)]
+	if !ok {
+		return nil, &types.NoSuchKey{}
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(b))}, nil
+}
+
+func (f *fakeS3) PutObject(_ context.Context, in *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	b, err := io.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.objects[aws.ToString(in.Key)] = b
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeS3) DeleteObject(_ context.Context, in *s3.DeleteObjectInput, _ ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	delete(f.objects, aws.ToString(in.Key))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (f *fakeS3) ListObjectsV2(_ context.Context, in *s3.ListObjectsV2Input, _ ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	var keys []string
+	for k := range f.objects {
+		if strings.HasPrefix(k, aws.ToString(in.Prefix)) && k > aws.ToString(in.ContinuationToken) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	out := &s3.ListObjectsV2Output{}
+	if len(keys) > 2 {
+		keys = keys[:2]
+		out.IsTruncated = aws.Bool(true)
+		out.NextContinuationToken = aws.String(keys[1])
+	}
+	for _, k := range keys {
+		out.Contents = append(out.Contents, types.Object{Key: aws.String(k)})
+	}
+	return out, nil
+}
+
+// TestKV verifies the byte-level round trip, ErrNotFound for missing keys
+// and paginated prefix listing.
+func TestKV(t *testing.T) {
+	ctx := context.Background()
+	kv := New(&fakeS3{objects: map[string][]byte{}}, "bucket")
+
+	if _, err := kv.Get(ctx, "missing"); !errors.Is(err, registry.ErrNotFound) {
+		t.Fatalf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+	for _, key := range []string{"jsl/a", "jsl/b", "jsl/c", "other"} {
+		if err := kv.Set(ctx, key, []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := kv.Get(ctx, "jsl/a")
+	if err != nil || string(got) != "jsl/a" {
+		t.Fatalf("Get() = %q, %v", got, err)
+	}
+	keys, err := kv.Keys(ctx, "jsl/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"jsl/a", "jsl/b", "jsl/c"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Keys() = %v, want %v", keys, want)
+	}
+	if err := kv.Delete(ctx, "jsl/a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kv.Get(ctx, "jsl/a"); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("Get() after Delete error = %v, want ErrNotFound", err)
+	}
+}
+
+// TestStore verifies artifacts round-trip through a KVStore backed by a
+// bucket.
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(&fakeS3{objects: map[string][]byte{}}, "bucket", "jsl/")
+	a := &registry.Artifact{Key: "k1", APIVersion: "1.0", Schema: map[string]any{"type": "object"}}
+	if err := store.Put(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get(ctx, "k1")
+	if err != nil || got.Key != "k1" || got.Schema["type"] != "object" {
+		t.Fatalf("Get() = %+v, %v", got, err)
+	}
+	keys, err := store.List(ctx)
+	if err != nil || !reflect.DeepEqual(keys, []string{"k1"}) {
+		t.Errorf("List() = %v, %v", keys, err)
+	}
+}
@@ -0,0 +1,162 @@
+package jsl
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// transformRename records the properties of one object schema whose names
+// strict grammars reject. Params["renames"] maps each safe name to the
+// original; Rehydrate renames the keys back.
+const transformRename = "rename"
+
+// maxSafeNameLen caps generated names; provider key limits are far higher,
+// but long names waste schema budget.
+const maxSafeNameLen = 64
+
+// isSafePropertyName reports whether name is a non-empty run of ASCII
+// letters, digits, '_' and '-'.
+func isSafePropertyName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// safePropertyName derives a safe name: accents are stripped, every other
+// run of disallowed characters becomes a single '_', and names with nothing
+// left become "field".
+func safePropertyName(name string) string {
+	var b strings.Builder
+	pending := false
+	for _, r := range norm.NFKD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < unicode.MaxASCII && isSafePropertyName(string(r)):
+			if pending && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			pending = false
+			b.WriteRune(r)
+		default:
+			pending = true
+		}
+	}
+	s := b.String()
+	if len(s) > maxSafeNameLen {
+		s = s[:maxSafeNameLen]
+	}
+	if s == "" {
+		s = "field"
+	}
+	return s
+}
+
+// renameUnsafeProperties renames properties whose names are not
+// isSafePropertyName, de-duplicating against the object's other names with
+// a numeric suffix.
+func renameUnsafeProperties(schema any, _ *ConvertOptions) (any, []HostTransform, error) {
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		props, ok := node["properties"].(map[string]any)
+		if !ok {
+			return node
+		}
+		taken := make(map[string]bool, len(props))
+		for k := range props {
+			taken[k] = true
+		}
+		renames := map[string]any{}
+		for _, k := range sortedKeys(props) {
+			if isSafePropertyName(k) {
+				continue
+			}
+			base := safePropertyName(k)
+			safe := base
+			for i := 2; taken[safe]; i++ {
+				safe = fmt.Sprintf("%s_%d", base, i)
+			}
+			taken[safe] = true
+			renames[safe] = k
+		}
+		if len(renames) == 0 {
+			return node
+		}
+		t := HostTransform{Type: transformRename, Path: loc, Params: map[string]any{"renames": renames}}
+		entries = append(entries, t)
+		return rewriteRename(node, &t)
+	})
+	return schema, entries, nil
+}
+
+func rewriteRename(n any, t *HostTransform) any {
+	node, ok := n.(map[string]any)
+	if !ok {
+		return n
+	}
+	renames, _ := t.Params["renames"].(map[string]any)
+	props, _ := node["properties"].(map[string]any)
+	byOriginal := make(map[string]string, len(renames))
+	for safe, orig := range renames {
+		o, _ := orig.(string)
+		byOriginal[o] = safe
+		if sub, ok := props[o]; ok {
+			delete(props, o)
+			props[safe] = sub
+		}
+	}
+	if required, ok := node["required"].([]any); ok {
+		out := make([]any, len(required))
+		for i, r := range required {
+			out[i] = r
+			if s, ok := r.(string); ok {
+				if safe, ok := byOriginal[s]; ok {
+					out[i] = safe
+				}
+			}
+		}
+		node["required"] = out
+	}
+	return node
+}
+
+// restoreRename moves each safe key of the returned object back to its
+// original name.
+func restoreRename(v any, t *HostTransform, _ string) (any, []Warning) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return v, nil
+	}
+	renames, _ := t.Params["renames"].(map[string]any)
+	for _, safe := range sortedKeys(renames) {
+		orig, _ := renames[safe].(string)
+		if val, ok := obj[safe]; ok {
+			delete(obj, safe)
+			obj[orig] = val
+		}
+	}
+	return obj, nil
+}
+
+func renameConvertWarning(t *HostTransform) (Warning, bool) {
+	renames, _ := t.Params["renames"].(map[string]any)
+	parts := make([]string, 0, len(renames))
+	for _, safe := range sortedKeys(renames) {
+		parts = append(parts, fmt.Sprintf("%q → %q", renames[safe], safe))
+	}
+	return Warning{
+		SchemaPath: t.Path,
+		Kind:       WarningKind{Type: WarnPropertyRenamed},
+		Message:    "renamed " + strings.Join(parts, ", "),
+	}, true
+}
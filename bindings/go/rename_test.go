@@ -0,0 +1,95 @@
+package jsl
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestSafePropertyName verifies accent stripping, separator collapsing and
+// the empty fallback.
+func TestSafePropertyName(t *testing.T) {
+	for in, want := range map[string]string{
+		"first name":  "first_name",
+		"Größe (cm)":  "Gro_e_cm",
+		"café":        "cafe",
+		"user.e-mail": "user_e-mail",
+		"  $ref ":     "ref",
+		"名前":          "field",
+	} {
+		if got := safePropertyName(in); got != want {
+			t.Errorf("safePropertyName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestRenameRoundTrip verifies renamed keys (including collisions and
+// required) are restored on rehydrate.
+func TestRenameRoundTrip(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"first name": {"type": "string"},
+			"first_name": {"type": "string"},
+			"address": {
+				"type": "object",
+				"properties": {"zip code": {"type": "string"}, "city": {"type": "string"}},
+				"required": ["zip code"]
+			}
+		},
+		"required": ["first name", "address"]
+	}`)
+	out, entries, err := renameUnsafeProperties(deepCopyJSON(schema), &ConvertOptions{SanitizePropertyNames: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(out)
+	want := `{"properties":{"address":{"properties":{"city":{"type":"string"},"zip_code":{"type":"string"}},"required":["zip_code"],"type":"object"},"first_name":{"type":"string"},"first_name_2":{"type":"string"}},"required":["first_name_2","address"],"type":"object"}`
+	if string(got) != want {
+		t.Errorf("renamed = %s", got)
+	}
+	warnings := hostConvertWarnings(entries)
+	if len(warnings) != 2 || warnings[1].Message != `renamed "first name" → "first_name_2"` {
+		t.Errorf("warnings = %+v", warnings)
+	}
+
+	entries = roundtripEntries(t, entries)
+	stages := hostStages(schema, entries)
+	data := decodeJSON(t, `{"first_name": "a", "first_name_2": "b", "address": {"zip_code": "1", "city": "c"}}`)
+	restored, _ := restoreHost(data, stages, entries)
+	if got, _ := json.Marshal(restored); string(got) != `{"address":{"city":"c","zip code":"1"},"first name":"b","first_name":"a"}` {
+		t.Errorf("restored = %s", got)
+	}
+}
+
+// TestRenameAfterPointerPasses verifies Overrides and FreeText.Fields
+// written against the original names still apply when renaming is on.
+func TestRenameAfterPointerPasses(t *testing.T) {
+	enum := map[string]any{"type": "string", "enum": []any{"a", "b", "c", "d"}}
+	schema := mustMarshal(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"free text":  map[string]any{"type": "string"},
+			"large enum": enum,
+			"kept enum":  enum,
+		},
+	})
+	opts := &ConvertOptions{
+		SanitizePropertyNames: true,
+		MaxEnumValues:         2,
+		FreeText:              &FreeTextOptions{Fields: []string{"#/properties/free text"}},
+		Overrides:             map[string]NodeOverride{"#/properties/kept enum": {KeepEnum: true}},
+	}
+	_, entries, err := runHostPasses(schema, opts, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []string
+	for _, e := range entries {
+		types = append(types, e.Type+" "+e.Path)
+	}
+	want := []string{"large_enum #/properties/large enum", "free_text #/properties/free text", "rename #"}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("entries = %v, want %v", types, want)
+	}
+}
@@ -0,0 +1,75 @@
+package jsl
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// repairTruncated makes output cut off mid-document (typically at the
+// provider's max_tokens) parseable: it cuts back to the last complete value,
+// dropping a trailing partial member or element, and closes every object and
+// array still open there. It returns raw unchanged and a nil warning when
+// raw is valid JSON, when it ends outside any string or container (so is
+// invalid for another reason), and when no complete prefix exists. The
+// result is not re-validated, so repair composes with the other raw-output
+// options (e.g. a NaN before the cut is left to SpecialNumbers).
+func repairTruncated(raw []byte) ([]byte, *Warning) {
+	if json.Valid(raw) {
+		return raw, nil
+	}
+	var (
+		sc        = newOutputScanner(raw)
+		safePos   = -1 // end of the last complete value, or just after an open bracket
+		safeDepth = 0  // containers open at safePos
+	)
+	for sc.next() {
+		if sc.err != nil {
+			break
+		}
+		switch sc.kind {
+		case tokPunct:
+			switch raw[sc.start] {
+			case '{', '[', '}', ']':
+				safePos, safeDepth = sc.pos, len(sc.stack)
+			}
+		case tokString:
+			if !sc.isKey() {
+				safePos, safeDepth = sc.pos, len(sc.stack)
+			}
+		case tokBare:
+			// A bare token running to the end may be a cut-off number or literal.
+			if sc.pos < len(raw) {
+				safePos, safeDepth = sc.pos, len(sc.stack)
+			}
+		}
+	}
+	if sc.err == nil && len(sc.stack) == 0 {
+		return raw, nil // complete, just invalid
+	}
+	if safePos < 0 || len(sc.stack) < safeDepth {
+		return raw, nil
+	}
+
+	// The frames below safeDepth are the ones open at safePos: none has been
+	// closed since, or closing it would have moved the safe point.
+	open := sc.stack[:safeDepth]
+	out := make([]byte, 0, safePos+len(open))
+	out = append(out, raw[:safePos]...)
+	for i := len(open) - 1; i >= 0; i-- {
+		if open[i].array {
+			out = append(out, ']')
+		} else {
+			out = append(out, '}')
+		}
+	}
+	path := ""
+	if len(open) > 0 {
+		path = framesPath(open[:len(open)-1])
+	}
+	return out, &Warning{
+		DataPath: path,
+		Kind:     WarningKind{Type: WarnOutputRepaired},
+		Message: fmt.Sprintf("truncated model output repaired: dropped %d trailing bytes, closed %d containers",
+			len(raw)-safePos, len(open)),
+	}
+}
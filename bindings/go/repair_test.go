@@ -0,0 +1,66 @@
+package jsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestRepairTruncated verifies cut-off output is closed at the last complete
+// value and the repair is reported at the innermost closed container.
+func TestRepairTruncated(t *testing.T) {
+	tests := []struct {
+		name, raw, want, path string
+	}{
+		{"partial string value", `{"a": 1, "b": "hel`, `{"a": 1}`, ""},
+		{"partial key", `{"a": 1, "b`, `{"a": 1}`, ""},
+		{"dangling colon", `{"a": 1, "b":`, `{"a": 1}`, ""},
+		{"partial number", `{"n": [1, 2, 3`, `{"n": [1, 2]}`, "/n"},
+		{"nested", `{"a": {"b": [{"c": true}, {"d": "x`, `{"a": {"b": [{"c": true}, {}]}}`, "/a/b/1"},
+		{"trailing comma", `[1, 2,`, `[1, 2]`, ""},
+		{"open object only", `{`, `{}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, w := repairTruncated([]byte(tt.raw))
+			if string(got) != tt.want {
+				t.Errorf("repaired = %s, want %s", got, tt.want)
+			}
+			if w == nil {
+				t.Fatal("no warning")
+			}
+			if w.Kind.Type != WarnOutputRepaired || w.DataPath != tt.path {
+				t.Errorf("warning = %+v, want %s at %q", w, WarnOutputRepaired, tt.path)
+			}
+		})
+	}
+}
+
+// TestRepairTruncatedLeavesOtherOutput verifies valid, complete-but-invalid
+// and unrepairable output are returned untouched.
+func TestRepairTruncatedLeavesOtherOutput(t *testing.T) {
+	for _, raw := range []string{`{"a": [1, 2]}`, `{"a": NaN}`, `{"a": 1} trailing`, `"cut off`} {
+		got, w := repairTruncated([]byte(raw))
+		if string(got) != raw || w != nil {
+			t.Errorf("repairTruncated(%s) = %s, %v; want it unchanged", raw, got, w)
+		}
+	}
+}
+
+// TestParseModelOutputRepairTruncated verifies repair is opt-in and
+// composes with SpecialNumbers.
+func TestParseModelOutputRepairTruncated(t *testing.T) {
+	raw := []byte(`{"score": NaN, "tags": ["a", "b`)
+	if _, _, err := parseModelOutput(raw, &RehydrateOptions{SpecialNumbers: SpecialNumbersNull}); err == nil {
+		t.Fatal("expected a parse error without RepairTruncated")
+	}
+	data, warnings, err := parseModelOutput(raw, &RehydrateOptions{SpecialNumbers: SpecialNumbersNull, RepairTruncated: true})
+	if err != nil {
+		t.Fatalf("parseModelOutput() failed: %v", err)
+	}
+	if got, _ := json.Marshal(data); string(got) != `{"score":null,"tags":["a"]}` {
+		t.Errorf("data = %s", got)
+	}
+	if len(warnings) != 2 || warnings[0].Kind.Type != WarnOutputRepaired || warnings[1].Kind.Type != WarnSpecialNumber {
+		t.Errorf("warnings = %+v", warnings)
+	}
+}
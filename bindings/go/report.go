@@ -0,0 +1,169 @@
+package jsl
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// IssueSource tells where an Issue came from.
+type IssueSource string
+
+const (
+	// SourceRehydrate: a rehydration Warning.
+	SourceRehydrate IssueSource = "rehydrate"
+	// SourceValidation: a JSON Schema validation failure.
+	SourceValidation IssueSource = "validation"
+)
+
+// CodeSchemaViolation is the Issue.Code of validation failures of a
+// keyword Warning.Code has no narrower code for (required,
+// additionalProperties, oneOf, ...).
+const CodeSchemaViolation = "schema_violation"
+
+// Issue is one entry of a Report. Its Severity is SeverityError when the
+// data does not satisfy the original schema, SeverityWarning when it was
+// adjusted or a constraint could not be checked.
+type Issue struct {
+	// DataPath is the RFC 6901 pointer to the offending value ("" for the
+	// root), as in Warning.DataPath.
+	DataPath string `json:"dataPath"`
+	// SchemaPath locates the schema node in the original schema ("#/..."),
+	// and Keyword the keyword at fault, when known.
+	SchemaPath string   `json:"schemaPath"`
+	Keyword    string   `json:"keyword,omitempty"`
+	Severity   Severity `json:"severity"`
+	// Code is Warning.Code for warnings; validation failures share its
+	// constraint codes (WarnRangeViolation and the like) and otherwise
+	// report CodeSchemaViolation.
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Source  IssueSource `json:"source"`
+	// Warning is the warning a SourceRehydrate issue was built from.
+	Warning *Warning `json:"-"`
+}
+
+// Report merges rehydration warnings and validation failures of one output
+// into a single list.
+type Report struct {
+	// Issues are sorted by DataPath, then errors before warnings.
+	Issues []Issue `json:"issues"`
+}
+
+// NewReport builds a report from the warnings of a RehydrateResult and the
+// error of validating its data (nil when it passed; see
+// ConvertResult.Validator). A validation failure already reported as a
+// constraint_violation warning for the same value and keyword appears
+// once, as the warning. An err that is not a *jsonschema.ValidationError
+// becomes one root-level issue.
+func NewReport(warnings []Warning, err error) *Report {
+	r := &Report{Issues: []Issue{}}
+	reported := map[string]bool{}
+	for i := range warnings {
+		w := &warnings[i]
+		issue := Issue{
+			DataPath:   ParsePointer(w.DataPath).DataPath(),
+			SchemaPath: ParsePointer(w.SchemaPath).String(),
+			Keyword:    w.Kind.Constraint,
+			Severity:   SeverityWarning,
+			Code:       w.Code(),
+			Message:    w.Message,
+			Source:     SourceRehydrate,
+			Warning:    w,
+		}
+		if w.Kind.Type == WarnConstraintViolation {
+			issue.Severity = SeverityError
+			reported[issue.DataPath+"\x00"+issue.Keyword] = true
+		}
+		r.Issues = append(r.Issues, issue)
+	}
+
+	var verr *jsonschema.ValidationError
+	switch {
+	case err == nil:
+	case errors.As(err, &verr):
+		p := message.NewPrinter(language.English)
+		for _, leaf := range validationLeaves(verr, nil) {
+			issue := Issue{
+				DataPath:   Pointer(leaf.InstanceLocation).DataPath(),
+				SchemaPath: schemaFragment(leaf.SchemaURL),
+				Severity:   SeverityError,
+				Code:       CodeSchemaViolation,
+				Message:    leaf.ErrorKind.LocalizedString(p),
+				Source:     SourceValidation,
+			}
+			if kw := leaf.ErrorKind.KeywordPath(); len(kw) > 0 {
+				issue.Keyword = kw[0]
+				if code, ok := constraintCodes[issue.Keyword]; ok {
+					issue.Code = code
+				}
+			}
+			if reported[issue.DataPath+"\x00"+issue.Keyword] {
+				continue
+			}
+			r.Issues = append(r.Issues, issue)
+		}
+	default:
+		r.Issues = append(r.Issues, Issue{
+			SchemaPath: "#",
+			Severity:   SeverityError,
+			Code:       CodeSchemaViolation,
+			Message:    err.Error(),
+			Source:     SourceValidation,
+		})
+	}
+
+	sort.SliceStable(r.Issues, func(i, j int) bool {
+		a, b := r.Issues[i], r.Issues[j]
+		if a.DataPath != b.DataPath {
+			return a.DataPath < b.DataPath
+		}
+		return a.Severity == SeverityError && b.Severity != SeverityError
+	})
+	return r
+}
+
+// Valid reports whether the report has no errors.
+func (r *Report) Valid() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return false
+		}
+	}
+	return true
+}
+
+// Errors returns the issues of SeverityError.
+func (r *Report) Errors() []Issue {
+	var out []Issue
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
+
+// validationLeaves returns the errors of the tree under e that have no
+// causes: the individual keyword failures.
+func validationLeaves(e *jsonschema.ValidationError, out []*jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(e.Causes) == 0 {
+		return append(out, e)
+	}
+	for _, c := range e.Causes {
+		out = validationLeaves(c, out)
+	}
+	return out
+}
+
+// schemaFragment returns the "#/..." location of an absolute schema URL.
+func schemaFragment(url string) string {
+	if i := strings.IndexByte(url, '#'); i >= 0 {
+		return ParsePointer(url[i:]).String()
+	}
+	return "#"
+}
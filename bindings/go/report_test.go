@@ -0,0 +1,79 @@
+package jsl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewReport(t *testing.T) {
+	v := &lazyValidator{source: []byte(`{
+		"type": "object",
+		"properties": {
+			"qty": {"type": "integer", "minimum": 1},
+			"tags": {"type": "array", "items": {"type": "string", "maxLength": 3}}
+		},
+		"required": ["qty", "id"]
+	}`)}
+	schema, err := v.compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]any{"qty": 0.0, "tags": []any{"ok", "toolong"}}
+	warnings := []Warning{
+		{DataPath: "/tags/1", SchemaPath: "#/properties/tags/items", Kind: WarningKind{Type: WarnConstraintViolation, Constraint: "maxLength"}, Message: "string too long"},
+		{DataPath: "/tags", SchemaPath: "#/properties/tags", Kind: WarningKind{Type: WarnTypeInferred}, Message: "inferred"},
+	}
+	r := NewReport(warnings, schema.Validate(data))
+
+	type key struct {
+		path     string
+		keyword  string
+		severity Severity
+		code     string
+		source   IssueSource
+	}
+	var got []key
+	for _, issue := range r.Issues {
+		got = append(got, key{issue.DataPath, issue.Keyword, issue.Severity, issue.Code, issue.Source})
+		if issue.Message == "" {
+			t.Errorf("issue %+v has no message", issue)
+		}
+	}
+	want := []key{
+		{"", "required", SeverityError, CodeSchemaViolation, SourceValidation},
+		{"/qty", "minimum", SeverityError, WarnRangeViolation, SourceValidation},
+		{"/tags", "", SeverityWarning, WarnTypeInferred, SourceRehydrate},
+		{"/tags/1", "maxLength", SeverityError, WarnRangeViolation, SourceRehydrate},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("issues = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("issue %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if r.Issues[1].SchemaPath != "#/properties/qty" {
+		t.Errorf("SchemaPath = %q, want #/properties/qty", r.Issues[1].SchemaPath)
+	}
+	if r.Issues[3].Warning != &warnings[0] {
+		t.Error("rehydrate issue does not point at its warning")
+	}
+	if r.Valid() || len(r.Errors()) != 3 {
+		t.Errorf("Valid() = %v, Errors() = %d; want false, 3", r.Valid(), len(r.Errors()))
+	}
+}
+
+func TestNewReportValid(t *testing.T) {
+	r := NewReport([]Warning{{DataPath: "/a", Kind: WarningKind{Type: WarnEnumCoerced}}}, nil)
+	if !r.Valid() || len(r.Issues) != 1 || r.Issues[0].Severity != SeverityWarning {
+		t.Errorf("report = %+v, want one warning", r)
+	}
+	if r := NewReport(nil, nil); !r.Valid() || r.Issues == nil {
+		t.Errorf("empty report = %+v", r)
+	}
+	r = NewReport(nil, errors.New("validator: decode schema: bad"))
+	if r.Valid() || r.Issues[0].SchemaPath != "#" || r.Issues[0].Code != CodeSchemaViolation {
+		t.Errorf("report = %+v, want one root error", r)
+	}
+}
@@ -0,0 +1,109 @@
+package jsl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTransient is matched (via errors.Is) by guest failures that may
+// succeed on a fresh instance: instantiation and allocation failures, and
+// traps. Errors the guest reports as *Error are deterministic and never
+// match.
+var ErrTransient = errors.New("transient guest failure")
+
+// transientError marks a callGuest failure as ErrTransient without
+// changing its message.
+type transientError struct{ err error }
+
+func (t *transientError) Error() string        { return t.err.Error() }
+func (t *transientError) Unwrap() error        { return t.err }
+func (t *transientError) Is(target error) bool { return target == ErrTransient }
+
+func transient(err error) error { return &transientError{err} }
+
+// RetryPolicy retries guest calls that fail transiently. Each attempt runs
+// on a fresh module instance, so a retry never sees the failed attempt's
+// state.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts per guest call, the first
+	// included. Values below 2 disable retries.
+	MaxAttempts int
+	// Backoff returns the delay after failed attempt n (1-based). Nil
+	// selects ExponentialBackoff(10*time.Millisecond, time.Second).
+	Backoff func(attempt int) time.Duration
+	// Retryable reports whether err is worth another attempt. Nil selects
+	// IsTransient.
+	Retryable func(err error) bool
+}
+
+// WithRetryPolicy retries transient guest failures per p. An engine from
+// NewSchemaLlmEngine runs under context.Background(), so its waits between
+// attempts always run to the end. To make them cancellable, call through
+// the copy SchemaLlmEngine.WithContext returns: its waits end early, failing
+// the call, once that context is done.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *engineConfig) {
+		if p.Backoff == nil {
+			p.Backoff = ExponentialBackoff(10*time.Millisecond, time.Second)
+		}
+		if p.Retryable == nil {
+			p.Retryable = IsTransient
+		}
+		c.retry = p
+	}
+}
+
+// IsTransient reports whether err matches ErrTransient.
+func IsTransient(err error) bool {
+	return errors.Is(err, ErrTransient)
+}
+
+// ExponentialBackoff returns a Backoff that waits base after the first
+// failure and doubles the wait after each further one, up to max.
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		return min(d, max)
+	}
+}
+
+// retry reports whether a call that failed with err on attempt n should be
+// attempted again.
+func (p RetryPolicy) retry(attempt int, err error) bool {
+	return attempt < p.MaxAttempts && p.Retryable != nil && p.Retryable(err)
+}
+
+// wait sleeps for the backoff after attempt, or until ctx is done.
+func (p RetryPolicy) wait(ctx context.Context, attempt int) error {
+	d := p.Backoff(attempt)
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// callWithRetry runs call until it succeeds, fails for good, or runs out
+// of attempts per the engine's retry policy.
+func (e *SchemaLlmEngine) callWithRetry(call func() ([]byte, error)) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		payload, err := call()
+		if err == nil || !e.retry.retry(attempt, err) {
+			return payload, err
+		}
+		if werr := e.retry.wait(e.ctx, attempt); werr != nil {
+			return nil, fmt.Errorf("%w (retry abandoned after %d attempts: %w)", err, attempt, werr)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package jsl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func retryEngine(p RetryPolicy) *SchemaLlmEngine {
+	cfg := &engineConfig{}
+	WithRetryPolicy(p)(cfg)
+	return &SchemaLlmEngine{ctx: context.Background(), retry: cfg.retry}
+}
+
+// failing returns a call failing with errs in turn, then succeeding, and a
+// counter of its calls.
+func failing(errs ...error) (func() ([]byte, error), *int) {
+	n := 0
+	return func() ([]byte, error) {
+		n++
+		if n <= len(errs) {
+			return nil, errs[n-1]
+		}
+		return []byte(`{}`), nil
+	}, &n
+}
+
+// TestRetryTransient verifies transient failures are retried up to
+// MaxAttempts and the last error is returned once attempts run out.
+func TestRetryTransient(t *testing.T) {
+	trap := transient(errors.New("jsl_convert trap: out of memory"))
+	eng := retryEngine(RetryPolicy{MaxAttempts: 3, Backoff: func(int) time.Duration { return 0 }})
+
+	call, n := failing(trap, trap)
+	if payload, err := eng.callWithRetry(call); err != nil || string(payload) != `{}` || *n != 3 {
+		t.Errorf("callWithRetry() = %q, %v after %d calls; want success after 3", payload, err, *n)
+	}
+	call, n = failing(trap, trap, trap)
+	if _, err := eng.callWithRetry(call); !errors.Is(err, ErrTransient) || *n != 3 {
+		t.Errorf("callWithRetry() error = %v after %d calls; want the trap after 3", err, *n)
+	}
+}
+
+// TestRetryPermanent verifies guest-reported errors and unclassified
+// failures are not retried, and that the policy is off by default.
+func TestRetryPermanent(t *testing.T) {
+	eng := retryEngine(RetryPolicy{MaxAttempts: 3})
+	for _, err := range []error{
+		&Error{Code: "schema_error", Message: "root schema must be an object"},
+		errors.New("missing export: jsl_convert"),
+	} {
+		call, n := failing(err)
+		if _, got := eng.callWithRetry(call); got != err || *n != 1 {
+			t.Errorf("callWithRetry() = %v after %d calls; want %v after 1", got, *n, err)
+		}
+	}
+
+	call, n := failing(transient(errors.New("alloc: trap")))
+	if _, err := (&SchemaLlmEngine{ctx: context.Background()}).callWithRetry(call); err == nil || *n != 1 {
+		t.Errorf("callWithRetry() without policy = %v after %d calls; want one failed call", err, *n)
+	}
+}
+
+// TestRetryClassifier verifies a custom Retryable replaces IsTransient.
+func TestRetryClassifier(t *testing.T) {
+	busy := errors.New("busy")
+	eng := retryEngine(RetryPolicy{
+		MaxAttempts: 2,
+		Backoff:     func(int) time.Duration { return 0 },
+		Retryable:   func(err error) bool { return errors.Is(err, busy) },
+	})
+	call, n := failing(fmt.Errorf("call: %w", busy))
+	if _, err := eng.callWithRetry(call); err != nil || *n != 2 {
+		t.Errorf("callWithRetry() = %v after %d calls; want success after 2", err, *n)
+	}
+	call, n = failing(transient(errors.New("trap")))
+	if _, err := eng.callWithRetry(call); err == nil || *n != 1 {
+		t.Errorf("callWithRetry() = %v after %d calls; want one failed call", err, *n)
+	}
+}
+
+// TestRetryCanceled verifies a done context ends the wait between
+// attempts, reporting both the guest failure and the context error.
+func TestRetryCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	eng := retryEngine(RetryPolicy{MaxAttempts: 5, Backoff: func(int) time.Duration { return time.Hour }}).WithContext(ctx)
+	trap := transient(errors.New("trap"))
+	call, n := failing(trap, trap)
+	_, err := eng.callWithRetry(call)
+	if !errors.Is(err, ErrTransient) || !errors.Is(err, context.Canceled) || *n != 1 {
+		t.Errorf("callWithRetry() = %v after %d calls; want the trap and context.Canceled after 1", err, *n)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	for attempt, want := range map[int]time.Duration{1: 10 * time.Millisecond, 2: 20 * time.Millisecond, 3: 40 * time.Millisecond, 4: 50 * time.Millisecond, 30: 50 * time.Millisecond} {
+		if got := b(attempt); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package jsl
+
+import (
+	"math/rand"
+	"time"
+)
+
+// warningSampler thins warnings before they are returned, bounding the cost
+// of logging and shipping them in high-volume services.
+type warningSampler struct {
+	rate       float64
+	maxPerKind int
+	rng        *rand.Rand
+}
+
+// WithWarningSampling keeps each Convert and Rehydrate warning with
+// probability rate (values outside (0, 1] mean 1) and at most maxPerKind
+// warnings of each kind per call (0 means no cap). Results report how many
+// warnings were dropped in DroppedWarnings, so rates can be reconstructed.
+func WithWarningSampling(rate float64, maxPerKind int) Option {
+	return func(c *engineConfig) {
+		if rate <= 0 || rate > 1 {
+			rate = 1
+		}
+		c.sampler = &warningSampler{
+			rate:       rate,
+			maxPerKind: maxPerKind,
+			rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		}
+	}
+}
+
+// sample returns the kept warnings and the number dropped. A nil sampler
+// keeps everything.
+func (s *warningSampler) sample(warnings []Warning) ([]Warning, int) {
+	if s == nil || len(warnings) == 0 {
+		return warnings, 0
+	}
+	perKind := map[WarningKind]int{}
+	kept := warnings[:0:0]
+	for _, w := range warnings {
+		if s.maxPerKind > 0 && perKind[w.Kind] >= s.maxPerKind {
+			continue
+		}
+		if s.rate < 1 && s.rng.Float64() >= s.rate {
+			continue
+		}
+		perKind[w.Kind]++
+		kept = append(kept, w)
+	}
+	return kept, len(warnings) - len(kept)
+}
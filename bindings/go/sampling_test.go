@@ -0,0 +1,42 @@
+package jsl
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestWarningSamplerCapsPerKind verifies maxPerKind bounds each kind independently.
+func TestWarningSamplerCapsPerKind(t *testing.T) {
+	s := &warningSampler{rate: 1, maxPerKind: 2, rng: rand.New(rand.NewSource(1))}
+	var in []Warning
+	for i := 0; i < 5; i++ {
+		in = append(in, Warning{Kind: WarningKind{Type: "constraint_violation", Constraint: "enum"}})
+		in = append(in, Warning{Kind: WarningKind{Type: "format_coerced"}})
+	}
+	kept, dropped := s.sample(in)
+	if len(kept) != 4 || dropped != 6 {
+		t.Errorf("kept %d, dropped %d", len(kept), dropped)
+	}
+	if len(in) != 10 {
+		t.Error("sample modified its input")
+	}
+}
+
+// TestWarningSamplerRate verifies the kept fraction tracks the rate.
+func TestWarningSamplerRate(t *testing.T) {
+	s := &warningSampler{rate: 0.1, rng: rand.New(rand.NewSource(1))}
+	in := make([]Warning, 10000)
+	kept, dropped := s.sample(in)
+	if len(kept) < 800 || len(kept) > 1200 || len(kept)+dropped != len(in) {
+		t.Errorf("kept %d of %d at rate 0.1", len(kept), len(in))
+	}
+}
+
+// TestWarningSamplerNil verifies engines without sampling keep everything.
+func TestWarningSamplerNil(t *testing.T) {
+	var s *warningSampler
+	in := []Warning{{}, {}}
+	if kept, dropped := s.sample(in); len(kept) != 2 || dropped != 0 {
+		t.Errorf("kept %d, dropped %d", len(kept), dropped)
+	}
+}
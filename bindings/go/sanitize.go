@@ -0,0 +1,154 @@
+package jsl
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ControlCharMode selects how control characters and invalid UTF-8 in model
+// output strings are handled.
+type ControlCharMode string
+
+const (
+	// ControlCharsKeep leaves strings untouched (default).
+	ControlCharsKeep ControlCharMode = ""
+	// ControlCharsStrip removes C0/C1 control characters (except tab, newline
+	// and carriage return) and invalid UTF-8 byte sequences.
+	ControlCharsStrip ControlCharMode = "strip"
+	// ControlCharsEscape replaces each control character with its visible
+	// \uXXXX escape text and each invalid byte with U+FFFD.
+	ControlCharsEscape ControlCharMode = "escape"
+)
+
+// sanitizeEnabled reports whether any string sanitization is requested.
+func (o *RehydrateOptions) sanitizeEnabled() bool {
+	return o != nil && (o.NormalizeUnicode || o.ControlChars != ControlCharsKeep)
+}
+
+// sanitizeStrings applies NFC normalization and control-character handling to
+// every string value in data, warning once per affected path.
+func sanitizeStrings(data any, opts *RehydrateOptions) (any, []Warning) {
+	var warnings []Warning
+	var walk func(v any, path string) any
+	walk = func(v any, path string) any {
+		switch t := v.(type) {
+		case map[string]any:
+			for k, c := range t {
+				t[k] = walk(c, childDataPath(path, k))
+			}
+		case []any:
+			for i, c := range t {
+				t[i] = walk(c, childDataPath(path, i))
+			}
+		case string:
+			out, changes := sanitizeString(t, opts)
+			if len(changes) > 0 {
+				warnings = append(warnings, Warning{
+					DataPath: path,
+					Kind:     WarningKind{Type: WarnSanitized},
+					Message:  "string sanitized: " + strings.Join(changes, ", "),
+				})
+			}
+			return out
+		}
+		return v
+	}
+	return walk(data, ""), warnings
+}
+
+// sanitizeString returns the cleaned string and a description of each change.
+func sanitizeString(s string, opts *RehydrateOptions) (string, []string) {
+	var changes []string
+	if opts.ControlChars != ControlCharsKeep {
+		var b strings.Builder
+		removed, escaped := 0, 0
+		for i := 0; i < len(s); {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			i += size
+			switch {
+			case r == utf8.RuneError && size == 1:
+				if opts.ControlChars == ControlCharsEscape {
+					b.WriteRune(utf8.RuneError)
+					escaped++
+				} else {
+					removed++
+				}
+			case isUnsafeControl(r):
+				if opts.ControlChars == ControlCharsEscape {
+					fmt.Fprintf(&b, `\u%04X`, r)
+					escaped++
+				} else {
+					removed++
+				}
+			default:
+				b.WriteRune(r)
+			}
+		}
+		if removed > 0 {
+			changes = append(changes, fmt.Sprintf("%d control/invalid character(s) removed", removed))
+		}
+		if escaped > 0 {
+			changes = append(changes, fmt.Sprintf("%d control/invalid character(s) escaped", escaped))
+		}
+		s = b.String()
+	}
+	if opts.NormalizeUnicode && !norm.NFC.IsNormalString(s) {
+		s = norm.NFC.String(s)
+		changes = append(changes, "normalized to NFC")
+	}
+	return s, changes
+}
+
+// isUnsafeControl reports control characters other than tab, LF and CR.
+func isUnsafeControl(r rune) bool {
+	return unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r'
+}
+
+// scrubInvalidUTF8 rewrites invalid UTF-8 inside raw string tokens before
+// decoding. encoding/json would otherwise silently turn each invalid byte
+// into U+FFFD, hiding the corruption.
+func scrubInvalidUTF8(raw []byte, mode ControlCharMode) ([]byte, []Warning) {
+	if utf8.Valid(raw) {
+		return raw, nil
+	}
+	var (
+		out      bytes.Buffer
+		warnings []Warning
+		sc       = newOutputScanner(raw)
+	)
+	out.Grow(len(raw))
+	for sc.next() {
+		tok := sc.token()
+		if sc.kind != tokString || utf8.Valid(tok) {
+			out.Write(tok)
+			continue
+		}
+		path := sc.path()
+		for i := 0; i < len(tok); {
+			r, size := utf8.DecodeRune(tok[i:])
+			if r == utf8.RuneError && size == 1 {
+				if mode == ControlCharsEscape {
+					out.WriteString("\uFFFD")
+				}
+			} else {
+				out.Write(tok[i : i+size])
+			}
+			i += size
+		}
+		warnings = append(warnings, Warning{
+			DataPath: path,
+			Kind:     WarningKind{Type: WarnSanitized},
+			Message:  "invalid UTF-8 in model output",
+		})
+	}
+	if sc.err != nil {
+		// Leave structural errors to encoding/json.
+		return raw, nil
+	}
+	return out.Bytes(), warnings
+}
@@ -0,0 +1,52 @@
+package jsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestSanitizeStrings verifies NFC normalization and control-character handling per path.
+func TestSanitizeStrings(t *testing.T) {
+	data := map[string]any{
+		"name":  "Cafe\u0301",                   // decomposed é
+		"notes": []any{"ok\n", "bell\u0007end"}, // newline is kept, BEL is not
+	}
+
+	out, warnings := sanitizeStrings(data, &RehydrateOptions{NormalizeUnicode: true, ControlChars: ControlCharsStrip})
+	obj := out.(map[string]any)
+	if obj["name"] != "Caf\u00e9" {
+		t.Errorf("name: got %q, want NFC form", obj["name"])
+	}
+	notes := obj["notes"].([]any)
+	if notes[0] != "ok\n" || notes[1] != "bellend" {
+		t.Errorf("notes: got %q", notes)
+	}
+	paths := map[string]bool{}
+	for _, w := range warnings {
+		paths[w.DataPath] = true
+	}
+	if len(paths) != 2 || !paths["/name"] || !paths["/notes/1"] {
+		t.Errorf("warning paths: got %v", paths)
+	}
+
+	escaped, _ := sanitizeString("a\u0007b", &RehydrateOptions{ControlChars: ControlCharsEscape})
+	if escaped != `a\u0007b` {
+		t.Errorf("escape: got %q", escaped)
+	}
+}
+
+// TestParseModelOutputInvalidUTF8 verifies invalid bytes are detected in raw output instead of silently replaced.
+func TestParseModelOutputInvalidUTF8(t *testing.T) {
+	raw := []byte("{\"a\": \"ok\", \"b\": \"bad\xff\xfebytes\"}")
+
+	data, warnings, err := parseModelOutput(raw, &RehydrateOptions{ControlChars: ControlCharsStrip})
+	if err != nil {
+		t.Fatalf("parseModelOutput() failed: %v", err)
+	}
+	if got, _ := json.Marshal(data); string(got) != `{"a":"ok","b":"badbytes"}` {
+		t.Errorf("data: got %s", got)
+	}
+	if len(warnings) != 1 || warnings[0].DataPath != "/b" {
+		t.Errorf("warnings: got %+v", warnings)
+	}
+}
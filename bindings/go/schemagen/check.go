@@ -0,0 +1,101 @@
+package schemagen
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ErrOpaque is returned by Check for conversions that turned part of the
+// schema into a JSON-encoded string: random text cannot stand in for the
+// model there, so the case is skipped rather than failed.
+var ErrOpaque = errors.New("schemagen: converted schema has JSON-encoded string fields")
+
+// Engine is the jsl surface Check needs; *jsl.SchemaLlmEngine satisfies it.
+type Engine interface {
+	jsl.Converter
+	jsl.Rehydrator
+}
+
+// Failure is a broken invariant, with what is needed to reproduce it.
+type Failure struct {
+	// Stage is "convert", "rehydrate" or "validate".
+	Stage  string
+	Schema map[string]any
+	// Data is the synthetic model output (nil for convert failures).
+	Data any
+	Err  error
+}
+
+func (f *Failure) Error() string {
+	return fmt.Sprintf("schemagen: %s: %v", f.Stage, f.Err)
+}
+
+func (f *Failure) Unwrap() error { return f.Err }
+
+// Check asserts the round-trip invariant for one schema: it converts
+// schema, synthesizes model output from the converted schema (Instance),
+// rehydrates it and validates the result against schema. Every validation
+// error must be covered by a rehydrate warning at the same data path or
+// above it, since the engine reports what the provider could not enforce.
+// It returns nil, ErrOpaque or a *Failure.
+func Check(e Engine, schema map[string]any, opts *jsl.ConvertOptions, rng *rand.Rand) error {
+	result, err := e.Convert(schema, opts)
+	if err != nil {
+		return &Failure{Stage: "convert", Schema: schema, Err: err}
+	}
+	entries, err := jsl.CodecEntries(result.Codec)
+	if err != nil {
+		return &Failure{Stage: "convert", Schema: schema, Err: err}
+	}
+	for _, entry := range entries {
+		if entry.Type == "json_string_parse" {
+			return ErrOpaque
+		}
+	}
+	data := Instance(result.Schema, rng)
+	rehydrated, err := e.Rehydrate(data, result.Codec, schema)
+	if err != nil {
+		return &Failure{Stage: "rehydrate", Schema: schema, Data: data, Err: err}
+	}
+	validator, err := result.Validator()
+	if err != nil {
+		return &Failure{Stage: "validate", Schema: schema, Data: data, Err: err}
+	}
+	var verr *jsonschema.ValidationError
+	if err := validator.Validate(rehydrated.Data); errors.As(err, &verr) {
+		if !covered(verr, rehydrated.Warnings) {
+			return &Failure{Stage: "validate", Schema: schema, Data: data, Err: err}
+		}
+	} else if err != nil {
+		return &Failure{Stage: "validate", Schema: schema, Data: data, Err: err}
+	}
+	return nil
+}
+
+// covered reports whether a warning accounts for err: one is reported at
+// its instance location or an ancestor, or every cause is covered.
+func covered(err *jsonschema.ValidationError, warnings []jsl.Warning) bool {
+	loc := ""
+	for _, seg := range err.InstanceLocation {
+		loc += "/" + strings.ReplaceAll(strings.ReplaceAll(seg, "~", "~0"), "/", "~1")
+	}
+	for _, w := range warnings {
+		if w.DataPath == loc || strings.HasPrefix(loc, w.DataPath+"/") {
+			return true
+		}
+	}
+	if len(err.Causes) == 0 {
+		return false
+	}
+	for _, c := range err.Causes {
+		if !covered(c, warnings) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,226 @@
+package schemagen
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// maxInstanceRefs bounds $ref hops along one branch of Instance, so
+// recursive converted schemas still terminate (with null where allowed).
+const maxInstanceRefs = 16
+
+// formatValues are valid values for the formats the generator emits.
+var formatValues = map[string][]string{
+	"date-time": {"2024-01-02T03:04:05Z", "1999-12-31T23:59:59+01:00"},
+	"date":      {"2024-01-02", "1999-12-31"},
+	"email":     {"a@example.com", "first.last@example.org"},
+	"uuid":      {"123e4567-e89b-12d3-a456-426614174000", "00000000-0000-4000-8000-000000000000"},
+}
+
+// Instance returns a random value a converted schema accepts, standing in
+// for model output: a random anyOf branch, enum value or type from a type
+// list, the merge of allOf object branches, every required property and a
+// random subset of the optional ones, and values within the bounds the
+// schema declares. Local $refs are resolved against schema's $defs.
+// Patterns, uniqueItems and multipleOf are not honored; the generator does
+// not emit them.
+func Instance(schema map[string]any, rng *rand.Rand) any {
+	return (&instanceGen{root: schema, rng: rng}).value(schema, 0)
+}
+
+type instanceGen struct {
+	root map[string]any
+	rng  *rand.Rand
+}
+
+func (g *instanceGen) value(schema any, refs int) any {
+	node, ok := schema.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if ref, ok := node["$ref"].(string); ok {
+		if refs >= maxInstanceRefs {
+			return nil
+		}
+		return g.value(g.resolve(ref), refs+1)
+	}
+	if c, ok := node["const"]; ok {
+		return c
+	}
+	if enum, ok := node["enum"].([]any); ok && len(enum) > 0 {
+		return enum[g.rng.Intn(len(enum))]
+	}
+	if branches, ok := node["allOf"].([]any); ok && len(branches) > 0 {
+		// Only object intersections are supported: the branches' values merge.
+		out := map[string]any{}
+		for _, b := range branches {
+			if m, ok := g.value(b, refs).(map[string]any); ok {
+				for k, v := range m {
+					out[k] = v
+				}
+			}
+		}
+		return out
+	}
+	for _, kw := range []string{"anyOf", "oneOf"} {
+		if branches, ok := node[kw].([]any); ok && len(branches) > 0 {
+			return g.value(branches[g.rng.Intn(len(branches))], refs)
+		}
+	}
+
+	typ := node["type"]
+	if types, ok := typ.([]any); ok && len(types) > 0 {
+		typ = types[g.rng.Intn(len(types))]
+		if refs >= maxInstanceRefs/2 && containsNull(types) {
+			typ = "null"
+		}
+	}
+	switch typ {
+	case "object":
+		return g.object(node, refs)
+	case "array":
+		lo, hi := intBounds(node, "minItems", "maxItems", 0, 3)
+		items := make([]any, lo+g.rng.Intn(hi-lo+1))
+		for i := range items {
+			items[i] = g.value(node["items"], refs)
+		}
+		return items
+	case "string":
+		if values, ok := formatValues[stringOf(node["format"])]; ok {
+			return values[g.rng.Intn(len(values))]
+		}
+		lo, hi := intBounds(node, "minLength", "maxLength", 0, 8)
+		return g.word(lo + g.rng.Intn(hi-lo+1))
+	case "integer":
+		lo, hi := numBounds(node)
+		lo, hi = math.Ceil(lo), math.Floor(hi)
+		if lo > hi {
+			return lo
+		}
+		return lo + float64(g.rng.Intn(int(hi-lo)+1))
+	case "number":
+		lo, hi := numBounds(node)
+		return math.Round((lo+g.rng.Float64()*(hi-lo))*100) / 100
+	case "boolean":
+		return g.rng.Intn(2) == 0
+	}
+	return nil
+}
+
+func (g *instanceGen) object(node map[string]any, refs int) map[string]any {
+	out := map[string]any{}
+	props, _ := node["properties"].(map[string]any)
+	required := map[string]bool{}
+	if req, ok := node["required"].([]any); ok {
+		for _, r := range req {
+			if name, ok := r.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+	for _, name := range sortedKeys(props) {
+		if required[name] || g.rng.Intn(2) == 0 {
+			out[name] = g.value(props[name], refs)
+		}
+	}
+	if extra, ok := node["additionalProperties"].(map[string]any); ok {
+		for i, n := 0, g.rng.Intn(3); i < n; i++ {
+			out[fmt.Sprintf("k%d_%s", i, g.word(3))] = g.value(extra, refs)
+		}
+	}
+	return out
+}
+
+// resolve looks up a local ref ("#/$defs/Name" or any JSON Pointer) in the
+// root schema.
+func (g *instanceGen) resolve(ref string) any {
+	if !strings.HasPrefix(ref, "#") {
+		return nil
+	}
+	var node any = g.root
+	for _, seg := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+		seg = strings.ReplaceAll(strings.ReplaceAll(seg, "~1", "/"), "~0", "~")
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil
+		}
+		node = m[seg]
+	}
+	return node
+}
+
+func (g *instanceGen) word(n int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[g.rng.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+// intBounds returns the [lo, hi] range of a count keyword pair, with
+// defaults when either is absent; hi is at least lo.
+func intBounds(node map[string]any, minKey, maxKey string, lo, span int) (int, int) {
+	if v, ok := node[minKey].(float64); ok {
+		lo = int(v)
+	}
+	hi := lo + span
+	if v, ok := node[maxKey].(float64); ok && int(v) < hi {
+		hi = int(v)
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+// numBounds returns the numeric range a schema allows, narrowed a little
+// for exclusive bounds.
+func numBounds(node map[string]any) (float64, float64) {
+	lo, hasLo := node["minimum"].(float64)
+	hi, hasHi := node["maximum"].(float64)
+	if v, ok := node["exclusiveMinimum"].(float64); ok {
+		lo, hasLo = v+1, true
+	}
+	if v, ok := node["exclusiveMaximum"].(float64); ok {
+		hi, hasHi = v-1, true
+	}
+	switch {
+	case !hasLo && !hasHi:
+		lo, hi = -100, 100
+	case !hasLo:
+		lo = hi - 100
+	case !hasHi:
+		hi = lo + 100
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsNull(types []any) bool {
+	for _, t := range types {
+		if t == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+func stringOf(v any) string {
+	s, _ := v.(string)
+	return s
+}
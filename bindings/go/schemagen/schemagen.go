@@ -0,0 +1,278 @@
+// Package schemagen generates random but valid JSON Schemas and checks the
+// convert → synthetic data → rehydrate → validate invariant on them. It
+// complements the fixed fixtures in tests/conformance with schemas nobody
+// wrote by hand; a failing seed reproduces the same schema every time.
+package schemagen
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Keyword is one construct the generator can mix into schemas.
+type Keyword string
+
+const (
+	// KeywordEnum gives strings and integers an enum.
+	KeywordEnum Keyword = "enum"
+	// KeywordConst pins a scalar to one value.
+	KeywordConst Keyword = "const"
+	// KeywordNullable adds "null" to a scalar's type.
+	KeywordNullable Keyword = "nullable"
+	// KeywordOptional leaves some object properties out of required.
+	KeywordOptional Keyword = "optional"
+	// KeywordAnyOf emits anyOf unions.
+	KeywordAnyOf Keyword = "anyOf"
+	// KeywordOneOf emits oneOf unions of objects told apart by a const
+	// "kind" property.
+	KeywordOneOf Keyword = "oneOf"
+	// KeywordAllOf emits allOf intersections of objects with disjoint
+	// properties.
+	KeywordAllOf Keyword = "allOf"
+	// KeywordMap emits dictionaries (additionalProperties schemas).
+	KeywordMap Keyword = "additionalProperties"
+	// KeywordRef moves subschemas to $defs behind a $ref. References are
+	// never cyclic.
+	KeywordRef Keyword = "$ref"
+	// KeywordBounds adds minimum/maximum, minLength/maxLength and
+	// minItems/maxItems.
+	KeywordBounds Keyword = "bounds"
+	// KeywordFormat gives strings a format.
+	KeywordFormat Keyword = "format"
+	// KeywordDescription adds descriptions.
+	KeywordDescription Keyword = "description"
+)
+
+// AllKeywords is the keyword mix Config selects by default.
+var AllKeywords = []Keyword{
+	KeywordEnum, KeywordConst, KeywordNullable, KeywordOptional, KeywordAnyOf, KeywordOneOf,
+	KeywordAllOf, KeywordMap, KeywordRef, KeywordBounds, KeywordFormat, KeywordDescription,
+}
+
+// formats are the string formats the generator emits; Instance produces a
+// valid value for each.
+var formats = []string{"date-time", "date", "email", "uuid"}
+
+// Config controls generated schemas.
+type Config struct {
+	// Seed makes the sequence of schemas reproducible.
+	Seed int64
+	// MaxDepth bounds nesting of objects, arrays and combinators below the
+	// root object (default 3).
+	MaxDepth int
+	// MaxWidth bounds properties per object, branches per union and enum
+	// sizes (default 4).
+	MaxWidth int
+	// Keywords is the keyword mix; nil selects AllKeywords. Objects, arrays
+	// and the scalar types are always generated.
+	Keywords []Keyword
+}
+
+// Generator produces random schemas. It is not safe for concurrent use.
+type Generator struct {
+	cfg  Config
+	rng  *rand.Rand
+	on   map[Keyword]bool
+	defs map[string]any
+}
+
+// New returns a Generator for cfg.
+func New(cfg Config) *Generator {
+	if cfg.MaxDepth <= 0 {
+		cfg.MaxDepth = 3
+	}
+	if cfg.MaxWidth <= 0 {
+		cfg.MaxWidth = 4
+	}
+	if cfg.Keywords == nil {
+		cfg.Keywords = AllKeywords
+	}
+	on := make(map[Keyword]bool, len(cfg.Keywords))
+	for _, k := range cfg.Keywords {
+		on[k] = true
+	}
+	return &Generator{cfg: cfg, rng: rand.New(rand.NewSource(cfg.Seed)), on: on}
+}
+
+// Schema returns the next schema. The root is always an object, as
+// structured-output targets require.
+func (g *Generator) Schema() map[string]any {
+	g.defs = map[string]any{}
+	root := g.object(g.cfg.MaxDepth)
+	if len(g.defs) > 0 {
+		root["$defs"] = g.defs
+	}
+	return root
+}
+
+// chance reports whether keyword k is enabled and fires with probability p.
+func (g *Generator) chance(k Keyword, p float64) bool {
+	return g.on[k] && g.rng.Float64() < p
+}
+
+func (g *Generator) width() int {
+	return 1 + g.rng.Intn(g.cfg.MaxWidth)
+}
+
+func (g *Generator) node(depth int) map[string]any {
+	if depth <= 0 || g.rng.Intn(3) == 0 {
+		return g.scalar()
+	}
+	kinds := []func(int) map[string]any{g.object, g.array}
+	for _, c := range []struct {
+		k  Keyword
+		fn func(int) map[string]any
+	}{
+		{KeywordAnyOf, g.anyOf},
+		{KeywordOneOf, g.oneOf},
+		{KeywordAllOf, g.allOf},
+		{KeywordMap, g.dictionary},
+		{KeywordRef, g.ref},
+	} {
+		if g.on[c.k] {
+			kinds = append(kinds, c.fn)
+		}
+	}
+	return kinds[g.rng.Intn(len(kinds))](depth)
+}
+
+func (g *Generator) describe(n map[string]any, what string) map[string]any {
+	if g.chance(KeywordDescription, 0.3) {
+		n["description"] = fmt.Sprintf("A generated %s.", what)
+	}
+	return n
+}
+
+func (g *Generator) scalar() map[string]any {
+	typ := []string{"string", "integer", "number", "boolean"}[g.rng.Intn(4)]
+	n := map[string]any{"type": typ}
+	switch {
+	case g.chance(KeywordConst, 0.1):
+		n["const"] = g.scalarValue(typ, 0)
+		return g.describe(n, "constant")
+	case (typ == "string" || typ == "integer") && g.chance(KeywordEnum, 0.25):
+		size := g.width()
+		enum := make([]any, size)
+		for i := range enum {
+			enum[i] = g.scalarValue(typ, i)
+		}
+		n["enum"] = enum
+	default:
+		g.bounds(n, typ)
+	}
+	if g.chance(KeywordNullable, 0.2) {
+		n["type"] = []any{typ, "null"}
+		if enum, ok := n["enum"].([]any); ok {
+			n["enum"] = append(enum, nil)
+		}
+	}
+	return g.describe(n, typ)
+}
+
+// scalarValue returns the i-th distinct value of typ.
+func (g *Generator) scalarValue(typ string, i int) any {
+	switch typ {
+	case "string":
+		return fmt.Sprintf("v%d", i)
+	case "integer":
+		return float64(i)
+	case "number":
+		return float64(i) + 0.5
+	}
+	return i%2 == 0
+}
+
+func (g *Generator) bounds(n map[string]any, typ string) {
+	switch typ {
+	case "string":
+		if g.chance(KeywordFormat, 0.2) {
+			n["format"] = formats[g.rng.Intn(len(formats))]
+			return
+		}
+		if g.chance(KeywordBounds, 0.3) {
+			lo := g.rng.Intn(4)
+			n["minLength"] = float64(lo)
+			n["maxLength"] = float64(lo + g.rng.Intn(8))
+		}
+	case "integer", "number":
+		if g.chance(KeywordBounds, 0.3) {
+			lo := g.rng.Intn(200) - 100
+			n["minimum"] = float64(lo)
+			n["maximum"] = float64(lo + g.rng.Intn(100))
+		}
+	}
+}
+
+func (g *Generator) object(depth int) map[string]any {
+	return g.objectWith(depth, "p", nil)
+}
+
+// objectWith returns an object whose generated properties are named
+// prefix0, prefix1, …, plus the fixed properties in extra (all required).
+func (g *Generator) objectWith(depth int, prefix string, extra map[string]any) map[string]any {
+	props := map[string]any{}
+	var required []any
+	for name, s := range extra {
+		props[name] = s
+		required = append(required, name)
+	}
+	for i, size := 0, g.width(); i < size; i++ {
+		name := fmt.Sprintf("%s%d", prefix, i)
+		props[name] = g.node(depth - 1)
+		if !g.chance(KeywordOptional, 0.3) {
+			required = append(required, name)
+		}
+	}
+	n := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		n["required"] = required
+	}
+	return g.describe(n, "object")
+}
+
+func (g *Generator) array(depth int) map[string]any {
+	n := map[string]any{"type": "array", "items": g.node(depth - 1)}
+	if g.chance(KeywordBounds, 0.3) {
+		lo := g.rng.Intn(3)
+		n["minItems"] = float64(lo)
+		n["maxItems"] = float64(lo + g.rng.Intn(4))
+	}
+	return g.describe(n, "array")
+}
+
+func (g *Generator) anyOf(depth int) map[string]any {
+	branches := make([]any, 1+g.width())
+	for i := range branches {
+		branches[i] = g.node(depth - 1)
+	}
+	return g.describe(map[string]any{"anyOf": branches}, "union")
+}
+
+// oneOf keeps branches mutually exclusive through a required const "kind",
+// so every value of one branch fails the others.
+func (g *Generator) oneOf(depth int) map[string]any {
+	branches := make([]any, 1+g.width())
+	for i := range branches {
+		kind := map[string]any{"type": "string", "const": fmt.Sprintf("k%d", i)}
+		branches[i] = g.objectWith(depth, "p", map[string]any{"kind": kind})
+	}
+	return g.describe(map[string]any{"oneOf": branches}, "variant")
+}
+
+func (g *Generator) allOf(depth int) map[string]any {
+	return g.describe(map[string]any{"allOf": []any{
+		g.objectWith(depth, "a", nil),
+		g.objectWith(depth, "b", nil),
+	}}, "intersection")
+}
+
+func (g *Generator) dictionary(depth int) map[string]any {
+	return g.describe(map[string]any{"type": "object", "additionalProperties": g.node(depth - 1)}, "map")
+}
+
+func (g *Generator) ref(depth int) map[string]any {
+	target := g.node(depth - 1)
+	name := fmt.Sprintf("Def%d", len(g.defs))
+	g.defs[name] = target
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
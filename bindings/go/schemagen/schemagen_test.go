@@ -0,0 +1,110 @@
+package schemagen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+func compile(t *testing.T, schema map[string]any) *jsonschema.Schema {
+	t.Helper()
+	b, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	s, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatalf("generated schema does not compile: %v\n%s", err, b)
+	}
+	return s
+}
+
+// TestGeneratorDeterministic verifies a seed reproduces the same schemas.
+func TestGeneratorDeterministic(t *testing.T) {
+	a, b := New(Config{Seed: 42}), New(Config{Seed: 42})
+	for i := 0; i < 5; i++ {
+		if x, y := a.Schema(), b.Schema(); !reflect.DeepEqual(x, y) {
+			t.Fatalf("schema %d differs between generators with the same seed", i)
+		}
+	}
+}
+
+// TestGeneratedSchemasSatisfiable verifies generated schemas are valid and
+// Instance produces values they accept.
+func TestGeneratedSchemasSatisfiable(t *testing.T) {
+	g := New(Config{Seed: 1})
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		schema := g.Schema()
+		v := compile(t, schema)
+		data := Instance(schema, rng)
+		if err := v.Validate(data); err != nil {
+			b, _ := json.Marshal(schema)
+			d, _ := json.Marshal(data)
+			t.Fatalf("schema %d rejects its instance: %v\nschema: %s\ndata: %s", i, err, b, d)
+		}
+	}
+}
+
+// TestKeywordMix verifies disabled keywords are never generated.
+func TestKeywordMix(t *testing.T) {
+	g := New(Config{Seed: 7, Keywords: []Keyword{}})
+	for i := 0; i < 50; i++ {
+		b, _ := json.Marshal(g.Schema())
+		for _, kw := range []string{`"anyOf"`, `"oneOf"`, `"allOf"`, `"$ref"`, `"enum"`, `"format"`, `"minimum"`, `"additionalProperties"`} {
+			if strings.Contains(string(b), kw) {
+				t.Fatalf("keyword %s generated with an empty mix: %s", kw, b)
+			}
+		}
+	}
+}
+
+// TestRoundtripInvariant runs Check over generated schemas for every target.
+func TestRoundtripInvariant(t *testing.T) {
+	eng, err := jsl.NewSchemaLlmEngine()
+	if err != nil {
+		t.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	n := 100
+	if testing.Short() {
+		n = 20
+	}
+	for _, target := range []string{jsl.TargetOpenAI, jsl.TargetGemini, jsl.TargetClaude} {
+		t.Run(target, func(t *testing.T) {
+			g := New(Config{Seed: 2024})
+			rng := rand.New(rand.NewSource(2024))
+			for i := 0; i < n; i++ {
+				schema := g.Schema()
+				err := Check(eng, schema, &jsl.ConvertOptions{Target: target}, rng)
+				if err == nil || errors.Is(err, ErrOpaque) {
+					continue
+				}
+				b, _ := json.Marshal(schema)
+				var f *Failure
+				if errors.As(err, &f) && f.Data != nil {
+					d, _ := json.Marshal(f.Data)
+					t.Errorf("schema %d: %v\nschema: %s\ndata: %s", i, err, b, d)
+				} else {
+					t.Errorf("schema %d: %v\nschema: %s", i, err, b)
+				}
+			}
+		})
+	}
+}
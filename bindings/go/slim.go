@@ -0,0 +1,304 @@
+package jsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Slimming transforms. Unlike the other host entries they are recorded
+// against the converted (LLM-facing) schema, so Rehydrate undoes them on the
+// raw model output before the guest runs, like x-custom entries.
+const (
+	transformSlimEnum      = "slim_enum"
+	transformSlimStringify = "slim_stringify"
+	// transformSlimRename shortens property names (AutoFit); Params["renames"]
+	// maps each new name to the original.
+	transformSlimRename = "slim_rename"
+)
+
+// slimTransformTypes are the entry types restored before the guest call.
+var slimTransformTypes = map[string]bool{transformSlimEnum: true, transformSlimStringify: true, transformSlimRename: true}
+
+const (
+	// bytesPerToken approximates provider tokenizers for MaxSchemaTokens.
+	bytesPerToken = 4
+	// slimEnumMin is the smallest enum slimming will collapse.
+	slimEnumMin = 20
+)
+
+// schemaBudget returns the byte budget implied by opts, or 0 when neither
+// MaxSchemaBytes nor MaxSchemaTokens is set.
+func schemaBudget(opts *ConvertOptions) int {
+	if opts == nil {
+		return 0
+	}
+	budget := opts.MaxSchemaBytes
+	if t := opts.MaxSchemaTokens * bytesPerToken; t > 0 && (budget == 0 || t < budget) {
+		budget = t
+	}
+	return budget
+}
+
+// slimSchema shrinks a converted schema until it serializes within budget
+// bytes. It escalates through three stages, stopping as soon as the schema
+// fits: descriptions are cut to their first sentence and then removed
+// (except at the root); enums of slimEnumMin or more values are collapsed to
+// plain strings, largest first; finally object/array subtrees are replaced by
+// JSON-encoded strings, deepest (lowest-priority) first. Nothing under an
+// anyOf/oneOf branch is collapsed or stringified; the union is stringified
+// whole instead. Every sacrifice is
+// reported as a "slimmed" warning; collapses and stringifications record
+// entries Rehydrate uses to check and decode the output.
+func slimSchema(schema map[string]any, budget int) (map[string]any, []HostTransform, []Warning, error) {
+	return slimUntil(schema, true, func() *Error {
+		if size := schemaSize(schema); size > budget {
+			return &Error{
+				Code:    "schema_budget_exceeded",
+				Message: fmt.Sprintf("converted schema is %d bytes after slimming; budget is %d", size, budget),
+			}
+		}
+		return nil
+	})
+}
+
+// slimUntil runs the slimming stages until check reports no error, and
+// returns check's error if the stages run out. The description stages only
+// run with trimDescriptions, since they cannot help checks that ignore
+// descriptions.
+func slimUntil(schema map[string]any, trimDescriptions bool, check func() *Error) (map[string]any, []HostTransform, []Warning, error) {
+	var (
+		entries  []HostTransform
+		warnings []Warning
+	)
+	fits := func() bool { return check() == nil }
+	sacrifice := func(loc, constraint, msg, entryType string) {
+		warnings = append(warnings, slimWarning(loc, constraint, msg, entryType))
+	}
+
+	for _, firstOnly := range []bool{true, false} {
+		if !trimDescriptions || fits() {
+			break
+		}
+		walkSlimmable(schema, "#", func(loc string, node map[string]any) {
+			desc, ok := node["description"].(string)
+			if !ok || loc == "#" && !firstOnly {
+				return
+			}
+			if firstOnly {
+				if short := firstSentence(desc); short != desc {
+					node["description"] = short
+					sacrifice(loc, "description", "description cut to its first sentence", "")
+				}
+				return
+			}
+			delete(node, "description")
+			sacrifice(loc, "description", "description removed", "")
+		})
+	}
+
+	var enums []locatedSchema
+	walkRestorable(schema, "#", func(loc string, node map[string]any) {
+		if enum, ok := node["enum"].([]any); ok && len(enum) >= slimEnumMin && allStrings(enum) {
+			enums = append(enums, locatedSchema{loc, node})
+		}
+	})
+	sort.SliceStable(enums, func(i, j int) bool {
+		return len(enums[i].node["enum"].([]any)) > len(enums[j].node["enum"].([]any))
+	})
+	for _, e := range enums {
+		if fits() {
+			return schema, entries, warnings, nil
+		}
+		values := e.node["enum"].([]any)
+		delete(e.node, "enum")
+		e.node["type"] = "string"
+		entries = append(entries, HostTransform{Type: transformSlimEnum, Path: e.loc, Params: map[string]any{"values": values}})
+		sacrifice(e.loc, "enum", fmt.Sprintf("enum of %d values collapsed to a plain string", len(values)), transformSlimEnum)
+	}
+
+	for {
+		err := check()
+		if err == nil {
+			break
+		}
+		loc, depth := "", -1
+		walkRestorable(schema, "#", func(l string, node map[string]any) {
+			if d := strings.Count(l, "/"); l != "#" && stringifiable(node) && d > depth {
+				loc, depth = l, d
+			}
+		})
+		if loc == "" {
+			return nil, nil, nil, err
+		}
+		replaceAtPointer(schema, loc, map[string]any{
+			"type":        "string",
+			"description": "A JSON-encoded value.",
+		})
+		entries = append(entries, HostTransform{Type: transformSlimStringify, Path: loc})
+		sacrifice(loc, "subtree", "subtree replaced by a JSON-encoded string", transformSlimStringify)
+	}
+	return schema, entries, warnings, nil
+}
+
+// slimWarning reports one slimming step; entryType names the entry it
+// recorded, if any.
+func slimWarning(loc, constraint, msg, entryType string) Warning {
+	w := Warning{
+		SchemaPath: loc,
+		Kind:       WarningKind{Type: WarnSlimmed, Constraint: constraint},
+		Message:    msg,
+	}
+	if entryType != "" {
+		w.EntryID = EntryID(entryType, loc)
+	}
+	return w
+}
+
+// walkSlimmable visits node and the subschemas reachable from it through
+// properties, items and anyOf/oneOf. $defs (recursive definitions) are left
+// alone.
+func walkSlimmable(node map[string]any, loc string, fn func(loc string, node map[string]any)) {
+	walkSlimTree(node, loc, true, fn)
+}
+
+// walkRestorable is walkSlimmable without anyOf/oneOf branches: the
+// locations rehydrate can map back to data unambiguously. A value under a
+// union may have taken any branch, and once a branch is slimmed the
+// branches can no longer be told apart, so entries are only recorded
+// outside them.
+func walkRestorable(node map[string]any, loc string, fn func(loc string, node map[string]any)) {
+	walkSlimTree(node, loc, false, fn)
+}
+
+// stringifiable reports whether the subtree at node is worth replacing by a
+// JSON-encoded string: a container, or a union whose branches slimming does
+// not otherwise touch.
+func stringifiable(node map[string]any) bool {
+	_, anyOf := node["anyOf"]
+	_, oneOf := node["oneOf"]
+	return isContainerSchema(node) || anyOf || oneOf
+}
+
+func walkSlimTree(node map[string]any, loc string, branches bool, fn func(loc string, node map[string]any)) {
+	fn(loc, node)
+	if props, ok := node["properties"].(map[string]any); ok {
+		for _, k := range sortedKeys(props) {
+			if c, ok := props[k].(map[string]any); ok {
+				walkSlimTree(c, childPointer(childPointer(loc, "properties"), k), branches, fn)
+			}
+		}
+	}
+	if c, ok := node["items"].(map[string]any); ok {
+		walkSlimTree(c, childPointer(loc, "items"), branches, fn)
+	}
+	if !branches {
+		return
+	}
+	for _, kw := range []string{"anyOf", "oneOf"} {
+		if bs, ok := node[kw].([]any); ok {
+			for i, b := range bs {
+				if c, ok := b.(map[string]any); ok {
+					walkSlimTree(c, childPointer(childPointer(loc, kw), itoa(i)), branches, fn)
+				}
+			}
+		}
+	}
+}
+
+func schemaSize(schema any) int {
+	b, _ := json.Marshal(schema)
+	return len(b)
+}
+
+// firstSentence returns s up to and including its first sentence terminator
+// followed by whitespace, or s when it has a single sentence.
+func firstSentence(s string) string {
+	for i := 0; i < len(s)-1; i++ {
+		switch s[i] {
+		case '.', '!', '?':
+			if s[i+1] == ' ' || s[i+1] == '\n' {
+				return s[:i+1]
+			}
+		}
+	}
+	return s
+}
+
+func allStrings(values []any) bool {
+	for _, v := range values {
+		if _, ok := v.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// splitSlimEntries separates slimming entries from the other host entries.
+func splitSlimEntries(entries []HostTransform) (rest, slim []HostTransform) {
+	for _, t := range entries {
+		if slimTransformTypes[t.Type] {
+			slim = append(slim, t)
+		} else {
+			rest = append(rest, t)
+		}
+	}
+	return rest, slim
+}
+
+// restoreSlim undoes slimming entries, newest first, on model output still
+// in the converted shape: stringified subtrees are decoded and collapsed
+// enums are checked against their original values.
+func restoreSlim(dataBytes []byte, entries []HostTransform) ([]byte, []Warning, error) {
+	var data any
+	if err := json.Unmarshal(dataBytes, &data); err != nil {
+		return nil, nil, fmt.Errorf("decode data: %w", err)
+	}
+	var warnings []Warning
+	for i := len(entries) - 1; i >= 0; i-- {
+		t := entries[i]
+		warn := func(dataPath, constraint, msg string) {
+			warnings = append(warnings, Warning{
+				DataPath:   dataPath,
+				SchemaPath: t.Path,
+				Kind:       WarningKind{Type: WarnConstraintViolation, Constraint: constraint},
+				Message:    msg,
+				EntryID:    EntryID(t.Type, t.Path),
+			})
+		}
+		data = rejoin(data, "", splitPointer(t.Path), func(v any, dataPath string) any {
+			if t.Type == transformSlimRename {
+				v, _ = restoreRename(v, &t, dataPath)
+				return v
+			}
+			s, ok := v.(string)
+			if !ok {
+				return v
+			}
+			switch t.Type {
+			case transformSlimStringify:
+				var decoded any
+				if err := json.Unmarshal([]byte(s), &decoded); err != nil {
+					warn(dataPath, "contentMediaType", fmt.Sprintf("stringified subtree is not valid JSON: %v", err))
+					return v
+				}
+				return decoded
+			case transformSlimEnum:
+				values, _ := t.Params["values"].([]any)
+				for _, allowed := range values {
+					if allowed == s {
+						return v
+					}
+				}
+				warn(dataPath, "enum", fmt.Sprintf("%q is not one of the %d collapsed enum values", s, len(values)))
+			}
+			return v
+		})
+	}
+	out, err := json.Marshal(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode data: %w", err)
+	}
+	return out, warnings, nil
+}
@@ -0,0 +1,148 @@
+package jsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func slimFixture() map[string]any {
+	enum := make([]any, 30)
+	for i := range enum {
+		enum[i] = fmt.Sprintf("country_%02d", i)
+	}
+	return map[string]any{
+		"type":        "object",
+		"description": "An order. Placed by a customer through the storefront.",
+		"properties": map[string]any{
+			"country": map[string]any{"type": "string", "enum": enum, "description": "Shipping country. Must be supported."},
+			"meta": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"tags": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				},
+				"required":             []any{"tags"},
+				"additionalProperties": false,
+			},
+		},
+		"required":             []any{"country", "meta"},
+		"additionalProperties": false,
+	}
+}
+
+// TestSlimSchemaStages verifies slimming stops at the first stage that fits.
+func TestSlimSchemaStages(t *testing.T) {
+	full := schemaSize(slimFixture())
+
+	schema, entries, warnings, err := slimSchema(slimFixture(), full-10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 || len(warnings) != 2 || warnings[0].Kind.Constraint != "description" {
+		t.Errorf("first-sentence stage: entries %v, warnings %v", entries, warnings)
+	}
+	if got := schema["description"]; got != "An order." {
+		t.Errorf("root description = %v", got)
+	}
+
+	_, entries, _, err = slimSchema(slimFixture(), full-200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Type != transformSlimEnum || entries[0].Path != "#/properties/country" {
+		t.Errorf("enum stage entries = %v", entries)
+	}
+
+	schema, entries, _, err = slimSchema(slimFixture(), 220)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := entries[len(entries)-1]; last.Type != transformSlimStringify {
+		t.Errorf("subtree stage entries = %v", entries)
+	}
+	if size := schemaSize(schema); size > 220 {
+		t.Errorf("slimmed size %d over budget", size)
+	}
+
+	if _, _, _, err := slimSchema(slimFixture(), 10); err == nil {
+		t.Error("impossible budget should fail")
+	}
+}
+
+// TestSlimSchemaSkipsBranches verifies nothing under an anyOf/oneOf branch
+// is collapsed or stringified, since rehydrate could not tell which branch
+// the output took; the union's ancestor is stringified whole instead.
+func TestSlimSchemaSkipsBranches(t *testing.T) {
+	fixture := func() map[string]any {
+		enum := slimFixture()["properties"].(map[string]any)["country"]
+		return map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"v": map[string]any{"anyOf": []any{
+					map[string]any{"type": "string"},
+					map[string]any{"type": "object", "properties": map[string]any{"country": enum}, "required": []any{"country"}, "additionalProperties": false},
+				}},
+			},
+			"required":             []any{"v"},
+			"additionalProperties": false,
+		}
+	}
+	_, entries, _, err := slimSchema(fixture(), schemaSize(fixture())-200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.Contains(e.Path, "/anyOf/") {
+			t.Errorf("entry under a branch: %+v", e)
+		}
+	}
+	if len(entries) != 1 || entries[0].Type != transformSlimStringify || entries[0].Path != "#/properties/v" {
+		t.Errorf("entries = %+v, want the union stringified", entries)
+	}
+
+	// The string branch's value passes through untouched.
+	out, _, err := restoreSlim([]byte(`{"v":"\"42\""}`), entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"v":"42"}` {
+		t.Errorf("restored = %s", out)
+	}
+}
+
+// TestRestoreSlim verifies stringified subtrees are decoded and collapsed enums checked.
+func TestRestoreSlim(t *testing.T) {
+	entries := []HostTransform{
+		{Type: transformSlimEnum, Path: "#/properties/country", Params: map[string]any{"values": []any{"fr", "de"}}},
+		{Type: transformSlimStringify, Path: "#/properties/meta"},
+	}
+	out, warnings, err := restoreSlim([]byte(`{"country":"xx","meta":"{\"tags\":[\"a\"]}"}`), entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"country":"xx","meta":{"tags":["a"]}}` {
+		t.Errorf("restored = %s", out)
+	}
+	if len(warnings) != 1 || warnings[0].Kind.Constraint != "enum" || warnings[0].DataPath != "/country" {
+		t.Errorf("warnings = %+v", warnings)
+	}
+}
+
+// TestSchemaBudget verifies the tighter of the byte and token budgets wins.
+func TestSchemaBudget(t *testing.T) {
+	for _, tc := range []struct {
+		opts *ConvertOptions
+		want int
+	}{
+		{nil, 0},
+		{&ConvertOptions{MaxSchemaBytes: 1000}, 1000},
+		{&ConvertOptions{MaxSchemaTokens: 100}, 400},
+		{&ConvertOptions{MaxSchemaBytes: 300, MaxSchemaTokens: 100}, 300},
+	} {
+		if got := schemaBudget(tc.opts); got != tc.want {
+			b, _ := json.Marshal(tc.opts)
+			t.Errorf("schemaBudget(%s) = %d, want %d", b, got, tc.want)
+		}
+	}
+}
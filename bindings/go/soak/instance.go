@@ -0,0 +1,57 @@
+package soak
+
+// MinimalInstance synthesizes the smallest value a converted (strict) schema
+// accepts: required properties only, empty strings and arrays, zero numbers,
+// the first enum value, and the first anyOf branch. It is enough to drive
+// Rehydrate without a model in the loop.
+func MinimalInstance(schema any) any {
+	node, ok := schema.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if c, ok := node["const"]; ok {
+		return c
+	}
+	if enum, ok := node["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	if branches, ok := node["anyOf"].([]any); ok && len(branches) > 0 {
+		return MinimalInstance(branches[0])
+	}
+
+	typ := node["type"]
+	if types, ok := typ.([]any); ok && len(types) > 0 {
+		typ = types[0]
+	}
+	switch typ {
+	case "object":
+		out := map[string]any{}
+		props, _ := node["properties"].(map[string]any)
+		required, _ := node["required"].([]any)
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				out[name] = MinimalInstance(props[name])
+			}
+		}
+		return out
+	case "array":
+		if n, ok := node["minItems"].(float64); ok && n > 0 {
+			items := make([]any, int(n))
+			for i := range items {
+				items[i] = MinimalInstance(node["items"])
+			}
+			return items
+		}
+		return []any{}
+	case "string":
+		return ""
+	case "integer", "number":
+		if m, ok := node["minimum"].(float64); ok {
+			return m
+		}
+		return 0
+	case "boolean":
+		return false
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+// Package soak runs long convert/rehydrate loops against a jsl engine while
+// tracking memory and latency, and fails the run when leak or regression
+// thresholds are crossed. It is the certification harness for engines that
+// serve traffic around the clock.
+package soak
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// ErrThresholdExceeded is returned (wrapped) by Run when the report has
+// violations.
+var ErrThresholdExceeded = errors.New("soak threshold exceeded")
+
+// Case is one schema in the rotation.
+type Case struct {
+	Name   string
+	Schema any
+	// Data is model output in the converted shape. When nil, a minimal
+	// instance of the converted schema is synthesized once per case.
+	Data any
+}
+
+// Thresholds fail the run when exceeded. Zero disables a check.
+type Thresholds struct {
+	// MaxHeapGrowth bounds Go heap growth (bytes) from the end of warm-up to
+	// the end of the run, measured after a forced GC.
+	MaxHeapGrowth uint64
+	// MaxRSSGrowth bounds resident set growth (bytes) over the same window.
+	// Only enforced where RSS can be read (Linux).
+	MaxRSSGrowth uint64
+	// MaxGuestMemory bounds the engine's guest linear memory peak (bytes).
+	MaxGuestMemory uint32
+	// MaxP99 bounds the 99th-percentile iteration latency.
+	MaxP99 time.Duration
+	// MaxErrorRate bounds failed iterations / total, in [0, 1].
+	MaxErrorRate float64
+}
+
+// Config controls a run. The run stops at whichever of Iterations or
+// Duration is reached first; at least one must be set.
+type Config struct {
+	Iterations int
+	Duration   time.Duration
+	// Warmup iterations are excluded from latency and growth measurements.
+	// Default: 1% of Iterations, capped at 1000.
+	Warmup int
+	// SampleEvery is how often (in iterations) memory is sampled. Default 1000.
+	SampleEvery int
+	// ConvertOptions are passed to every Convert call.
+	ConvertOptions *jsl.ConvertOptions
+	Thresholds     Thresholds
+	// Logf, when set, receives a progress line per memory sample.
+	Logf func(format string, args ...any)
+}
+
+// Report summarizes a run.
+type Report struct {
+	Iterations int
+	Errors     int
+	// FirstError is the first iteration error seen, if any.
+	FirstError error
+
+	P50, P90, P99, Max time.Duration
+
+	HeapStart, HeapEnd uint64
+	RSSStart, RSSEnd   uint64
+	GuestMemoryPeak    uint32
+
+	Violations []string
+}
+
+// latencyReservoir bounds the latency samples kept for percentiles.
+const latencyReservoir = 100_000
+
+// Run executes the soak loop, rotating through cases. It returns the report
+// and, when any threshold is exceeded, an error wrapping
+// ErrThresholdExceeded.
+func Run(ctx context.Context, engine *jsl.SchemaLlmEngine, cases []Case, cfg Config) (*Report, error) {
+	if len(cases) == 0 {
+		return nil, errors.New("soak: no cases")
+	}
+	if cfg.Iterations <= 0 && cfg.Duration <= 0 {
+		return nil, errors.New("soak: set Iterations or Duration")
+	}
+	warmup := cfg.Warmup
+	if warmup == 0 && cfg.Iterations > 0 {
+		warmup = min(cfg.Iterations/100, 1000)
+	}
+	sampleEvery := cfg.SampleEvery
+	if sampleEvery <= 0 {
+		sampleEvery = 1000
+	}
+
+	rep := &Report{}
+	rng := rand.New(rand.NewSource(1))
+	samples := make([]time.Duration, 0, min(max(cfg.Iterations, 1), latencyReservoir))
+	measured := 0
+	var deadline time.Time
+	if cfg.Duration > 0 {
+		deadline = time.Now().Add(cfg.Duration)
+	}
+
+	for i := 0; cfg.Iterations <= 0 || i < cfg.Iterations; i++ {
+		if err := ctx.Err(); err != nil {
+			return rep, err
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			break
+		}
+		if i == warmup {
+			rep.HeapStart, rep.RSSStart = memorySnapshot()
+		}
+
+		c := &cases[i%len(cases)]
+		start := time.Now()
+		err := iterate(engine, c, cfg.ConvertOptions)
+		elapsed := time.Since(start)
+		rep.Iterations++
+		if err != nil {
+			rep.Errors++
+			if rep.FirstError == nil {
+				rep.FirstError = fmt.Errorf("%s: %w", c.Name, err)
+			}
+		}
+
+		if i >= warmup {
+			measured++
+			if len(samples) < latencyReservoir {
+				samples = append(samples, elapsed)
+			} else if j := rng.Intn(measured); j < latencyReservoir {
+				samples[j] = elapsed
+			}
+			if elapsed > rep.Max {
+				rep.Max = elapsed
+			}
+		}
+		if cfg.Logf != nil && (i+1)%sampleEvery == 0 {
+			heap, rss := memorySnapshot()
+			cfg.Logf("soak: %d iterations, %d errors, heap %d B, rss %d B, guest peak %d B",
+				i+1, rep.Errors, heap, rss, engine.GuestMemoryPeak())
+		}
+	}
+	if rep.HeapStart == 0 {
+		rep.HeapStart, rep.RSSStart = memorySnapshot()
+	}
+
+	rep.HeapEnd, rep.RSSEnd = memorySnapshot()
+	rep.GuestMemoryPeak = engine.GuestMemoryPeak()
+	rep.P50, rep.P90, rep.P99 = percentile(samples, 0.50), percentile(samples, 0.90), percentile(samples, 0.99)
+	rep.Violations = check(rep, cfg.Thresholds)
+	if len(rep.Violations) > 0 {
+		return rep, fmt.Errorf("%w: %s", ErrThresholdExceeded, strings.Join(rep.Violations, "; "))
+	}
+	return rep, nil
+}
+
+// iterate runs one convert → rehydrate round trip.
+func iterate(engine *jsl.SchemaLlmEngine, c *Case, opts *jsl.ConvertOptions) error {
+	converted, err := engine.Convert(c.Schema, opts)
+	if err != nil {
+		return fmt.Errorf("convert: %w", err)
+	}
+	if c.Data == nil {
+		c.Data = MinimalInstance(converted.Schema)
+	}
+	if _, err := engine.Rehydrate(c.Data, converted.Codec, c.Schema); err != nil {
+		return fmt.Errorf("rehydrate: %w", err)
+	}
+	return nil
+}
+
+func check(rep *Report, th Thresholds) []string {
+	var v []string
+	if th.MaxHeapGrowth > 0 && rep.HeapEnd > rep.HeapStart && rep.HeapEnd-rep.HeapStart > th.MaxHeapGrowth {
+		v = append(v, fmt.Sprintf("heap grew %d B (max %d B)", rep.HeapEnd-rep.HeapStart, th.MaxHeapGrowth))
+	}
+	if th.MaxRSSGrowth > 0 && rep.RSSStart > 0 && rep.RSSEnd > rep.RSSStart && rep.RSSEnd-rep.RSSStart > th.MaxRSSGrowth {
+		v = append(v, fmt.Sprintf("rss grew %d B (max %d B)", rep.RSSEnd-rep.RSSStart, th.MaxRSSGrowth))
+	}
+	if th.MaxGuestMemory > 0 && rep.GuestMemoryPeak > th.MaxGuestMemory {
+		v = append(v, fmt.Sprintf("guest memory peaked at %d B (max %d B)", rep.GuestMemoryPeak, th.MaxGuestMemory))
+	}
+	if th.MaxP99 > 0 && rep.P99 > th.MaxP99 {
+		v = append(v, fmt.Sprintf("p99 latency %s (max %s)", rep.P99, th.MaxP99))
+	}
+	if th.MaxErrorRate > 0 && rep.Iterations > 0 {
+		if rate := float64(rep.Errors) / float64(rep.Iterations); rate > th.MaxErrorRate {
+			v = append(v, fmt.Sprintf("error rate %.4f (max %.4f)", rate, th.MaxErrorRate))
+		}
+	}
+	return v
+}
+
+// percentile returns the q-quantile of samples (nearest rank). It sorts
+// samples in place.
+func percentile(samples []time.Duration, q float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	idx := int(q*float64(len(samples))+0.5) - 1
+	return samples[max(0, min(idx, len(samples)-1))]
+}
+
+// memorySnapshot returns the live Go heap after a GC and the process RSS
+// (0 where it cannot be read).
+func memorySnapshot() (heap, rss uint64) {
+	runtime.GC()
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.HeapAlloc, readRSS()
+}
+
+// readRSS reads VmRSS from /proc/self/status.
+func readRSS() uint64 {
+	b, err := os.ReadFile("/proc/self/status")
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		if rest, ok := strings.CutPrefix(line, "VmRSS:"); ok {
+			fields := strings.Fields(rest)
+			if len(fields) == 0 {
+				return 0
+			}
+			kb, err := strconv.ParseUint(fields[0], 10, 64)
+			if err != nil {
+				return 0
+			}
+			return kb * 1024
+		}
+	}
+	return 0
+}
@@ -0,0 +1,78 @@
+package soak
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+)
+
+// TestPercentile verifies nearest-rank percentiles.
+func TestPercentile(t *testing.T) {
+	samples := make([]time.Duration, 100)
+	for i := range samples {
+		samples[i] = time.Duration(100-i) * time.Millisecond
+	}
+	if got := percentile(samples, 0.5); got != 50*time.Millisecond {
+		t.Errorf("p50 = %s", got)
+	}
+	if got := percentile(samples, 0.99); got != 99*time.Millisecond {
+		t.Errorf("p99 = %s", got)
+	}
+	if got := percentile(nil, 0.99); got != 0 {
+		t.Errorf("empty p99 = %s", got)
+	}
+}
+
+// TestMinimalInstance verifies the synthesized value satisfies required keys, enums and nullable types.
+func TestMinimalInstance(t *testing.T) {
+	var schema map[string]any
+	_ = json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"kind": {"type": "string", "enum": ["a", "b"]},
+			"tags": {"type": "array", "items": {"type": "string"}, "minItems": 1},
+			"note": {"type": ["null", "string"]},
+			"age": {"type": "integer", "minimum": 18}
+		},
+		"required": ["kind", "tags", "note", "age"]
+	}`), &schema)
+	got, _ := json.Marshal(MinimalInstance(schema))
+	want := `{"age":18,"kind":"a","note":null,"tags":[""]}`
+	if string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+// TestCheckThresholds verifies each threshold produces a violation.
+func TestCheckThresholds(t *testing.T) {
+	rep := &Report{Iterations: 100, Errors: 5, HeapStart: 1 << 20, HeapEnd: 3 << 20, P99: time.Second, GuestMemoryPeak: 1 << 30}
+	v := check(rep, Thresholds{MaxHeapGrowth: 1 << 20, MaxGuestMemory: 1 << 20, MaxP99: time.Millisecond, MaxErrorRate: 0.01})
+	if len(v) != 4 {
+		t.Errorf("expected 4 violations, got %v", v)
+	}
+}
+
+// TestRunShortSoak runs a brief soak against the real engine.
+func TestRunShortSoak(t *testing.T) {
+	eng, err := jsl.NewSchemaLlmEngine()
+	if err != nil {
+		t.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	cases := []Case{
+		{Name: "simple", Schema: map[string]any{"type": "object", "properties": map[string]any{"a": map[string]any{"type": "string"}}}},
+		{Name: "map", Schema: map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "integer"}}},
+	}
+	rep, err := Run(context.Background(), eng, cases, Config{Iterations: 50, Thresholds: Thresholds{MaxErrorRate: 0.0001}})
+	if err != nil && !errors.Is(err, ErrThresholdExceeded) {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if rep.Iterations != 50 || rep.Errors != 0 {
+		t.Errorf("report = %+v (first error: %v)", rep, rep.FirstError)
+	}
+}
@@ -0,0 +1,234 @@
+package jsl
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// transformSplitObject records an object partitioned into part1..partN
+// sub-objects. Params["parts"] lists the property names in each part;
+// Rehydrate merges the parts back into one object.
+const transformSplitObject = "split_object"
+
+// splitThreshold returns the per-object property cap: MaxObjectProperties,
+// else the target's ProviderLimits.MaxObjectProperties.
+func splitThreshold(opts *ConvertOptions) int {
+	if opts.MaxObjectProperties > 0 {
+		return opts.MaxObjectProperties
+	}
+	return convertLimits(opts).MaxObjectProperties
+}
+
+// splitLargeObjects partitions every object with more than splitThreshold
+// properties into sub-objects of at most that many properties each, in
+// name order. Dictionary-like objects (schema-valued additionalProperties
+// or patternProperties) are left alone, as are objects whose other keywords
+// could not follow their properties into the parts (see splittable).
+func splitLargeObjects(schema any, opts *ConvertOptions) (any, []HostTransform, error) {
+	limit := splitThreshold(opts)
+	if limit < 2 {
+		return nil, nil, fmt.Errorf("MaxObjectProperties must be at least 2, got %d", limit)
+	}
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		props, ok := node["properties"].(map[string]any)
+		if !ok || len(props) <= limit {
+			return node
+		}
+		if _, dict := node["additionalProperties"].(map[string]any); dict {
+			return node
+		}
+		if _, dict := node["patternProperties"]; dict {
+			return node
+		}
+		names := sortedKeys(props)
+		var parts []any
+		for len(names) > 0 {
+			n := min(limit, len(names))
+			chunk := make([]any, n)
+			for i, name := range names[:n] {
+				chunk[i] = name
+			}
+			parts = append(parts, chunk)
+			names = names[n:]
+		}
+		if !splittable(node, parts) {
+			return node
+		}
+		t := HostTransform{Type: transformSplitObject, Path: loc, Params: map[string]any{"parts": parts}}
+		entries = append(entries, t)
+		return rewriteSplitObject(node, &t)
+	})
+	return schema, entries, nil
+}
+
+// splitBlockers are keywords that constrain an object's properties
+// together. After a split they would constrain the part names instead, and
+// no per-part rewrite keeps their meaning, so an object carrying one is not
+// split.
+var splitBlockers = []string{
+	"minProperties", "maxProperties", "dependentSchemas", "unevaluatedProperties",
+	"if", "then", "else", "allOf", "anyOf", "oneOf", "not",
+}
+
+// splittable reports whether node can be partitioned into parts without
+// losing a constraint: it carries no splitBlockers, every required name is
+// a declared property, and each dependentRequired (or array-valued
+// dependencies) entry names properties that land in a single part.
+func splittable(node map[string]any, parts []any) bool {
+	for _, kw := range splitBlockers {
+		if _, ok := node[kw]; ok {
+			return false
+		}
+	}
+	partOf := map[string]int{}
+	for i, p := range parts {
+		for _, n := range p.([]any) {
+			partOf[n.(string)] = i
+		}
+	}
+	if req, ok := node["required"].([]any); ok {
+		for _, r := range req {
+			name, _ := r.(string)
+			if _, ok := partOf[name]; !ok {
+				return false
+			}
+		}
+	}
+	for _, kw := range []string{"dependentRequired", "dependencies"} {
+		deps, ok := node[kw]
+		if !ok {
+			continue
+		}
+		m, ok := deps.(map[string]any)
+		if !ok {
+			return false
+		}
+		for name, d := range m {
+			names, ok := d.([]any)
+			if !ok {
+				return false
+			}
+			part, ok := partOf[name]
+			if !ok {
+				return false
+			}
+			for _, n := range names {
+				dep, _ := n.(string)
+				if p, ok := partOf[dep]; !ok || p != part {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func splitPartName(i int) string {
+	return "part" + strconv.Itoa(i+1)
+}
+
+func rewriteSplitObject(n any, t *HostTransform) any {
+	node, ok := n.(map[string]any)
+	if !ok {
+		return n
+	}
+	props, _ := node["properties"].(map[string]any)
+	required := map[string]bool{}
+	if req, ok := node["required"].([]any); ok {
+		for _, r := range req {
+			if s, ok := r.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+	parts, _ := t.Params["parts"].([]any)
+	outer := make(map[string]any, len(parts))
+	outerRequired := make([]any, 0, len(parts))
+	for i, p := range parts {
+		names, _ := p.([]any)
+		partProps := make(map[string]any, len(names))
+		var partRequired []any
+		for _, n := range names {
+			name, _ := n.(string)
+			if sub, ok := props[name]; ok {
+				partProps[name] = sub
+			}
+			if required[name] {
+				partRequired = append(partRequired, name)
+			}
+		}
+		part := map[string]any{"type": "object", "properties": partProps}
+		if len(partRequired) > 0 {
+			part["required"] = partRequired
+		}
+		if ap, ok := node["additionalProperties"]; ok {
+			part["additionalProperties"] = ap
+		}
+		// propertyNames holds for each part's subset of the names, and
+		// splittable guarantees each dependency stays within one part.
+		if pn, ok := node["propertyNames"]; ok {
+			part["propertyNames"] = pn
+		}
+		for _, kw := range []string{"dependentRequired", "dependencies"} {
+			deps, _ := node[kw].(map[string]any)
+			partDeps := map[string]any{}
+			for _, n := range names {
+				name, _ := n.(string)
+				if d, ok := deps[name]; ok {
+					partDeps[name] = d
+				}
+			}
+			if len(partDeps) > 0 {
+				part[kw] = partDeps
+			}
+		}
+		outer[splitPartName(i)] = part
+		outerRequired = append(outerRequired, splitPartName(i))
+	}
+	node["properties"] = outer
+	node["required"] = outerRequired
+	delete(node, "propertyNames")
+	delete(node, "dependentRequired")
+	delete(node, "dependencies")
+	return node
+}
+
+// restoreSplitObject merges the returned parts into a single object.
+func restoreSplitObject(v any, t *HostTransform, dataPath string) (any, []Warning) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return v, nil
+	}
+	parts, _ := t.Params["parts"].([]any)
+	merged := map[string]any{}
+	var warnings []Warning
+	for i := range parts {
+		name := splitPartName(i)
+		part, ok := obj[name].(map[string]any)
+		if !ok {
+			if obj[name] != nil {
+				warnings = append(warnings, Warning{
+					DataPath:   childDataPath(dataPath, name),
+					SchemaPath: t.Path,
+					Kind:       WarningKind{Type: WarnConstraintViolation, Constraint: "type"},
+					Message:    fmt.Sprintf("split part %s is not an object", name),
+				})
+			}
+			continue
+		}
+		for k, val := range part {
+			merged[k] = val
+		}
+	}
+	return merged, warnings
+}
+
+func splitObjectConvertWarning(t *HostTransform) (Warning, bool) {
+	parts, _ := t.Params["parts"].([]any)
+	return Warning{
+		SchemaPath: t.Path,
+		Kind:       WarningKind{Type: WarnObjectSplit},
+		Message:    fmt.Sprintf("object split into %d parts to stay within the per-object property limit", len(parts)),
+	}, true
+}
@@ -0,0 +1,107 @@
+package jsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestSplitObjectRoundTrip verifies an oversized object is partitioned with
+// per-part required lists and merged back on rehydrate.
+func TestSplitObjectRoundTrip(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"description": "A record.",
+		"properties": {
+			"a": {"type": "string"}, "b": {"type": "string"}, "c": {"type": "string"},
+			"d": {"type": "string"}, "e": {"type": "string"}
+		},
+		"required": ["a", "d"],
+		"additionalProperties": false
+	}`)
+	out, entries, err := splitLargeObjects(deepCopyJSON(schema), &ConvertOptions{MaxObjectProperties: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(out)
+	want := `{"additionalProperties":false,"description":"A record.","properties":{` +
+		`"part1":{"additionalProperties":false,"properties":{"a":{"type":"string"},"b":{"type":"string"}},"required":["a"],"type":"object"},` +
+		`"part2":{"additionalProperties":false,"properties":{"c":{"type":"string"},"d":{"type":"string"}},"required":["d"],"type":"object"},` +
+		`"part3":{"additionalProperties":false,"properties":{"e":{"type":"string"}},"type":"object"}},` +
+		`"required":["part1","part2","part3"],"type":"object"}`
+	if string(got) != want {
+		t.Errorf("split = %s", got)
+	}
+
+	entries = roundtripEntries(t, entries)
+	stages := hostStages(schema, entries)
+	data := decodeJSON(t, `{"part1": {"a": "1", "b": "2"}, "part2": {"d": "4"}, "part3": {"e": "5"}}`)
+	restored, warnings := restoreHost(data, stages, entries)
+	if got, _ := json.Marshal(restored); string(got) != `{"a":"1","b":"2","d":"4","e":"5"}` || len(warnings) != 0 {
+		t.Errorf("restored = %s, warnings = %+v", got, warnings)
+	}
+}
+
+// TestSplitObjectsRejectsTinyLimit guards against a limit that cannot partition.
+func TestSplitObjectsRejectsTinyLimit(t *testing.T) {
+	if _, _, err := splitLargeObjects(map[string]any{}, &ConvertOptions{MaxObjectProperties: 1}); err == nil {
+		t.Error("MaxObjectProperties 1 should fail")
+	}
+}
+
+// TestSplitObjectMovesKeywords verifies propertyNames and dependencies
+// that stay within one part follow the properties into the parts.
+func TestSplitObjectMovesKeywords(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {"a": {"type": "string"}, "b": {"type": "string"}, "c": {"type": "string"}},
+		"propertyNames": {"maxLength": 8},
+		"dependentRequired": {"a": ["b"]},
+		"dependencies": {"c": []}
+	}`)
+	out, entries, err := splitLargeObjects(schema, &ConvertOptions{MaxObjectProperties: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries = %+v, want one split", entries)
+	}
+	got, _ := json.Marshal(out)
+	want := `{"properties":{` +
+		`"part1":{"dependentRequired":{"a":["b"]},"properties":{"a":{"type":"string"},"b":{"type":"string"}},"propertyNames":{"maxLength":8},"type":"object"},` +
+		`"part2":{"dependencies":{"c":[]},"properties":{"c":{"type":"string"}},"propertyNames":{"maxLength":8},"type":"object"}},` +
+		`"required":["part1","part2"],"type":"object"}`
+	if string(got) != want {
+		t.Errorf("split = %s", got)
+	}
+}
+
+// TestSplitObjectRefuses verifies objects whose constraints cannot follow
+// their properties into parts are left whole.
+func TestSplitObjectRefuses(t *testing.T) {
+	props := `"properties": {"a": {"type": "string"}, "b": {"type": "string"}, "c": {"type": "string"}}`
+	for name, extra := range map[string]string{
+		"undeclared required":   `"required": ["a", "z"]`,
+		"cross-part dependency": `"dependentRequired": {"a": ["c"]}`,
+		"undeclared dependency": `"dependentRequired": {"z": ["a"]}`,
+		"schema dependency":     `"dependencies": {"a": {"required": ["b"]}}`,
+		"dependentSchemas":      `"dependentSchemas": {"a": {"required": ["c"]}}`,
+		"minProperties":         `"minProperties": 2`,
+		"maxProperties":         `"maxProperties": 2`,
+		"if/then":               `"if": {"required": ["a"]}, "then": {"required": ["c"]}`,
+		"allOf":                 `"allOf": [{"required": ["a"]}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			schema := decodeJSON(t, `{"type": "object", `+props+`, `+extra+`}`)
+			out, entries, err := splitLargeObjects(deepCopyJSON(schema), &ConvertOptions{MaxObjectProperties: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("entries = %+v, want none", entries)
+			}
+			if got, _ := json.Marshal(out); string(got) != string(mustMarshal(schema)) {
+				t.Errorf("schema = %s, want it unchanged", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package jsl
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// TargetLimits returns the documented limits of a conversion target, or
+// zero ProviderLimits (no checks) for targets without published limits.
+func TargetLimits(target string) ProviderLimits {
+	switch target {
+	case "", defaultTarget:
+		return OpenAIStrictLimits
+	}
+	return ProviderLimits{}
+}
+
+// convertLimits returns the limits Convert enforces for opts.
+func convertLimits(opts *ConvertOptions) ProviderLimits {
+	if opts != nil && opts.Limits != nil {
+		return *opts.Limits
+	}
+	return TargetLimits(targetOf(opts))
+}
+
+// nodeStringLength counts the characters node contributes to
+// MaxStringLength: its property and definition names and its string enum
+// and const values.
+func nodeStringLength(node map[string]any) int {
+	n := 0
+	for _, kw := range []string{"properties", "$defs", "definitions"} {
+		if m, ok := node[kw].(map[string]any); ok {
+			for k := range m {
+				n += utf8.RuneCountInString(k)
+			}
+		}
+	}
+	if enum, ok := node["enum"].([]any); ok {
+		for _, v := range enum {
+			if s, ok := v.(string); ok {
+				n += utf8.RuneCountInString(s)
+			}
+		}
+	}
+	if s, ok := node["const"].(string); ok {
+		n += utf8.RuneCountInString(s)
+	}
+	return n
+}
+
+// checkTargetLimits walks a converted schema in a fixed order, keeping
+// running totals, and reports the first limit crossed with the pointer
+// where it was crossed.
+func checkTargetLimits(schema map[string]any, l ProviderLimits) *Error {
+	var (
+		props, enums, chars int
+		found               *Error
+	)
+	violate := func(loc, limit string, actual, max int) {
+		found = &Error{
+			Code:    "target_limit_exceeded",
+			Message: fmt.Sprintf("%s exceeded: %d > %d", limit, actual, max),
+			Path:    loc,
+		}
+	}
+	if l.MaxSchemaBytes > 0 {
+		if size := schemaSize(schema); size > l.MaxSchemaBytes {
+			violate("#", "MaxSchemaBytes", size, l.MaxSchemaBytes)
+			return found
+		}
+	}
+	// depth is the number of object/array levels above node.
+	var walk func(loc string, node map[string]any, depth int)
+	walk = func(loc string, node map[string]any, depth int) {
+		level := depth
+		if isContainerSchema(node) {
+			level++
+		}
+		if l.MaxNestingDepth > 0 && level > l.MaxNestingDepth {
+			violate(loc, "MaxNestingDepth", level, l.MaxNestingDepth)
+			return
+		}
+		chars += nodeStringLength(node)
+		if l.MaxStringLength > 0 && chars > l.MaxStringLength {
+			violate(loc, "MaxStringLength", chars, l.MaxStringLength)
+			return
+		}
+		if enum, ok := node["enum"].([]any); ok {
+			if enums += len(enum); l.MaxEnumValues > 0 && enums > l.MaxEnumValues {
+				violate(loc, "MaxEnumValues", enums, l.MaxEnumValues)
+				return
+			}
+		}
+		propsNode, _ := node["properties"].(map[string]any)
+		if l.MaxObjectProperties > 0 && len(propsNode) > l.MaxObjectProperties {
+			violate(loc, "MaxObjectProperties", len(propsNode), l.MaxObjectProperties)
+			return
+		}
+		for _, k := range sortedKeys(propsNode) {
+			ploc := childPointer(childPointer(loc, "properties"), k)
+			if n := utf8.RuneCountInString(k); l.MaxNameLength > 0 && n > l.MaxNameLength {
+				violate(ploc, "MaxNameLength", n, l.MaxNameLength)
+				return
+			}
+			if props++; l.MaxProperties > 0 && props > l.MaxProperties {
+				violate(ploc, "MaxProperties", props, l.MaxProperties)
+				return
+			}
+		}
+		for _, k := range sortedKeys(propsNode) {
+			if c, ok := propsNode[k].(map[string]any); ok && found == nil {
+				walk(childPointer(childPointer(loc, "properties"), k), c, level)
+			}
+		}
+		if c, ok := node["items"].(map[string]any); ok && found == nil {
+			walk(childPointer(loc, "items"), c, level)
+		}
+		for _, kw := range []string{"anyOf", "oneOf", "allOf"} {
+			branches, _ := node[kw].([]any)
+			for i, b := range branches {
+				if c, ok := b.(map[string]any); ok && found == nil {
+					walk(childPointer(childPointer(loc, kw), itoa(i)), c, depth)
+				}
+			}
+		}
+		for _, kw := range []string{"$defs", "definitions"} {
+			defs, _ := node[kw].(map[string]any)
+			for _, k := range sortedKeys(defs) {
+				if c, ok := defs[k].(map[string]any); ok && found == nil {
+					walk(childPointer(childPointer(loc, kw), k), c, 0)
+				}
+			}
+		}
+	}
+	walk("#", schema, 0)
+	return found
+}
+
+// autoFitSchema remediates limit violations in a converted schema: property
+// names over MaxNameLength are shortened (outside anyOf/oneOf branches),
+// then enums are collapsed and subtrees stringified (see slimUntil) until
+// checkTargetLimits passes.
+func autoFitSchema(schema map[string]any, l ProviderLimits) (map[string]any, []HostTransform, []Warning, error) {
+	var (
+		entries  []HostTransform
+		warnings []Warning
+	)
+	if l.MaxNameLength > 0 {
+		walkRestorable(schema, "#", func(loc string, node map[string]any) {
+			props, ok := node["properties"].(map[string]any)
+			if !ok {
+				return
+			}
+			renames := map[string]any{}
+			for _, k := range sortedKeys(props) {
+				if utf8.RuneCountInString(k) <= l.MaxNameLength {
+					continue
+				}
+				short := truncateRunes(k, l.MaxNameLength)
+				for i := 2; props[short] != nil || renames[short] != nil; i++ {
+					suffix := "_" + itoa(i)
+					short = truncateRunes(k, l.MaxNameLength-len(suffix)) + suffix
+				}
+				renames[short] = k
+			}
+			if len(renames) == 0 {
+				return
+			}
+			t := HostTransform{Type: transformSlimRename, Path: loc, Params: map[string]any{"renames": renames}}
+			rewriteRename(node, &t)
+			entries = append(entries, t)
+			warnings = append(warnings, slimWarning(loc, "propertyName", fmt.Sprintf("%d property name(s) shortened to %d characters", len(renames), l.MaxNameLength), transformSlimRename))
+		})
+	}
+	schema, slimEntries, slimWarnings, err := slimUntil(schema, false, func() *Error {
+		return checkTargetLimits(schema, l)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return schema, append(entries, slimEntries...), append(warnings, slimWarnings...), nil
+}
+
+func truncateRunes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	i := 0
+	for pos := range s {
+		if i == n {
+			return s[:pos]
+		}
+		i++
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+package jsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestCheckTargetLimits verifies each limit reports the pointer where it is crossed.
+func TestCheckTargetLimits(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"a": {"type": "string", "enum": ["x", "y", "z"]},
+			"b": {"type": "object", "properties": {"c": {"type": "array", "items": {"type": "object", "properties": {"d": {"type": "string"}}}}}},
+			"a_very_long_property_name": {"type": "string"}
+		}
+	}`).(map[string]any)
+	for _, tc := range []struct {
+		limits ProviderLimits
+		path   string
+		msg    string
+	}{
+		{ProviderLimits{MaxProperties: 4}, "#/properties/b/properties/c/items/properties/d", "MaxProperties exceeded: 5 > 4"},
+		{ProviderLimits{MaxNestingDepth: 3}, "#/properties/b/properties/c/items", "MaxNestingDepth exceeded: 4 > 3"},
+		{ProviderLimits{MaxEnumValues: 2}, "#/properties/a", "MaxEnumValues exceeded: 3 > 2"},
+		{ProviderLimits{MaxNameLength: 10}, "#/properties/a_very_long_property_name", "MaxNameLength exceeded: 25 > 10"},
+		{ProviderLimits{MaxStringLength: 28}, "#/properties/a", "MaxStringLength exceeded: 30 > 28"},
+	} {
+		err := checkTargetLimits(schema, tc.limits)
+		if err == nil || err.Code != "target_limit_exceeded" || err.Path != tc.path || err.Message != tc.msg {
+			t.Errorf("%+v: err = %+v", tc.limits, err)
+		}
+	}
+	if err := checkTargetLimits(schema, OpenAIStrictLimits); err != nil {
+		t.Errorf("openai limits: %v", err)
+	}
+}
+
+// TestAutoFitRoundTrip verifies shortened names and collapsed enums fit the
+// limits and are undone on the model output.
+func TestAutoFitRoundTrip(t *testing.T) {
+	values := make([]string, 30)
+	for i := range values {
+		values[i] = fmt.Sprintf("%q", fmt.Sprintf("v%02d", i))
+	}
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"customer_billing_address_line": {"type": "string"},
+			"customer_billing_address_city": {"type": "string"},
+			"code": {"type": "string", "enum": [`+strings.Join(values, ",")+`]}
+		},
+		"required": ["customer_billing_address_line", "customer_billing_address_city", "code"]
+	}`).(map[string]any)
+	limits := ProviderLimits{MaxNameLength: 16, MaxEnumValues: 10}
+	out, entries, warnings, err := autoFitSchema(schema, limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkTargetLimits(out, limits); err != nil {
+		t.Fatalf("still over limits: %v", err)
+	}
+	props := out["properties"].(map[string]any)
+	if _, ok := props["customer_billi_2"]; !ok || len(entries) != 2 || len(warnings) != 2 {
+		t.Fatalf("props = %v, entries = %+v", sortedKeys(props), entries)
+	}
+
+	data := mustMarshal(map[string]any{"customer_billing": "Main St", "customer_billi_2": "Springfield", "code": "v07"})
+	restored, ws, err := restoreSlim(data, roundtripEntries(t, entries))
+	if err != nil || len(ws) != 0 {
+		t.Fatalf("restore: %v %+v", err, ws)
+	}
+	var got map[string]any
+	_ = json.Unmarshal(restored, &got)
+	if got["customer_billing_address_line"] != "Springfield" || got["customer_billing_address_city"] != "Main St" || got["code"] != "v07" {
+		t.Errorf("restored = %v", got)
+	}
+}
@@ -0,0 +1,202 @@
+package jsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// validToolName is the tool name format OpenAI, Anthropic and Gemini all
+// accept.
+var validToolName = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// ConvertToolsResult is the result of ConvertTools.
+type ConvertToolsResult struct {
+	// Defs holds the definitions of the whole tool set after merging: one
+	// entry per distinct definition, keyed by its shared name.
+	Defs map[string]any `json:"defs,omitempty"`
+	// Tools holds one entry per tool, sorted by name.
+	Tools []ConvertedTool `json:"tools"`
+}
+
+// ConvertedTool is one converted tool of a ConvertTools set. It marshals as
+// a function definition ({"name", "description", "parameters"}).
+type ConvertedTool struct {
+	Name string `json:"name"`
+	// Description is the input schema's root description.
+	Description string `json:"description,omitempty"`
+	// Parameters is Result.SchemaJSON, the schema to send as the tool's
+	// parameters (input_schema for Anthropic).
+	Parameters json.RawMessage `json:"parameters"`
+	// Schema is the input schema passed to Convert, with only the shared
+	// $defs it reaches. Pass it to Rehydrate with Result.Codec; the $defs
+	// names in the codec are the shared ones, not the caller's.
+	Schema map[string]any `json:"-"`
+	// Result is the tool's full conversion result.
+	Result *ConvertResult `json:"-"`
+}
+
+// ConvertTools converts a set of tool input schemas, keyed by tool name,
+// with the same options. Each schema's definitions ($defs, "definitions"
+// and copies of other local ref targets, as Bundle lays them out) are
+// merged into one namespace first: a definition several tools declare
+// identically keeps its name and converts the same way everywhere, and one
+// that differs from an earlier tool's (in name order), or refers back to
+// its tool's root, is renamed "<tool>_<name>" when the name is taken. Tool
+// names must be 1-64 letters, digits, '_' or '-'. ConvertOptions.Overrides
+// keys address the merged layout.
+func (e *SchemaLlmEngine) ConvertTools(schemas map[string]any, opts *ConvertOptions) (*ConvertToolsResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		if !validToolName.MatchString(name) {
+			return nil, &Error{Code: "invalid_tool_name", Message: fmt.Sprintf("tool name %q must be 1-64 letters, digits, '_' or '-'", name)}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	roots := make(map[string]map[string]any, len(names))
+	for _, name := range names {
+		decoded, err := decodeForDiff(schemas[name])
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: decode schema: %w", name, err)
+		}
+		root, ok := decoded.(map[string]any)
+		if !ok {
+			return nil, &Error{Code: "schema_error", Message: fmt.Sprintf("tool %s: root schema must be an object", name)}
+		}
+		if err := bundleLocalRefs(root); err != nil {
+			return nil, fmt.Errorf("tool %s: %w", name, err)
+		}
+		pruneDefs(root)
+		roots[name] = root
+	}
+	shared := mergeToolDefs(names, roots)
+
+	result := &ConvertToolsResult{Tools: make([]ConvertedTool, 0, len(names))}
+	if len(shared) > 0 {
+		result.Defs = shared
+	}
+	for _, name := range names {
+		root := roots[name]
+		if len(shared) > 0 {
+			root["$defs"] = deepCopyJSON(shared)
+			pruneDefs(root)
+			if defs, _ := root["$defs"].(map[string]any); len(defs) == 0 {
+				delete(root, "$defs")
+			}
+		}
+		converted, err := e.Convert(root, opts)
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: %w", name, err)
+		}
+		desc, _ := root["description"].(string)
+		result.Tools = append(result.Tools, ConvertedTool{
+			Name:        name,
+			Description: desc,
+			Parameters:  converted.SchemaJSON,
+			Schema:      root,
+			Result:      converted,
+		})
+	}
+	return result, nil
+}
+
+// mergeToolDefs moves the $defs of every root (visited in names order) into
+// one namespace, rewriting refs to renamed definitions, and returns it.
+// A definition joins an existing entry of the same name only when it is
+// identical after its own refs are renamed; renames can make more
+// definitions differ, so they are settled to a fixed point per tool.
+// Definitions with a "#" ref point at their own tool's root and are never
+// joined.
+func mergeToolDefs(names []string, roots map[string]map[string]any) map[string]any {
+	shared := map[string]any{}
+	pinned := map[string]bool{} // shared names bound to one tool's root
+	for _, tool := range names {
+		root := roots[tool]
+		defs, _ := root["$defs"].(map[string]any)
+		delete(root, "$defs")
+		if len(defs) == 0 {
+			continue
+		}
+		rename := make(map[string]string, len(defs))
+		rootBound := map[string]bool{}
+		for name, def := range defs {
+			rename[name] = name
+			walkRefs(def, func(ref string) {
+				if ref == "#" {
+					rootBound[name] = true
+				}
+			})
+		}
+		for changed := true; changed; {
+			changed = false
+			for _, name := range sortedKeys(defs) {
+				existing, ok := shared[rename[name]]
+				if !ok || !rootBound[name] && !pinned[rename[name]] &&
+					reflect.DeepEqual(existing, renameDefRefs(deepCopyJSON(defs[name]), rename)) {
+					continue
+				}
+				rename[name] = freeToolDefName(shared, rename, tool+"_"+name)
+				changed = true
+			}
+		}
+		for _, name := range sortedKeys(defs) {
+			if to := rename[name]; shared[to] == nil {
+				shared[to] = renameDefRefs(defs[name], rename)
+				pinned[to] = rootBound[name]
+			}
+		}
+		renameDefRefs(root, rename)
+	}
+	return shared
+}
+
+// freeToolDefName picks a name neither shared nor already a rename target.
+func freeToolDefName(shared map[string]any, rename map[string]string, name string) string {
+	taken := func(n string) bool {
+		if shared[n] != nil {
+			return true
+		}
+		for _, to := range rename {
+			if to == n {
+				return true
+			}
+		}
+		return false
+	}
+	candidate := name
+	for i := 2; taken(candidate); i++ {
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+	return candidate
+}
+
+// renameDefRefs rewrites every "#/$defs/<name>" ref under node per rename,
+// in place, and returns node.
+func renameDefRefs(node any, rename map[string]string) any {
+	switch t := node.(type) {
+	case map[string]any:
+		if ref, ok := t["$ref"].(string); ok && strings.HasPrefix(ref, "#/$defs/") {
+			if segs := splitPointer(ref); len(segs) == 2 && rename[segs[1]] != "" {
+				t["$ref"] = "#/$defs/" + escapePointerSegment(rename[segs[1]])
+			}
+		}
+		for k, c := range t {
+			if k != "$ref" {
+				renameDefRefs(c, rename)
+			}
+		}
+	case []any:
+		for _, c := range t {
+			renameDefRefs(c, rename)
+		}
+	}
+	return node
+}
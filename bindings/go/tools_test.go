@@ -0,0 +1,79 @@
+package jsl
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestMergeToolDefs verifies identical definitions are shared, conflicting
+// ones renamed with their refs, and root-bound ones never joined.
+func TestMergeToolDefs(t *testing.T) {
+	roots := map[string]map[string]any{
+		"create_user": decodeJSON(t, `{
+			"type": "object",
+			"properties": {"address": {"$ref": "#/$defs/Address"}, "id": {"$ref": "#/$defs/Id"}},
+			"$defs": {
+				"Address": {"type": "object", "properties": {"city": {"type": "string"}}},
+				"Id": {"type": "string"}
+			}
+		}`).(map[string]any),
+		"ship_order": decodeJSON(t, `{
+			"type": "object",
+			"properties": {"to": {"$ref": "#/$defs/Address"}, "id": {"$ref": "#/$defs/Id"}},
+			"$defs": {
+				"Address": {"type": "object", "properties": {"city": {"type": "string"}}},
+				"Id": {"type": "integer"}
+			}
+		}`).(map[string]any),
+		"tree": decodeJSON(t, `{
+			"type": "object",
+			"properties": {"children": {"$ref": "#/$defs/Address"}},
+			"$defs": {"Address": {"type": "array", "items": {"$ref": "#"}}}
+		}`).(map[string]any),
+	}
+	shared := mergeToolDefs([]string{"create_user", "ship_order", "tree"}, roots)
+
+	if got, want := sortedKeys(shared), []string{"Address", "Id", "ship_order_Id", "tree_Address"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("shared defs = %v, want %v", got, want)
+	}
+	for _, tt := range []struct{ tool, ptr, want string }{
+		{"create_user", "#/properties/id/$ref", "#/$defs/Id"},
+		{"ship_order", "#/properties/to/$ref", "#/$defs/Address"},
+		{"ship_order", "#/properties/id/$ref", "#/$defs/ship_order_Id"},
+		{"tree", "#/properties/children/$ref", "#/$defs/tree_Address"},
+	} {
+		if got, _ := lookupPointer(roots[tt.tool], tt.ptr); got != tt.want {
+			t.Errorf("%s %s = %v, want %s", tt.tool, tt.ptr, got, tt.want)
+		}
+	}
+	if _, ok := roots["tree"]["$defs"]; ok {
+		t.Error("$defs left on a root")
+	}
+}
+
+// TestMergeToolDefsRenamesDependents verifies a definition whose ref target
+// was renamed no longer joins its namesake.
+func TestMergeToolDefsRenamesDependents(t *testing.T) {
+	roots := map[string]map[string]any{
+		"a": decodeJSON(t, `{"$ref": "#/$defs/Order", "$defs": {"Order": {"properties": {"id": {"$ref": "#/$defs/Id"}}}, "Id": {"type": "string"}}}`).(map[string]any),
+		"b": decodeJSON(t, `{"$ref": "#/$defs/Order", "$defs": {"Order": {"properties": {"id": {"$ref": "#/$defs/Id"}}}, "Id": {"type": "integer"}}}`).(map[string]any),
+	}
+	shared := mergeToolDefs([]string{"a", "b"}, roots)
+	if got, _ := lookupPointer(shared, "#/b_Order/properties/id/$ref"); got != "#/$defs/b_Id" {
+		t.Errorf("b_Order id ref = %v (shared %v)", got, sortedKeys(shared))
+	}
+	if roots["b"]["$ref"] != "#/$defs/b_Order" {
+		t.Errorf("b root ref = %v", roots["b"]["$ref"])
+	}
+}
+
+// TestConvertToolsInvalidName verifies tool names are checked before any
+// conversion.
+func TestConvertToolsInvalidName(t *testing.T) {
+	var e *SchemaLlmEngine
+	_, err := e.ConvertTools(map[string]any{"get weather": map[string]any{"type": "object"}}, nil)
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("err = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,78 @@
+package jsl
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/dotslashderek/json-schema-llm/bindings/go"
+
+// WithTracerProvider wraps Convert, Rehydrate and ExtractComponent in spans
+// from tp, carrying the input's SchemaFingerprint ("jsl.schema_hash") and
+// per-operation counters (target, passes, codec transforms, warnings).
+// Spans are children of the span in the engine's context; see WithContext.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *engineConfig) {
+		c.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// WithContext returns a shallow copy of e whose guest calls use ctx and
+// whose spans are children of the span in ctx, so one engine can serve
+// traced requests one at a time. The copy's retry waits (see
+// WithRetryPolicy) end when ctx is done. The copy shares e's runtime: Close only
+// one of them.
+func (e *SchemaLlmEngine) WithContext(ctx context.Context) *SchemaLlmEngine {
+	c := *e
+	c.ctx = ctx
+	return &c
+}
+
+// startSpan starts a span for op over schema, or returns nil when tracing
+// is off so callers skip the attribute work.
+func (e *SchemaLlmEngine) startSpan(op string, schema any, attrs ...attribute.KeyValue) trace.Span {
+	if e == nil || e.tracer == nil {
+		return nil
+	}
+	if hash, err := SchemaFingerprint(schema); err == nil {
+		attrs = append(attrs, attribute.String("jsl.schema_hash", hash))
+	}
+	_, span := e.tracer.Start(e.ctx, "jsl."+op, trace.WithAttributes(attrs...))
+	return span
+}
+
+// endSpan records err and attrs (the operation's result counters) on span
+// and ends it. A nil span is a no-op.
+func endSpan(span trace.Span, err error, attrs ...attribute.KeyValue) {
+	if span == nil {
+		return
+	}
+	span.SetAttributes(attrs...)
+	if err != nil {
+		var jslErr *Error
+		if errors.As(err, &jslErr) {
+			span.SetAttributes(attribute.String("jsl.error_code", jslErr.Code))
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// convertSpanAttrs are the result counters recorded on a Convert span.
+func (e *SchemaLlmEngine) convertSpanAttrs(result *ConvertResult) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.Int("jsl.custom_passes", len(e.customPasses))}
+	if result == nil {
+		return attrs
+	}
+	if entries, err := CodecEntries(result.Codec); err == nil {
+		attrs = append(attrs, attribute.Int("jsl.codec_transforms", len(entries)))
+	}
+	return append(attrs,
+		attribute.Int("jsl.warnings", len(result.Warnings)),
+		attribute.Int("jsl.unsupported", len(result.Unsupported)))
+}
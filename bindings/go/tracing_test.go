@@ -0,0 +1,79 @@
+package jsl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func spanAttrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	m := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+// TestWithTracerProvider verifies Convert records a span with the schema
+// hash, target and result counters.
+func TestWithTracerProvider(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	eng, err := NewSchemaLlmEngine(WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))))
+	if err != nil {
+		t.Fatalf("NewSchemaLlmEngine() failed: %v", err)
+	}
+	defer eng.Close()
+
+	schema := json.RawMessage(`{"type":"object","properties":{"a":{"type":"string"}}}`)
+	if _, err := eng.Convert(schema, nil); err != nil {
+		t.Fatal(err)
+	}
+	spans := rec.Ended()
+	if len(spans) != 1 || spans[0].Name() != "jsl.Convert" {
+		t.Fatalf("spans = %v", spans)
+	}
+	hash, _ := SchemaFingerprint(schema)
+	attrs := spanAttrs(spans[0])
+	if attrs["jsl.schema_hash"].AsString() != hash || attrs["jsl.target"].AsString() != "openai-strict" {
+		t.Errorf("attributes = %v", attrs)
+	}
+	if _, ok := attrs["jsl.codec_transforms"]; !ok {
+		t.Errorf("missing jsl.codec_transforms in %v", attrs)
+	}
+}
+
+// TestSpanParentAndError verifies spans nest under the WithContext span and
+// carry the error code and status of a failed operation.
+func TestSpanParentAndError(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+	e := (&SchemaLlmEngine{ctx: context.Background(), tracer: tp.Tracer(tracerName)}).WithContext(ctx)
+
+	span := e.startSpan("ExtractComponent", json.RawMessage(`{"type":"object"}`), attribute.String("jsl.pointer", "#/x"))
+	endSpan(span, fmt.Errorf("extract: %w", &Error{Code: "unresolvable_ref", Message: "no #/x"}))
+	parent.End()
+
+	got := rec.Ended()[0]
+	if got.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("span is not a child of the context span")
+	}
+	if got.Status().Code != codes.Error || spanAttrs(got)["jsl.error_code"].AsString() != "unresolvable_ref" {
+		t.Errorf("status %v, attributes %v", got.Status(), spanAttrs(got))
+	}
+}
+
+// TestSpansDisabled verifies engines without a tracer skip span work.
+func TestSpansDisabled(t *testing.T) {
+	e := &SchemaLlmEngine{ctx: context.Background()}
+	if span := e.startSpan("Convert", map[string]any{}); span != nil {
+		t.Error("startSpan returned a span without a tracer")
+	}
+	endSpan(nil, nil)
+}
@@ -0,0 +1,69 @@
+package jsl
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// transformSummaryLimit caps TransformRecord.Before and After, in runes.
+const transformSummaryLimit = 120
+
+// TransformRecord is one modification conversion made to the schema
+// (ConvertOptions.RecordTransforms).
+type TransformRecord struct {
+	// Pointer locates the modified node in the schema the pass received
+	// ("#/properties/tags").
+	Pointer string `json:"pointer"`
+	// Pass names the pass that made the change, as in PassSnapshot.Pass.
+	Pass string `json:"pass"`
+	// Op is "add", "remove" or "replace".
+	Op string `json:"op"`
+	// Before and After summarize the node's compact JSON, cut to 120
+	// characters; Before is empty for "add" and After for "remove".
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// transformRecords diffs each snapshot against the schema before it,
+// starting from original, and attributes every change to its pass.
+func transformRecords(original []byte, snapshots []PassSnapshot) ([]TransformRecord, error) {
+	prev, err := decodeForDiff(original)
+	if err != nil {
+		return nil, fmt.Errorf("decode schema: %w", err)
+	}
+	var records []TransformRecord
+	for _, s := range snapshots {
+		next, err := decodeForDiff(s.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("pass %s: %w", s.Pass, err)
+		}
+		var ops []PatchOp
+		if err := diffJSON(prev, next, "", &ops); err != nil {
+			return nil, fmt.Errorf("pass %s: %w", s.Pass, err)
+		}
+		for _, op := range ops {
+			r := TransformRecord{Pointer: ParsePointer(op.Path).String(), Pass: s.Pass, Op: op.Op}
+			if op.Op != "add" {
+				if old, ok := lookupPointer(prev, op.Path); ok {
+					b, _ := json.Marshal(old)
+					r.Before = summarizeJSON(b)
+				}
+			}
+			if op.Op != "remove" {
+				r.After = summarizeJSON(op.Value)
+			}
+			records = append(records, r)
+		}
+		prev = next
+	}
+	return records, nil
+}
+
+// summarizeJSON shortens raw to transformSummaryLimit runes.
+func summarizeJSON(raw []byte) string {
+	runes := []rune(string(raw))
+	if len(runes) <= transformSummaryLimit {
+		return string(runes)
+	}
+	return string(runes[:transformSummaryLimit-1]) + "…"
+}
@@ -0,0 +1,46 @@
+package jsl
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestTransformRecords verifies changes are attributed to the pass whose
+// snapshot introduced them and summarize the node before and after.
+func TestTransformRecords(t *testing.T) {
+	original := []byte(`{"type": "object", "properties": {"a": {"type": "string", "not": {"const": "x"}}, "m": {"type": "object", "additionalProperties": {"type": "string"}}}}`)
+	snapshots := []PassSnapshot{
+		{Pass: "not", Schema: decodeJSON(t, `{"type": "object", "properties": {"a": {"type": "string"}, "m": {"type": "object", "additionalProperties": {"type": "string"}}}}`).(map[string]any)},
+		{Pass: "normalize", Schema: decodeJSON(t, `{"type": "object", "properties": {"a": {"type": "string"}, "m": {"type": "object", "additionalProperties": {"type": "string"}}}}`).(map[string]any)},
+		{Pass: "dictionary", Schema: decodeJSON(t, `{"type": "object", "properties": {"a": {"type": "string"}, "m": {"type": "array"}}, "additionalProperties": false}`).(map[string]any)},
+	}
+	records, err := transformRecords(original, snapshots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range records {
+		got = append(got, fmt.Sprintf("%s %s %s %s -> %s", r.Pass, r.Op, r.Pointer, r.Before, r.After))
+	}
+	want := []string{
+		`not remove #/properties/a/not {"const":"x"} -> `,
+		`dictionary add #/additionalProperties  -> false`,
+		`dictionary remove #/properties/m/additionalProperties {"type":"string"} -> `,
+		`dictionary replace #/properties/m/type "object" -> "array"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("records =\n%s", strings.Join(got, "\n"))
+	}
+}
+
+// TestSummarizeJSON verifies long values are cut with an ellipsis.
+func TestSummarizeJSON(t *testing.T) {
+	long := []byte(`"` + strings.Repeat("x", 200) + `"`)
+	if s := summarizeJSON(long); len([]rune(s)) != transformSummaryLimit || !strings.HasSuffix(s, "…") {
+		t.Errorf("summary = %q", s)
+	}
+	if s := summarizeJSON([]byte(`{"a":1}`)); s != `{"a":1}` {
+		t.Errorf("summary = %q", s)
+	}
+}
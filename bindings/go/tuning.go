@@ -0,0 +1,242 @@
+package jsl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Outcome is the result of one convert → generate → rehydrate → validate run.
+type Outcome struct {
+	// Passed reports whether the rehydrated output validated against the
+	// original schema.
+	Passed bool
+	// Warnings is the number of rehydration warnings.
+	Warnings int
+}
+
+// DefaultMinRuns is the number of recorded runs an option set needs before
+// OutcomeRecorder.Recommend trusts its pass rate.
+const DefaultMinRuns = 5
+
+// OutcomeRecorder accumulates outcomes per schema fingerprint and option set
+// and recommends the option set with the best observed pass rate. It is
+// opt-in, in-memory, and safe for concurrent use.
+type OutcomeRecorder struct {
+	// MinRuns overrides DefaultMinRuns when positive.
+	MinRuns int
+
+	mu    sync.Mutex
+	stats map[string]map[string]*optionStats // fingerprint → options key → stats
+}
+
+type optionStats struct {
+	options  map[string]any
+	runs     int
+	passed   int
+	warnings int
+}
+
+func (s *optionStats) passRate() float64 {
+	if s.runs == 0 {
+		return 0
+	}
+	return float64(s.passed) / float64(s.runs)
+}
+
+// NewOutcomeRecorder returns an empty recorder.
+func NewOutcomeRecorder() *OutcomeRecorder {
+	return &OutcomeRecorder{stats: map[string]map[string]*optionStats{}}
+}
+
+// RecordOutcome records one run of the schema with the given fingerprint
+// (see SchemaFingerprint) converted with opts.
+func (r *OutcomeRecorder) RecordOutcome(fingerprint string, opts *ConvertOptions, outcome Outcome) {
+	snap := optionsSnapshot(opts)
+	key := optionsKey(snap)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bySet := r.stats[fingerprint]
+	if bySet == nil {
+		bySet = map[string]*optionStats{}
+		r.stats[fingerprint] = bySet
+	}
+	s := bySet[key]
+	if s == nil {
+		s = &optionStats{options: snap}
+		bySet[key] = s
+	}
+	s.runs++
+	s.warnings += outcome.Warnings
+	if outcome.Passed {
+		s.passed++
+	}
+}
+
+// Recommendation suggests an option change for one schema.
+type Recommendation struct {
+	// Options is the recommended option set, as non-zero fields by name.
+	Options map[string]any
+	// Changes lists the field changes from the current options, e.g.
+	// "DescribeNumericBounds: false → true".
+	Changes []string
+	// CurrentRate and RecommendedRate are observed pass rates in [0, 1];
+	// CurrentRate is -1 when the current options have too few runs.
+	CurrentRate     float64
+	RecommendedRate float64
+	// Runs is the number of runs behind RecommendedRate.
+	Runs int
+}
+
+// String renders the recommendation for logs and reports.
+func (rec *Recommendation) String() string {
+	current := "untested"
+	if rec.CurrentRate >= 0 {
+		current = fmt.Sprintf("%.0f%%", rec.CurrentRate*100)
+	}
+	return fmt.Sprintf("%s for this schema; pass rate %s→%.0f%% in %d recorded runs",
+		strings.Join(rec.Changes, ", "), current, rec.RecommendedRate*100, rec.Runs)
+}
+
+// Recommend returns the best recorded option set for the schema when it
+// beats current's pass rate (or current has too few runs). Ties prefer more
+// runs, then fewer warnings per run.
+func (r *OutcomeRecorder) Recommend(fingerprint string, current *ConvertOptions) (*Recommendation, bool) {
+	minRuns := r.MinRuns
+	if minRuns <= 0 {
+		minRuns = DefaultMinRuns
+	}
+	cur := optionsSnapshot(current)
+	curKey := optionsKey(cur)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bySet := r.stats[fingerprint]
+
+	keys := make([]string, 0, len(bySet))
+	for k := range bySet {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var best *optionStats
+	var bestKey string
+	for _, k := range keys {
+		s := bySet[k]
+		if s.runs < minRuns {
+			continue
+		}
+		if best == nil || betterStats(s, best) {
+			best, bestKey = s, k
+		}
+	}
+	if best == nil || bestKey == curKey {
+		return nil, false
+	}
+	currentRate := -1.0
+	if s := bySet[curKey]; s != nil && s.runs >= minRuns {
+		if s.passRate() >= best.passRate() {
+			return nil, false
+		}
+		currentRate = s.passRate()
+	}
+	return &Recommendation{
+		Options:         best.options,
+		Changes:         optionChanges(cur, best.options),
+		CurrentRate:     currentRate,
+		RecommendedRate: best.passRate(),
+		Runs:            best.runs,
+	}, true
+}
+
+func betterStats(a, b *optionStats) bool {
+	if a.passRate() != b.passRate() {
+		return a.passRate() > b.passRate()
+	}
+	if a.runs != b.runs {
+		return a.runs > b.runs
+	}
+	return float64(a.warnings)/float64(a.runs) < float64(b.warnings)/float64(b.runs)
+}
+
+// optionsSnapshot captures every non-zero exported ConvertOptions field,
+// including host-only ones that are not serialized, keyed by field name.
+func optionsSnapshot(opts *ConvertOptions) map[string]any {
+	out := map[string]any{}
+	if opts == nil {
+		return out
+	}
+	v := reflect.ValueOf(*opts)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := v.Field(i)
+		if !t.Field(i).IsExported() || f.IsZero() {
+			continue
+		}
+		switch f.Kind() {
+		case reflect.Pointer:
+			f = f.Elem()
+		case reflect.Interface, reflect.Func:
+			// Behavioral hooks (e.g. RefResolver) are identified by type.
+			out[t.Field(i).Name] = fmt.Sprintf("%T", f.Interface())
+			continue
+		}
+		out[t.Field(i).Name] = f.Interface()
+	}
+	return out
+}
+
+func optionsKey(snap map[string]any) string {
+	b, _ := json.Marshal(snap) // map keys are sorted
+	return string(b)
+}
+
+// optionChanges describes how to get from one snapshot to another.
+func optionChanges(from, to map[string]any) []string {
+	names := map[string]bool{}
+	for k := range from {
+		names[k] = true
+	}
+	for k := range to {
+		names[k] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for k := range names {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var changes []string
+	for _, k := range sorted {
+		a, b := optionValue(from, k), optionValue(to, k)
+		if a != b {
+			changes = append(changes, fmt.Sprintf("%s: %s → %s", k, a, b))
+		}
+	}
+	return changes
+}
+
+func optionValue(snap map[string]any, name string) string {
+	v, ok := snap[name]
+	if !ok {
+		return "unset"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// OptionsFingerprint is a stable hex digest of the non-zero fields of opts,
+// including host-only fields, for keying cached conversions.
+func OptionsFingerprint(opts *ConvertOptions) string {
+	sum := sha256.Sum256([]byte(optionsKey(optionsSnapshot(opts))))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,47 @@
+package jsl
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestOutcomeRecorderRecommends verifies the recommender picks the better option set once it has enough runs.
+func TestOutcomeRecorderRecommends(t *testing.T) {
+	r := NewOutcomeRecorder()
+	base := &ConvertOptions{Target: "openai-strict"}
+	tuned := &ConvertOptions{Target: "openai-strict", DescribeNumericBounds: true}
+
+	record := func(opts *ConvertOptions, passed, failed int) {
+		for i := 0; i < passed; i++ {
+			r.RecordOutcome("fp", opts, Outcome{Passed: true})
+		}
+		for i := 0; i < failed; i++ {
+			r.RecordOutcome("fp", opts, Outcome{Passed: false, Warnings: 2})
+		}
+	}
+
+	record(base, 5, 3)
+	record(tuned, 3, 0)
+	if _, ok := r.Recommend("fp", base); ok {
+		t.Error("should not recommend an option set with fewer than MinRuns runs")
+	}
+
+	record(tuned, 6, 1)
+	rec, ok := r.Recommend("fp", base)
+	if !ok {
+		t.Fatal("expected a recommendation")
+	}
+	if len(rec.Changes) != 1 || rec.Changes[0] != "DescribeNumericBounds: unset → true" {
+		t.Errorf("Changes = %v", rec.Changes)
+	}
+	if got := rec.String(); !strings.Contains(got, "pass rate 62%→90% in 10 recorded runs") {
+		t.Errorf("String() = %q", got)
+	}
+
+	if _, ok := r.Recommend("fp", tuned); ok {
+		t.Error("should not recommend when current options are already best")
+	}
+	if _, ok := r.Recommend("other", base); ok {
+		t.Error("should not recommend for an unknown schema")
+	}
+}
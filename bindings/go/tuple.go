@@ -0,0 +1,190 @@
+package jsl
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TupleStrategy selects how positional tuples (prefixItems, or draft-07
+// array-form items) are rewritten before conversion.
+type TupleStrategy string
+
+const (
+	// TupleStrategyNone leaves tuples to the guest (default).
+	TupleStrategyNone TupleStrategy = ""
+	// TupleStrategyObject rewrites a tuple into an object with positional
+	// keys item_0, item_1, … (plus "rest" for open tuples), all required.
+	TupleStrategyObject TupleStrategy = "object"
+	// TupleStrategyArray rewrites a tuple into a uniform array whose items
+	// are the anyOf of the positional schemas, bounded by minItems/maxItems.
+	TupleStrategyArray TupleStrategy = "array"
+)
+
+const (
+	transformTupleObject = "tuple_to_object"
+	transformTupleArray  = "tuple_to_array"
+
+	tupleItemPrefix = "item_"
+	tupleRestKey    = "rest"
+)
+
+// transpileTuples rewrites every tuple schema according to opts.TupleStrategy.
+func transpileTuples(schema any, opts *ConvertOptions) (any, []HostTransform, error) {
+	typ := transformTupleObject
+	switch opts.TupleStrategy {
+	case TupleStrategyObject:
+	case TupleStrategyArray:
+		typ = transformTupleArray
+	default:
+		return nil, nil, fmt.Errorf("unknown TupleStrategy %q", opts.TupleStrategy)
+	}
+	rewrite := hostHandlers[typ].rewrite
+
+	var entries []HostTransform
+	schema = walkSchema(schema, func(loc string, node map[string]any) any {
+		prefix, _ := tupleParts(node)
+		if prefix == nil {
+			return node
+		}
+		t := HostTransform{Type: typ, Path: loc, Params: map[string]any{"length": len(prefix)}}
+		entries = append(entries, t)
+		return rewrite(node, &t)
+	})
+	return schema, entries, nil
+}
+
+// tupleParts returns the positional schemas of a tuple node and the schema
+// for trailing items (nil when the tuple is closed).
+func tupleParts(node map[string]any) (prefix []any, rest any) {
+	if p, ok := node["prefixItems"].([]any); ok {
+		rest = node["items"]
+		if b, ok := rest.(bool); ok && !b {
+			rest = nil
+		}
+		return p, rest
+	}
+	if p, ok := node["items"].([]any); ok {
+		rest = node["additionalItems"]
+		if b, ok := rest.(bool); ok && !b {
+			rest = nil
+		}
+		return p, rest
+	}
+	return nil, nil
+}
+
+// tupleAnnotations are carried over from the tuple node to its replacement.
+var tupleAnnotations = []string{"title", "description", "$comment"}
+
+func rewriteTupleObject(n any, _ *HostTransform) any {
+	node, ok := n.(map[string]any)
+	if !ok {
+		return n
+	}
+	prefix, rest := tupleParts(node)
+	props := make(map[string]any, len(prefix)+1)
+	required := make([]any, 0, len(prefix)+1)
+	for i, s := range prefix {
+		key := tupleItemPrefix + strconv.Itoa(i)
+		props[key] = s
+		required = append(required, key)
+	}
+	if rest != nil {
+		props[tupleRestKey] = map[string]any{"type": "array", "items": rest}
+		required = append(required, tupleRestKey)
+	}
+	out := map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"required":             required,
+		"additionalProperties": false,
+	}
+	copyAnnotations(node, out)
+	return out
+}
+
+func rewriteTupleArray(n any, _ *HostTransform) any {
+	node, ok := n.(map[string]any)
+	if !ok {
+		return n
+	}
+	prefix, rest := tupleParts(node)
+	branches := append([]any{}, prefix...)
+	if rest != nil {
+		branches = append(branches, rest)
+	}
+	out := map[string]any{
+		"type":     "array",
+		"items":    map[string]any{"anyOf": branches},
+		"minItems": len(prefix),
+	}
+	if rest == nil {
+		out["maxItems"] = len(prefix)
+	}
+	copyAnnotations(node, out)
+	return out
+}
+
+func copyAnnotations(from, to map[string]any) {
+	for _, kw := range tupleAnnotations {
+		if v, ok := from[kw]; ok {
+			to[kw] = v
+		}
+	}
+}
+
+// restoreTupleObject rebuilds the positional array from item_N keys.
+func restoreTupleObject(v any, t *HostTransform, dataPath string) (any, []Warning) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return v, nil
+	}
+	keys := make([]int, 0, len(obj))
+	for k := range obj {
+		if idx, err := strconv.Atoi(strings.TrimPrefix(k, tupleItemPrefix)); err == nil && strings.HasPrefix(k, tupleItemPrefix) {
+			keys = append(keys, idx)
+		}
+	}
+	sort.Ints(keys)
+	out := make([]any, 0, len(keys))
+	var warnings []Warning
+	for i, idx := range keys {
+		if idx != i {
+			warnings = append(warnings, Warning{
+				DataPath:   dataPath,
+				SchemaPath: t.Path,
+				Kind:       WarningKind{Type: WarnTupleGap},
+				Message:    fmt.Sprintf("tuple position %d missing from model output", i),
+			})
+			break
+		}
+		out = append(out, obj[tupleItemPrefix+strconv.Itoa(idx)])
+	}
+	if rest, ok := obj[tupleRestKey].([]any); ok {
+		out = append(out, rest...)
+	}
+	return out, warnings
+}
+
+// restoreTupleArray checks the uniform array still has the tuple's length.
+func restoreTupleArray(v any, t *HostTransform, dataPath string) (any, []Warning) {
+	arr, ok := v.([]any)
+	if !ok {
+		return v, nil
+	}
+	length, _ := t.Params["length"].(float64)
+	if n, ok := t.Params["length"].(int); ok {
+		length = float64(n)
+	}
+	if len(arr) >= int(length) {
+		return v, nil
+	}
+	return v, []Warning{{
+		DataPath:   dataPath,
+		SchemaPath: t.Path,
+		Kind:       WarningKind{Type: WarnTupleGap},
+		Message:    fmt.Sprintf("tuple has %d items, expected at least %d", len(arr), int(length)),
+	}}
+}
@@ -0,0 +1,79 @@
+package jsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestTupleObjectRoundtrip verifies nested tuples are rewritten to objects and restored in order.
+func TestTupleObjectRoundtrip(t *testing.T) {
+	schema := decodeJSON(t, `{
+		"type": "object",
+		"properties": {
+			"segment": {
+				"description": "start and end points",
+				"prefixItems": [
+					{"prefixItems": [{"type": "number"}, {"type": "number"}], "items": false},
+					{"prefixItems": [{"type": "number"}, {"type": "number"}], "items": false}
+				],
+				"items": false
+			},
+			"tagged": {"prefixItems": [{"type": "string"}], "items": {"type": "integer"}}
+		}
+	}`)
+
+	converted, entries, err := transpileTuples(deepCopyJSON(schema), &ConvertOptions{TupleStrategy: TupleStrategyObject})
+	if err != nil {
+		t.Fatalf("transpileTuples() failed: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 tuple entries, got %d: %+v", len(entries), entries)
+	}
+	segment, _ := lookupPointer(converted, "#/properties/segment")
+	if seg := segment.(map[string]any); seg["type"] != "object" || seg["description"] != "start and end points" {
+		t.Errorf("segment should be an object keeping its description, got %v", seg)
+	}
+	if _, ok := lookupPointer(converted, "#/properties/segment/properties/item_1/properties/item_0"); !ok {
+		t.Error("inner tuple should be rewritten under item_1")
+	}
+	if _, ok := lookupPointer(converted, "#/properties/tagged/properties/rest"); !ok {
+		t.Error("open tuple should gain a rest property")
+	}
+
+	stages := hostStages(schema, entries)
+	if got, _ := json.Marshal(stages[len(stages)-1]); string(got) != string(mustMarshal(converted)) {
+		t.Errorf("re-derived schema differs from converted schema:\n  got:  %s\n  want: %s", got, mustMarshal(converted))
+	}
+
+	data := decodeJSON(t, `{
+		"segment": {"item_0": {"item_0": 0, "item_1": 1}, "item_1": {"item_0": 2, "item_1": 3}},
+		"tagged": {"item_0": "x", "rest": [1, 2]}
+	}`)
+	restored, warnings := restoreHost(data, stages, entries)
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %+v", warnings)
+	}
+	want := `{"segment":[[0,1],[2,3]],"tagged":["x",1,2]}`
+	if got, _ := json.Marshal(restored); string(got) != want {
+		t.Errorf("restored:\n  got:  %s\n  want: %s", got, want)
+	}
+}
+
+// TestTupleArrayStrategy verifies the uniform-array rewrite and its length check.
+func TestTupleArrayStrategy(t *testing.T) {
+	schema := decodeJSON(t, `{"type": "array", "items": [{"type": "string"}, {"type": "integer"}]}`)
+
+	converted, entries, err := transpileTuples(deepCopyJSON(schema), &ConvertOptions{TupleStrategy: TupleStrategyArray})
+	if err != nil {
+		t.Fatalf("transpileTuples() failed: %v", err)
+	}
+	want := `{"items":{"anyOf":[{"type":"string"},{"type":"integer"}]},"maxItems":2,"minItems":2,"type":"array"}`
+	if got := string(mustMarshal(converted)); got != want {
+		t.Errorf("converted:\n  got:  %s\n  want: %s", got, want)
+	}
+
+	_, warnings := restoreHost(decodeJSON(t, `["only"]`), hostStages(schema, entries), entries)
+	if len(warnings) != 1 || warnings[0].Kind.Type != "tuple_gap" {
+		t.Errorf("expected one tuple_gap warning, got %+v", warnings)
+	}
+}
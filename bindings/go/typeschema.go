@@ -0,0 +1,378 @@
+package jsl
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchemaOf returns the JSON Schema for T's encoding/json form, built by
+// reflection. Struct fields follow their json tags (name, "-", omitempty);
+// fields without omitempty are required, and pointer fields without it
+// are nullable. Structs are closed (additionalProperties: false), embedded
+// structs are flattened, and recursive types are emitted under $defs.
+//
+// A `jsonschema` tag adds keywords to a field, comma-separated (a literal
+// comma is written `\\,` inside the tag's quoted value):
+//
+//	Status string `json:"status" jsonschema:"description=Order state,enum=open|closed"`
+//
+// Keys are description, title, format, pattern, enum ("|"-separated),
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, minLength,
+// maxLength, minItems and maxItems, plus the flags required and optional
+// to override the omitempty rule.
+func SchemaOf[T any]() (map[string]any, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	b := &typeSchemaBuilder{root: t, building: map[reflect.Type]bool{}, recursive: map[reflect.Type]bool{}, names: map[reflect.Type]string{}, defs: map[string]any{}}
+	s, err := b.schema(t)
+	if err != nil {
+		return nil, err
+	}
+	if len(b.defs) > 0 {
+		s["$defs"] = b.defs
+	}
+	return s, nil
+}
+
+// ConvertType converts SchemaOf[T]. Pass SchemaOf[T] as the original
+// schema to Rehydrate.
+func ConvertType[T any](e *SchemaLlmEngine, opts *ConvertOptions) (*ConvertResult, error) {
+	schema, err := SchemaOf[T]()
+	if err != nil {
+		return nil, err
+	}
+	return e.Convert(schema, opts)
+}
+
+// RehydrateInto rehydrates data and decodes the result into a T. Decoding
+// is strict: a key T has no field for is an error, so a schema that has
+// drifted from T is caught here rather than silently dropped.
+func RehydrateInto[T any](e *SchemaLlmEngine, data, codec, schema any) (T, []Warning, error) {
+	var out T
+	result, err := e.Rehydrate(data, codec, schema)
+	if err != nil {
+		return out, nil, err
+	}
+	b, err := json.Marshal(result.Data)
+	if err != nil {
+		return out, result.Warnings, fmt.Errorf("marshal rehydrated data: %w", err)
+	}
+	if err := decodeStrict(b, &out); err != nil {
+		return out, result.Warnings, fmt.Errorf("decode into %T: %w", out, err)
+	}
+	return out, result.Warnings, nil
+}
+
+func decodeStrict(b []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage(nil))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+type typeSchemaBuilder struct {
+	root      reflect.Type
+	building  map[reflect.Type]bool
+	recursive map[reflect.Type]bool
+	names     map[reflect.Type]string
+	defs      map[string]any
+}
+
+func (b *typeSchemaBuilder) schema(t reflect.Type) (map[string]any, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}, nil
+	case t == rawMessageType:
+		return map[string]any{}, nil
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		// Custom encodings are opaque to reflection.
+		return map[string]any{}, nil
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return map[string]any{"type": "string"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]any{"type": "integer", "minimum": 0}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Interface:
+		return map[string]any{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := b.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		s := map[string]any{"type": "array", "items": items}
+		if t.Kind() == reflect.Array {
+			s["minItems"], s["maxItems"] = t.Len(), t.Len()
+		}
+		return s, nil
+	case reflect.Map:
+		switch t.Key().Kind() {
+		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			return nil, fmt.Errorf("schema of %s: unsupported map key type %s", t, t.Key())
+		}
+		values, err := b.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+	case reflect.Struct:
+		return b.structSchema(t)
+	}
+	return nil, fmt.Errorf("schema of %s: unsupported kind %s", t, t.Kind())
+}
+
+// structSchema builds a struct inline, or a $ref when t is already being
+// built higher up (recursion). Recursive types end up in $defs.
+func (b *typeSchemaBuilder) structSchema(t reflect.Type) (map[string]any, error) {
+	if b.building[t] {
+		b.recursive[t] = true
+		return b.ref(t), nil
+	}
+	if b.recursive[t] {
+		return b.ref(t), nil
+	}
+	b.building[t] = true
+	defer delete(b.building, t)
+
+	props := map[string]any{}
+	var required []any
+	if err := b.fields(t, props, &required, map[string]bool{}); err != nil {
+		return nil, err
+	}
+	s := map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	if !b.recursive[t] || t == b.root {
+		return s, nil
+	}
+	b.defs[b.defName(t)] = s
+	return b.ref(t), nil
+}
+
+// fields adds t's encoded fields; embedded structs are flattened, and
+// names already taken by an outer struct win.
+func (b *typeSchemaBuilder) fields(t reflect.Type, props map[string]any, required *[]any, taken map[string]bool) error {
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, flags, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			embedded = append(embedded, ft)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if taken[name] {
+			continue
+		}
+		taken[name] = true
+
+		s, err := b.schema(f.Type)
+		if err != nil {
+			return fmt.Errorf("field %s.%s: %w", t.Name(), f.Name, err)
+		}
+		optional := hasTagFlag(flags, "omitempty") || hasTagFlag(flags, "omitzero")
+		if extra := f.Tag.Get("jsonschema"); extra != "" {
+			// Copy so keywords never leak into a shared $ref node.
+			s = copyMap(s)
+			if optional, err = applySchemaTag(s, extra, f.Type, optional); err != nil {
+				return fmt.Errorf("field %s.%s: %w", t.Name(), f.Name, err)
+			}
+		}
+		if f.Type.Kind() == reflect.Pointer && !optional {
+			s = nullableSchema(s)
+		}
+		if hasTagFlag(flags, "string") {
+			s = map[string]any{"type": "string"}
+		}
+		props[name] = s
+		if !optional {
+			*required = append(*required, name)
+		}
+	}
+	for _, et := range embedded {
+		if err := b.fields(et, props, required, taken); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *typeSchemaBuilder) ref(t reflect.Type) map[string]any {
+	if t == b.root {
+		return map[string]any{"$ref": "#"}
+	}
+	return map[string]any{"$ref": "#/$defs/" + escapePointerSegment(b.defName(t))}
+}
+
+// defName names t in $defs: its type name, qualified by package when two
+// types share a name.
+func (b *typeSchemaBuilder) defName(t reflect.Type) string {
+	if name, ok := b.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if name == "" {
+		name = "Anonymous"
+	}
+	for _, used := range b.names {
+		if used == name {
+			name = strings.NewReplacer("/", "_", ".", "_").Replace(t.PkgPath()) + "_" + name
+			break
+		}
+	}
+	b.names[t] = name
+	return name
+}
+
+func hasTagFlag(flags, flag string) bool {
+	for _, f := range strings.Split(flags, ",") {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+func copyMap(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// nullableSchema widens s to also accept null.
+func nullableSchema(s map[string]any) map[string]any {
+	if typ, ok := s["type"].(string); ok {
+		s = copyMap(s)
+		s["type"] = []any{typ, "null"}
+		return s
+	}
+	return map[string]any{"anyOf": []any{s, map[string]any{"type": "null"}}}
+}
+
+// applySchemaTag adds the keywords of a `jsonschema` tag to s and returns
+// whether the field is optional after the required/optional flags.
+func applySchemaTag(s map[string]any, tag string, ft reflect.Type, optional bool) (bool, error) {
+	for _, part := range splitSchemaTag(tag) {
+		key, value, hasValue := strings.Cut(part, "=")
+		switch key {
+		case "required":
+			optional = false
+		case "optional":
+			optional = true
+		case "description", "title", "format", "pattern":
+			s[key] = value
+		case "enum":
+			var values []any
+			for _, v := range strings.Split(value, "|") {
+				parsed, err := parseTagValue(v, ft)
+				if err != nil {
+					return false, fmt.Errorf("jsonschema tag enum: %w", err)
+				}
+				values = append(values, parsed)
+			}
+			s["enum"] = values
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return false, fmt.Errorf("jsonschema tag %s: %w", key, err)
+			}
+			s[key] = n
+		case "minLength", "maxLength", "minItems", "maxItems":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return false, fmt.Errorf("jsonschema tag %s: invalid count %q", key, value)
+			}
+			s[key] = n
+		default:
+			return false, fmt.Errorf("jsonschema tag: unknown key %q", key)
+		}
+		if hasValue == (key == "required" || key == "optional") {
+			return false, fmt.Errorf("jsonschema tag: malformed %q", part)
+		}
+	}
+	return optional, nil
+}
+
+// splitSchemaTag splits on commas not escaped with a backslash.
+func splitSchemaTag(tag string) []string {
+	var parts []string
+	var cur strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			cur.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			parts = append(parts, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(tag[i])
+		}
+	}
+	return append(parts, cur.String())
+}
+
+// parseTagValue parses an enum value as the field's scalar type.
+func parseTagValue(v string, ft reflect.Type) (any, error) {
+	for ft.Kind() == reflect.Pointer {
+		ft = ft.Elem()
+	}
+	switch ft.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(v, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(v, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(v, 64)
+	case reflect.Bool:
+		return strconv.ParseBool(v)
+	}
+	return v, nil
+}
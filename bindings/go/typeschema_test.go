@@ -0,0 +1,137 @@
+package jsl
+
+import (
+	"testing"
+	"time"
+)
+
+type testAddress struct {
+	Street string `json:"street" jsonschema:"description=Street and number\\, no city"`
+	Zip    string `json:"zip,omitempty" jsonschema:"pattern=^[0-9]{5}$"`
+}
+
+type testAudit struct {
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type testCustomer struct {
+	testAudit
+	Name     string            `json:"name" jsonschema:"minLength=1"`
+	Tier     string            `json:"tier" jsonschema:"enum=free|pro"`
+	Priority int               `json:"priority,omitempty" jsonschema:"enum=1|2|3,required"`
+	Age      *uint8            `json:"age"`
+	Address  *testAddress      `json:"address,omitempty"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Avatar   []byte            `json:"avatar,omitempty"`
+	Extra    any               `json:"extra,omitempty"`
+	Internal string            `json:"-"`
+	secret   string
+}
+
+func TestSchemaOf(t *testing.T) {
+	got, err := SchemaOf[testCustomer]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := decodeJSON(t, `{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"createdAt": {"type": "string", "format": "date-time"},
+			"name": {"type": "string", "minLength": 1},
+			"tier": {"type": "string", "enum": ["free", "pro"]},
+			"priority": {"type": "integer", "enum": [1, 2, 3]},
+			"age": {"type": ["integer", "null"], "minimum": 0},
+			"address": {
+				"type": "object",
+				"additionalProperties": false,
+				"properties": {
+					"street": {"type": "string", "description": "Street and number, no city"},
+					"zip": {"type": "string", "pattern": "^[0-9]{5}$"}
+				},
+				"required": ["street"]
+			},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+			"avatar": {"type": "string", "contentEncoding": "base64"},
+			"extra": {}
+		},
+		"required": ["name", "tier", "priority", "age", "tags", "createdAt"]
+	}`)
+	if !jsonEqual(decodeJSON(t, string(mustMarshal(got))), want) {
+		t.Errorf("got %s", mustMarshal(got))
+	}
+}
+
+type testTreeNode struct {
+	Value    string          `json:"value"`
+	Children []*testTreeNode `json:"children,omitempty"`
+	Meta     *testMeta       `json:"meta,omitempty"`
+}
+
+type testMeta struct {
+	Owner *testMeta     `json:"owner,omitempty"`
+	Root  *testTreeNode `json:"root,omitempty"`
+}
+
+func TestSchemaOfRecursive(t *testing.T) {
+	got, err := SchemaOf[testTreeNode]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := decodeJSON(t, `{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"value": {"type": "string"},
+			"children": {"type": "array", "items": {"$ref": "#"}},
+			"meta": {"$ref": "#/$defs/testMeta"}
+		},
+		"required": ["value"],
+		"$defs": {
+			"testMeta": {
+				"type": "object",
+				"additionalProperties": false,
+				"properties": {
+					"owner": {"$ref": "#/$defs/testMeta"},
+					"root": {"$ref": "#"}
+				}
+			}
+		}
+	}`)
+	if !jsonEqual(decodeJSON(t, string(mustMarshal(got))), want) {
+		t.Errorf("got %s", mustMarshal(got))
+	}
+}
+
+func TestSchemaOfErrors(t *testing.T) {
+	type badTag struct {
+		N int `json:"n" jsonschema:"minimum=low"`
+	}
+	if _, err := SchemaOf[badTag](); err == nil {
+		t.Error("expected an error for a malformed minimum")
+	}
+	type unknownKey struct {
+		N int `json:"n" jsonschema:"colour=red"`
+	}
+	if _, err := SchemaOf[unknownKey](); err == nil {
+		t.Error("expected an error for an unknown tag key")
+	}
+	type badKind struct {
+		C chan int `json:"c"`
+	}
+	if _, err := SchemaOf[badKind](); err == nil {
+		t.Error("expected an error for a channel field")
+	}
+}
+
+func TestDecodeStrict(t *testing.T) {
+	var a testAddress
+	if err := decodeStrict([]byte(`{"street": "1 Main St", "zip": "12345"}`), &a); err != nil || a.Zip != "12345" {
+		t.Fatalf("decode = %+v, %v", a, err)
+	}
+	if err := decodeStrict([]byte(`{"street": "1 Main St", "city": "Springfield"}`), &a); err == nil {
+		t.Fatal("expected an error for an unknown field")
+	}
+}
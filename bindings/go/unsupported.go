@@ -0,0 +1,215 @@
+package jsl
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RemediationAction names one way to resolve an UnsupportedFeature.
+type RemediationAction string
+
+const (
+	// RemediateChangeSchema: rewrite the schema so the target can express it.
+	RemediateChangeSchema RemediationAction = "change_schema"
+	// RemediateChangeTarget: convert for a target that supports the feature.
+	RemediateChangeTarget RemediationAction = "change_target"
+	// RemediateAcceptStringify: keep the current conversion, in which the
+	// affected subtree is sent as a JSON string (or otherwise degraded) and
+	// restored by Rehydrate.
+	RemediateAcceptStringify RemediationAction = "accept_stringify"
+)
+
+// Remediation is a suggested fix for an UnsupportedFeature.
+type Remediation struct {
+	Action      RemediationAction `json:"action"`
+	Description string            `json:"description"`
+}
+
+// UnsupportedFeature describes something the target cannot express at all,
+// located by Pointer (a schema pointer into the converted schema, or into
+// the input schema for errors raised before conversion finished).
+type UnsupportedFeature struct {
+	Feature      string        `json:"feature"`
+	Pointer      string        `json:"pointer"`
+	Target       string        `json:"target"`
+	Message      string        `json:"message"`
+	Remediations []Remediation `json:"remediations"`
+}
+
+// defaultTarget is the guest's target when ConvertOptions.Target is empty.
+const defaultTarget = TargetOpenAI
+
+func targetOf(opts *ConvertOptions) string {
+	if opts == nil || opts.Target == "" {
+		return defaultTarget
+	}
+	return opts.Target
+}
+
+// providerCompatError is the guest's providerCompatErrors entry shape.
+type providerCompatError struct {
+	Type    string `json:"type"`
+	Path    string `json:"path"`
+	Keyword string `json:"keyword"`
+	Target  string `json:"target"`
+	Hint    string `json:"hint"`
+}
+
+// unsupportedFeatures derives the UnsupportedFeature report from a guest
+// convert payload: the provider compatibility findings plus every
+// recursion the guest had to cut off with a recursive_inflate entry.
+func unsupportedFeatures(payload []byte, opts *ConvertOptions) []UnsupportedFeature {
+	var raw struct {
+		Codec struct {
+			Transforms []struct {
+				Type        string `json:"type"`
+				Path        string `json:"path"`
+				OriginalRef string `json:"originalRef"`
+			} `json:"transforms"`
+		} `json:"codec"`
+		ProviderCompatErrors []providerCompatError `json:"providerCompatErrors"`
+	}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil
+	}
+	target := targetOf(opts)
+	var out []UnsupportedFeature
+	for _, t := range raw.Codec.Transforms {
+		if t.Type != "recursive_inflate" {
+			continue
+		}
+		out = append(out, UnsupportedFeature{
+			Feature: "recursion",
+			Pointer: t.Path,
+			Target:  target,
+			Message: fmt.Sprintf("%s cannot express the recursive reference %s; it is cut off at the recursion limit", target, t.OriginalRef),
+			Remediations: []Remediation{
+				{RemediateChangeSchema, fmt.Sprintf("replace the self-reference to %s with a bounded structure", t.OriginalRef)},
+				{RemediateChangeTarget, "gemini supports recursive schemas natively"},
+				{RemediateAcceptStringify, fmt.Sprintf("keep it: levels past RecursionLimit (or RecursionLimits[%q]) are sent as JSON strings and parsed back by Rehydrate", t.OriginalRef)},
+			},
+		})
+	}
+	for _, c := range raw.ProviderCompatErrors {
+		if f, ok := compatFeature(c, target); ok {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// compatFeature maps a provider compatibility finding to an
+// UnsupportedFeature. Findings the guest fully expressed another way
+// (type arrays as anyOf, redundant required branches) are not reported.
+func compatFeature(c providerCompatError, target string) (UnsupportedFeature, bool) {
+	if c.Target != "" {
+		target = c.Target
+	}
+	f := UnsupportedFeature{Feature: c.Type, Pointer: c.Path, Target: target, Message: c.Hint}
+	if f.Pointer == "" {
+		f.Pointer = "#"
+	}
+	switch c.Type {
+	case "root_type_incompatible":
+		f.Feature = "non_object_root"
+		f.Remediations = []Remediation{
+			{RemediateChangeSchema, "make the root schema an object"},
+			{RemediateChangeTarget, "gemini and claude accept non-object roots"},
+			{RemediateAcceptStringify, "keep it: the root is wrapped in an object and unwrapped by Rehydrate"},
+		}
+	case "depth_budget_exceeded":
+		f.Feature = "nesting_depth"
+		f.Remediations = []Remediation{
+			{RemediateChangeSchema, "flatten the schema, or set ConvertOptions.MaxNestingDepth to hoist deep subtrees"},
+			{RemediateChangeTarget, "gemini and claude allow deeper nesting"},
+			{RemediateAcceptStringify, "lower MaxDepth so deep subtrees are sent as JSON strings"},
+		}
+	case "mixed_enum_types":
+		f.Remediations = []Remediation{
+			{RemediateChangeSchema, "split the enum into one enum per type under anyOf"},
+			{RemediateChangeTarget, "gemini accepts mixed-type enums"},
+			{RemediateAcceptStringify, "keep it: values are sent as strings and converted back by Rehydrate"},
+		}
+	case "unconstrained_schema":
+		f.Remediations = []Remediation{
+			{RemediateChangeSchema, "give the schema a type (or enable ConvertOptions.InferOpaqueTypes)"},
+			{RemediateChangeTarget, "gemini accepts unconstrained schemas"},
+			{RemediateAcceptStringify, "keep it: the value is sent as a JSON string and parsed by Rehydrate"},
+		}
+	case "pattern_properties_stripped", "pattern_properties_stringified":
+		f.Feature = "pattern_properties"
+		f.Remediations = []Remediation{
+			{RemediateChangeSchema, "declare the keys as properties or use additionalProperties"},
+			{RemediateChangeTarget, "gemini keeps patternProperties"},
+			{RemediateAcceptStringify, "keep it: the object is sent as a JSON string (or its key patterns are dropped)"},
+		}
+	case "ref_keyword_stripped":
+		f.Feature = c.Keyword
+		f.Remediations = []Remediation{
+			{RemediateChangeSchema, fmt.Sprintf("replace %s with $ref to a $defs entry", c.Keyword)},
+			{RemediateAcceptStringify, fmt.Sprintf("keep it: %s is removed and the reference left unconstrained", c.Keyword)},
+		}
+	case "type_array_converted", "bare_required_stripped":
+		return UnsupportedFeature{}, false
+	}
+	return f, true
+}
+
+// unsupportedFromError builds the report for a Convert error that means the
+// target cannot express the schema.
+func unsupportedFromError(err *Error, opts *ConvertOptions) []UnsupportedFeature {
+	target := targetOf(opts)
+	pointer := err.Path
+	if pointer == "" {
+		pointer = "#"
+	}
+	switch err.Code {
+	case "recursion_depth_exceeded":
+		return []UnsupportedFeature{{
+			Feature: "recursion",
+			Pointer: pointer,
+			Target:  target,
+			Message: err.Message,
+			Remediations: []Remediation{
+				{RemediateChangeSchema, "remove the reference cycle or bound it explicitly"},
+				{RemediateChangeTarget, "gemini supports recursive schemas natively"},
+				{RemediateAcceptStringify, "lower RecursionLimit so the cycle is cut off and sent as a JSON string"},
+			},
+		}}
+	case "unsupported_feature":
+		return []UnsupportedFeature{{
+			Feature: "unsupported_feature",
+			Pointer: pointer,
+			Target:  target,
+			Message: err.Message,
+			Remediations: []Remediation{
+				{RemediateChangeSchema, "rewrite the construct at " + pointer},
+				{RemediateChangeTarget, "try a less restrictive target"},
+			},
+		}}
+	case "target_limit_exceeded":
+		return []UnsupportedFeature{{
+			Feature: "target_limits",
+			Pointer: pointer,
+			Target:  target,
+			Message: err.Message,
+			Remediations: []Remediation{
+				{RemediateChangeSchema, "reduce the schema below the target's limits at " + pointer},
+				{RemediateChangeTarget, "convert for a target with higher limits"},
+				{RemediateAcceptStringify, "set ConvertOptions.AutoFit to shorten names, collapse enums and stringify subtrees until it fits"},
+			},
+		}}
+	case "schema_budget_exceeded":
+		return []UnsupportedFeature{{
+			Feature: "schema_size",
+			Pointer: pointer,
+			Target:  target,
+			Message: err.Message,
+			Remediations: []Remediation{
+				{RemediateChangeSchema, "split the schema or move rarely used fields out"},
+				{RemediateChangeTarget, "raise MaxSchemaBytes/MaxSchemaTokens to the target's real limit"},
+			},
+		}}
+	}
+	return nil
+}
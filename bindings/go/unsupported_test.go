@@ -0,0 +1,45 @@
+package jsl
+
+import "testing"
+
+// TestUnsupportedFeatures verifies recursion cut-offs and provider findings
+// are reported with remediations, and fully expressed findings are not.
+func TestUnsupportedFeatures(t *testing.T) {
+	payload := []byte(`{
+		"apiVersion": "1.0",
+		"schema": {},
+		"codec": {"transforms": [
+			{"type": "json_string_parse", "path": "#/properties/meta"},
+			{"type": "recursive_inflate", "path": "#/properties/children/items/properties/children", "originalRef": "#/$defs/Node"}
+		]},
+		"providerCompatErrors": [
+			{"type": "mixed_enum_types", "path": "#/properties/v", "types_found": ["string", "integer"], "target": "openai-strict", "hint": "use one type"},
+			{"type": "type_array_converted", "path": "#/properties/x", "types": ["string", "null"], "target": "openai-strict", "hint": ""}
+		]
+	}`)
+	got := unsupportedFeatures(payload, nil)
+	if len(got) != 2 {
+		t.Fatalf("unsupported = %+v", got)
+	}
+	if got[0].Feature != "recursion" || got[0].Pointer != "#/properties/children/items/properties/children" || got[0].Target != "openai-strict" {
+		t.Errorf("recursion = %+v", got[0])
+	}
+	if len(got[0].Remediations) != 3 || got[0].Remediations[2].Action != RemediateAcceptStringify {
+		t.Errorf("remediations = %+v", got[0].Remediations)
+	}
+	if got[1].Feature != "mixed_enum_types" || got[1].Pointer != "#/properties/v" || got[1].Message != "use one type" {
+		t.Errorf("compat = %+v", got[1])
+	}
+}
+
+// TestUnsupportedFromError verifies only feature-related error codes get a report.
+func TestUnsupportedFromError(t *testing.T) {
+	opts := &ConvertOptions{Target: "claude"}
+	got := unsupportedFromError(&Error{Code: "recursion_depth_exceeded", Path: "#/$defs/A", Message: "too deep"}, opts)
+	if len(got) != 1 || got[0].Feature != "recursion" || got[0].Target != "claude" || got[0].Pointer != "#/$defs/A" {
+		t.Errorf("unsupported = %+v", got)
+	}
+	if got := unsupportedFromError(&Error{Code: "json_parse_error"}, opts); got != nil {
+		t.Errorf("unsupported = %+v", got)
+	}
+}
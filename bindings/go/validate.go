@@ -0,0 +1,169 @@
+package jsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// OptionError reports an invalid ConvertOptions field.
+type OptionError struct {
+	// Field is the Go field name (e.g. "MaxDepth"), or the JSON key for
+	// unknown keys.
+	Field   string
+	Message string
+}
+
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("jsl convert options: %s: %s", e.Field, e.Message)
+}
+
+// knownTargets are the targets the guest accepts.
+var knownTargets = []string{TargetOpenAI, TargetGemini, TargetClaude}
+
+// Validate reports every field of opts that the engine would reject or
+// silently ignore, as *OptionError values joined with errors.Join. Convert
+// calls it before any work is done. A nil opts is valid.
+func (o *ConvertOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+	var errs []error
+	bad := func(field, format string, args ...any) {
+		errs = append(errs, &OptionError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	oneOf := func(field, value string, allowed ...string) {
+		for _, a := range allowed {
+			if value == a {
+				return
+			}
+		}
+		quoted := make([]string, 0, len(allowed))
+		for _, a := range allowed {
+			if a != "" {
+				quoted = append(quoted, fmt.Sprintf("%q", a))
+			}
+		}
+		bad(field, "unknown value %q (want %s)", value, strings.Join(quoted, ", "))
+	}
+	nonNegative := func(field string, v int) {
+		if v < 0 {
+			bad(field, "must not be negative, got %d", v)
+		}
+	}
+
+	oneOf("Target", o.Target, append([]string{""}, knownTargets...)...)
+	oneOf("Polymorphism", o.Polymorphism, "", PolymorphismAnyOf, PolymorphismFlatten)
+	nonNegative("MaxDepth", o.MaxDepth)
+	nonNegative("RecursionLimit", o.RecursionLimit)
+	for _, ref := range sortedIntKeys(o.RecursionLimits) {
+		if !strings.HasPrefix(ref, "#") {
+			bad("RecursionLimits", "key %q is not a $ref pointer", ref)
+		}
+		if n := o.RecursionLimits[ref]; n < 0 {
+			bad("RecursionLimits", "limit for %s must not be negative, got %d", ref, n)
+		}
+	}
+	for _, name := range o.DisablePasses {
+		switch {
+		case containsString(requiredPasses, name):
+			bad("DisablePasses", "pass %q cannot be disabled", name)
+		case !knownPass(name):
+			bad("DisablePasses", "unknown pass %q", name)
+		}
+	}
+	for _, k := range sortedOverrideKeys(o.Overrides) {
+		if !strings.HasPrefix(k, "#") {
+			bad("Overrides", "key %q is not a schema pointer", k)
+		} else if o.Overrides[k].Stringify && len(ParsePointer(k)) == 0 {
+			bad("Overrides", "the root schema cannot be stringified")
+		}
+	}
+	oneOf("Dialect", string(o.Dialect), string(DialectJSONSchema), string(DialectSwagger2))
+	oneOf("TupleStrategy", string(o.TupleStrategy), string(TupleStrategyNone), string(TupleStrategyObject), string(TupleStrategyArray))
+	oneOf("ConstStrategy", string(o.ConstStrategy), string(ConstStrategyNone), string(ConstStrategyEnum), string(ConstStrategyDescription))
+	oneOf("ConditionalStrategy", string(o.ConditionalStrategy), string(ConditionalStrategyNone), string(ConditionalStrategyAnyOf), string(ConditionalStrategyDrop))
+	oneOf("OptionalStrategy", string(o.OptionalStrategy), string(OptionalNullable), string(OptionalKeepNull), string(OptionalDrop))
+	oneOf("PropertyOrder", string(o.PropertyOrder), string(PropertyOrderSorted), string(PropertyOrderSource))
+	if o.Canonical && o.PropertyOrder == PropertyOrderSource {
+		bad("Canonical", "requires sorted properties, not PropertyOrderSource")
+	}
+	if o.InferConfidence < 0 || o.InferConfidence > 1 {
+		bad("InferConfidence", "must be between 0 and 1, got %g", o.InferConfidence)
+	}
+	if o.MaxNestingDepth != 0 && o.MaxNestingDepth < minFlattenDepth {
+		bad("MaxNestingDepth", "must be 0 or at least %d, got %d", minFlattenDepth, o.MaxNestingDepth)
+	}
+	nonNegative("MaxSchemaBytes", o.MaxSchemaBytes)
+	nonNegative("MaxSchemaTokens", o.MaxSchemaTokens)
+	nonNegative("MaxDescriptionLength", o.MaxDescriptionLength)
+	nonNegative("MaxEnumValues", o.MaxEnumValues)
+	nonNegative("MaxObjectProperties", o.MaxObjectProperties)
+	return errors.Join(errs...)
+}
+
+// UnmarshalJSON decodes options written with their kebab-case JSON keys
+// (e.g. config files), rejecting unknown keys, which would otherwise be
+// dropped without effect, with an *OptionError naming the key.
+func (o *ConvertOptions) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	known := convertOptionKeys()
+	var errs []error
+	for _, k := range sortedRawKeys(fields) {
+		if !known[k] {
+			errs = append(errs, &OptionError{Field: k, Message: "unknown option"})
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	type plain ConvertOptions
+	return json.NewDecoder(bytes.NewReader(data)).Decode((*plain)(o))
+}
+
+// convertOptionKeys returns the JSON keys ConvertOptions decodes.
+func convertOptionKeys() map[string]bool {
+	keys := map[string]bool{}
+	t := reflect.TypeOf(ConvertOptions{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+func sortedIntKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedOverrideKeys(m map[string]NodeOverride) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedRawKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,62 @@
+package jsl
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestValidate verifies each invalid field is reported by name.
+func TestValidate(t *testing.T) {
+	opts := &ConvertOptions{
+		Target:           "openai",
+		MaxDepth:         -1,
+		RecursionLimits:  map[string]int{"TreeNode": 2},
+		OptionalStrategy: "skip",
+		Canonical:        true,
+		PropertyOrder:    PropertyOrderSource,
+		MaxNestingDepth:  3,
+	}
+	err := opts.Validate()
+	var fields []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var oe *OptionError
+		if !errors.As(e, &oe) {
+			t.Fatalf("%v is not an *OptionError", e)
+		}
+		fields = append(fields, oe.Field)
+	}
+	want := "Target MaxDepth RecursionLimits OptionalStrategy Canonical MaxNestingDepth"
+	if got := strings.Join(fields, " "); got != want {
+		t.Errorf("fields = %s, want %s", got, want)
+	}
+	if !strings.Contains(err.Error(), `Target: unknown value "openai" (want "openai-strict", "gemini", "claude")`) {
+		t.Errorf("error = %v", err)
+	}
+
+	if err := (&ConvertOptions{Target: "gemini", Polymorphism: "flatten", MaxNestingDepth: 5}).Validate(); err != nil {
+		t.Errorf("valid options: %v", err)
+	}
+	if err := (*ConvertOptions)(nil).Validate(); err != nil {
+		t.Errorf("nil options: %v", err)
+	}
+}
+
+// TestUnmarshalConvertOptions verifies kebab-case keys decode and unknown
+// keys are rejected by name.
+func TestUnmarshalConvertOptions(t *testing.T) {
+	var opts ConvertOptions
+	if err := json.Unmarshal([]byte(`{"target": "gemini", "max-depth": 10, "recursion-limits": {"#/$defs/A": 2}}`), &opts); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Target != "gemini" || opts.MaxDepth != 10 || opts.RecursionLimits["#/$defs/A"] != 2 {
+		t.Errorf("opts = %+v", opts)
+	}
+
+	err := json.Unmarshal([]byte(`{"target": "gemini", "max_depth": 10, "recursionLimit": 2}`), &opts)
+	var oe *OptionError
+	if !errors.As(err, &oe) || oe.Field != "max_depth" || !strings.Contains(err.Error(), "recursionLimit") {
+		t.Errorf("err = %v", err)
+	}
+}
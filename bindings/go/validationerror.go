@@ -0,0 +1,52 @@
+package jsl
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/message"
+)
+
+// WarningErrorKind is the jsonschema.ErrorKind of a validation error built
+// from a Warning; type-assert ValidationError.ErrorKind to recover it.
+type WarningErrorKind struct {
+	Warning Warning
+}
+
+// KeywordPath returns the violated keyword, if the warning names one.
+func (k *WarningErrorKind) KeywordPath() []string {
+	if k.Warning.Kind.Constraint == "" {
+		return nil
+	}
+	return []string{k.Warning.Kind.Constraint}
+}
+
+// LocalizedString returns the warning message; it is not translated.
+func (k *WarningErrorKind) LocalizedString(*message.Printer) string {
+	return k.Warning.Message
+}
+
+// WarningsAsValidationError converts warnings into a
+// *jsonschema.ValidationError shaped like a failed validation: a root
+// error for the schema at schemaURL with one cause per warning, whose
+// instance location is the warning's DataPath and whose keyword location is
+// its SchemaPath plus constraint. Error, BasicOutput and DetailedOutput
+// then render warnings like the validator's own errors. schemaURL is the
+// URL the schema is compiled under (ValidatorSchemaURL for
+// ConvertResult.Validator). It returns nil when there are no warnings.
+func WarningsAsValidationError(schemaURL string, warnings []Warning) *jsonschema.ValidationError {
+	if len(warnings) == 0 {
+		return nil
+	}
+	root := &jsonschema.ValidationError{
+		SchemaURL: schemaURL + "#",
+		ErrorKind: &kind.Schema{Location: schemaURL + "#"},
+	}
+	for _, w := range warnings {
+		root.Causes = append(root.Causes, &jsonschema.ValidationError{
+			SchemaURL:        schemaURL + ParsePointer(w.SchemaPath).String(),
+			InstanceLocation: ParsePointer(w.DataPath),
+			ErrorKind:        &WarningErrorKind{Warning: w},
+		})
+	}
+	return root
+}
@@ -0,0 +1,36 @@
+package jsl
+
+import "testing"
+
+// TestWarningsAsValidationError verifies warnings render like validator
+// errors, with instance and keyword locations.
+func TestWarningsAsValidationError(t *testing.T) {
+	if err := WarningsAsValidationError("schema.json", nil); err != nil {
+		t.Errorf("no warnings = %v, want nil", err)
+	}
+	err := WarningsAsValidationError("schema.json", []Warning{
+		{DataPath: "/users/0/age", SchemaPath: "#/properties/users/items/properties/age", Kind: WarningKind{Type: WarnConstraintViolation, Constraint: "minimum"}, Message: "-1 is less than minimum 0"},
+		{DataPath: "/country", SchemaPath: "#/properties/country", Kind: WarningKind{Type: WarnEnumCoerced}, Message: `"uk" matched to enum value "UK"`},
+	})
+	want := `jsonschema validation failed with 'schema.json#'
+- at '/users/0/age': -1 is less than minimum 0
+- at '/country': "uk" matched to enum value "UK"`
+	if got := err.Error(); got != want {
+		t.Errorf("Error() =\n%s\nwant\n%s", got, want)
+	}
+
+	out := err.BasicOutput()
+	if len(out.Errors) != 2 {
+		t.Fatalf("basic output errors = %+v", out.Errors)
+	}
+	if got := out.Errors[0].KeywordLocation; got != "/properties/users/items/properties/age/minimum" {
+		t.Errorf("keyword location = %s", got)
+	}
+	if got := out.Errors[0].InstanceLocation; got != "/users/0/age" {
+		t.Errorf("instance location = %s", got)
+	}
+	kind, ok := err.Causes[1].ErrorKind.(*WarningErrorKind)
+	if !ok || kind.Warning.Kind.Type != WarnEnumCoerced || kind.KeywordPath() != nil {
+		t.Errorf("error kind = %#v", err.Causes[1].ErrorKind)
+	}
+}
@@ -0,0 +1,53 @@
+package jsl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ValidatorSchemaURL is the URL ConvertResult.Validator compiles the
+// original schema under; pass it to WarningsAsValidationError so both
+// sources report the same keyword locations.
+const ValidatorSchemaURL = "schema.json"
+
+// lazyValidator compiles the original schema once, on first use. Copies of
+// a ConvertResult (including cached ones) share it.
+type lazyValidator struct {
+	once   sync.Once
+	source []byte
+	schema *jsonschema.Schema
+	err    error
+}
+
+func (v *lazyValidator) compile() (*jsonschema.Schema, error) {
+	v.once.Do(func() {
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(v.source))
+		if err != nil {
+			v.err = fmt.Errorf("validator: decode schema: %w", err)
+			return
+		}
+		c := jsonschema.NewCompiler()
+		if err := c.AddResource(ValidatorSchemaURL, doc); err != nil {
+			v.err = fmt.Errorf("validator: %w", err)
+			return
+		}
+		if v.schema, err = c.Compile(ValidatorSchemaURL); err != nil {
+			v.err = fmt.Errorf("validator: %w", err)
+		}
+	})
+	return v.schema, v.err
+}
+
+// Validator returns a validator for the original schema passed to Convert
+// (BundledSchema when external refs were bundled), compiled on first call,
+// for checking rehydrated data. It is safe for concurrent use.
+func (r *ConvertResult) Validator() (*jsonschema.Schema, error) {
+	if r.validator == nil {
+		return nil, errors.New("validator: result was not produced by Convert")
+	}
+	return r.validator.compile()
+}
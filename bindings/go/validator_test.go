@@ -0,0 +1,46 @@
+package jsl
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// TestConvertResultValidator verifies the validator checks data against the
+// original schema, is compiled once, and is shared with cached copies.
+func TestConvertResultValidator(t *testing.T) {
+	r := &ConvertResult{validator: &lazyValidator{source: []byte(`{
+		"type": "object",
+		"properties": {"age": {"type": "integer", "minimum": 0}},
+		"required": ["age"]
+	}`)}}
+	v, err := r.Validator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Validate(decodeJSON(t, `{"age": 3}`)); err != nil {
+		t.Errorf("valid data: %v", err)
+	}
+	err = v.Validate(decodeJSON(t, `{"age": -1}`))
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) || !strings.Contains(err.Error(), "at '/age'") {
+		t.Errorf("invalid data: %v", err)
+	}
+	if again, _ := cloneConvertResult(r).Validator(); again != v {
+		t.Error("validator recompiled for a copy")
+	}
+}
+
+// TestConvertResultValidatorErrors verifies results without a source and
+// uncompilable schemas report errors.
+func TestConvertResultValidatorErrors(t *testing.T) {
+	if _, err := (&ConvertResult{}).Validator(); err == nil {
+		t.Error("expected an error for a result not produced by Convert")
+	}
+	r := &ConvertResult{validator: &lazyValidator{source: []byte(`{"type": 7}`)}}
+	if _, err := r.Validator(); err == nil || !strings.HasPrefix(err.Error(), "validator: ") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package jsl
+
+import (
+	"regexp"
+	"strings"
+)
+
+// instanceVisitor is invoked for every schema node reached while co-walking a
+// schema and an instance. loc is the canonical location of the node (after
+// following $ref), dataPath the RFC 6901 pointer of the value. The returned
+// value replaces v in the instance.
+type instanceVisitor func(loc string, node map[string]any, dataPath string, v any) any
+
+// maxRefHops bounds $ref chains that do not descend into the instance
+// (e.g. A → B → A), which would otherwise loop forever.
+const maxRefHops = 32
+
+// walkInstance co-walks an original-shape schema and a decoded instance,
+// calling visit in post-order (children first) so a visitor may reshape a
+// value after its children have been handled.
+//
+// Applicators are followed the way a validator would: allOf visits every
+// branch, anyOf/oneOf visit the first branch that admits the value, and
+// properties/patternProperties/additionalProperties/prefixItems/items descend
+// into matching children. Only local ("#/...") references are resolved.
+func walkInstance(root any, value any, visit instanceVisitor) any {
+	w := &instanceWalker{root: root, visit: visit, regexes: map[string]*regexp.Regexp{}}
+	return w.walk(root, "#", "", value, 0)
+}
+
+type instanceWalker struct {
+	root    any
+	visit   instanceVisitor
+	regexes map[string]*regexp.Regexp
+}
+
+func (w *instanceWalker) walk(schema any, loc, dataPath string, v any, hops int) any {
+	node, ok := schema.(map[string]any)
+	if !ok {
+		return v
+	}
+
+	if ref, ok := node["$ref"].(string); ok && strings.HasPrefix(ref, "#") && hops < maxRefHops {
+		if target, ok := lookupPointer(w.root, ref); ok {
+			v = w.walk(target, canonicalRef(ref), dataPath, v, hops+1)
+		}
+	}
+
+	if branches, ok := node["allOf"].([]any); ok {
+		for i, b := range branches {
+			v = w.walk(b, childPointer(childPointer(loc, "allOf"), itoa(i)), dataPath, v, hops)
+		}
+	}
+	for _, kw := range []string{"anyOf", "oneOf"} {
+		branches, ok := node[kw].([]any)
+		if !ok {
+			continue
+		}
+		for i, b := range branches {
+			if w.admits(b, v, 0) {
+				v = w.walk(b, childPointer(childPointer(loc, kw), itoa(i)), dataPath, v, hops)
+				break
+			}
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		props, _ := node["properties"].(map[string]any)
+		patterns, _ := node["patternProperties"].(map[string]any)
+		for key, child := range val {
+			matched := false
+			if sub, ok := props[key]; ok {
+				val[key] = w.walk(sub, childPointer(childPointer(loc, "properties"), key), childDataPath(dataPath, key), val[key], 0)
+				matched = true
+			}
+			for pattern, sub := range patterns {
+				if re := w.regex(pattern); re != nil && re.MatchString(key) {
+					val[key] = w.walk(sub, childPointer(childPointer(loc, "patternProperties"), pattern), childDataPath(dataPath, key), val[key], 0)
+					matched = true
+				}
+			}
+			if !matched {
+				if sub, ok := node["additionalProperties"].(map[string]any); ok {
+					val[key] = w.walk(sub, childPointer(loc, "additionalProperties"), childDataPath(dataPath, key), child, 0)
+				}
+			}
+		}
+	case []any:
+		prefix, _ := node["prefixItems"].([]any)
+		for i := range val {
+			switch items := node["items"].(type) {
+			case []any: // draft-07 tuple form
+				if i < len(items) {
+					val[i] = w.walk(items[i], childPointer(childPointer(loc, "items"), itoa(i)), childDataPath(dataPath, i), val[i], 0)
+				}
+				continue
+			default:
+				if i < len(prefix) {
+					val[i] = w.walk(prefix[i], childPointer(childPointer(loc, "prefixItems"), itoa(i)), childDataPath(dataPath, i), val[i], 0)
+				} else if items != nil {
+					val[i] = w.walk(items, childPointer(loc, "items"), childDataPath(dataPath, i), val[i], 0)
+				}
+			}
+		}
+	}
+
+	return w.visit(loc, node, dataPath, v)
+}
+
+// admits is a cheap structural check used to pick an anyOf/oneOf branch:
+// type, const/enum membership, required keys, and (recursively) the
+// properties that are present. It is deliberately lenient — the guest has
+// already validated shape; this only has to disambiguate.
+func (w *instanceWalker) admits(schema any, v any, hops int) bool {
+	node, ok := schema.(map[string]any)
+	if !ok {
+		b, isBool := schema.(bool)
+		return !isBool || b
+	}
+	if ref, ok := node["$ref"].(string); ok && strings.HasPrefix(ref, "#") && hops < maxRefHops {
+		if target, ok := lookupPointer(w.root, ref); ok && !w.admits(target, v, hops+1) {
+			return false
+		}
+	}
+	if t, ok := node["type"]; ok && !typeAdmits(t, v) {
+		return false
+	}
+	if c, ok := node["const"]; ok && !jsonEqual(c, v) {
+		return false
+	}
+	if enum, ok := node["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if jsonEqual(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if obj, ok := v.(map[string]any); ok {
+		if req, ok := node["required"].([]any); ok {
+			for _, r := range req {
+				if key, ok := r.(string); ok {
+					if _, present := obj[key]; !present {
+						return false
+					}
+				}
+			}
+		}
+		if props, ok := node["properties"].(map[string]any); ok {
+			for key, sub := range props {
+				if child, present := obj[key]; present && !w.admits(sub, child, hops) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func (w *instanceWalker) regex(pattern string) *regexp.Regexp {
+	re, ok := w.regexes[pattern]
+	if !ok {
+		re, _ = regexp.Compile(pattern) // invalid patterns cache as nil
+		w.regexes[pattern] = re
+	}
+	return re
+}
+
+// canonicalRef normalizes a local $ref to the "#/..." location form.
+func canonicalRef(ref string) string {
+	if ref == "#" {
+		return "#"
+	}
+	return "#/" + joinSegments(splitPointer(ref))
+}
+
+// typeAdmits reports whether a JSON Schema "type" (string or array) admits v.
+func typeAdmits(t any, v any) bool {
+	switch tt := t.(type) {
+	case string:
+		return jsonTypeMatches(tt, v)
+	case []any:
+		for _, x := range tt {
+			if s, ok := x.(string); ok && jsonTypeMatches(s, v) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func jsonTypeMatches(t string, v any) bool {
+	switch t {
+	case "null":
+		return v == nil
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == float64(int64(f))
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package jsl
+
+// WarningKind.Type values. These strings are stable: they are only ever
+// added to, never renamed or reused, so monitoring can aggregate on them.
+const (
+	// WarnConstraintViolation: the output violates a constraint the target
+	// could not express (WarningKind.Constraint names it). Warning.Code
+	// refines it by constraint.
+	WarnConstraintViolation = "constraint_violation"
+	// WarnConstraintUnevaluable: a dropped constraint could not be checked
+	// (e.g. an invalid regex).
+	WarnConstraintUnevaluable = "constraint_unevaluable"
+	// WarnPathNotFound: a codec entry's path is absent from the output.
+	WarnPathNotFound = "path_not_found"
+
+	WarnAnnotationStripped      = "annotation_stripped"
+	WarnBooleanSchema           = "boolean_schema"
+	WarnConditionalDropped      = "conditional_dropped"
+	WarnDuplicateKey            = "duplicate_key"
+	WarnEnumCoerced             = "enum_coerced"
+	WarnFlattenJoinMissing      = "flatten_join_missing"
+	WarnFormatCoerced           = "format_coerced"
+	WarnInjectionMarkerStripped = "injection_marker_stripped"
+	WarnObjectSplit             = "object_split"
+	WarnOutputRepaired          = "output_repaired"
+	WarnPropertyRenamed         = "property_renamed"
+	WarnSanitized               = "sanitized"
+	WarnSlimmed                 = "slimmed"
+	WarnSpecialNumber           = "special_number"
+	WarnTruncated               = "truncated"
+	WarnTupleGap                = "tuple_gap"
+	WarnTypeInferenceFallback   = "type_inference_fallback"
+	WarnTypeInferred            = "type_inferred"
+)
+
+// Codes Warning.Code reports for WarnConstraintViolation, by constraint.
+const (
+	// WarnPatternMismatch: a string does not match its pattern.
+	WarnPatternMismatch = "pattern_mismatch"
+	// WarnRangeViolation: a numeric bound, multipleOf, or a length, item
+	// or property count bound is violated.
+	WarnRangeViolation = "range_violation"
+	// WarnEnumMiss: a value is not one of its enum or const values.
+	WarnEnumMiss = "enum_miss"
+	// WarnFormatMismatch: a string does not match its format.
+	WarnFormatMismatch = "format_mismatch"
+	// WarnTypeMismatch: a value has the wrong JSON type.
+	WarnTypeMismatch = "type_mismatch"
+)
+
+var constraintCodes = map[string]string{
+	"pattern":          WarnPatternMismatch,
+	"minimum":          WarnRangeViolation,
+	"maximum":          WarnRangeViolation,
+	"exclusiveMinimum": WarnRangeViolation,
+	"exclusiveMaximum": WarnRangeViolation,
+	"multipleOf":       WarnRangeViolation,
+	"minLength":        WarnRangeViolation,
+	"maxLength":        WarnRangeViolation,
+	"minItems":         WarnRangeViolation,
+	"maxItems":         WarnRangeViolation,
+	"minProperties":    WarnRangeViolation,
+	"maxProperties":    WarnRangeViolation,
+	"minContains":      WarnRangeViolation,
+	"maxContains":      WarnRangeViolation,
+	"enum":             WarnEnumMiss,
+	"const":            WarnEnumMiss,
+	"format":           WarnFormatMismatch,
+	"type":             WarnTypeMismatch,
+}
+
+// Code returns a stable, machine-readable code for w: Kind.Type, except
+// that constraint violations of a known constraint report the narrower
+// WarnPatternMismatch, WarnRangeViolation, WarnEnumMiss, WarnFormatMismatch
+// or WarnTypeMismatch.
+func (w Warning) Code() string {
+	if w.Kind.Type == WarnConstraintViolation {
+		if code, ok := constraintCodes[w.Kind.Constraint]; ok {
+			return code
+		}
+	}
+	return w.Kind.Type
+}
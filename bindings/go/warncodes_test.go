@@ -0,0 +1,46 @@
+package jsl
+
+import "testing"
+
+func TestWarningCode(t *testing.T) {
+	for _, tc := range []struct {
+		kind WarningKind
+		want string
+	}{
+		{WarningKind{Type: WarnConstraintViolation, Constraint: "pattern"}, "pattern_mismatch"},
+		{WarningKind{Type: WarnConstraintViolation, Constraint: "maxLength"}, "range_violation"},
+		{WarningKind{Type: WarnConstraintViolation, Constraint: "const"}, "enum_miss"},
+		{WarningKind{Type: WarnConstraintViolation, Constraint: "format"}, "format_mismatch"},
+		{WarningKind{Type: WarnConstraintViolation, Constraint: "type"}, "type_mismatch"},
+		// Constraints without a narrower code keep the kind's type.
+		{WarningKind{Type: WarnConstraintViolation, Constraint: "not"}, "constraint_violation"},
+		{WarningKind{Type: WarnConstraintUnevaluable, Constraint: "pattern"}, "constraint_unevaluable"},
+		{WarningKind{Type: WarnTruncated, Constraint: "maxLength"}, "truncated"},
+		{WarningKind{Type: "x-custom-kind"}, "x-custom-kind"},
+	} {
+		if got := (Warning{Kind: tc.kind}).Code(); got != tc.want {
+			t.Errorf("Code(%+v) = %q, want %q", tc.kind, got, tc.want)
+		}
+	}
+}
+
+// TestWarningCodesStable pins the published code strings; changing one
+// breaks every dashboard that aggregates on it.
+func TestWarningCodesStable(t *testing.T) {
+	for got, want := range map[string]string{
+		WarnConstraintViolation:   "constraint_violation",
+		WarnConstraintUnevaluable: "constraint_unevaluable",
+		WarnPathNotFound:          "path_not_found",
+		WarnPatternMismatch:       "pattern_mismatch",
+		WarnRangeViolation:        "range_violation",
+		WarnEnumMiss:              "enum_miss",
+		WarnFormatMismatch:        "format_mismatch",
+		WarnTypeMismatch:          "type_mismatch",
+		WarnEnumCoerced:           "enum_coerced",
+		WarnTruncated:             "truncated",
+	} {
+		if got != want {
+			t.Errorf("code %q changed, want %q", got, want)
+		}
+	}
+}
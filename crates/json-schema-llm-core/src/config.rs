@@ -1,5 +1,7 @@
 //! Configuration for schema conversion.
 
+use std::collections::{BTreeMap, BTreeSet};
+
 use serde::{Deserialize, Serialize};
 
 /// Target LLM provider for schema conversion.
@@ -46,6 +48,10 @@ pub struct ConvertOptions {
     /// being replaced with an opaque JSON-string placeholder (Pass 5).
     /// Default: 3. Keep low to avoid exponential schema expansion.
     pub recursion_limit: usize,
+    /// Per-definition overrides of `recursion_limit`, keyed by the `$ref`
+    /// JSON Pointer (e.g. `#/$defs/TreeNode`). Refs not listed use the
+    /// global limit. Default: empty.
+    pub recursion_limits: BTreeMap<String, usize>,
     /// Polymorphism strategy override.
     pub polymorphism: PolymorphismStrategy,
     /// If `true`, [`convert_all_components`](crate::convert_all_components) skips
@@ -54,6 +60,15 @@ pub struct ConvertOptions {
     /// This flag is a no-op when calling [`convert`](crate::convert) directly.
     /// Default: `false`.
     pub skip_components: bool,
+    /// If `true`, [`convert`](crate::convert) records the schema after each
+    /// pass in [`ConvertResult::trace`](crate::ConvertResult::trace), for
+    /// debugging unexpected output. Default: `false`.
+    pub trace: bool,
+    /// Passes [`convert`](crate::convert) skips, by the names used in
+    /// [`ConvertResult::trace`](crate::ConvertResult::trace) (e.g.
+    /// `dictionary`), for targets that accept the original construct.
+    /// `normalize` and `recursion` always run. Default: empty.
+    pub disable_passes: BTreeSet<String>,
 }
 
 /// Strategy for handling oneOf/anyOf polymorphism.
@@ -74,12 +89,31 @@ impl Default for ConvertOptions {
             mode: Mode::Strict,
             max_depth: 50,
             recursion_limit: 3,
+            recursion_limits: BTreeMap::new(),
             polymorphism: PolymorphismStrategy::AnyOf,
             skip_components: false,
+            trace: false,
+            disable_passes: BTreeSet::new(),
         }
     }
 }
 
+impl ConvertOptions {
+    /// Recursion limit for a `$ref` target: its override in
+    /// `recursion_limits`, or the global `recursion_limit`.
+    pub fn recursion_limit_for(&self, pointer: &str) -> usize {
+        self.recursion_limits
+            .get(pointer)
+            .copied()
+            .unwrap_or(self.recursion_limit)
+    }
+
+    /// Whether the optional pass `pass` runs (see `disable_passes`).
+    pub fn pass_enabled(&self, pass: &str) -> bool {
+        !self.disable_passes.contains(pass)
+    }
+}
+
 #[cfg(test)]
 mod tests {
     use super::*;
@@ -116,6 +150,27 @@ mod tests {
         assert_eq!(deserialized.polymorphism, PolymorphismStrategy::Flatten);
     }
 
+    #[test]
+    fn test_recursion_limits_override_global() {
+        let json = r##"{
+            "recursion-limit": 3,
+            "recursion-limits": { "#/$defs/TreeNode": 5, "#/$defs/Comment": 2 }
+        }"##;
+
+        let opts: ConvertOptions = serde_json::from_str(json).unwrap();
+        assert_eq!(opts.recursion_limit_for("#/$defs/TreeNode"), 5);
+        assert_eq!(opts.recursion_limit_for("#/$defs/Comment"), 2);
+        assert_eq!(opts.recursion_limit_for("#/$defs/Other"), 3);
+    }
+
+    #[test]
+    fn test_disable_passes() {
+        let opts: ConvertOptions =
+            serde_json::from_str(r#"{ "disable-passes": ["dictionary"] }"#).unwrap();
+        assert!(!opts.pass_enabled("dictionary"));
+        assert!(opts.pass_enabled("opaque"));
+    }
+
     #[test]
     fn test_mode_defaults_to_strict_when_omitted() {
         // Simulate JSON from an older caller that doesn't include the `mode` field
@@ -60,6 +60,19 @@ pub struct ConvertResult {
     /// Provider compatibility warnings/soft-errors.
     #[serde(default, skip_serializing_if = "Vec::is_empty")]
     pub provider_compat_errors: Vec<ProviderCompatError>,
+    /// The schema after each pass, in pipeline order. Empty unless
+    /// [`ConvertOptions::trace`] is set.
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub trace: Vec<PassSnapshot>,
+}
+
+/// The schema as it stood after one conversion pass.
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct PassSnapshot {
+    /// Pass name, e.g. `"normalize"` or `"strict"`.
+    pub pass: String,
+    /// The schema the pass produced.
+    pub schema: Value,
 }
 
 /// Convert a JSON Schema into an LLM-compatible structured output schema.
@@ -74,10 +87,20 @@ pub struct ConvertResult {
 /// A `ConvertResult` containing the converted schema and codec.
 pub fn convert(schema: &Value, options: &ConvertOptions) -> Result<ConvertResult, ConvertError> {
     let mut codec = Codec::new();
+    let mut trace = Vec::new();
+    let mut snapshot = |pass: &str, schema: &Value| {
+        if options.trace {
+            trace.push(PassSnapshot {
+                pass: pass.to_string(),
+                schema: schema.clone(),
+            });
+        }
+    };
 
     // Pass 0: Normalize ($ref resolution, draft migration)
     let p0 = passes::p0_normalize::normalize(schema, options)?;
     let schema = p0.pass.schema;
+    snapshot("normalize", &schema);
 
     if !p0.recursive_refs.is_empty() {
         tracing::debug!(
@@ -88,49 +111,94 @@ pub fn convert(schema: &Value, options: &ConvertOptions) -> Result<ConvertResult
     }
 
     // Pass 1: Composition (allOf merge)
-    let p1 = passes::p1_composition::compile_composition(schema, options)?;
-    let schema = p1.merge_into_codec(&mut codec);
+    let schema = if options.pass_enabled("composition") {
+        let p1 = passes::p1_composition::compile_composition(schema, options)?;
+        let schema = p1.merge_into_codec(&mut codec);
+        snapshot("composition", &schema);
+        schema
+    } else {
+        schema
+    };
 
     // Pass 2: Polymorphism (oneOf → anyOf)
-    let p2 = passes::p2_polymorphism::simplify_polymorphism(schema, options)?;
-    let schema = p2.merge_into_codec(&mut codec);
+    let schema = if options.pass_enabled("polymorphism") {
+        let p2 = passes::p2_polymorphism::simplify_polymorphism(schema, options)?;
+        let schema = p2.merge_into_codec(&mut codec);
+        snapshot("polymorphism", &schema);
+        schema
+    } else {
+        schema
+    };
 
     // Pass 3: Dictionary (Map → Array)
-    let p3 = passes::p3_dictionary::transpile_dictionaries(schema, options)?;
-    let schema = p3.merge_into_codec(&mut codec);
+    let schema = if options.pass_enabled("dictionary") {
+        let p3 = passes::p3_dictionary::transpile_dictionaries(schema, options)?;
+        let schema = p3.merge_into_codec(&mut codec);
+        snapshot("dictionary", &schema);
+        schema
+    } else {
+        schema
+    };
 
     // Pass 4: Opaque (open objects → string)
-    let p4 = passes::p4_opaque::stringify_opaque(schema, options)?;
-    let schema = p4.merge_into_codec(&mut codec);
+    let schema = if options.pass_enabled("opaque") {
+        let p4 = passes::p4_opaque::stringify_opaque(schema, options)?;
+        let schema = p4.merge_into_codec(&mut codec);
+        snapshot("opaque", &schema);
+        schema
+    } else {
+        schema
+    };
 
-    // Pass 5: Recursion Breaking
+    // Pass 5: Recursion Breaking (always runs: it inlines the remaining refs)
     let p5 = passes::p5_recursion::break_recursion(schema, options)?;
     let mut schema = p5.merge_into_codec(&mut codec);
+    snapshot("recursion", &schema);
 
     // Pass 6: Strict enforcement
-    if options.mode == Mode::Strict {
+    if options.mode == Mode::Strict && options.pass_enabled("strict") {
         let p6 = passes::p6_strict::enforce_strict(schema, options)?;
         schema = p6.merge_into_codec(&mut codec);
+        snapshot("strict", &schema);
     }
 
     // Pass 8: Adaptive opaque stringification (before constraint pruning
     // so it can detect `contains`, closed-tuple `prefixItems`, etc.)
-    let p8 = passes::p8_adaptive_opaque::adaptive_opaque(schema, options)?;
-    let schema = p8.merge_into_codec(&mut codec);
+    let schema = if options.pass_enabled("adaptive-opaque") {
+        let p8 = passes::p8_adaptive_opaque::adaptive_opaque(schema, options)?;
+        let schema = p8.merge_into_codec(&mut codec);
+        snapshot("adaptive-opaque", &schema);
+        schema
+    } else {
+        schema
+    };
 
     // Pass 7: Constraint pruning
-    let p7 = passes::p7_constraints::prune_constraints(schema, options)?;
-    let schema = p7.merge_into_codec(&mut codec);
+    let schema = if options.pass_enabled("constraints") {
+        let p7 = passes::p7_constraints::prune_constraints(schema, options)?;
+        let schema = p7.merge_into_codec(&mut codec);
+        snapshot("constraints", &schema);
+        schema
+    } else {
+        schema
+    };
 
     // Pass 9: Provider compatibility checks (soft errors)
-    let p9 = passes::p9_provider_compat::check_provider_compat(schema, options);
-    let provider_compat_errors = p9.errors;
-    let schema = p9.pass.merge_into_codec(&mut codec);
+    let (schema, provider_compat_errors) = if options.pass_enabled("provider-compat") {
+        let p9 = passes::p9_provider_compat::check_provider_compat(schema, options);
+        let provider_compat_errors = p9.errors;
+        let schema = p9.pass.merge_into_codec(&mut codec);
+        snapshot("provider-compat", &schema);
+        (schema, provider_compat_errors)
+    } else {
+        (schema, Vec::new())
+    };
 
     Ok(ConvertResult {
         schema,
         codec,
         provider_compat_errors,
+        trace,
     })
 }
 
@@ -710,6 +778,63 @@ mod tests {
         assert!(!opts.skip_components);
     }
 
+    #[test]
+    fn test_trace_records_each_pass() {
+        let schema = json!({
+            "type": "object",
+            "properties": { "tags": { "type": "object", "additionalProperties": { "type": "string" } } }
+        });
+        let untraced = convert(&schema, &default_opts()).unwrap();
+        assert!(untraced.trace.is_empty());
+
+        let opts = ConvertOptions {
+            trace: true,
+            ..default_opts()
+        };
+        let result = convert(&schema, &opts).unwrap();
+        let passes: Vec<&str> = result.trace.iter().map(|s| s.pass.as_str()).collect();
+        assert_eq!(
+            passes,
+            [
+                "normalize",
+                "composition",
+                "polymorphism",
+                "dictionary",
+                "opaque",
+                "recursion",
+                "strict",
+                "adaptive-opaque",
+                "constraints",
+                "provider-compat"
+            ]
+        );
+        assert_eq!(result.trace.last().unwrap().schema, result.schema);
+        assert_ne!(
+            result.trace[2].schema, result.trace[3].schema,
+            "dictionary pass rewrites the map"
+        );
+    }
+
+    #[test]
+    fn test_disable_passes_skips_dictionary() {
+        let schema = json!({
+            "type": "object",
+            "properties": { "tags": { "type": "object", "additionalProperties": { "type": "string" } } }
+        });
+        let mut opts = ConvertOptions {
+            trace: true,
+            ..default_opts()
+        };
+        opts.disable_passes.insert("dictionary".to_string());
+        let result = convert(&schema, &opts).unwrap();
+        assert!(result.trace.iter().all(|s| s.pass != "dictionary"));
+        assert!(!result
+            .codec
+            .transforms
+            .iter()
+            .any(|t| matches!(t, crate::codec::Transform::MapToArray { .. })));
+    }
+
     // -----------------------------------------------------------------------
     // Bridge JSON API — unit tests (#177)
     // -----------------------------------------------------------------------
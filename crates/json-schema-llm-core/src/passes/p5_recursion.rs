@@ -1,7 +1,8 @@
 //! Pass 5: Recursion Breaking
 //!
 //! Walks the schema tree, inlines all remaining `$ref` nodes, and breaks
-//! recursive cycles at `config.recursion_limit` by replacing them with
+//! recursive cycles at `config.recursion_limit` (or the per-ref override in
+//! `config.recursion_limits`) by replacing them with
 //! opaque JSON-string placeholders. Emits `RecursiveInflate` codec entries
 //! for round-trip rehydration.
 //!
@@ -90,7 +91,7 @@ impl crate::schema_walker::SchemaFolder for RecursionFolder<'_> {
             let type_name = extract_type_name(&ref_str);
             let count = self.ref_counts.get(&ref_str).copied().unwrap_or(0);
 
-            if count >= self.config.recursion_limit {
+            if count >= self.config.recursion_limit_for(&ref_str) {
                 // Break: replace with opaque string placeholder.
                 self.transforms.push(Transform::RecursiveInflate {
                     path: path.to_string(),
@@ -591,6 +592,54 @@ mod tests {
         );
     }
 
+    #[test]
+    fn test_per_ref_limit_override() {
+        let schema = json!({
+            "type": "object",
+            "properties": {
+                "tree": { "$ref": "#/$defs/TreeNode" },
+                "comment": { "$ref": "#/$defs/Comment" }
+            },
+            "$defs": {
+                "TreeNode": {
+                    "type": "object",
+                    "properties": { "child": { "$ref": "#/$defs/TreeNode" } }
+                },
+                "Comment": {
+                    "type": "object",
+                    "properties": { "reply": { "$ref": "#/$defs/Comment" } }
+                }
+            }
+        });
+
+        let mut config = config_with_limit(1);
+        config
+            .recursion_limits
+            .insert("#/$defs/TreeNode".to_string(), 3);
+        let result = break_recursion(schema, &config).unwrap();
+
+        let break_paths: Vec<String> = result
+            .transforms
+            .iter()
+            .filter_map(|t| match t {
+                Transform::RecursiveInflate { path, .. } => Some(path.clone()),
+                _ => None,
+            })
+            .collect();
+        assert!(
+            break_paths.contains(
+                &"#/properties/tree/properties/child/properties/child/properties/child".to_string()
+            ),
+            "TreeNode should unroll 3 deep; breaks: {:?}",
+            break_paths
+        );
+        assert!(
+            break_paths.contains(&"#/properties/comment/properties/reply".to_string()),
+            "Comment should use the global limit of 1; breaks: {:?}",
+            break_paths
+        );
+    }
+
     // -----------------------------------------------------------------------
     // Test 5: Gemini target skips recursion breaking
     // -----------------------------------------------------------------------
@@ -17,6 +17,14 @@
 //! - `jsl_alloc(len) → ptr` — allocate `len` bytes in guest linear memory
 //! - `jsl_free(ptr, len)` — free a guest allocation
 //!
+//! ### Capability Negotiation
+//!
+//! - `jsl_negotiate(offered, min_bytes) → agreed` — optional; the host offers
+//!   capability bits and the guest returns the subset it will use for the
+//!   rest of the instance's lifetime. With `CAP_GZIP` agreed, inputs may be
+//!   gzip-compressed and results of at least `min_bytes` are returned
+//!   compressed; either kind is recognized by the gzip magic header.
+//!
 //! ### Operations
 //!
 //! - `jsl_convert(schema_ptr, schema_len, opts_ptr, opts_len) → result_ptr`
@@ -43,6 +51,13 @@
 //! Panics will trap the WASM module — hosts should handle WASM traps at the
 //! runtime level. All internal code paths return errors rather than panicking.
 
+use std::io::{Read, Write};
+use std::sync::atomic::{AtomicU32, Ordering};
+
+use flate2::read::GzDecoder;
+use flate2::write::GzEncoder;
+use flate2::Compression;
+
 // ---------------------------------------------------------------------------
 // Result protocol
 // ---------------------------------------------------------------------------
@@ -63,6 +78,54 @@ pub extern "C" fn jsl_abi_version() -> u32 {
     ABI_VERSION
 }
 
+// ---------------------------------------------------------------------------
+// Capability negotiation
+// ---------------------------------------------------------------------------
+
+/// Capability bit: gzip-compressed payloads in both directions.
+const CAP_GZIP: u32 = 1 << 0;
+
+/// The two-byte gzip header. A JSON document never starts with 0x1f, so a
+/// payload carrying it is unambiguously compressed.
+const GZIP_MAGIC: [u8; 2] = [0x1f, 0x8b];
+
+/// Capabilities agreed by `jsl_negotiate`. Hosts use a fresh instance per
+/// call, so the agreement never outlives the call it was made for.
+static AGREED_CAPS: AtomicU32 = AtomicU32::new(0);
+
+/// Smallest result compressed once gzip is agreed.
+static COMPRESS_MIN: AtomicU32 = AtomicU32::new(u32::MAX);
+
+/// Agree on the capabilities in `offered` this binary supports.
+///
+/// `min_bytes` is the host's compression threshold; results shorter than it
+/// are returned as plain JSON. Returns the agreed capability bits.
+#[no_mangle]
+pub extern "C" fn jsl_negotiate(offered: u32, min_bytes: u32) -> u32 {
+    let agreed = offered & CAP_GZIP;
+    AGREED_CAPS.store(agreed, Ordering::Relaxed);
+    COMPRESS_MIN.store(min_bytes, Ordering::Relaxed);
+    agreed
+}
+
+fn gzip_agreed() -> bool {
+    AGREED_CAPS.load(Ordering::Relaxed) & CAP_GZIP != 0
+}
+
+/// Compress a result payload when gzip is agreed and it meets the host's
+/// threshold. Compression into memory cannot fail in practice; if it does,
+/// the payload is returned plain, which the host also accepts.
+fn encode_payload(bytes: Vec<u8>) -> Vec<u8> {
+    if !gzip_agreed() || bytes.len() < COMPRESS_MIN.load(Ordering::Relaxed) as usize {
+        return bytes;
+    }
+    let mut encoder = GzEncoder::new(Vec::with_capacity(bytes.len() / 4), Compression::fast());
+    if encoder.write_all(&bytes).is_err() {
+        return bytes;
+    }
+    encoder.finish().unwrap_or(bytes)
+}
+
 /// C-ABI result struct returned from `jsl_convert` and `jsl_rehydrate`.
 ///
 /// Layout: 12 bytes (3 × u32), `#[repr(C)]` for stable ABI.
@@ -81,12 +144,13 @@ impl JslResult {
     }
 }
 
-/// Leak a string as a `(ptr, len)` pair using a boxed slice.
+/// Leak a string as a `(ptr, len)` pair using a boxed slice, compressed
+/// when negotiated (see [`encode_payload`]).
 ///
 /// Using `into_boxed_slice()` ensures capacity == len, so the deallocation
 /// in `jsl_result_free` uses the correct layout.
 fn leak_string(s: String) -> (u32, u32) {
-    let boxed: Box<[u8]> = s.into_bytes().into_boxed_slice();
+    let boxed: Box<[u8]> = encode_payload(s.into_bytes()).into_boxed_slice();
     let len = boxed.len() as u32;
     let ptr = Box::into_raw(boxed) as *mut u8 as u32;
     (ptr, len)
@@ -184,6 +248,7 @@ pub unsafe extern "C" fn jsl_result_free(result_ptr: u32) {
 /// Read a UTF-8 string from guest linear memory with checked decoding.
 ///
 /// Returns `Ok(String)` on valid UTF-8, or `Err(JslResult ptr)` on invalid input.
+/// When gzip is agreed, a compressed input is decompressed first.
 ///
 /// # Null / zero-length handling
 ///
@@ -204,6 +269,13 @@ unsafe fn read_guest_str(ptr: u32, len: u32) -> Result<String, *mut JslResult> {
         ));
     }
     let slice = std::slice::from_raw_parts(ptr as *const u8, len as usize);
+    if gzip_agreed() && slice.starts_with(&GZIP_MAGIC) {
+        let mut out = String::new();
+        return GzDecoder::new(slice)
+            .read_to_string(&mut out)
+            .map(|_| out)
+            .map_err(|e| result_from_input_error("invalid_gzip", &format!("gzip input: {e}")));
+    }
     std::str::from_utf8(slice)
         .map(|s| s.to_owned())
         .map_err(|e| {
@@ -39,6 +39,8 @@ struct WasmConvertResult<'a> {
     codec: &'a json_schema_llm_core::Codec,
     #[serde(skip_serializing_if = "is_empty_slice")]
     provider_compat_errors: &'a [ProviderCompatError],
+    #[serde(skip_serializing_if = "is_empty_slice")]
+    trace: &'a [json_schema_llm_core::PassSnapshot],
 }
 
 /// WASM envelope for `rehydrate` results.
@@ -72,10 +74,16 @@ struct WasmConvertOptions {
     max_depth: Option<usize>,
     #[serde(alias = "recursion-limit")]
     recursion_limit: Option<usize>,
+    #[serde(alias = "recursion-limits")]
+    recursion_limits: Option<std::collections::BTreeMap<String, usize>>,
     #[serde(alias = "polymorphism")]
     polymorphism: Option<PolymorphismStrategy>,
     #[serde(alias = "skip-components")]
     skip_components: Option<bool>,
+    #[serde(alias = "trace")]
+    trace: Option<bool>,
+    #[serde(alias = "disable-passes")]
+    disable_passes: Option<std::collections::BTreeSet<String>>,
 }
 
 impl From<WasmConvertOptions> for ConvertOptions {
@@ -93,12 +101,21 @@ impl From<WasmConvertOptions> for ConvertOptions {
         if let Some(recursion_limit) = wasm.recursion_limit {
             opts.recursion_limit = recursion_limit;
         }
+        if let Some(recursion_limits) = wasm.recursion_limits {
+            opts.recursion_limits = recursion_limits;
+        }
         if let Some(polymorphism) = wasm.polymorphism {
             opts.polymorphism = polymorphism;
         }
         if let Some(skip_components) = wasm.skip_components {
             opts.skip_components = skip_components;
         }
+        if let Some(trace) = wasm.trace {
+            opts.trace = trace;
+        }
+        if let Some(disable_passes) = wasm.disable_passes {
+            opts.disable_passes = disable_passes;
+        }
         opts
     }
 }
@@ -184,6 +201,7 @@ pub fn convert(schema: JsValue, options: JsValue) -> Result<JsValue, JsValue> {
         schema: &result.schema,
         codec: &result.codec,
         provider_compat_errors: &result.provider_compat_errors,
+        trace: &result.trace,
     };
 
     let serializer = Serializer::json_compatible();
@@ -253,6 +271,8 @@ export interface ConvertOptions {
   recursionLimit?: number;
   polymorphism?: PolymorphismStrategy;
   skipComponents?: boolean;
+  trace?: boolean;
+  disablePasses?: string[];
 }
 
 export interface Codec {
@@ -290,6 +310,12 @@ export interface ConvertResult {
   schema: Record<string, unknown>;
   codec: Codec;
   providerCompatErrors?: ProviderCompatError[];
+  trace?: PassSnapshot[];
+}
+
+export interface PassSnapshot {
+  pass: string;
+  schema: Record<string, unknown>;
 }
 
 export interface RehydrateResult {
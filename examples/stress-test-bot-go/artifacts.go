@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// artifacts are the intermediate values of one pipeline run, kept so a
+// failure can be reproduced stage by stage. Stages not reached stay nil.
+type artifacts struct {
+	Original   any
+	Converted  any
+	Codec      any
+	Response   *string
+	Rehydrated any
+	Warnings   any
+}
+
+// unsafePathChars are replaced in artifact directory names.
+var unsafePathChars = regexp.MustCompile(`[^a-zA-Z0-9._+-]+`)
+
+// dumpArtifacts writes a failed run's artifacts and error under dir, in a
+// directory named after the schema, and returns that directory.
+func dumpArtifacts(dir, name string, r schemaResult) (string, error) {
+	path := filepath.Join(dir, unsafePathChars.ReplaceAllString(name, "_"))
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return "", err
+	}
+	a := r.artifacts
+	if a == nil {
+		a = &artifacts{}
+	}
+	type file struct {
+		name string
+		v    any
+	}
+	files := []file{
+		{"original.json", a.Original},
+		{"converted.json", a.Converted},
+		{"codec.json", a.Codec},
+		{"rehydrated.json", a.Rehydrated},
+		{"warnings.json", a.Warnings},
+	}
+	var verr *jsonschema.ValidationError
+	if errors.As(r.err, &verr) {
+		files = append(files, file{"validation.json", verr.DetailedOutput()})
+	}
+	for _, f := range files {
+		if f.v == nil {
+			continue
+		}
+		b, err := json.MarshalIndent(f.v, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", f.name, err)
+		}
+		if err := os.WriteFile(filepath.Join(path, f.name), append(b, '\n'), 0o644); err != nil {
+			return "", err
+		}
+	}
+	if a.Response != nil {
+		if err := os.WriteFile(filepath.Join(path, "response.txt"), []byte(*a.Response), 0o644); err != nil {
+			return "", err
+		}
+	}
+	msg := fmt.Sprintf("category: %s\nattempts: %d\n\n%v\n", r.category, r.attempts, r.err)
+	if err := os.WriteFile(filepath.Join(path, "error.txt"), []byte(msg), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
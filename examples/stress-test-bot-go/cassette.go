@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// errNoCassette is returned in replay mode for requests never recorded.
+var errNoCassette = errors.New("no recorded response")
+
+// cassette is an http.RoundTripper that records API responses to dir, one
+// file per distinct request, or replays them from it without network
+// access. Requests are matched by method, path and JSON body, so a replay
+// needs the same schemas, model and prompts as the recording.
+type cassette struct {
+	dir    string
+	replay bool
+	next   http.RoundTripper
+}
+
+// recording is the file format of one recorded exchange.
+type recording struct {
+	Method  string          `json:"method"`
+	Path    string          `json:"path"`
+	Request json.RawMessage `json:"request,omitempty"`
+	Status  int             `json:"status"`
+	Body    string          `json:"body"`
+}
+
+// newCassette returns a cassette over dir, creating it when recording.
+func newCassette(dir string, replay bool) (*cassette, error) {
+	if replay {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("replay: %w", err)
+		}
+	} else if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("record: %w", err)
+	}
+	return &cassette{dir: dir, replay: replay, next: http.DefaultTransport}, nil
+}
+
+func (c *cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	canonical := canonicalBody(body)
+	sum := sha256.Sum256([]byte(req.Method + " " + req.URL.Path + "\n" + string(canonical)))
+	path := filepath.Join(c.dir, hex.EncodeToString(sum[:12])+".json")
+
+	if c.replay {
+		b, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w for %s %s (%s)", errNoCassette, req.Method, req.URL.Path, filepath.Base(path))
+		}
+		if err != nil {
+			return nil, err
+		}
+		var rec recording
+		if err := json.Unmarshal(b, &rec); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+			StatusCode:    rec.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			Body:          io.NopCloser(bytes.NewReader([]byte(rec.Body))),
+			ContentLength: int64(len(rec.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := c.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	// Rate limits and server errors are not worth replaying.
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return resp, nil
+	}
+	rec := recording{Method: req.Method, Path: req.URL.Path, Status: resp.StatusCode, Body: string(respBody)}
+	if json.Valid(canonical) {
+		rec.Request = canonical
+	}
+	b, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	// Workers may record the same request at once: write atomically.
+	tmp, err := os.CreateTemp(c.dir, ".recording-*")
+	if err != nil {
+		return nil, err
+	}
+	_, werr := tmp.Write(append(b, '\n'))
+	if cerr := tmp.Close(); werr == nil {
+		werr = cerr
+	}
+	if werr == nil {
+		werr = os.Rename(tmp.Name(), path)
+	}
+	if werr != nil {
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("record %s: %w", path, werr)
+	}
+	return resp, nil
+}
+
+// canonicalBody re-encodes a JSON body with sorted keys, so requests match
+// however the client ordered them; other bodies are used as is.
+func canonicalBody(body []byte) []byte {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return b
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	"github.com/dotslashderek/json-schema-llm/bindings/go/bench"
+	"github.com/openai/openai-go"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// runCompare benchmarks the jsl pipeline against raw schema pass-through and
+// prompt-only JSON for every schema and model, printing a Markdown report
+// and optionally writing the full JSON report.
+func runCompare(client *openai.Client, schemas []schemaEntry, models []string, reportPath string) error {
+	engine, err := jsl.NewSchemaLlmEngine()
+	if err != nil {
+		return fmt.Errorf("initialize WASI engine: %w", err)
+	}
+	defer engine.Close()
+
+	cases := make([]bench.Case, len(schemas))
+	for i, s := range schemas {
+		cases[i] = bench.Case{Name: s.name, Schema: s.schema}
+	}
+	rep, err := bench.Run(context.Background(), engine, openAIGenerator(client), validateSchema, cases, bench.Config{
+		Models: models,
+		Logf: func(format string, args ...any) {
+			fmt.Printf(format+"\n", args...)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	if err := rep.WriteMarkdown(os.Stdout); err != nil {
+		return err
+	}
+	if reportPath == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(reportPath, append(b, '\n'), 0o644)
+}
+
+func openAIGenerator(client *openai.Client) bench.Generator {
+	return func(ctx context.Context, req bench.Request) (string, error) {
+		params := openai.ChatCompletionNewParams{
+			Model: openai.F(req.Model),
+			Messages: openai.F([]openai.ChatCompletionMessageParamUnion{
+				openai.UserMessage(req.Prompt),
+			}),
+		}
+		if req.Schema != nil {
+			params.ResponseFormat = openai.F[openai.ChatCompletionNewParamsResponseFormatUnion](
+				openai.ResponseFormatJSONSchemaParam{
+					Type: openai.F(openai.ResponseFormatJSONSchemaTypeJSONSchema),
+					JSONSchema: openai.F(openai.ResponseFormatJSONSchemaJSONSchemaParam{
+						Name:   openai.F("response"),
+						Schema: openai.F(any(req.Schema)),
+						Strict: openai.F(true),
+					}),
+				},
+			)
+		}
+		resp, err := client.Chat.Completions.New(ctx, params)
+		if err != nil {
+			var apiErr *openai.Error
+			if errors.As(err, &apiErr) && apiErr.StatusCode == 400 && req.Schema != nil {
+				return "", fmt.Errorf("%w: %v", bench.ErrSchemaRejected, err)
+			}
+			return "", err
+		}
+		if len(resp.Choices) == 0 {
+			return "", errors.New("openai: empty choices")
+		}
+		if refusal := resp.Choices[0].Message.Refusal; refusal != "" {
+			return "", &jsl.RefusalError{Provider: jsl.ProviderOpenAI, Reason: "refusal", Text: refusal}
+		}
+		return resp.Choices[0].Message.Content, nil
+	}
+}
+
+func validateSchema(schema, data any) error {
+	schemaBytes, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(string(schemaBytes)))
+	if err != nil {
+		return err
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("schema.json", doc); err != nil {
+		return err
+	}
+	sch, err := compiler.Compile("schema.json")
+	if err != nil {
+		return err
+	}
+	return sch.Validate(data)
+}
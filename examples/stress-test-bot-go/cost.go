@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// price is a model's list price in USD per million tokens.
+type price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// modelPrices are list prices at the time of writing, for estimates only;
+// pass -price for other models or when they change.
+var modelPrices = map[string]price{
+	"gpt-4o-mini":  {0.15, 0.60},
+	"gpt-4o":       {2.50, 10.00},
+	"gpt-4.1-nano": {0.10, 0.40},
+	"gpt-4.1-mini": {0.40, 1.60},
+	"gpt-4.1":      {2.00, 8.00},
+	"o3-mini":      {1.10, 4.40},
+	"o4-mini":      {1.10, 4.40},
+}
+
+// lookupPrice returns the price of model, matching dated snapshots
+// ("gpt-4o-mini-2024-07-18") by their longest known prefix.
+func lookupPrice(model string) (price, bool) {
+	best := ""
+	for name := range modelPrices {
+		if (model == name || strings.HasPrefix(model, name+"-")) && len(name) > len(best) {
+			best = name
+		}
+	}
+	p, ok := modelPrices[best]
+	return p, ok
+}
+
+// parsePrice reads a -price value: "input,output" in USD per million tokens.
+func parsePrice(v string) (price, error) {
+	in, out, ok := strings.Cut(v, ",")
+	if !ok {
+		return price{}, fmt.Errorf("price %q: want input,output in USD per million tokens", v)
+	}
+	var p price
+	var err error
+	if p.Input, err = strconv.ParseFloat(strings.TrimSpace(in), 64); err != nil {
+		return price{}, fmt.Errorf("price %q: %w", v, err)
+	}
+	if p.Output, err = strconv.ParseFloat(strings.TrimSpace(out), 64); err != nil {
+		return price{}, fmt.Errorf("price %q: %w", v, err)
+	}
+	return p, nil
+}
+
+// cost estimates the USD cost of u at p.
+func (p price) cost(u tokenUsage) float64 {
+	return (float64(u.Prompt)*p.Input + float64(u.Completion)*p.Output) / 1e6
+}
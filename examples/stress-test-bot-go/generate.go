@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// adversarialKeys are property names that trip escaping, normalization and
+// identifier rules.
+var adversarialKeys = []string{
+	"ключ", "键", "キー", "🔑", "emoji_😀_key", "a/b", "a~b", "~1", "$ref", "$defs",
+	"with space", "dot.key", "zero\u200bwidth", "null", "__proto__", "constructor",
+	"\u00e9", "e\u0301", "RTL_שלום", "#", "%20",
+}
+
+// feature adds one adversarial construct as a property of an object schema
+// and returns its short name.
+type feature func(g *adversary, props map[string]any, required *[]any) string
+
+var features = []feature{
+	(*adversary).deepNesting,
+	(*adversary).wideEnum,
+	(*adversary).unicodeKeys,
+	(*adversary).booleanSubschemas,
+	(*adversary).polymorphism,
+}
+
+// adversary synthesizes adversarial schemas in the spirit of the
+// checked-in stress corpus (tests/schemas/stress), deterministically from
+// a seed.
+type adversary struct {
+	rng *rand.Rand
+}
+
+// generateSchemas returns n adversarial schemas for seed. Each mixes one
+// to three features into an object root; its name lists them, so a
+// failing schema is reproduced by -generate with the same seed.
+func generateSchemas(n int, seed int64) []schemaEntry {
+	g := &adversary{rng: rand.New(rand.NewSource(seed))}
+	entries := make([]schemaEntry, n)
+	for i := range entries {
+		props := map[string]any{}
+		var required []any
+		var names []string
+		for _, j := range g.rng.Perm(len(features))[:1+g.rng.Intn(3)] {
+			names = append(names, features[j](g, props, &required))
+		}
+		schema := map[string]any{"type": "object", "properties": props}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		entries[i] = schemaEntry{
+			name:   fmt.Sprintf("generated/%d_%03d_%s", seed, i, strings.Join(names, "+")),
+			schema: schema,
+		}
+	}
+	return entries
+}
+
+// deepNesting nests objects 5–50 levels deep, 1–5 leaves wide per level.
+func (g *adversary) deepNesting(props map[string]any, required *[]any) string {
+	depth, width := 5+g.rng.Intn(46), 1+g.rng.Intn(5)
+	var node map[string]any
+	for level := depth - 1; level >= 0; level-- {
+		p := map[string]any{}
+		req := []any{}
+		for i := 0; i < width; i++ {
+			name := fmt.Sprintf("item_%d", i)
+			p[name] = map[string]any{"type": []string{"string", "integer", "number", "boolean"}[g.rng.Intn(4)]}
+			req = append(req, name)
+		}
+		if node != nil {
+			name := fmt.Sprintf("level_%d", level+1)
+			p[name] = node
+			req = append(req, name)
+		}
+		node = map[string]any{"type": "object", "properties": p, "required": req}
+	}
+	props["level_0"] = node
+	*required = append(*required, "level_0")
+	return fmt.Sprintf("depth%d", depth)
+}
+
+// wideEnum adds an enum of 50–2000 values, optionally mixing types and
+// near-duplicate strings.
+func (g *adversary) wideEnum(props map[string]any, required *[]any) string {
+	size := 50 + g.rng.Intn(1951)
+	mixed := g.rng.Intn(3) == 0
+	values := make([]any, size)
+	for i := range values {
+		switch {
+		case mixed && i%4 == 1:
+			values[i] = float64(i)
+		case mixed && i%4 == 3:
+			values[i] = nil
+		case i%7 == 0:
+			values[i] = fmt.Sprintf("Value %d ", i) // trailing space
+		default:
+			values[i] = fmt.Sprintf("value_%d_%s", i, adversarialKeys[i%len(adversarialKeys)])
+		}
+	}
+	props["wide_enum"] = map[string]any{"enum": values}
+	*required = append(*required, "wide_enum")
+	return fmt.Sprintf("enum%d", size)
+}
+
+// unicodeKeys adds an object keyed by adversarial names, some required.
+func (g *adversary) unicodeKeys(props map[string]any, required *[]any) string {
+	p := map[string]any{}
+	var req []any
+	for _, i := range g.rng.Perm(len(adversarialKeys))[:5+g.rng.Intn(len(adversarialKeys)-5)] {
+		key := adversarialKeys[i]
+		p[key] = map[string]any{"type": "string", "description": "Key " + key}
+		if g.rng.Intn(2) == 0 {
+			req = append(req, key)
+		}
+	}
+	node := map[string]any{"type": "object", "properties": p}
+	if len(req) > 0 {
+		node["required"] = req
+	}
+	props["unicode_keys"] = node
+	*required = append(*required, "unicode_keys")
+	return "unicode"
+}
+
+// booleanSubschemas uses true and false as property, items and
+// additionalProperties schemas. false properties stay optional, so the
+// schema remains satisfiable.
+func (g *adversary) booleanSubschemas(props map[string]any, required *[]any) string {
+	props["anything"] = true
+	props["nothing"] = false
+	props["list_of_anything"] = map[string]any{"type": "array", "items": true}
+	props["empty_list"] = map[string]any{"type": "array", "items": false}
+	props["open_map"] = map[string]any{"type": "object", "additionalProperties": g.rng.Intn(2) == 0}
+	*required = append(*required, "anything", "list_of_anything", "open_map")
+	return "boolean"
+}
+
+// polymorphism nests anyOf/oneOf unions of objects and scalars 1–4 deep.
+func (g *adversary) polymorphism(props map[string]any, required *[]any) string {
+	depth := 1 + g.rng.Intn(4)
+	props["union"] = g.union(depth)
+	*required = append(*required, "union")
+	return fmt.Sprintf("poly%d", depth)
+}
+
+func (g *adversary) union(depth int) map[string]any {
+	kw := []string{"anyOf", "oneOf"}[g.rng.Intn(2)]
+	branches := make([]any, 2+g.rng.Intn(3))
+	for i := range branches {
+		switch {
+		case depth > 1 && i == 0:
+			branches[i] = g.union(depth - 1)
+		case kw == "oneOf":
+			// Distinct const tags keep oneOf branches exclusive.
+			tag := fmt.Sprintf("variant_%d_%d", depth, i)
+			branches[i] = map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"kind": map[string]any{"const": tag}, "value": map[string]any{"type": "string"}},
+				"required":   []any{"kind"},
+			}
+		default:
+			branches[i] = map[string]any{"type": []string{"string", "integer", "boolean", "null"}[g.rng.Intn(4)]}
+		}
+	}
+	return map[string]any{kw: branches}
+}
@@ -1,6 +1,6 @@
 module github.com/dotslashderek/json-schema-llm/examples/stress-test-bot-go
 
-go 1.22
+go 1.23.0
 
 require (
 	github.com/dotslashderek/json-schema-llm/bindings/go v0.0.0
@@ -10,11 +10,13 @@ require (
 
 require (
 	github.com/tetratelabs/wazero v1.8.2 // indirect
-	github.com/tidwall/gjson v1.14.4 // indirect
+	github.com/tidwall/gjson v1.18.0 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.1 // indirect
 	github.com/tidwall/sjson v1.2.5 // indirect
-	golang.org/x/text v0.16.0 // indirect
+	go.opentelemetry.io/otel v1.32.0 // indirect
+	go.opentelemetry.io/otel/trace v1.32.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
 )
 
 replace github.com/dotslashderek/json-schema-llm/bindings/go => ../../bindings/go
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	"github.com/dotslashderek/json-schema-llm/bindings/go/soak"
+)
+
+// maxHammerMismatches bounds the mismatches printed per phase.
+const maxHammerMismatches = 5
+
+// hammerCacheSize is the convert cache of the shared engine, large enough
+// that repeated schemas are served from it and their results are handed to
+// several goroutines at once.
+const hammerCacheSize = 256
+
+// errHammerMismatch is returned by runHammer when a concurrent result
+// differs from the single-threaded baseline.
+var errHammerMismatch = errors.New("concurrent results differ from the baseline")
+
+// hammerEngine is what a hammer phase drives; it is called from many
+// goroutines at once.
+type hammerEngine interface {
+	Convert(schema any, opts *jsl.ConvertOptions) (*jsl.ConvertResult, error)
+	Rehydrate(data, codec, schema any) (*jsl.RehydrateResult, error)
+}
+
+// lockedEngine is one engine shared the way the binding documents: engines
+// are not safe for concurrent use, so callers serialize the calls. Results
+// are used outside the lock.
+type lockedEngine struct {
+	mu     sync.Mutex
+	engine *jsl.SchemaLlmEngine
+}
+
+func (l *lockedEngine) Convert(schema any, opts *jsl.ConvertOptions) (*jsl.ConvertResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.engine.Convert(schema, opts)
+}
+
+func (l *lockedEngine) Rehydrate(data, codec, schema any) (*jsl.RehydrateResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.engine.Rehydrate(data, codec, schema)
+}
+
+// hammerOutcome runs convert → synthetic output → rehydrate → validate on
+// one schema and returns everything observable about it as a string, so
+// runs can be compared exactly. Failures are part of the outcome: the same
+// schema must fail the same way every time.
+func hammerOutcome(engine hammerEngine, s schemaEntry) string {
+	converted, err := engine.Convert(s.schema, nil)
+	if err != nil {
+		return "convert error: " + err.Error()
+	}
+	data := soak.MinimalInstance(converted.Schema)
+	rehydrated, err := engine.Rehydrate(data, converted.Codec, s.schema)
+	if err != nil {
+		return "rehydrate error: " + err.Error()
+	}
+	validation := "valid"
+	if v, err := converted.Validator(); err != nil {
+		validation = err.Error()
+	} else if err := v.Validate(rehydrated.Data); err != nil {
+		validation = err.Error()
+	}
+	b, err := jsl.CanonicalJSON(map[string]any{
+		"schema":     converted.Schema,
+		"codec":      converted.Codec,
+		"data":       rehydrated.Data,
+		"warnings":   rehydrated.Warnings,
+		"validation": validation,
+	})
+	if err != nil {
+		return "encode outcome: " + err.Error()
+	}
+	return string(b)
+}
+
+// runHammer drives the binding from goroutines concurrent goroutines, each
+// running iterations pipelines over the schemas, and fails when any result
+// differs from a single-threaded baseline. It runs two phases: one engine
+// shared behind a lock (with a convert cache, so cached results are shared
+// across goroutines too), then one engine per goroutine. Build with -race
+// to have the race detector check both.
+func runHammer(schemas []schemaEntry, goroutines, iterations int) error {
+	if len(schemas) == 0 {
+		return errors.New("no schemas")
+	}
+	goroutines, iterations = max(1, goroutines), max(1, iterations)
+	baseEngine, err := jsl.NewSchemaLlmEngine()
+	if err != nil {
+		return fmt.Errorf("initialize WASI engine: %w", err)
+	}
+	baseline := make([]string, len(schemas))
+	for i, s := range schemas {
+		baseline[i] = hammerOutcome(baseEngine, s)
+	}
+	baseEngine.Close()
+
+	shared, err := jsl.NewSchemaLlmEngine(jsl.WithConvertCache(hammerCacheSize))
+	if err != nil {
+		return fmt.Errorf("initialize WASI engine: %w", err)
+	}
+	defer shared.Close()
+	locked := &lockedEngine{engine: shared}
+	sharedOK := hammerPhase("shared engine", schemas, baseline, goroutines, iterations, func(int) hammerEngine { return locked })
+	stats := shared.ConvertCacheStats()
+	fmt.Printf("   cache: %d hits, %d misses\n", stats.Hits, stats.Misses)
+
+	engines := make([]*jsl.SchemaLlmEngine, goroutines)
+	defer func() {
+		for _, e := range engines {
+			if e != nil {
+				e.Close()
+			}
+		}
+	}()
+	for g := range engines {
+		if engines[g], err = jsl.NewSchemaLlmEngine(); err != nil {
+			return fmt.Errorf("initialize WASI engine: %w", err)
+		}
+	}
+	ownOK := hammerPhase("engine per goroutine", schemas, baseline, goroutines, iterations, func(g int) hammerEngine { return engines[g] })
+
+	if !sharedOK || !ownOK {
+		return errHammerMismatch
+	}
+	return nil
+}
+
+// hammerPhase runs one phase of runHammer, with engineFor giving goroutine
+// g its engine, prints its summary and reports whether every outcome
+// matched the baseline.
+func hammerPhase(name string, schemas []schemaEntry, baseline []string, goroutines, iterations int, engineFor func(g int) hammerEngine) bool {
+	var calls, mismatches atomic.Int64
+	var mu sync.Mutex
+	var shown []string
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			engine := engineFor(g)
+			for it := 0; it < iterations; it++ {
+				// Offset by goroutine so different schemas run side by side.
+				i := (g + it*goroutines) % len(schemas)
+				got := hammerOutcome(engine, schemas[i])
+				calls.Add(1)
+				if got == baseline[i] {
+					continue
+				}
+				mismatches.Add(1)
+				mu.Lock()
+				if len(shown) < maxHammerMismatches {
+					shown = append(shown, fmt.Sprintf("%s (goroutine %d, iteration %d):\n      want %s\n      got  %s", schemas[i].name, g, it, truncate(baseline[i], 200), truncate(got, 200)))
+				}
+				mu.Unlock()
+			}
+		}(g)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	status := "✅"
+	if mismatches.Load() > 0 {
+		status = "❌"
+	}
+	fmt.Printf("%s %s: %d goroutines, %d pipelines in %v (%.0f/s), %d mismatches\n",
+		status, name, goroutines, calls.Load(), elapsed.Round(time.Millisecond), float64(calls.Load())/elapsed.Seconds(), mismatches.Load())
+	for _, m := range shown {
+		fmt.Printf("   %s\n", m)
+	}
+	return mismatches.Load() == 0
+}
+
+// truncate shortens s to at most n bytes for display.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
@@ -16,6 +17,7 @@ import (
 	"time"
 
 	jsl "github.com/dotslashderek/json-schema-llm/bindings/go"
+	"github.com/dotslashderek/json-schema-llm/bindings/go/compat"
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
 	"github.com/santhosh-tekuri/jsonschema/v6"
@@ -23,25 +25,86 @@ import (
 
 func main() {
 	count := flag.Int("count", 0, "Number of schemas to test (0 = all)")
-	seed := flag.Int("seed", 0, "Random seed for schema selection")
-	model := flag.String("model", "gpt-4o-mini", "OpenAI model to use")
+	seed := flag.Int("seed", 0, "Random seed for schema selection, or for -generate")
+	generate := flag.Int("generate", 0, "Test N adversarial schemas synthesized from -seed instead of the corpus")
+	model := flag.String("model", "gpt-4o-mini", "OpenAI model to use (comma-separated with -compare)")
+	compare := flag.Bool("compare", false, "Benchmark the jsl pipeline against raw schema pass-through and prompt-only JSON")
+	report := flag.String("report", "", "Write a machine-readable report: json or junit (json only with -compare)")
+	out := flag.String("out", "", "Report file (default stress-report.json or stress-report.xml)")
 	schemasDir := flag.String("schemas-dir", "", "Path to schemas directory")
+	baseURL := flag.String("base-url", "", "Base URL of an OpenAI-compatible API (e.g. http://localhost:11434/v1 for ollama)")
+	apiKeyEnv := flag.String("api-key-env", "OPENAI_API_KEY", "Environment variable holding the API key (may be unset for local servers)")
+	workers := flag.Int("workers", 1, "Number of schemas tested concurrently, each worker with its own engine (ignored with -compare)")
+	retries := flag.Int("retries", 3, "Retries per schema after a 429, 5xx or transport error")
+	retryBackoff := flag.Duration("retry-backoff", 2*time.Second, "Delay before the first retry, doubling per retry, unless the server sends Retry-After")
+	priceFlag := flag.String("price", "", "Model price as input,output USD per million tokens, overriding the built-in list prices for cost estimates")
+	record := flag.String("record", "", "Record API responses to this directory")
+	replay := flag.String("replay", "", "Replay API responses recorded with -record from this directory, offline")
+	artifactsDir := flag.String("artifacts", "", "Write each failed schema's converted schema, codec, raw response, rehydrated output and errors under this directory")
+	statePath := flag.String("state", ".stress-state.json", "Checkpoint file recording completed schemas, removed when the run finishes")
+	resume := flag.Bool("resume", false, "Continue the run checkpointed in -state after its last completed schema")
+	only := flag.String("only", "", "Test only schemas with one of these comma-separated tags ("+strings.Join(allTags, ", ")+")")
+	skip := flag.String("skip", "", "Skip schemas with any of these comma-separated tags")
+	hammer := flag.Bool("hammer", false, "Drive the binding from many goroutines offline and compare every result with a single-threaded run (build with -race)")
+	hammerGoroutines := flag.Int("hammer-goroutines", 32, "Goroutines for -hammer")
+	hammerIterations := flag.Int("hammer-iterations", 50, "Pipelines per goroutine for -hammer")
+	rpm := flag.Int("rpm", 0, "Maximum requests started per minute across all workers (0 = unlimited; ignored with -compare)")
 	flag.Parse()
 
+	modelPrice, hasPrice := lookupPrice(*model)
+	if *priceFlag != "" {
+		p, err := parsePrice(*priceFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -price: %v\n", err)
+			os.Exit(2)
+		}
+		modelPrice, hasPrice = p, true
+	}
+
+	switch {
+	case *record != "" && *replay != "":
+		fmt.Fprintf(os.Stderr, "-record and -replay are mutually exclusive\n")
+		os.Exit(2)
+	case *report != "" && *report != reportJSON && *report != reportJUnit:
+		fmt.Fprintf(os.Stderr, "Unknown -report format %q (want %s or %s)\n", *report, reportJSON, reportJUnit)
+		os.Exit(2)
+	case *compare && *report == reportJUnit:
+		fmt.Fprintf(os.Stderr, "-compare only writes json reports\n")
+		os.Exit(2)
+	}
+
+	onlyTags, err := parseTags(*only)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -only: %v\n", err)
+		os.Exit(2)
+	}
+	skipTags, err := parseTags(*skip)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -skip: %v\n", err)
+		os.Exit(2)
+	}
+
 	if *schemasDir == "" {
 		// Default: relative to this binary
 		*schemasDir = filepath.Join("..", "..", "tests", "schemas")
 	}
 
-	// Load schemas
-	schemas, err := loadSchemas(*schemasDir)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load schemas: %v\n", err)
-		os.Exit(1)
+	// Load or synthesize schemas
+	var schemas []schemaEntry
+	if *generate > 0 {
+		schemas = generateSchemas(*generate, int64(*seed))
+	} else {
+		schemas, err = loadSchemas(*schemasDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load schemas: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
+	schemas = filterSchemas(schemas, onlyTags, skipTags)
+
 	// Shuffle with deterministic PRNG
-	if *seed != 0 {
+	if *seed != 0 && *generate == 0 {
 		shuffle(schemas, uint32(*seed))
 	}
 
@@ -49,43 +112,143 @@ func main() {
 		schemas = schemas[:*count]
 	}
 
+	if *hammer {
+		fmt.Printf("🔨 Go Stress Test Bot: hammer mode\n")
+		fmt.Printf("   Schemas: %d\n\n", len(schemas))
+		if err := runHammer(schemas, *hammerGoroutines, *hammerIterations); err != nil {
+			fmt.Fprintf(os.Stderr, "Hammer failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	fmt.Printf("🤖 Go Stress Test Bot\n")
 	fmt.Printf("   Model: %s\n", *model)
+	if *baseURL != "" {
+		fmt.Printf("   Endpoint: %s\n", *baseURL)
+	}
 	fmt.Printf("   Schemas: %d\n", len(schemas))
-	fmt.Printf("   Seed: %d\n\n", *seed)
+	if *only != "" || *skip != "" {
+		fmt.Printf("   Filter: only=%q skip=%q\n", *only, *skip)
+	}
+	fmt.Printf("   Seed: %d\n", *seed)
+	fmt.Printf("   Workers: %d\n", *workers)
 
-	// Initialize engine
-	engine, err := jsl.New()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize WASI engine: %v\n", err)
-		os.Exit(1)
+	clientOpts := clientOptions(*baseURL, *apiKeyEnv)
+	if *record != "" || *replay != "" {
+		c, err := newCassette(*record+*replay, *replay != "")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Cassette: %v\n", err)
+			os.Exit(1)
+		}
+		clientOpts = append(clientOpts, option.WithHTTPClient(&http.Client{Transport: c}))
+		if c.replay {
+			// Replays never reach the API, so any key will do.
+			clientOpts = append(clientOpts, option.WithAPIKey("replay"))
+			fmt.Printf("   Replaying: %s\n", c.dir)
+		} else {
+			fmt.Printf("   Recording: %s\n", c.dir)
+		}
+	}
+	fmt.Println()
+
+	if *compare {
+		client := openai.NewClient(clientOpts...)
+		path := ""
+		if *report != "" {
+			path = reportPath(*out, *report)
+		}
+		if err := runCompare(client, schemas, strings.Split(*model, ","), path); err != nil {
+			fmt.Fprintf(os.Stderr, "Compare failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
-	defer engine.Close()
 
-	// Initialize OpenAI client
-	client := openai.NewClient(option.WithAPIKey(os.Getenv("OPENAI_API_KEY")))
+	// Initialize OpenAI client; retryPolicy replaces the SDK's retries so
+	// attempts are counted in the report.
+	client := openai.NewClient(append(clientOpts, option.WithMaxRetries(0))...)
 
-	passed := 0
-	failed := 0
+	rep := &runReport{
+		Model:      *model,
+		Endpoint:   *baseURL,
+		Seed:       *seed,
+		Workers:    *workers,
+		Started:    time.Now(),
+		Categories: map[string]int{},
+		Schemas:    []schemaReport{},
+	}
+	if hasPrice {
+		rep.Price = &modelPrice
+	}
 	var totalElapsed time.Duration
 
-	for i, s := range schemas {
-		fmt.Printf("[%d/%d] %s ... ", i+1, len(schemas), s.name)
-		ok, elapsed, testErr := testSchema(engine, client, s, *model)
-		totalElapsed += elapsed
-		if ok {
-			passed++
-			fmt.Printf("✅ (%.2fs)\n", elapsed.Seconds())
+	state := &runState{Model: *model, Schemas: schemaNames(schemas), Completed: []schemaReport{}}
+	if *resume {
+		st, err := loadState(*statePath, *model, schemas)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot resume: %v\n", err)
+			os.Exit(1)
+		}
+		state = st
+		for _, s := range state.Completed {
+			rep.addEntry(s)
+			totalElapsed += s.Duration
+		}
+		fmt.Printf("⏩ Resuming after %d/%d completed schemas (%d passed, %d failed)\n",
+			len(state.Completed), len(schemas), rep.Passed, rep.Failed)
+	}
+	done := len(state.Completed)
+
+	err = runWorkers(client, schemas[done:], *model, *workers, newLimiter(*rpm), retryPolicy{max: *retries, base: *retryBackoff}, func(j int, r schemaResult) {
+		i := done + j
+		fmt.Printf("[%d/%d] %s ... ", i+1, len(schemas), schemas[i].name)
+		totalElapsed += r.elapsed
+		state.Completed = append(state.Completed, rep.add(schemas[i].name, r))
+		if err := state.save(*statePath); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to checkpoint: %v\n", err)
+		}
+		if r.ok {
+			fmt.Printf("✅ (%.2fs, %d tokens)\n", r.elapsed.Seconds(), r.usage.Total)
 		} else {
-			failed++
-			fmt.Printf("❌ %v\n", testErr)
+			fmt.Printf("❌ %v\n", r.err)
+			if *artifactsDir != "" {
+				if path, err := dumpArtifacts(*artifactsDir, schemas[i].name, r); err != nil {
+					fmt.Fprintf(os.Stderr, "   failed to write artifacts: %v\n", err)
+				} else {
+					fmt.Printf("   artifacts: %s\n", path)
+				}
+			}
 		}
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Stress run failed: %v\n", err)
+		os.Exit(1)
 	}
+	rep.Duration = time.Since(rep.Started)
+	os.Remove(*statePath)
 
-	fmt.Printf("\n📊 Results: %d passed, %d failed, %.2fs total\n",
-		passed, failed, totalElapsed.Seconds())
+	fmt.Printf("\n📊 Results: %d passed, %d failed, %.2fs total, %.2fs wall\n",
+		rep.Passed, rep.Failed, totalElapsed.Seconds(), rep.Duration.Seconds())
+	fmt.Printf("🔢 Tokens: %d prompt, %d completion, %d total\n",
+		rep.Usage.Prompt, rep.Usage.Completion, rep.Usage.Total)
+	if rep.CostUSD != nil {
+		fmt.Printf("💰 Estimated cost: $%.4f (%s at $%.2f/$%.2f per 1M input/output tokens)\n",
+			*rep.CostUSD, *model, rep.Price.Input, rep.Price.Output)
+	} else {
+		fmt.Printf("💰 Estimated cost: unknown for %s (pass -price input,output)\n", *model)
+	}
 
-	if failed > 0 {
+	if *report != "" {
+		path := reportPath(*out, *report)
+		if err := writeReport(rep, *report, path); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("📝 Report written to %s\n", path)
+	}
+
+	if rep.Failed > 0 {
 		os.Exit(1)
 	}
 }
@@ -135,28 +298,49 @@ func loadSchemas(dir string) ([]schemaEntry, error) {
 	return entries, nil
 }
 
+// clientOptions targets baseURL (the OpenAI API when empty) with the key
+// in the apiKeyEnv variable. Local servers such as ollama, vLLM and LiteLLM
+// proxies usually need no key, so the variable may be unset; the SDK then
+// falls back to OPENAI_API_KEY, if set.
+func clientOptions(baseURL, apiKeyEnv string) []option.RequestOption {
+	var opts []option.RequestOption
+	if key := os.Getenv(apiKeyEnv); key != "" {
+		opts = append(opts, option.WithAPIKey(key))
+	}
+	if baseURL != "" {
+		opts = append(opts, option.WithBaseURL(baseURL))
+	}
+	return opts
+}
+
 func testSchema(
-	engine *jsl.Engine,
+	engine *compat.Engine,
 	client *openai.Client,
 	s schemaEntry,
 	model string,
-) (bool, time.Duration, error) {
+) schemaResult {
 	start := time.Now()
+	var usage tokenUsage
+	art := &artifacts{Original: s.schema}
+	fail := func(category string, err error) schemaResult {
+		return schemaResult{elapsed: time.Since(start), category: category, err: err, usage: usage, artifacts: art}
+	}
 
 	// 1. Convert
 	convertResult, err := engine.Convert(s.schema, nil)
 	if err != nil {
-		return false, time.Since(start), fmt.Errorf("convert: %w", err)
+		return fail(categoryConvert, fmt.Errorf("convert: %w", err))
 	}
+	art.Converted, art.Codec = convertResult.Schema, convertResult.Codec
 
 	// 2. Call OpenAI
 	convertedSchemaBytes, err := json.Marshal(convertResult.Schema)
 	if err != nil {
-		return false, time.Since(start), fmt.Errorf("marshal converted schema: %w", err)
+		return fail(categoryConvert, fmt.Errorf("marshal converted schema: %w", err))
 	}
 	var schemaParam map[string]any
 	if err := json.Unmarshal(convertedSchemaBytes, &schemaParam); err != nil {
-		return false, time.Since(start), fmt.Errorf("unmarshal schema param: %w", err)
+		return fail(categoryConvert, fmt.Errorf("unmarshal schema param: %w", err))
 	}
 
 	resp, err := client.Chat.Completions.New(context.Background(),
@@ -179,53 +363,69 @@ func testSchema(
 		},
 	)
 	if err != nil {
-		return false, time.Since(start), fmt.Errorf("openai: %w", err)
+		if isRateLimited(err) {
+			return fail(categoryRateLimit, fmt.Errorf("openai: %w", err))
+		}
+		return fail(categoryAPI, fmt.Errorf("openai: %w", err))
+	}
+	usage = tokenUsage{
+		Prompt:     resp.Usage.PromptTokens,
+		Completion: resp.Usage.CompletionTokens,
+		Total:      resp.Usage.TotalTokens,
 	}
 
 	if len(resp.Choices) == 0 {
-		return false, time.Since(start), fmt.Errorf("openai: empty choices")
+		return fail(categoryAPI, fmt.Errorf("openai: empty choices"))
 	}
 
+	if refusal := resp.Choices[0].Message.Refusal; refusal != "" {
+		return fail(categoryRefusal, &jsl.RefusalError{Provider: jsl.ProviderOpenAI, Reason: "refusal", Text: refusal})
+	}
 	content := resp.Choices[0].Message.Content
+	art.Response = &content
 	var llmData any
 	if err := json.Unmarshal([]byte(content), &llmData); err != nil {
-		return false, time.Since(start), fmt.Errorf("parse llm response: %w", err)
+		return fail(categoryParse, fmt.Errorf("parse llm response: %w", err))
 	}
 
 	// 3. Rehydrate
 	rehydrateResult, err := engine.Rehydrate(llmData, convertResult.Codec, s.schema)
 	if err != nil {
-		return false, time.Since(start), fmt.Errorf("rehydrate: %w", err)
+		return fail(categoryRehydrate, fmt.Errorf("rehydrate: %w", err))
+	}
+	art.Rehydrated = rehydrateResult.Data
+	if len(rehydrateResult.Warnings) > 0 {
+		art.Warnings = rehydrateResult.Warnings
 	}
 
 	// 4. Validate
 	rehydratedBytes, err := json.Marshal(rehydrateResult.Data)
 	if err != nil {
-		return false, time.Since(start), fmt.Errorf("marshal rehydrated: %w", err)
+		return fail(categoryRehydrate, fmt.Errorf("marshal rehydrated: %w", err))
 	}
 	schemaBytes, err := json.Marshal(s.schema)
 	if err != nil {
-		return false, time.Since(start), fmt.Errorf("marshal schema: %w", err)
+		return fail(categoryValidate, fmt.Errorf("marshal schema: %w", err))
 	}
 
 	compiler := jsonschema.NewCompiler()
 	if err := compiler.AddResource("schema.json", strings.NewReader(string(schemaBytes))); err != nil {
-		return false, time.Since(start), fmt.Errorf("add schema: %w", err)
+		return fail(categoryValidate, fmt.Errorf("add schema: %w", err))
 	}
 	sch, err := compiler.Compile("schema.json")
 	if err != nil {
-		return false, time.Since(start), fmt.Errorf("compile schema: %w", err)
+		return fail(categoryValidate, fmt.Errorf("compile schema: %w", err))
 	}
 
 	var rehydratedAny any
 	if err := json.Unmarshal(rehydratedBytes, &rehydratedAny); err != nil {
-		return false, time.Since(start), fmt.Errorf("unmarshal rehydrated: %w", err)
+		return fail(categoryValidate, fmt.Errorf("unmarshal rehydrated: %w", err))
 	}
 	if err := sch.Validate(rehydratedAny); err != nil {
-		return false, time.Since(start), fmt.Errorf("validate: %w", err)
+		return fail(categoryValidate, fmt.Errorf("validate: %w", err))
 	}
 
-	return true, time.Since(start), nil
+	return schemaResult{ok: true, elapsed: time.Since(start), usage: usage}
 }
 
 // Mulberry32 PRNG + Fisher-Yates shuffle for deterministic ordering
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Report formats selected by -report.
+const (
+	reportJSON  = "json"
+	reportJUnit = "junit"
+)
+
+// runReport is the machine-readable summary of a run, written by -report.
+type runReport struct {
+	Model    string        `json:"model"`
+	Endpoint string        `json:"endpoint,omitempty"`
+	Seed     int           `json:"seed"`
+	Workers  int           `json:"workers"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration"`
+	Passed   int           `json:"passed"`
+	Failed   int           `json:"failed"`
+	// Categories counts failures by category.
+	Categories map[string]int `json:"categories"`
+	Usage      tokenUsage     `json:"usage"`
+	// Price and CostUSD are set when the model's price is known (see
+	// -price); the cost is an estimate from list prices.
+	Price   *price   `json:"price,omitempty"`
+	CostUSD *float64 `json:"costUsd,omitempty"`
+	// Schemas are in run order, whatever the number of workers.
+	Schemas []schemaReport `json:"schemas"`
+}
+
+type schemaReport struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Duration time.Duration `json:"duration"`
+	Attempts int           `json:"attempts"`
+	Category string        `json:"category,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Usage    tokenUsage    `json:"usage"`
+	CostUSD  *float64      `json:"costUsd,omitempty"`
+}
+
+// add records the result of one schema and returns its entry.
+func (r *runReport) add(name string, res schemaResult) schemaReport {
+	s := schemaReport{Name: name, Passed: res.ok, Duration: res.elapsed, Attempts: res.attempts, Usage: res.usage}
+	if !res.ok {
+		s.Category = res.category
+		s.Error = res.err.Error()
+	}
+	return r.addEntry(s)
+}
+
+// addEntry records an entry, such as one restored from a checkpoint.
+func (r *runReport) addEntry(s schemaReport) schemaReport {
+	if s.Passed {
+		r.Passed++
+	} else {
+		r.Failed++
+		r.Categories[s.Category]++
+	}
+	r.Usage = r.Usage.add(s.Usage)
+	s.CostUSD = nil
+	if r.Price != nil {
+		c, total := r.Price.cost(s.Usage), r.Price.cost(r.Usage)
+		s.CostUSD, r.CostUSD = &c, &total
+	}
+	r.Schemas = append(r.Schemas, s)
+	return s
+}
+
+// reportPath is -out, or a default file name for format.
+func reportPath(out, format string) string {
+	if out != "" {
+		return out
+	}
+	if format == reportJUnit {
+		return "stress-report.xml"
+	}
+	return "stress-report.json"
+}
+
+// writeReport writes rep to path in format.
+func writeReport(rep *runReport, format, path string) error {
+	var b []byte
+	var err error
+	switch format {
+	case reportJSON:
+		b, err = json.MarshalIndent(rep, "", "  ")
+	case reportJUnit:
+		b, err = xml.MarshalIndent(junitReport(rep), "", "  ")
+		b = append([]byte(xml.Header), b...)
+	default:
+		return fmt.Errorf("unknown report format %q (want %s or %s)", format, reportJSON, reportJUnit)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	Cases      []junitCase     `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitReport lays rep out as one suite per run; failure types are the
+// failure categories and token usage goes to each case's system-out.
+func junitReport(rep *runReport) junitSuites {
+	seconds := func(d time.Duration) string { return fmt.Sprintf("%.3f", d.Seconds()) }
+	classname := "stress." + strings.NewReplacer(".", "_", "/", "_").Replace(rep.Model)
+	suite := junitSuite{
+		Name:      "stress-test-bot-go " + rep.Model,
+		Tests:     len(rep.Schemas),
+		Failures:  rep.Failed,
+		Time:      seconds(rep.Duration),
+		Timestamp: rep.Started.UTC().Format("2006-01-02T15:04:05"),
+		Properties: []junitProperty{
+			{"model", rep.Model},
+			{"seed", fmt.Sprint(rep.Seed)},
+			{"workers", fmt.Sprint(rep.Workers)},
+			{"tokens.total", fmt.Sprint(rep.Usage.Total)},
+		},
+	}
+	if rep.CostUSD != nil {
+		suite.Properties = append(suite.Properties, junitProperty{"cost.usd", fmt.Sprintf("%.6f", *rep.CostUSD)})
+	}
+	if rep.Endpoint != "" {
+		suite.Properties = append(suite.Properties, junitProperty{"endpoint", rep.Endpoint})
+	}
+	for _, s := range rep.Schemas {
+		c := junitCase{
+			Name:      s.Name,
+			Classname: classname,
+			Time:      seconds(s.Duration),
+			SystemOut: fmt.Sprintf("attempts=%d tokens.prompt=%d tokens.completion=%d tokens.total=%d",
+				s.Attempts, s.Usage.Prompt, s.Usage.Completion, s.Usage.Total),
+		}
+		if !s.Passed {
+			c.Failure = &junitFailure{Message: s.Error, Type: s.Category, Text: s.Error}
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+	return junitSuites{
+		Name:     "stress-test-bot-go",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     suite.Time,
+		Suites:   []junitSuite{suite},
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/openai/openai-go"
+)
+
+// maxRetryDelay caps the exponential backoff; a longer Retry-After is
+// still honored.
+const maxRetryDelay = time.Minute
+
+// retryPolicy retries schemas whose request failed transiently (429, 5xx,
+// transport errors), so only genuine pipeline failures are reported.
+type retryPolicy struct {
+	// max is the number of retries after the first attempt.
+	max int
+	// base is the delay before the first retry; it doubles per retry.
+	base time.Duration
+}
+
+// run calls test until it succeeds, fails for good, or runs out of
+// retries. The result's time and usage cover every attempt.
+func (p retryPolicy) run(lim *limiter, test func() schemaResult) schemaResult {
+	var elapsed time.Duration
+	var usage tokenUsage
+	for attempt := 1; ; attempt++ {
+		lim.wait()
+		r := test()
+		elapsed += r.elapsed
+		usage = usage.add(r.usage)
+		if attempt <= p.max && isTransient(r.err) {
+			d := p.delay(r.err, attempt)
+			if isRateLimited(r.err) {
+				// The limit is shared: hold every worker back.
+				lim.pause(d)
+			} else {
+				time.Sleep(d)
+			}
+			continue
+		}
+		r.elapsed, r.usage, r.attempts = elapsed, usage, attempt
+		return r
+	}
+}
+
+// delay is the server's Retry-After, or the backoff after attempt.
+func (p retryPolicy) delay(err error, attempt int) time.Duration {
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) && apiErr.Response != nil {
+		if d, ok := parseRetryAfter(apiErr.Response.Header.Get("Retry-After")); ok {
+			return d
+		}
+	}
+	d := p.base
+	for i := 1; i < attempt && d < maxRetryDelay; i++ {
+		d *= 2
+	}
+	return min(d, maxRetryDelay)
+}
+
+// parseRetryAfter reads a Retry-After value in seconds or as an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// isRateLimited reports whether err is a 429 response.
+func isRateLimited(err error) bool {
+	var apiErr *openai.Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+// isTransient reports whether err is a rate limit, a server error or a
+// transport failure: worth another attempt.
+func isTransient(err error) bool {
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
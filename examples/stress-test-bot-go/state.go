@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// runState is the checkpoint of a run: the schemas it covers and the
+// results of those completed so far, a prefix of them in run order.
+type runState struct {
+	Model     string         `json:"model"`
+	Schemas   []string       `json:"schemas"`
+	Completed []schemaReport `json:"completed"`
+}
+
+// loadState reads the checkpoint at path and checks it belongs to a run
+// of model over the same schemas.
+func loadState(path, model string, schemas []schemaEntry) (*runState, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var st runState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if st.Model != model || !slices.Equal(st.Schemas, schemaNames(schemas)) {
+		return nil, fmt.Errorf("%s is from a different run (model or schema selection changed)", path)
+	}
+	if len(st.Completed) > len(st.Schemas) {
+		return nil, fmt.Errorf("%s: more results than schemas", path)
+	}
+	return &st, nil
+}
+
+// save writes the checkpoint atomically, so a run killed mid-write keeps
+// the previous one.
+func (st *runState) save(path string) error {
+	b, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".stress-state-*")
+	if err != nil {
+		return err
+	}
+	_, werr := tmp.Write(b)
+	if cerr := tmp.Close(); werr == nil {
+		werr = cerr
+	}
+	if werr == nil {
+		werr = os.Rename(tmp.Name(), path)
+	}
+	if werr != nil {
+		os.Remove(tmp.Name())
+	}
+	return werr
+}
+
+func schemaNames(schemas []schemaEntry) []string {
+	names := make([]string, len(schemas))
+	for i, s := range schemas {
+		names[i] = s.name
+	}
+	return names
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Feature tags derived from a schema's content, for -only and -skip.
+const (
+	tagPolymorphism = "polymorphism" // any of oneOf, anyOf, allOf
+	tagOneOf        = "oneOf"
+	tagAnyOf        = "anyOf"
+	tagAllOf        = "allOf"
+	tagMaps         = "maps"      // additionalProperties or patternProperties schemas
+	tagRecursion    = "recursion" // a $ref cycle
+	tagRefs         = "refs"
+	tagOpaque       = "opaque"       // unconstrained subschemas ({} or true)
+	tagTuples       = "tuples"       // prefixItems or array-form items
+	tagConditionals = "conditionals" // if/then/else, dependentSchemas, not
+	tagEnums        = "enums"
+	tagBoolean      = "boolean" // true/false subschemas
+	tagDeep         = "deep"    // nested past deepNesting levels
+)
+
+var allTags = []string{
+	tagPolymorphism, tagOneOf, tagAnyOf, tagAllOf, tagMaps, tagRecursion, tagRefs,
+	tagOpaque, tagTuples, tagConditionals, tagEnums, tagBoolean, tagDeep,
+}
+
+// deepNesting is the subschema depth past which a schema is tagged deep.
+const deepNesting = 10
+
+// Subschema locations, by the shape of their keyword's value.
+var (
+	schemaMapKeywords  = []string{"properties", "patternProperties", "$defs", "definitions", "dependentSchemas"}
+	schemaListKeywords = []string{"oneOf", "anyOf", "allOf", "prefixItems"}
+	schemaKeywords     = []string{"items", "additionalProperties", "additionalItems", "not", "if", "then", "else",
+		"contains", "propertyNames", "unevaluatedProperties", "unevaluatedItems"}
+)
+
+// tagger collects the tags of one schema.
+type tagger struct {
+	root map[string]any
+	tags map[string]bool
+	// refs maps each $ref's location to its target, for cycle detection.
+	refs map[string]string
+}
+
+// schemaTags returns the feature tags of schema.
+func schemaTags(schema map[string]any) map[string]bool {
+	t := &tagger{root: schema, tags: map[string]bool{}, refs: map[string]string{}}
+	t.visit(schema, "#", 0)
+	if t.cyclic() {
+		t.tags[tagRecursion] = true
+	}
+	return t.tags
+}
+
+func (t *tagger) visit(node any, loc string, depth int) {
+	if depth > deepNesting {
+		t.tags[tagDeep] = true
+	}
+	m, ok := node.(map[string]any)
+	if !ok {
+		if _, isBool := node.(bool); isBool && loc != "#" {
+			t.tags[tagBoolean] = true
+			if node == true {
+				t.tags[tagOpaque] = true
+			}
+		}
+		return
+	}
+	if len(m) == 0 && loc != "#" {
+		t.tags[tagOpaque] = true
+	}
+	if ref, ok := m["$ref"].(string); ok {
+		t.tags[tagRefs] = true
+		t.refs[loc] = ref
+	}
+	for _, kw := range []string{tagOneOf, tagAnyOf, tagAllOf} {
+		if _, ok := m[kw]; ok {
+			t.tags[kw] = true
+			t.tags[tagPolymorphism] = true
+		}
+	}
+	if _, ok := m["patternProperties"]; ok {
+		t.tags[tagMaps] = true
+	}
+	if ap, ok := m["additionalProperties"].(map[string]any); ok && len(ap) > 0 {
+		t.tags[tagMaps] = true
+	}
+	if _, ok := m["prefixItems"]; ok {
+		t.tags[tagTuples] = true
+	}
+	if _, ok := m["items"].([]any); ok {
+		t.tags[tagTuples] = true
+	}
+	for _, kw := range []string{"if", "dependentSchemas", "not"} {
+		if _, ok := m[kw]; ok {
+			t.tags[tagConditionals] = true
+		}
+	}
+	if _, ok := m["enum"]; ok {
+		t.tags[tagEnums] = true
+	}
+
+	for _, kw := range schemaMapKeywords {
+		children, _ := m[kw].(map[string]any)
+		for k, c := range children {
+			t.visit(c, loc+"/"+kw+"/"+escapeToken(k), depth+1)
+		}
+	}
+	for _, kw := range schemaListKeywords {
+		children, _ := m[kw].([]any)
+		for i, c := range children {
+			t.visit(c, fmt.Sprintf("%s/%s/%d", loc, kw, i), depth+1)
+		}
+	}
+	for _, kw := range schemaKeywords {
+		switch c := m[kw].(type) {
+		case []any: // draft-07 tuple items
+			for i, item := range c {
+				t.visit(item, fmt.Sprintf("%s/%s/%d", loc, kw, i), depth+1)
+			}
+		case nil:
+		default:
+			t.visit(c, loc+"/"+kw, depth+1)
+		}
+	}
+}
+
+// cyclic reports whether following $refs can lead back into a schema
+// already being expanded: a ref to an ancestor, or a chain of definitions
+// referring to each other.
+func (t *tagger) cyclic() bool {
+	// A ref at loc lies inside every target that is a prefix of loc, so
+	// expanding that target expands the ref too.
+	edges := map[string][]string{}
+	targets := map[string]bool{"#": true}
+	for _, target := range t.refs {
+		targets[target] = true
+	}
+	for loc, target := range t.refs {
+		for from := range targets {
+			if loc == from || strings.HasPrefix(loc, from+"/") {
+				edges[from] = append(edges[from], target)
+			}
+		}
+	}
+	state := map[string]int{} // 1 = on the stack, 2 = done
+	var visit func(string) bool
+	visit = func(n string) bool {
+		switch state[n] {
+		case 1:
+			return true
+		case 2:
+			return false
+		}
+		state[n] = 1
+		for _, next := range edges[n] {
+			if visit(next) {
+				return true
+			}
+		}
+		state[n] = 2
+		return false
+	}
+	return visit("#")
+}
+
+func escapeToken(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// parseTags splits a comma-separated tag list, rejecting unknown tags.
+func parseTags(v string) (map[string]bool, error) {
+	if v == "" {
+		return nil, nil
+	}
+	known := map[string]bool{}
+	for _, tag := range allTags {
+		known[tag] = true
+	}
+	tags := map[string]bool{}
+	for _, tag := range strings.Split(v, ",") {
+		tag = strings.TrimSpace(tag)
+		if !known[tag] {
+			return nil, fmt.Errorf("unknown tag %q (known: %s)", tag, strings.Join(allTags, ", "))
+		}
+		tags[tag] = true
+	}
+	return tags, nil
+}
+
+// filterSchemas keeps the schemas with at least one tag of only (all when
+// only is empty) and none of skip.
+func filterSchemas(schemas []schemaEntry, only, skip map[string]bool) []schemaEntry {
+	if len(only) == 0 && len(skip) == 0 {
+		return schemas
+	}
+	var kept []schemaEntry
+	for _, s := range schemas {
+		tags := schemaTags(s.schema)
+		if len(only) > 0 && !anyTag(tags, only) || anyTag(tags, skip) {
+			continue
+		}
+		kept = append(kept, s)
+	}
+	return kept
+}
+
+func anyTag(tags, want map[string]bool) bool {
+	for tag := range want {
+		if tags[tag] {
+			return true
+		}
+	}
+	return false
+}
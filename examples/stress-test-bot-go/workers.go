@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dotslashderek/json-schema-llm/bindings/go/compat"
+	"github.com/openai/openai-go"
+)
+
+// Failure categories of a schemaResult: the pipeline stage that failed.
+const (
+	categoryConvert   = "convert"
+	categoryAPI       = "api"
+	categoryRateLimit = "rate_limit"
+	categoryRefusal   = "refusal"
+	categoryParse     = "parse"
+	categoryRehydrate = "rehydrate"
+	categoryValidate  = "validate"
+)
+
+// schemaResult is the outcome of one schema.
+type schemaResult struct {
+	ok       bool
+	elapsed  time.Duration
+	category string // empty when ok
+	err      error
+	usage    tokenUsage
+	attempts int
+	// artifacts are the failed run's intermediate values.
+	artifacts *artifacts
+}
+
+// tokenUsage counts the tokens a schema's requests used.
+type tokenUsage struct {
+	Prompt     int64 `json:"prompt"`
+	Completion int64 `json:"completion"`
+	Total      int64 `json:"total"`
+}
+
+func (u tokenUsage) add(o tokenUsage) tokenUsage {
+	return tokenUsage{u.Prompt + o.Prompt, u.Completion + o.Completion, u.Total + o.Total}
+}
+
+// limiter spaces request starts across all workers (one per interval, no
+// spacing when zero) and holds every worker back after a rate-limit
+// response.
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newLimiter(rpm int) *limiter {
+	l := &limiter{}
+	if rpm > 0 {
+		l.interval = time.Minute / time.Duration(rpm)
+	}
+	return l
+}
+
+// wait blocks until the caller's reserved start slot.
+func (l *limiter) wait() {
+	l.mu.Lock()
+	at := l.next
+	if now := time.Now(); at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+	time.Sleep(time.Until(at))
+}
+
+// pause delays every start not yet reserved by at least d.
+func (l *limiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t := time.Now().Add(d); t.After(l.next) {
+		l.next = t
+	}
+}
+
+// runWorkers tests schemas on n workers, each with its own engine, and
+// calls report for every result in schema order as soon as it and all
+// earlier ones are done, so the output does not depend on scheduling.
+func runWorkers(client *openai.Client, schemas []schemaEntry, model string, n int, lim *limiter, retry retryPolicy, report func(i int, r schemaResult)) error {
+	n = max(1, min(n, len(schemas)))
+	engines := make([]*compat.Engine, n)
+	for w := range engines {
+		engine, err := compat.New()
+		if err != nil {
+			for _, e := range engines[:w] {
+				e.Close()
+			}
+			return fmt.Errorf("initialize WASI engine: %w", err)
+		}
+		engines[w] = engine
+	}
+	defer func() {
+		for _, e := range engines {
+			e.Close()
+		}
+	}()
+
+	jobs := make(chan int)
+	type done struct {
+		i int
+		r schemaResult
+	}
+	results := make(chan done)
+	var wg sync.WaitGroup
+	for _, engine := range engines {
+		wg.Add(1)
+		go func(engine *compat.Engine) {
+			defer wg.Done()
+			for i := range jobs {
+				results <- done{i, retry.run(lim, func() schemaResult { return testSchema(engine, client, schemas[i], model) })}
+			}
+		}(engine)
+	}
+	go func() {
+		for i := range schemas {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := map[int]schemaResult{}
+	next := 0
+	for d := range results {
+		pending[d.i] = d.r
+		for r, ok := pending[next]; ok; r, ok = pending[next] {
+			delete(pending, next)
+			report(next, r)
+			next++
+		}
+	}
+	return nil
+}